@@ -0,0 +1,185 @@
+package atc
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// Expression is a node of a parsed expression.
+// It is one of *Logical, *Not or *Predicate.
+type Expression interface {
+	fmt.Stringer
+	expression()
+}
+
+// LogicalOperator combines two expressions.
+type LogicalOperator string
+
+const (
+	And LogicalOperator = "&&"
+	Or  LogicalOperator = "||"
+)
+
+// Logical is the combination of two expressions with && or ||.
+type Logical struct {
+	Operator    LogicalOperator
+	Left, Right Expression
+}
+
+// Not is the negation of a parenthesised expression.
+type Not struct {
+	Expression Expression
+}
+
+// Operator compares a field with a value.
+type Operator string
+
+const (
+	Equals      Operator = "=="
+	NotEquals   Operator = "!="
+	Regex       Operator = "~"
+	Prefix      Operator = "^="
+	Postfix     Operator = "=^"
+	Greater     Operator = ">"
+	GreaterOrEq Operator = ">="
+	Less        Operator = "<"
+	LessOrEq    Operator = "<="
+	In          Operator = "in"
+	NotIn       Operator = "not in"
+	Contains    Operator = "contains"
+)
+
+// Predicate compares a field, possibly transformed, with a literal value.
+type Predicate struct {
+	LHS      LHS
+	Operator Operator
+	RHS      Value
+	// Offset is the position of the predicate in the parsed expression.
+	Offset int
+}
+
+// Transform is a function applied to a field before the comparison.
+type Transform string
+
+const (
+	// Lower lowercases the value of the field.
+	Lower Transform = "lower"
+	// Any matches if any of the values of a multi-valued field matches.
+	Any Transform = "any"
+)
+
+// LHS is the left-hand side of a predicate.
+type LHS struct {
+	Field string
+	// Transforms are listed from the outermost to the innermost.
+	Transforms []Transform
+}
+
+// Type is the type of a field or of a literal value.
+type Type int
+
+const (
+	String Type = iota
+	Int
+	IPAddr
+	IPCIDR
+)
+
+func (t Type) String() string {
+	switch t {
+	case String:
+		return "String"
+	case Int:
+		return "Int"
+	case IPAddr:
+		return "IpAddr"
+	case IPCIDR:
+		return "IpCidr"
+	default:
+		return "Unknown"
+	}
+}
+
+// Value is a literal value on the right-hand side of a predicate.
+type Value struct {
+	Type   Type
+	String string
+	Int    int64
+	Addr   netip.Addr
+	Prefix netip.Prefix
+}
+
+func (*Logical) expression()   {}
+func (*Not) expression()       {}
+func (*Predicate) expression() {}
+
+func (l *Logical) String() string {
+	return l.operand(l.Left) + " " + string(l.Operator) + " " + l.operand(l.Right)
+}
+
+// operand wraps e in parentheses when it binds looser than l.
+func (l *Logical) operand(e Expression) string {
+	if child, ok := e.(*Logical); ok && l.Operator == And && child.Operator == Or {
+		return "(" + child.String() + ")"
+	}
+	return e.String()
+}
+
+func (n *Not) String() string {
+	return "!(" + n.Expression.String() + ")"
+}
+
+func (p *Predicate) String() string {
+	return p.LHS.String() + " " + string(p.Operator) + " " + p.RHS.Literal()
+}
+
+func (l LHS) String() string {
+	res := l.Field
+	for i := len(l.Transforms) - 1; i >= 0; i-- {
+		res = string(l.Transforms[i]) + "(" + res + ")"
+	}
+	return res
+}
+
+// Literal returns the value as it is written in an expression.
+func (v Value) Literal() string {
+	switch v.Type {
+	case Int:
+		return strconv.FormatInt(v.Int, 10)
+	case IPAddr:
+		return v.Addr.String()
+	case IPCIDR:
+		return v.Prefix.String()
+	default:
+		// raw strings spare the escaping of regexes.
+		if strings.Contains(v.String, `\`) && !strings.Contains(v.String, `"#`) {
+			return `r#"` + v.String + `"#`
+		}
+		return quote(v.String)
+	}
+}
+
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
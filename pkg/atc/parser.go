@@ -0,0 +1,290 @@
+package atc
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// Error is a syntax or validation error in an expression.
+type Error struct {
+	// Offset is the position in the expression where the error was found.
+	Offset  int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s (at offset %d)", e.Message, e.Offset)
+}
+
+type parser struct {
+	input string
+	pos   int
+}
+
+// Parse parses an expression written in the language of Kong's expressions
+// router. Only the syntax is checked, use Validate to check field names and
+// types as well.
+func Parse(expression string) (Expression, error) {
+	p := &parser{input: expression}
+	p.skipSpaces()
+	if p.eof() {
+		return nil, p.errorf("empty expression")
+	}
+	res, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if !p.eof() {
+		return nil, p.errorf("unexpected %q", p.rest(10))
+	}
+	return res, nil
+}
+
+func (p *parser) errorf(format string, args ...any) *Error {
+	return &Error{Offset: p.pos, Message: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+// rest returns up to n bytes of the remaining input, for error messages.
+func (p *parser) rest(n int) string {
+	return p.input[p.pos:min(p.pos+n, len(p.input))]
+}
+
+func (p *parser) skipSpaces() {
+	for !p.eof() && strings.ContainsRune(" \t\r\n", rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+// consume skips the token if the remaining input starts with it.
+func (p *parser) consume(token string) bool {
+	p.skipSpaces()
+	if !strings.HasPrefix(p.input[p.pos:], token) {
+		return false
+	}
+	end := p.pos + len(token)
+	// keywords must not be followed by an identifier character.
+	if isIdentChar(token[len(token)-1]) && end < len(p.input) && isIdentChar(p.input[end]) {
+		return false
+	}
+	p.pos = end
+	return true
+}
+
+func (p *parser) expect(token string) error {
+	if !p.consume(token) {
+		return p.errorf("expected %q", token)
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (Expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.consume(string(Or)) {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Logical{Operator: Or, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expression, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.consume(string(And)) {
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = &Logical{Operator: And, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseTerm() (Expression, error) {
+	if p.consume("!") {
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		e, err := p.parseParenthesised()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Expression: e}, nil
+	}
+	if p.consume("(") {
+		return p.parseParenthesised()
+	}
+	return p.parsePredicate()
+}
+
+// parseParenthesised parses the rest of an expression after its opening parenthesis.
+func (p *parser) parseParenthesised() (Expression, error) {
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (p *parser) parsePredicate() (Expression, error) {
+	p.skipSpaces()
+	offset := p.pos
+	lhs, err := p.parseLHS()
+	if err != nil {
+		return nil, err
+	}
+	op, err := p.parseOperator()
+	if err != nil {
+		return nil, err
+	}
+	rhs, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	return &Predicate{LHS: lhs, Operator: op, RHS: rhs, Offset: offset}, nil
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c == '.' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func (p *parser) parseIdent() (string, error) {
+	p.skipSpaces()
+	start := p.pos
+	if p.eof() || !(p.input[p.pos] >= 'a' && p.input[p.pos] <= 'z' || p.input[p.pos] >= 'A' && p.input[p.pos] <= 'Z') {
+		return "", p.errorf("expected a field name, found %q", p.rest(10))
+	}
+	for !p.eof() && isIdentChar(p.input[p.pos]) {
+		p.pos++
+	}
+	return p.input[start:p.pos], nil
+}
+
+func (p *parser) parseLHS() (LHS, error) {
+	start := p.pos
+	ident, err := p.parseIdent()
+	if err != nil {
+		return LHS{}, err
+	}
+	if !p.consume("(") {
+		return LHS{Field: ident}, nil
+	}
+	transform := Transform(ident)
+	if transform != Lower && transform != Any {
+		return LHS{}, &Error{Offset: start, Message: fmt.Sprintf("unknown transformation function %q", ident)}
+	}
+	inner, err := p.parseLHS()
+	if err != nil {
+		return LHS{}, err
+	}
+	if err := p.expect(")"); err != nil {
+		return LHS{}, err
+	}
+	inner.Transforms = append([]Transform{transform}, inner.Transforms...)
+	return inner, nil
+}
+
+// operators are sorted so that no operator is a prefix of a later one.
+var operators = []Operator{
+	Equals, NotEquals, Prefix, Postfix, GreaterOrEq, Greater, LessOrEq, Less, Regex, Contains, In,
+}
+
+func (p *parser) parseOperator() (Operator, error) {
+	for _, op := range operators {
+		if p.consume(string(op)) {
+			return op, nil
+		}
+	}
+	if p.consume("not") {
+		if p.consume("in") {
+			return NotIn, nil
+		}
+	}
+	return "", p.errorf("expected an operator, found %q", p.rest(10))
+}
+
+func (p *parser) parseValue() (Value, error) {
+	p.skipSpaces()
+	switch {
+	case strings.HasPrefix(p.input[p.pos:], `r#"`):
+		return p.parseRawString()
+	case strings.HasPrefix(p.input[p.pos:], `"`):
+		return p.parseString()
+	}
+
+	start := p.pos
+	for !p.eof() && (isIdentChar(p.input[p.pos]) || strings.ContainsRune(":/-", rune(p.input[p.pos]))) {
+		p.pos++
+	}
+	literal := p.input[start:p.pos]
+	if literal == "" {
+		return Value{}, p.errorf("expected a value, found %q", p.rest(10))
+	}
+	if i, err := strconv.ParseInt(literal, 0, 64); err == nil {
+		return Value{Type: Int, Int: i}, nil
+	}
+	if prefix, err := netip.ParsePrefix(literal); err == nil {
+		return Value{Type: IPCIDR, Prefix: prefix}, nil
+	}
+	if addr, err := netip.ParseAddr(literal); err == nil {
+		return Value{Type: IPAddr, Addr: addr}, nil
+	}
+	return Value{}, &Error{Offset: start, Message: fmt.Sprintf("invalid value %q", literal)}
+}
+
+func (p *parser) parseRawString() (Value, error) {
+	start := p.pos
+	p.pos += len(`r#"`)
+	end := strings.Index(p.input[p.pos:], `"#`)
+	if end < 0 {
+		return Value{}, &Error{Offset: start, Message: "unterminated raw string"}
+	}
+	s := p.input[p.pos : p.pos+end]
+	p.pos += end + len(`"#`)
+	return Value{Type: String, String: s}, nil
+}
+
+func (p *parser) parseString() (Value, error) {
+	start := p.pos
+	p.pos++
+	var b strings.Builder
+	for !p.eof() {
+		c := p.input[p.pos]
+		switch c {
+		case '"':
+			p.pos++
+			return Value{Type: String, String: b.String()}, nil
+		case '\\':
+			if p.pos+1 >= len(p.input) {
+				return Value{}, &Error{Offset: start, Message: "unterminated string"}
+			}
+			escaped, ok := map[byte]byte{'"': '"', '\\': '\\', 'n': '\n', 'r': '\r', 't': '\t'}[p.input[p.pos+1]]
+			if !ok {
+				return Value{}, p.errorf("invalid escape sequence %q", p.input[p.pos:p.pos+2])
+			}
+			b.WriteByte(escaped)
+			p.pos += 2
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+	return Value{}, &Error{Offset: start, Message: "unterminated string"}
+}
@@ -0,0 +1,146 @@
+package atc
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		want       Expression
+		// canonical is the expected String() output, if it differs from expression.
+		canonical string
+	}{
+		{
+			name:       "single predicate",
+			expression: `http.path == "/foo"`,
+			want: &Predicate{
+				LHS:      LHS{Field: "http.path"},
+				Operator: Equals,
+				RHS:      Value{Type: String, String: "/foo"},
+			},
+		},
+		{
+			name:       "and binds tighter than or",
+			expression: `http.method == "GET" || http.method == "POST" && http.path ^= "/api"`,
+			want: &Logical{
+				Operator: Or,
+				Left: &Predicate{
+					LHS: LHS{Field: "http.method"}, Operator: Equals,
+					RHS: Value{Type: String, String: "GET"},
+				},
+				Right: &Logical{
+					Operator: And,
+					Left: &Predicate{
+						LHS: LHS{Field: "http.method"}, Operator: Equals,
+						RHS: Value{Type: String, String: "POST"}, Offset: 24,
+					},
+					Right: &Predicate{
+						LHS: LHS{Field: "http.path"}, Operator: Prefix,
+						RHS: Value{Type: String, String: "/api"}, Offset: 49,
+					},
+				},
+			},
+		},
+		{
+			name:       "grouping, negation and transforms",
+			expression: `!(lower(http.host) =^ ".example.com") && (net.dst.port >= 8000 || net.src.ip not in 10.0.0.0/8)`,
+			want: &Logical{
+				Operator: And,
+				Left: &Not{Expression: &Predicate{
+					LHS: LHS{Field: "http.host", Transforms: []Transform{Lower}}, Operator: Postfix,
+					RHS: Value{Type: String, String: ".example.com"}, Offset: 2,
+				}},
+				Right: &Logical{
+					Operator: Or,
+					Left: &Predicate{
+						LHS: LHS{Field: "net.dst.port"}, Operator: GreaterOrEq,
+						RHS: Value{Type: Int, Int: 8000}, Offset: 42,
+					},
+					Right: &Predicate{
+						LHS: LHS{Field: "net.src.ip"}, Operator: NotIn,
+						RHS: Value{Type: IPCIDR, Prefix: netip.MustParsePrefix("10.0.0.0/8")}, Offset: 66,
+					},
+				},
+			},
+		},
+		{
+			name:       "string literals",
+			expression: "any(http.headers.x_foo) ~ r#\"^\\d+\"# || http.path contains \"a\\\"b\\n\"",
+			want: &Logical{
+				Operator: Or,
+				Left: &Predicate{
+					LHS: LHS{Field: "http.headers.x_foo", Transforms: []Transform{Any}}, Operator: Regex,
+					RHS: Value{Type: String, String: `^\d+`},
+				},
+				Right: &Predicate{
+					LHS: LHS{Field: "http.path"}, Operator: Contains,
+					RHS: Value{Type: String, String: "a\"b\n"}, Offset: 39,
+				},
+			},
+		},
+		{
+			name:       "ip address and hexadecimal integer",
+			expression: `net.dst.ip == ::1 && net.src.port==0x50`,
+			want: &Logical{
+				Operator: And,
+				Left: &Predicate{
+					LHS: LHS{Field: "net.dst.ip"}, Operator: Equals,
+					RHS: Value{Type: IPAddr, Addr: netip.MustParseAddr("::1")},
+				},
+				Right: &Predicate{
+					LHS: LHS{Field: "net.src.port"}, Operator: Equals,
+					RHS: Value{Type: Int, Int: 80}, Offset: 21,
+				},
+			},
+			canonical: `net.dst.ip == ::1 && net.src.port == 80`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.expression)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			canonical := tt.canonical
+			if canonical == "" {
+				canonical = tt.expression
+			}
+			assert.Equal(t, canonical, got.String())
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       string
+	}{
+		{``, `empty expression (at offset 0)`},
+		{`http.path`, `expected an operator, found "" (at offset 9)`},
+		{`http.path = "/foo"`, `expected an operator, found "= \"/foo\"" (at offset 10)`},
+		{`http.path == "/foo`, `unterminated string (at offset 13)`},
+		{`http.path == "\d"`, `invalid escape sequence "\\d" (at offset 14)`},
+		{`http.path == r#"/foo"`, `unterminated raw string (at offset 13)`},
+		{`upper(http.path) == "/foo"`, `unknown transformation function "upper" (at offset 0)`},
+		{`(http.path == "/foo"`, `expected ")" (at offset 20)`},
+		{`!http.path == "/foo"`, `expected "(" (at offset 1)`},
+		{`http.path == "/foo" &&`, `expected a field name, found "" (at offset 22)`},
+		{`http.path == "/foo" http.host == "a"`, `unexpected "http.host " (at offset 20)`},
+		{`net.src.port == 80a`, `invalid value "80a" (at offset 16)`},
+		{`http.path in`, `expected a value, found "" (at offset 12)`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			_, err := Parse(tt.expression)
+			var atcErr *Error
+			require.ErrorAs(t, err, &atcErr)
+			assert.EqualError(t, err, tt.want)
+		})
+	}
+}
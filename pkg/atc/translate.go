@@ -0,0 +1,185 @@
+package atc
+
+import (
+	"errors"
+	"net/netip"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kong/go-kong/kong"
+)
+
+var errNoMatchingCriteria = errors.New("route has no matching criteria")
+
+// FromRoute translates the matching criteria of a traditional route
+// (protocols, methods, hosts, paths, headers, snis, sources and
+// destinations) into an equivalent expression.
+// Priorities are not translated: the priority of the resulting expression
+// route must be set so that routes are evaluated in the same order.
+func FromRoute(route kong.Route) (Expression, error) {
+	var groups []Expression
+
+	protocols := stringValues(route.Protocols)
+	slices.Sort(protocols)
+	if len(protocols) > 0 && !slices.Equal(protocols, []string{"http", "https"}) {
+		groups = appendAnyOf(groups, protocols, func(p string) Expression {
+			return stringPredicate("net.protocol", Equals, p)
+		})
+	}
+	groups = appendAnyOf(groups, stringValues(route.Methods), func(m string) Expression {
+		return stringPredicate("http.method", Equals, strings.ToUpper(m))
+	})
+	groups = appendAnyOf(groups, stringValues(route.Hosts), hostExpression)
+	groups = appendAnyOf(groups, stringValues(route.Paths), pathExpression)
+
+	headers := make([]string, 0, len(route.Headers))
+	for name := range route.Headers {
+		headers = append(headers, name)
+	}
+	sort.Strings(headers)
+	for _, name := range headers {
+		field := "http.headers." + strings.ReplaceAll(strings.ToLower(name), "-", "_")
+		groups = appendAnyOf(groups, route.Headers[name], func(v string) Expression {
+			// values prefixed with ~* are case-insensitive regexes.
+			if regex, ok := strings.CutPrefix(v, "~*"); ok {
+				return stringPredicate(field, Regex, "(?i)"+regex)
+			}
+			p := stringPredicate(field, Equals, strings.ToLower(v))
+			p.LHS.Transforms = []Transform{Lower}
+			return p
+		})
+	}
+
+	groups = appendAnyOf(groups, stringValues(route.SNIs), func(sni string) Expression {
+		return stringPredicate("tls.sni", Equals, strings.TrimSuffix(sni, "."))
+	})
+
+	var err error
+	groups, err = appendEndpoints(groups, "net.src", route.Sources)
+	if err != nil {
+		return nil, err
+	}
+	groups, err = appendEndpoints(groups, "net.dst", route.Destinations)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(groups) == 0 {
+		return nil, errNoMatchingCriteria
+	}
+	return combine(And, groups), nil
+}
+
+func stringPredicate(field string, op Operator, value string) *Predicate {
+	return &Predicate{LHS: LHS{Field: field}, Operator: op, RHS: Value{Type: String, String: value}}
+}
+
+func intPredicate(field string, value int) *Predicate {
+	return &Predicate{LHS: LHS{Field: field}, Operator: Equals, RHS: Value{Type: Int, Int: int64(value)}}
+}
+
+// combine joins expressions with op, from left to right.
+func combine(op LogicalOperator, exprs []Expression) Expression {
+	res := exprs[0]
+	for _, e := range exprs[1:] {
+		res = &Logical{Operator: op, Left: res, Right: e}
+	}
+	return res
+}
+
+// appendAnyOf appends to groups an expression matching any of values.
+func appendAnyOf(groups []Expression, values []string, fn func(string) Expression) []Expression {
+	if len(values) == 0 {
+		return groups
+	}
+	exprs := make([]Expression, 0, len(values))
+	for _, v := range values {
+		exprs = append(exprs, fn(v))
+	}
+	return append(groups, combine(Or, exprs))
+}
+
+func hostExpression(host string) Expression {
+	var port int
+	if i := strings.LastIndexByte(host, ':'); i >= 0 {
+		if p, err := strconv.Atoi(host[i+1:]); err == nil {
+			host, port = host[:i], p
+		}
+	}
+	var e Expression
+	switch {
+	case strings.HasPrefix(host, "*"):
+		e = stringPredicate("http.host", Postfix, host[1:])
+	case strings.HasSuffix(host, "*"):
+		e = stringPredicate("http.host", Prefix, host[:len(host)-1])
+	default:
+		e = stringPredicate("http.host", Equals, host)
+	}
+	if port != 0 {
+		e = &Logical{Operator: And, Left: e, Right: intPredicate("net.dst.port", port)}
+	}
+	return e
+}
+
+func pathExpression(path string) Expression {
+	regex, ok := strings.CutPrefix(path, "~")
+	if !ok {
+		return stringPredicate("http.path", Prefix, path)
+	}
+	// regex paths are anchored at the beginning of the request path.
+	if !strings.HasPrefix(regex, "^") {
+		regex = "^" + regex
+	}
+	return stringPredicate("http.path", Regex, regex)
+}
+
+func appendEndpoints(groups []Expression, prefix string, endpoints []*kong.CIDRPort) ([]Expression, error) {
+	exprs := make([]Expression, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		var conditions []Expression
+		if endpoint.IP != nil {
+			ip, err := ipPredicate(prefix+".ip", *endpoint.IP)
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, ip)
+		}
+		if endpoint.Port != nil {
+			conditions = append(conditions, intPredicate(prefix+".port", *endpoint.Port))
+		}
+		if len(conditions) > 0 {
+			exprs = append(exprs, combine(And, conditions))
+		}
+	}
+	if len(exprs) == 0 {
+		return groups, nil
+	}
+	return append(groups, combine(Or, exprs)), nil
+}
+
+func ipPredicate(field, ip string) (*Predicate, error) {
+	if strings.Contains(ip, "/") {
+		prefix, err := netip.ParsePrefix(ip)
+		if err != nil {
+			return nil, err
+		}
+		return &Predicate{LHS: LHS{Field: field}, Operator: In, RHS: Value{Type: IPCIDR, Prefix: prefix}}, nil
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return nil, err
+	}
+	return &Predicate{LHS: LHS{Field: field}, Operator: Equals, RHS: Value{Type: IPAddr, Addr: addr}}, nil
+}
+
+func stringValues(values []*string) []string {
+	res := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != nil {
+			res = append(res, *v)
+		}
+	}
+	return res
+}
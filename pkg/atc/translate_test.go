@@ -0,0 +1,86 @@
+package atc
+
+import (
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromRoute(t *testing.T) {
+	tests := []struct {
+		name  string
+		route kong.Route
+		want  string
+	}{
+		{
+			name: "paths and methods",
+			route: kong.Route{
+				Protocols: []*string{new("https"), new("http")},
+				Methods:   []*string{new("get"), new("POST")},
+				Paths:     []*string{new("/api"), new(`~/users/\d+$`)},
+			},
+			want: `(http.method == "GET" || http.method == "POST") && ` +
+				`(http.path ^= "/api" || http.path ~ r#"^/users/\d+$"#)`,
+		},
+		{
+			name: "hosts",
+			route: kong.Route{
+				Hosts: []*string{new("example.com"), new("*.example.com"), new("example.*"), new("api.example.com:8443")},
+			},
+			want: `http.host == "example.com" || http.host =^ ".example.com" || http.host ^= "example." || ` +
+				`http.host == "api.example.com" && net.dst.port == 8443`,
+		},
+		{
+			name: "headers and snis",
+			route: kong.Route{
+				Protocols: []*string{new("https")},
+				Headers: map[string][]string{
+					"X-Version": {"V1", "v2"},
+					"Accept":    {"~*json"},
+				},
+				SNIs: []*string{new("example.com.")},
+			},
+			want: `net.protocol == "https" && http.headers.accept ~ "(?i)json" && ` +
+				`(lower(http.headers.x_version) == "v1" || lower(http.headers.x_version) == "v2") && ` +
+				`tls.sni == "example.com"`,
+		},
+		{
+			name: "stream route",
+			route: kong.Route{
+				Protocols: []*string{new("tcp")},
+				Sources: []*kong.CIDRPort{
+					{IP: new("10.0.0.0/8")},
+					{IP: new("192.168.0.1"), Port: new(1234)},
+				},
+				Destinations: []*kong.CIDRPort{{Port: new(5432)}},
+			},
+			want: `net.protocol == "tcp" && ` +
+				`(net.src.ip in 10.0.0.0/8 || net.src.ip == 192.168.0.1 && net.src.port == 1234) && ` +
+				`net.dst.port == 5432`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromRoute(tt.route)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.String())
+			// the translation must be valid and parse back to the same expression.
+			require.NoError(t, Validate(got.String()))
+			parsed, err := Parse(got.String())
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, parsed.String())
+		})
+	}
+
+	t.Run("route without matching criteria", func(t *testing.T) {
+		_, err := FromRoute(kong.Route{Protocols: []*string{new("http"), new("https")}})
+		require.ErrorIs(t, err, errNoMatchingCriteria)
+	})
+
+	t.Run("invalid source", func(t *testing.T) {
+		_, err := FromRoute(kong.Route{Sources: []*kong.CIDRPort{{IP: new("not-an-ip")}}})
+		require.Error(t, err)
+	})
+}
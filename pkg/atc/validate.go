@@ -0,0 +1,135 @@
+package atc
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/kong/go-database-reconciler/pkg/utils"
+)
+
+// fields are the fields of the http and stream expressions router schemas,
+// with their types.
+var fields = map[string]Type{
+	"net.protocol":           String,
+	"tls.sni":                String,
+	"http.method":            String,
+	"http.host":              String,
+	"http.path":              String,
+	"http.path.segments.len": Int,
+	"net.src.ip":             IPAddr,
+	"net.src.port":           Int,
+	"net.dst.ip":             IPAddr,
+	"net.dst.port":           Int,
+	// deprecated alias of net.dst.port.
+	"net.port": Int,
+}
+
+// multiValuedFields are the prefixes of fields whose name ends with a
+// user-defined key, and which can hold several values.
+var multiValuedFields = []string{"http.headers.", "http.queries."}
+
+var pathSegmentsRegex = regexp.MustCompile(`^http\.path\.segments\.(\d+|\d+_\d+)$`)
+
+var operatorsByType = map[Type][]Operator{
+	String: {Equals, NotEquals, Regex, Prefix, Postfix, Contains},
+	Int:    {Equals, NotEquals, Greater, GreaterOrEq, Less, LessOrEq},
+	IPAddr: {Equals, NotEquals, In, NotIn},
+}
+
+// FieldType returns the type of a field of the expressions router schema.
+func FieldType(field string) (Type, bool) {
+	if t, ok := fields[field]; ok {
+		return t, true
+	}
+	if isMultiValued(field) || pathSegmentsRegex.MatchString(field) {
+		return String, true
+	}
+	return 0, false
+}
+
+func isMultiValued(field string) bool {
+	for _, prefix := range multiValuedFields {
+		if strings.HasPrefix(field, prefix) && len(field) > len(prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate parses an expression and checks that it only uses known fields,
+// and that every operator and value is compatible with the type of the
+// field it applies to.
+// Errors are reported as *Error, or utils.ErrArray of *Error if there are
+// several of them.
+func Validate(expression string) error {
+	e, err := Parse(expression)
+	if err != nil {
+		return err
+	}
+	var errs utils.ErrArray
+	walk(e, func(p *Predicate) {
+		if err := validatePredicate(p); err != nil {
+			errs.Errors = append(errs.Errors, err)
+		}
+	})
+	switch len(errs.Errors) {
+	case 0:
+		return nil
+	case 1:
+		return errs.Errors[0]
+	default:
+		return errs
+	}
+}
+
+// walk calls fn for every predicate of e, from left to right.
+func walk(e Expression, fn func(*Predicate)) {
+	switch e := e.(type) {
+	case *Logical:
+		walk(e.Left, fn)
+		walk(e.Right, fn)
+	case *Not:
+		walk(e.Expression, fn)
+	case *Predicate:
+		fn(e)
+	}
+}
+
+func validatePredicate(p *Predicate) error {
+	errorf := func(format string, args ...any) error {
+		return &Error{Offset: p.Offset, Message: fmt.Sprintf(format, args...)}
+	}
+
+	fieldType, ok := FieldType(p.LHS.Field)
+	if !ok {
+		return errorf("unknown field %q", p.LHS.Field)
+	}
+	for _, t := range p.LHS.Transforms {
+		switch {
+		case t == Lower && fieldType != String:
+			return errorf("lower() can only be applied to String fields, %s is %s", p.LHS.Field, fieldType)
+		case t == Any && !isMultiValued(p.LHS.Field):
+			return errorf("any() can only be applied to http.headers.* and http.queries.* fields")
+		}
+	}
+	if !slices.Contains(operatorsByType[fieldType], p.Operator) {
+		return errorf("operator %s is not supported by %s field %s", p.Operator, fieldType, p.LHS.Field)
+	}
+
+	wantType := fieldType
+	if p.Operator == In || p.Operator == NotIn {
+		wantType = IPCIDR
+	}
+	if p.RHS.Type != wantType {
+		return errorf("%s %s expects a %s value, found %s %s",
+			p.LHS.Field, p.Operator, wantType, p.RHS.Type, p.RHS.Literal())
+	}
+	if p.Operator == Regex {
+		if _, err := regexp.Compile(p.RHS.String); err != nil {
+			return errorf("invalid regex %s: %v", p.RHS.Literal(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package atc
+
+import (
+	"testing"
+
+	"github.com/kong/go-database-reconciler/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	valid := []string{
+		`http.path ^= "/api" && http.method == "GET"`,
+		`lower(http.headers.x_version) == "v1" || any(http.queries.debug) == "true"`,
+		`http.path.segments.0 == "api" && http.path.segments.1_2 == "v1/users" && http.path.segments.len > 2`,
+		`net.protocol == "tcp" && net.src.ip in 10.0.0.0/8 && net.dst.ip == 192.168.0.1 && net.dst.port != 22`,
+		`tls.sni =^ ".example.com"`,
+		`http.path ~ r#"^/users/\d+$"#`,
+	}
+	for _, expression := range valid {
+		t.Run(expression, func(t *testing.T) {
+			assert.NoError(t, Validate(expression))
+		})
+	}
+
+	invalid := []struct {
+		expression string
+		want       string
+	}{
+		{`http.pth == "/foo"`, `unknown field "http.pth" (at offset 0)`},
+		{`http.headers. == "a"`, `unknown field "http.headers." (at offset 0)`},
+		{`http.path.segments.a == "a"`, `unknown field "http.path.segments.a" (at offset 0)`},
+		{`net.dst.port ^= "80"`, `operator ^= is not supported by Int field net.dst.port (at offset 0)`},
+		{`http.path > "/foo"`, `operator > is not supported by String field http.path (at offset 0)`},
+		{`net.dst.port == "80"`, `net.dst.port == expects a Int value, found String "80" (at offset 0)`},
+		{`net.src.ip in 10.0.0.1`, `net.src.ip in expects a IpCidr value, found IpAddr 10.0.0.1 (at offset 0)`},
+		{`net.src.ip == 10.0.0.0/8`, `net.src.ip == expects a IpAddr value, found IpCidr 10.0.0.0/8 (at offset 0)`},
+		{`lower(net.dst.port) == 80`, `lower() can only be applied to String fields, net.dst.port is Int (at offset 0)`},
+		{`any(http.path) == "/"`, `any() can only be applied to http.headers.* and http.queries.* fields (at offset 0)`},
+		{`http.path ~ "(foo"`, "invalid regex \"(foo\": error parsing regexp: missing closing ): `(foo` (at offset 0)"},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.expression, func(t *testing.T) {
+			err := Validate(tt.expression)
+			var atcErr *Error
+			require.ErrorAs(t, err, &atcErr)
+			assert.EqualError(t, err, tt.want)
+		})
+	}
+
+	t.Run("reports all invalid predicates", func(t *testing.T) {
+		err := Validate(`http.pth == "/foo" || net.dst.port == "80"`)
+		var errs utils.ErrArray
+		require.ErrorAs(t, err, &errs)
+		require.Len(t, errs.Errors, 2)
+		assert.EqualError(t, errs.Errors[0], `unknown field "http.pth" (at offset 0)`)
+		assert.EqualError(t, errs.Errors[1],
+			`net.dst.port == expects a Int value, found String "80" (at offset 22)`)
+	})
+
+	t.Run("syntax errors", func(t *testing.T) {
+		assert.EqualError(t, Validate(`http.path ==`), `expected a value, found "" (at offset 12)`)
+	})
+}
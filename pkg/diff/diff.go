@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -13,6 +14,7 @@ import (
 	"github.com/kong/go-database-reconciler/pkg/cprint"
 	"github.com/kong/go-database-reconciler/pkg/crud"
 	"github.com/kong/go-database-reconciler/pkg/konnect"
+	"github.com/kong/go-database-reconciler/pkg/policy"
 	"github.com/kong/go-database-reconciler/pkg/schema"
 	"github.com/kong/go-database-reconciler/pkg/state"
 	"github.com/kong/go-database-reconciler/pkg/types"
@@ -59,6 +61,10 @@ type EntityChanges struct {
 	DroppedCreations []EntityState `json:"dropped_creations,omitempty"`
 	DroppedUpdates   []EntityState `json:"dropped_updates,omitempty"`
 	DroppedDeletions []EntityState `json:"dropped_deletions,omitempty"`
+
+	// Warnings reports the entities left untouched because they are not owned
+	// by the Syncer.
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 // ------------------------------------------------------
@@ -148,8 +154,10 @@ type Syncer struct {
 
 	entityDiffers map[types.EntityType]types.Differ
 
-	noMaskValues    bool
-	includeLicenses bool
+	noMaskValues      bool
+	includeLicenses   bool
+	includeWorkspaces bool
+	includeEventHooks bool
 
 	isKonnect bool
 
@@ -166,6 +174,23 @@ type Syncer struct {
 	// schemaRegistry is the central schema manager used for fetching and caching
 	// all entity schemas (plugins, partials, vaults, generic entities).
 	schemaRegistry *schema.Registry
+
+	// policies is evaluated against the target state before solving, if set.
+	policies *policy.Engine
+
+	// ownershipTag is the tag marking the entities managed by the Syncer, if
+	// set. Entities without it are never updated nor deleted.
+	ownershipTag string
+	// adoptedIDs holds the IDs of the entities of the current state adopted
+	// into management, which are updated even without the ownership tag.
+	adoptedIDs map[string]bool
+	// ownershipWarnings holds the updates skipped because the current entity
+	// is not owned by the Syncer, and skippedDeletes counts the deletes
+	// skipped for the same reason by kind, in the order of the kinds. Both are
+	// reset by Solve.
+	ownershipWarnings []string
+	skippedDeletes    map[crud.Kind]int
+	skippedKinds      []crud.Kind
 }
 
 type SyncerOpts struct {
@@ -181,6 +206,15 @@ type SyncerOpts struct {
 	NoMaskValues    bool
 	IncludeLicenses bool
 
+	// IncludeWorkspaces syncs the workspaces of Kong Enterprise. Workspaces
+	// of the current state missing from the target state are deleted, except
+	// for the default workspace.
+	IncludeWorkspaces bool
+
+	// IncludeEventHooks syncs the event hooks of Kong Enterprise. Event hooks
+	// of the current state missing from the target state are deleted.
+	IncludeEventHooks bool
+
 	IsKonnect bool
 
 	CreatePrintln func(a ...any)
@@ -201,6 +235,23 @@ type SyncerOpts struct {
 
 	// SkipSchemaDefaults prevents schema-based default filling for plugins and partials.
 	SkipSchemaDefaults bool
+
+	// Policies are evaluated against the target state before solving. If any
+	// entity violates a policy, Solve returns the violations as errors and
+	// does not apply any change.
+	Policies []policy.Policy
+
+	// OwnershipTag, if set, is added to every entity of the target state, so
+	// that all the entities created by the Syncer carry it. Entities of the
+	// current state without this tag are never updated nor deleted: the
+	// updates and deletions are skipped and reported as warnings.
+	// Entities that cannot be tagged are not subject to ownership.
+	OwnershipTag string
+
+	// AdoptedIDs are the IDs of entities of the current state adopted into
+	// management. They are updated even if they do not carry OwnershipTag
+	// yet, which adds it to them.
+	AdoptedIDs []string
 }
 
 // NewSyncer constructs a Syncer.
@@ -217,19 +268,28 @@ func NewSyncer(opts SyncerOpts) (*Syncer, error) {
 
 		noMaskValues: opts.NoMaskValues,
 
-		createPrintln:   opts.CreatePrintln,
-		updatePrintln:   opts.UpdatePrintln,
-		deletePrintln:   opts.DeletePrintln,
-		includeLicenses: opts.IncludeLicenses,
-		isKonnect:       opts.IsKonnect,
+		createPrintln:     opts.CreatePrintln,
+		updatePrintln:     opts.UpdatePrintln,
+		deletePrintln:     opts.DeletePrintln,
+		includeLicenses:   opts.IncludeLicenses,
+		includeWorkspaces: opts.IncludeWorkspaces,
+		includeEventHooks: opts.IncludeEventHooks,
+		isKonnect:         opts.IsKonnect,
 
 		enableEntityActions: opts.EnableEntityActions,
 		noDeletes:           opts.NoDeletes,
 		skipSchemaDefaults:  opts.SkipSchemaDefaults,
+		ownershipTag:        opts.OwnershipTag,
+		adoptedIDs:          map[string]bool{},
+		skippedDeletes:      map[crud.Kind]int{},
+	}
+	for _, id := range opts.AdoptedIDs {
+		s.adoptedIDs[id] = true
 	}
 
 	if opts.IsKonnect {
 		s.includeLicenses = false
+		s.includeWorkspaces = false
 	}
 
 	if s.createPrintln == nil {
@@ -242,6 +302,20 @@ func NewSyncer(opts SyncerOpts) (*Syncer, error) {
 		s.deletePrintln = cprint.DeletePrintln
 	}
 
+	if len(opts.Policies) > 0 {
+		engine, err := policy.NewEngine(opts.Policies)
+		if err != nil {
+			return nil, fmt.Errorf("compiling policies: %w", err)
+		}
+		s.policies = engine
+	}
+
+	if s.ownershipTag != "" && s.targetState != nil {
+		if err := s.targetState.MergeTags([]string{s.ownershipTag}); err != nil {
+			return nil, fmt.Errorf("adding ownership tag: %w", err)
+		}
+	}
+
 	err := s.init()
 	if err != nil {
 		return nil, err
@@ -289,10 +363,15 @@ func (sc *Syncer) init() error {
 
 		types.Vault,
 		types.License,
+		types.Workspace,
+		types.EventHook,
 
-		types.RBACRole, types.RBACEndpointPermission,
+		types.RBACRole, types.RBACEndpointPermission, types.RBACEntityPermission,
+		types.RBACUser, types.Admin,
 
 		types.ServicePackage, types.ServiceVersion, types.Document,
+		types.APIProduct, types.APIProductVersion, types.APIProductDocument, types.Portal,
+		types.ControlPlane, types.ControlPlaneGroupMembership,
 
 		types.FilterChain,
 
@@ -307,12 +386,13 @@ func (sc *Syncer) init() error {
 		types.ClonedPluginDefinition,
 
 		types.CustomPluginDefinition,
+
+		types.CustomEntity,
 	}
 
 	sc.entityDiffers = map[types.EntityType]types.Differ{}
 	for _, entityType := range entities {
-		// Skip licenses if includeLicenses is disabled.
-		if !sc.includeLicenses && entityType == types.License {
+		if sc.skipEntityType(entityType) {
 			continue
 		}
 		entity, err := types.NewEntity(entityType, opts)
@@ -407,11 +487,25 @@ func (sc *Syncer) processDeleteDuplicates(eventsByLevel [][]crud.Event) error {
 	return nil
 }
 
+// skipEntityType returns true for the opt-in entity types which are not
+// synced: licenses unless includeLicenses is enabled, and workspaces unless
+// includeWorkspaces is enabled.
+func (sc *Syncer) skipEntityType(entityType types.EntityType) bool {
+	switch entityType {
+	case types.License:
+		return !sc.includeLicenses
+	case types.Workspace:
+		return !sc.includeWorkspaces
+	case types.EventHook:
+		return !sc.includeEventHooks
+	}
+	return false
+}
+
 func (sc *Syncer) delete() error {
 	for _, typeSet := range reverseOrder() {
 		for _, entityType := range typeSet {
-			// Skip licenses if includeLicenses is disabled.
-			if !sc.includeLicenses && entityType == types.License {
+			if sc.skipEntityType(entityType) {
 				continue
 			}
 			err := sc.entityDiffers[entityType].Deletes(sc.queueEvent)
@@ -427,8 +521,7 @@ func (sc *Syncer) delete() error {
 func (sc *Syncer) createUpdate() error {
 	for _, typeSet := range order() {
 		for _, entityType := range typeSet {
-			// Skip licenses if includeLicenses is disabled.
-			if !sc.includeLicenses && entityType == types.License {
+			if sc.skipEntityType(entityType) {
 				continue
 			}
 			err := sc.entityDiffers[entityType].CreateAndUpdates(sc.queueEvent)
@@ -442,6 +535,9 @@ func (sc *Syncer) createUpdate() error {
 }
 
 func (sc *Syncer) queueEvent(e crud.Event) error {
+	if !sc.owns(e) {
+		return nil
+	}
 	sc.inFlightOps.Add(1)
 	select {
 	case sc.eventChan <- e:
@@ -453,6 +549,53 @@ func (sc *Syncer) queueEvent(e crud.Event) error {
 	}
 }
 
+// owns reports whether the Syncer may apply e. Only the entities carrying the
+// ownership tag, if set, may be updated or deleted.
+func (sc *Syncer) owns(e crud.Event) bool {
+	if sc.ownershipTag == "" {
+		return true
+	}
+	var current any
+	switch e.Op {
+	case crud.Update:
+		current = e.OldObj
+	case crud.Delete:
+		current = e.Obj
+	default:
+		return true
+	}
+	v := reflect.Indirect(reflect.ValueOf(current))
+	if id := v.FieldByName("ID"); e.Op == crud.Update && id.IsValid() && id.Kind() == reflect.Pointer &&
+		!id.IsNil() && sc.adoptedIDs[id.Elem().String()] {
+		return true
+	}
+	tags := v.FieldByName("Tags")
+	if !tags.IsValid() {
+		return true
+	}
+	for i := range tags.Len() {
+		if tag := reflect.Indirect(tags.Index(i)); tag.IsValid() && tag.String() == sc.ownershipTag {
+			return true
+		}
+	}
+	if e.Op == crud.Delete {
+		// current entities not managed by the Syncer may be numerous: they
+		// are reported by kind.
+		if sc.skippedDeletes[e.Kind] == 0 {
+			sc.skippedKinds = append(sc.skippedKinds, e.Kind)
+		}
+		sc.skippedDeletes[e.Kind]++
+		return false
+	}
+	name := ""
+	if c, ok := current.(state.ConsoleString); ok {
+		name = c.Console()
+	}
+	sc.ownershipWarnings = append(sc.ownershipWarnings, fmt.Sprintf(
+		"%s %s is not tagged with %s and was not updated", e.Kind, name, sc.ownershipTag))
+	return false
+}
+
 func (sc *Syncer) eventCompleted() {
 	sc.inFlightOps.Add(-1)
 }
@@ -719,6 +862,23 @@ func (sc *Syncer) Solve(ctx context.Context, parallelism int, dry bool, isJSONOu
 		Updating: []EntityState{},
 		Deleting: []EntityState{},
 	}
+	sc.ownershipWarnings = nil
+	sc.skippedDeletes = map[crud.Kind]int{}
+	sc.skippedKinds = nil
+
+	if sc.policies != nil {
+		violations, err := sc.policies.Evaluate(sc.targetState)
+		if err != nil {
+			return stats, []error{fmt.Errorf("evaluating policies: %w", err)}, output
+		}
+		if len(violations) > 0 {
+			errs := make([]error, 0, len(violations))
+			for i := range violations {
+				errs = append(errs, &violations[i])
+			}
+			return stats, errs, output
+		}
+	}
 
 	// The length makes it confusing to read, but the code below _isn't being run here_, it's an anon func
 	// arg to Run(), which parallelizes it. However, because it's defined in Solve()'s scope, the output created above
@@ -819,6 +979,12 @@ func (sc *Syncer) Solve(ctx context.Context, parallelism int, dry bool, isJSONOu
 			}
 		}
 
+		// Secrets, such as the tokens of RBAC users, are never displayed.
+		if !sc.noMaskValues {
+			e.Obj = maskSecrets(e.Obj)
+			e.OldObj = maskSecrets(e.OldObj)
+		}
+
 		c := e.Obj.(state.ConsoleString)
 		objDiff := map[string]any{
 			"old": e.OldObj,
@@ -866,6 +1032,7 @@ func (sc *Syncer) Solve(ctx context.Context, parallelism int, dry bool, isJSONOu
 			// prints and JSON blob building will be moved to the deck client.
 			if sc.enableEntityActions {
 				actionResult.Action = UpdateAction
+				actionResult.Diff = diffString
 				if err != nil {
 					actionResult.Error = err
 					select {
@@ -990,5 +1157,17 @@ func (sc *Syncer) Solve(ctx context.Context, parallelism int, dry bool, isJSONOu
 			}
 		}
 	}
+	for _, kind := range sc.skippedKinds {
+		sc.ownershipWarnings = append(sc.ownershipWarnings, fmt.Sprintf(
+			"%d %s entities are not tagged with %s and were not deleted",
+			sc.skippedDeletes[kind], kind, sc.ownershipTag))
+	}
+	for _, warning := range sc.ownershipWarnings {
+		if isJSONOut {
+			output.Warnings = append(output.Warnings, warning)
+		} else if !sc.silenceWarnings {
+			sc.updatePrintln("Warning:", warning)
+		}
+	}
 	return stats, errs, output
 }
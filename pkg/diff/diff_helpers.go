@@ -13,6 +13,7 @@ import (
 	"github.com/hexops/gotextdiff"
 	"github.com/hexops/gotextdiff/myers"
 	"github.com/hexops/gotextdiff/span"
+	"github.com/kong/go-database-reconciler/pkg/crud"
 	"github.com/kong/go-database-reconciler/pkg/state"
 )
 
@@ -193,6 +194,51 @@ func parseDeckEnvVars() []EnvVar {
 
 const maskedValue = "[masked]"
 
+// maskSecrets returns a copy of obj with its secret fields masked, or obj as
+// is if it has none. The tokens of RBAC users, the credentials of admins and
+// the secret and headers of event hooks are masked, since they would otherwise
+// be displayed in clear in diffs.
+func maskSecrets(obj crud.Arg) crud.Arg {
+	mask := func(value *string) *string {
+		if value == nil {
+			return nil
+		}
+		return new(maskedValue)
+	}
+	switch o := obj.(type) {
+	case *state.RBACUser:
+		if o == nil {
+			return obj
+		}
+		masked := &state.RBACUser{RBACUser: *o.DeepCopy(), Roles: o.Roles}
+		masked.UserToken = mask(masked.UserToken)
+		return masked
+	case *state.Admin:
+		if o == nil {
+			return obj
+		}
+		masked := &state.Admin{Admin: *o.DeepCopy(), Roles: o.Roles}
+		masked.Password = mask(masked.Password)
+		masked.Token = mask(masked.Token)
+		return masked
+	case *state.EventHook:
+		if o == nil {
+			return obj
+		}
+		masked := &state.EventHook{EventHook: *o.DeepCopy()}
+		if secret, ok := masked.Config["secret"]; ok && secret != nil {
+			masked.Config["secret"] = maskedValue
+		}
+		if headers, ok := masked.Config["headers"].(map[string]any); ok {
+			for name := range headers {
+				headers[name] = maskedValue
+			}
+		}
+		return masked
+	}
+	return obj
+}
+
 // Compiled patterns for identifying values in diff output.
 var (
 	// jsonKeyPattern detects JSON-formatted output by matching a quoted key
@@ -3,8 +3,13 @@ package diff
 import (
 	"testing"
 
+	"github.com/kong/go-database-reconciler/pkg/crud"
 	"github.com/kong/go-database-reconciler/pkg/konnect"
 	"github.com/kong/go-database-reconciler/pkg/state"
+	"github.com/kong/go-database-reconciler/pkg/utils"
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_PrettyPrintJSONString(t *testing.T) {
@@ -250,3 +255,52 @@ func Test_MaskEnvVarsValues(t *testing.T) {
 		})
 	}
 }
+
+func Test_maskSecrets(t *testing.T) {
+	user := &state.RBACUser{
+		RBACUser: kong.RBACUser{Name: new("ci"), UserToken: new("secret")},
+		Roles:    []string{"deployer"},
+	}
+	masked, ok := maskSecrets(user).(*state.RBACUser)
+	require.True(t, ok)
+	assert.Equal(t, maskedValue, *masked.UserToken)
+	assert.Equal(t, []string{"deployer"}, masked.Roles)
+	// the object sent to Kong is left untouched
+	assert.Equal(t, "secret", *user.UserToken)
+
+	oldUser := &state.RBACUser{RBACUser: kong.RBACUser{Name: new("ci")}}
+	diffString, err := generateDiffString(crud.Event{
+		Obj:    masked,
+		OldObj: maskSecrets(oldUser),
+	}, false, false)
+	require.NoError(t, err)
+	assert.NotContains(t, diffString, "secret")
+	assert.Contains(t, diffString, maskedValue)
+
+	admin := &state.Admin{Admin: kong.Admin{Username: new("alice"), Password: new("secret")}}
+	maskedAdmin, ok := maskSecrets(admin).(*state.Admin)
+	require.True(t, ok)
+	assert.Equal(t, maskedValue, *maskedAdmin.Password)
+	assert.Nil(t, maskedAdmin.Token)
+
+	hook := &state.EventHook{EventHook: utils.EventHook{
+		ID:      new("hook-1"),
+		Handler: new("webhook"),
+		Config: kong.Configuration{
+			"url":     "https://example.com/hook",
+			"secret":  "s3cr3t",
+			"headers": map[string]any{"authorization": "Bearer t0k3n"},
+		},
+	}}
+	maskedHook, ok := maskSecrets(hook).(*state.EventHook)
+	require.True(t, ok)
+	assert.Equal(t, maskedValue, maskedHook.Config["secret"])
+	assert.Equal(t, map[string]any{"authorization": maskedValue}, maskedHook.Config["headers"])
+	assert.Equal(t, "https://example.com/hook", maskedHook.Config["url"])
+	assert.Equal(t, "s3cr3t", hook.Config["secret"])
+	assert.Equal(t, "Bearer t0k3n", hook.Config["headers"].(map[string]any)["authorization"])
+
+	service := &state.Service{Service: kong.Service{Name: new("svc")}}
+	assert.Same(t, service, maskSecrets(service))
+	assert.Nil(t, maskSecrets(nil))
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"testing"
 
+	"github.com/kong/go-database-reconciler/pkg/policy"
+	"github.com/kong/go-database-reconciler/pkg/state"
+	"github.com/kong/go-kong/kong"
 	"github.com/stretchr/testify/require"
 )
 
@@ -14,3 +17,60 @@ func TestSolve_InvalidParallelism_Negative(t *testing.T) {
 	require.Len(t, errs, 1, "Solve should return exactly one error for parallelism=%d", parallelism)
 	require.EqualError(t, errs[0], "parallelism can not be less than 1")
 }
+
+func TestSolve_PolicyViolations(t *testing.T) {
+	current, err := state.NewKongState()
+	require.NoError(t, err)
+	target, err := state.NewKongState()
+	require.NoError(t, err)
+	require.NoError(t, target.Services.Add(state.Service{Service: kong.Service{
+		ID: new("svc-id"), Name: new("svc"), Protocol: new("http"),
+	}}))
+
+	policies := []policy.Policy{{
+		Name: "https-only",
+		Kind: "service",
+		Rule: `self.protocol == "https"`,
+	}}
+	sc, err := NewSyncer(SyncerOpts{CurrentState: current, TargetState: target, Policies: policies})
+	require.NoError(t, err)
+	stats, errs, _ := sc.Solve(context.Background(), 1, true, false)
+	require.Len(t, errs, 1)
+	require.EqualError(t, errs[0], "policy https-only violated by service svc")
+	require.Equal(t, int32(0), stats.CreateOps.Count())
+
+	_, err = NewSyncer(SyncerOpts{Policies: []policy.Policy{{Name: "invalid", Kind: "service", Rule: "self.("}}})
+	require.ErrorContains(t, err, "compiling policies")
+}
+
+func TestSolve_OwnershipWarnings(t *testing.T) {
+	current, err := state.NewKongState()
+	require.NoError(t, err)
+	require.NoError(t, current.Services.Add(state.Service{Service: kong.Service{
+		ID: new("shared-id"), Name: new("shared"), Host: new("other.example.com"),
+	}}))
+	for _, name := range []string{"rogue", "other"} {
+		require.NoError(t, current.Services.Add(state.Service{Service: kong.Service{
+			ID: new(name + "-id"), Name: new(name), Host: new(name + ".example.com"),
+		}}))
+	}
+	target, err := state.NewKongState()
+	require.NoError(t, err)
+	require.NoError(t, target.Services.Add(state.Service{Service: kong.Service{
+		ID: new("shared-id"), Name: new("shared"), Host: new("shared.example.com"),
+	}}))
+
+	sc, err := NewSyncer(SyncerOpts{CurrentState: current, TargetState: target, OwnershipTag: "managed-by:ci"})
+	require.NoError(t, err)
+	expected := []string{
+		"service shared is not tagged with managed-by:ci and was not updated",
+		"2 service entities are not tagged with managed-by:ci and were not deleted",
+	}
+	// warnings left by a previous Solve are not reported again.
+	sc.ownershipWarnings = []string{"stale"}
+	stats, errs, changes := sc.Solve(context.Background(), 1, true, true)
+	require.Empty(t, errs)
+	require.Equal(t, int32(0), stats.UpdateOps.Count())
+	require.Equal(t, int32(0), stats.DeleteOps.Count())
+	require.Equal(t, expected, changes.Warnings)
+}
@@ -26,6 +26,11 @@ L4      +----------> Document   <---------+          +-> Plugins / <---------+
                                                          FilterChains
 														 CustomEntities - DegraphqlRoute
 														               - GraphqlRateLimitingCostDecoration
+
+L5                                RBACEntityPermission (of any entity above)
+                                  CustomEntity (of custom DAOs, referencing any entity above)
+
+RBACUsers and Admins are at L2, after the RBACRoles assigned to them.
 */
 
 // dependencyOrder defines the order in which entities will be synced by decK.
@@ -36,21 +41,31 @@ L4      +----------> Document   <---------+          +-> Plugins / <---------+
 // for delete stage is bottom-up.
 var dependencyOrder = [][]types.EntityType{
 	{
+		// Workspaces come first, since all the other Kong entities are
+		// scoped to one.
+		types.Workspace,
 		types.ServicePackage,
+		types.Portal,
+		types.ControlPlane,
 		types.RBACRole,
 		types.Certificate,
 		types.CACertificate,
 		types.Consumer,
 		types.Vault,
 		types.License,
+		types.EventHook,
 		types.Partial,
 		types.KeySet,
 		types.ClonedPluginDefinition,
 		types.CustomPluginDefinition,
 	},
 	{
+		types.APIProduct,
+		types.ControlPlaneGroupMembership,
 		types.ConsumerGroup,
 		types.RBACEndpointPermission,
+		types.RBACUser,
+		types.Admin,
 		types.SNI,
 		types.Service,
 		types.Upstream,
@@ -62,6 +77,8 @@ var dependencyOrder = [][]types.EntityType{
 	},
 	{
 		types.ServiceVersion,
+		types.APIProductVersion,
+		types.APIProductDocument,
 		types.Route,
 		types.Target,
 		types.ConsumerGroupConsumer,
@@ -74,6 +91,12 @@ var dependencyOrder = [][]types.EntityType{
 		types.DegraphqlRoute,
 		types.GraphqlRateLimitingCostDecoration,
 	},
+	{
+		// Entity permissions and the entities of custom DAOs may refer to
+		// any of the entities above.
+		types.RBACEntityPermission,
+		types.CustomEntity,
+	},
 }
 
 func order() [][]types.EntityType {
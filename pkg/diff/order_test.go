@@ -65,12 +65,16 @@ func TestEventsInOrder(t *testing.T) {
 		e(types.ConsumerGroup),
 		e(types.ServiceVersion),
 		e(types.Plugin),
+		e(types.RBACEntityPermission),
 	}
 
 	order := reverseOrder()
 	result := eventsInOrder(eventsOutOfOrder, order)
 
 	require.Equal(t, [][]crud.Event{
+		{
+			e(types.RBACEntityPermission),
+		},
 		{
 			e(types.Plugin),
 		},
@@ -0,0 +1,211 @@
+package dump
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/kong/go-database-reconciler/pkg/utils"
+)
+
+// Checkpoint records the progress of Stream, so that a failed dump can resume
+// after the last page passed to its PageFunc instead of starting over.
+//
+// The pages passed to the PageFunc are kept in a file next to the checkpoint,
+// with the .pages extension, so that a resumed dump can pass them again,
+// even in another process. The configuration of the proxy is fetched and
+// passed last, as a whole: it is fetched again by a resumed dump.
+type Checkpoint struct {
+	// SelectorTags and SelectorTagExpression select the entities of the dump.
+	// A checkpoint can only be resumed by a dump selecting the same entities.
+	SelectorTags          []string `json:"selector_tags,omitempty"`
+	SelectorTagExpression string   `json:"selector_tag_expression,omitempty"`
+
+	// Completed lists the kinds of entities which were fully streamed.
+	Completed []string `json:"completed,omitempty"`
+
+	// Kind is the kind of entities being streamed, and Offset the offset of
+	// the next page of these entities.
+	Kind   string `json:"kind,omitempty"`
+	Offset string `json:"offset,omitempty"`
+
+	// PagesSize is the size of the file of pages when the checkpoint was
+	// recorded. Pages written after it are discarded on resume, as their
+	// progress was not recorded.
+	PagesSize int64 `json:"pages_size,omitempty"`
+
+	path string
+}
+
+// ReadCheckpoint reads the checkpoint recorded by Stream in path.
+func ReadCheckpoint(path string) (*Checkpoint, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var checkpoint Checkpoint
+	if err := json.Unmarshal(b, &checkpoint); err != nil {
+		return nil, fmt.Errorf("parsing checkpoint %s: %w", path, err)
+	}
+	checkpoint.path = path
+	return &checkpoint, nil
+}
+
+// loadCheckpoint reads the checkpoint in path for a dump with config, or
+// returns a new one if the file does not exist.
+func loadCheckpoint(path string, config Config) (*Checkpoint, error) {
+	var expression string
+	if config.SelectorTagExpression != nil {
+		expression = config.SelectorTagExpression.String()
+	}
+	checkpoint, err := ReadCheckpoint(path)
+	if errors.Is(err, os.ErrNotExist) {
+		// pages left by a dump which failed before recording any progress
+		// are stale.
+		if err := os.Remove(pagesPath(path)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("removing checkpoint pages: %w", err)
+		}
+		return &Checkpoint{
+			SelectorTags:          config.SelectorTags,
+			SelectorTagExpression: expression,
+			path:                  path,
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Equal(checkpoint.SelectorTags, config.SelectorTags) ||
+		checkpoint.SelectorTagExpression != expression {
+		return nil, fmt.Errorf("checkpoint %s was recorded by a dump selecting other entities", path)
+	}
+	err = os.Truncate(pagesPath(path), checkpoint.PagesSize)
+	if errors.Is(err, os.ErrNotExist) && checkpoint.PagesSize == 0 {
+		err = nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading checkpoint pages: %w", err)
+	}
+	return checkpoint, nil
+}
+
+func pagesPath(path string) string {
+	return path + ".pages"
+}
+
+// appendPage keeps page with the checkpoint. Its progress must be recorded
+// next for the page to be kept on resume.
+func (c *Checkpoint) appendPage(page *utils.KongRawState) error {
+	if c == nil {
+		return nil
+	}
+	b, err := json.Marshal(page)
+	if err != nil {
+		return fmt.Errorf("writing checkpoint pages: %w", err)
+	}
+	f, err := os.OpenFile(pagesPath(c.path), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("writing checkpoint pages: %w", err)
+	}
+	n, err := f.Write(append(b, '\n'))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("writing checkpoint pages: %w", err)
+	}
+	c.PagesSize += int64(n)
+	return nil
+}
+
+// replay passes the pages kept with the checkpoint to fn, in order.
+func (c *Checkpoint) replay(fn PageFunc) error {
+	if c == nil || c.PagesSize == 0 {
+		return nil
+	}
+	f, err := os.Open(pagesPath(c.path))
+	if err != nil {
+		return fmt.Errorf("reading checkpoint pages: %w", err)
+	}
+	defer f.Close()
+	decoder := json.NewDecoder(io.LimitReader(f, c.PagesSize))
+	for decoder.More() {
+		var page utils.KongRawState
+		if err := decoder.Decode(&page); err != nil {
+			return fmt.Errorf("reading checkpoint pages: %w", err)
+		}
+		if err := fn(&page); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// done reports whether entities of kind were fully streamed.
+func (c *Checkpoint) done(kind string) bool {
+	return c != nil && slices.Contains(c.Completed, kind)
+}
+
+// offset returns the offset of the next page of entities of kind.
+func (c *Checkpoint) offset(kind string) string {
+	if c == nil || c.Kind != kind {
+		return ""
+	}
+	return c.Offset
+}
+
+// record records that entities of kind were streamed up to offset, or fully
+// streamed if offset is empty.
+func (c *Checkpoint) record(kind, offset string) error {
+	if c == nil {
+		return nil
+	}
+	if offset == "" {
+		c.Completed = append(c.Completed, kind)
+		c.Kind = ""
+	} else {
+		c.Kind = kind
+	}
+	c.Offset = offset
+	return c.save()
+}
+
+// save atomically writes the checkpoint to its file.
+func (c *Checkpoint) save() error {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*")
+	if err != nil {
+		return fmt.Errorf("writing checkpoint: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing checkpoint: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return fmt.Errorf("writing checkpoint: %w", err)
+	}
+	return nil
+}
+
+// remove removes the checkpoint files once the dump is complete.
+func (c *Checkpoint) remove() error {
+	if c == nil {
+		return nil
+	}
+	for _, path := range []string{c.path, pagesPath(c.path)} {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("removing checkpoint: %w", err)
+		}
+	}
+	return nil
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
+	"strings"
 	"sync"
 
 	"github.com/blang/semver/v4"
@@ -22,8 +24,16 @@ const (
 	DefaultLookupTag
 
 	GraphQLRLCostDecorationEntityType = "graphql_ratelimiting_cost_decorations"
+	DegraphqlRoutesEntityType         = "degraphql_routes"
 )
 
+// IsGenericCustomEntityType returns true if the custom entities of type
+// entityType are handled generically from their schema, rather than with code
+// specific to their type.
+func IsGenericCustomEntityType(entityType string) bool {
+	return entityType != DegraphqlRoutesEntityType && entityType != GraphQLRLCostDecorationEntityType
+}
+
 // Config can be used to skip exporting certain entities
 type Config struct {
 	// If true, only RBAC resources are exported.
@@ -43,6 +53,16 @@ type Config struct {
 	// If true, licenses are exported.
 	IncludeLicenses bool
 
+	// If true, the workspaces of Kong Enterprise are exported, all of them
+	// regardless of the workspace of the client. Workspaces are never
+	// exported from Konnect.
+	IncludeWorkspaces bool
+
+	// If true, the event hooks of Kong Enterprise are exported. Event hooks
+	// cannot be tagged, so they are only exported without selector tags, and
+	// never from Konnect.
+	IncludeEventHooks bool
+
 	// CustomEntityTypes lists types of custom entities to list.
 	CustomEntityTypes                  []string
 	SkipCustomEntitiesWithSelectorTags bool
@@ -51,6 +71,15 @@ type Config struct {
 	// tags.
 	SelectorTags []string
 
+	// SelectorTagExpression can be used instead of SelectorTags to export
+	// entities selected by a boolean tag expression, e.g. "team-a OR team-b".
+	// Expressions which Kong can evaluate itself are sent as-is, others are
+	// narrowed down by Kong to their required tags and evaluated client-side.
+	SelectorTagExpression *utils.TagExpression
+
+	// tagFilter is the part of SelectorTagExpression evaluated client-side.
+	tagFilter *utils.TagExpression
+
 	// LookUpSelectorTags* can be used to ensure state lookup for entities using
 	// these tags. This functionality is essential when using a plugin that references
 	// consumers or routes associated with tags different from those in the sync command.
@@ -97,6 +126,25 @@ type Config struct {
 
 	// DiagnosticPolicy controls warning/error severity overrides.
 	DiagnosticPolicy utils.DiagnosticPolicy
+
+	// PageSize is the number of consumers and credentials requested per page
+	// by Stream. Defaults to DefaultPageSize when zero.
+	PageSize int
+
+	// Concurrency limits the number of kinds of entities listed concurrently.
+	// Zero means no limit.
+	Concurrency int
+
+	// PageRetries is the number of times a request listing a page of entities
+	// is retried, with randomized exponential backoff, when it fails with a
+	// network error, a 429 or a 5xx status code. Zero disables retries.
+	PageRetries int
+
+	// CheckpointFile is the file in which Stream and Get record their
+	// progress. When the file exists, they resume from the recorded progress
+	// instead of starting over. The file is removed once the dump completes.
+	// It cannot be set when RBACResourcesOnly is set.
+	CheckpointFile string
 }
 
 func deduplicate(stringSlice []string) []string {
@@ -115,12 +163,36 @@ func deduplicate(stringSlice []string) []string {
 
 func newOpt(tags []string) *kong.ListOpt {
 	opt := new(kong.ListOpt)
-	opt.Size = 1000
+	opt.Size = DefaultPageSize
 	opt.Tags = kong.StringSlice(deduplicate(tags)...)
 	opt.MatchAllTags = true
 	return opt
 }
 
+// selectorTags translates a tag expression into the tags sent to Kong, and the
+// filter, if any, which must still be applied to the entities it returns.
+// Kong matches entities carrying all of the tags in "tags=a,b" and any of
+// the tags in "tags=a/b", but cannot combine both.
+func selectorTags(expression *utils.TagExpression) ([]string, *utils.TagExpression) {
+	if tags, ok := expression.AllOf(); ok {
+		return tags, nil
+	}
+	if tags, ok := expression.AnyOf(); ok {
+		return []string{strings.Join(tags, "/")}, nil
+	}
+	return expression.RequiredTags(), expression
+}
+
+// selectTagged drops the entities not selected by filter, if any.
+func selectTagged[T any](filter *utils.TagExpression, entities []T) []T {
+	if filter == nil {
+		return entities
+	}
+	return slices.DeleteFunc(entities, func(entity T) bool {
+		return !filter.MatchesObject(entity)
+	})
+}
+
 func validateConfig(config Config) error {
 	if config.RBACResourcesOnly {
 		if config.SkipConsumers {
@@ -129,6 +201,18 @@ func validateConfig(config Config) error {
 		if len(config.SelectorTags) != 0 {
 			return fmt.Errorf("dump: config: SelectorTags cannot be set when RBACResourcesOnly is set")
 		}
+		if config.SelectorTagExpression != nil {
+			return fmt.Errorf("dump: config: SelectorTagExpression cannot be set when RBACResourcesOnly is set")
+		}
+		if config.CheckpointFile != "" {
+			return fmt.Errorf("dump: config: CheckpointFile cannot be set when RBACResourcesOnly is set")
+		}
+	}
+	if config.Concurrency < 0 || config.PageRetries < 0 {
+		return fmt.Errorf("dump: config: Concurrency and PageRetries must not be negative")
+	}
+	if len(config.SelectorTags) != 0 && config.SelectorTagExpression != nil {
+		return fmt.Errorf("dump: config: SelectorTags and SelectorTagExpression cannot be set together")
 	}
 	return nil
 }
@@ -171,28 +255,44 @@ func getConsumerGroupsConfiguration(ctx context.Context, group *errgroup.Group,
 		// Define the function to be used based on
 		// whether we wish to see policy overrides or not
 		// GetAllConsumerGroups lists consumers as well as policy-based overrides for consumer-groups
-		getConsumerGroupsFunc := GetAllConsumerGroups
+		getConsumerGroupsFunc := getAllConsumerGroups
 		if !config.IsConsumerGroupPolicyOverrideSet && isKongVersion34Plus {
 			// This won't dump policy-based overrides for consumer-groups
-			getConsumerGroupsFunc = GetAllConsumerGroupsDefault
+			getConsumerGroupsFunc = getAllConsumerGroupsDefault
 
 			if config.SkipConsumersWithConsumerGroups {
-				getConsumerGroupsFunc = GetAllConsumerGroupsWithoutConsumersDefault
+				getConsumerGroupsFunc = getAllConsumerGroupsWithoutConsumersDefault
 			}
 		} else if config.SkipConsumersWithConsumerGroups {
-			getConsumerGroupsFunc = GetAllConsumerGroupsWithoutConsumers
+			getConsumerGroupsFunc = getAllConsumerGroupsWithoutConsumers
 		}
 
 		// Passing config.SelectorTags here fetches only those consumer-groups (and inclusive consumers,
 		// where applicable) which are tagged with the same tag as provided in the config.SelectorTags.
 		// If config.SelectorTags is empty, all consumer-groups are fetched.
-		consumerGroups, err = getConsumerGroupsFunc(ctx, client, config.SelectorTags, SelectTag)
+		// Consumers within the consumer-groups are matched against SelectorTagExpression
+		// client-side, as the tags sent to Kong may not be a plain list of tags.
+		tagType := SelectTag
+		if config.SelectorTagExpression != nil {
+			tagType = DefaultLookupTag
+		}
+		consumerGroups, err = getConsumerGroupsFunc(ctx, client, config.SelectorTags, tagType, config.PageRetries)
 		if err != nil {
 			if kong.IsNotFoundErr(err) || kong.IsForbiddenErr(err) {
 				return nil
 			}
 			return fmt.Errorf("consumer_groups: %w", err)
 		}
+		if config.SelectorTagExpression != nil {
+			consumerGroups = slices.DeleteFunc(consumerGroups, func(cg *kong.ConsumerGroupObject) bool {
+				return !config.SelectorTagExpression.MatchesObject(cg.ConsumerGroup)
+			})
+			for _, cg := range consumerGroups {
+				if cg.Consumers != nil {
+					cg.Consumers = selectTagged(config.SelectorTagExpression, cg.Consumers)
+				}
+			}
+		}
 		if config.LookUpSelectorTagsConsumerGroups != nil {
 			// Passing config.LookUpSelectorTagsConsumerGroups here fetches only those consumer-groups
 			// which are tagged with the same tag as provided in the config.LookUpSelectorTagsConsumerGroups.
@@ -202,7 +302,7 @@ func getConsumerGroupsConfiguration(ctx context.Context, group *errgroup.Group,
 			// under process refers to a consumer-group which exists on the gateway but is not defined in the
 			// same config file, the presence of lookup tags will be able to fetch that consumer-group.
 			globalConsumerGroups, err := getConsumerGroupsFunc(ctx, client, config.LookUpSelectorTagsConsumerGroups,
-				DefaultLookupTag)
+				DefaultLookupTag, config.PageRetries)
 			if err != nil {
 				return fmt.Errorf("error retrieving global consumer groups: %w", err)
 			}
@@ -230,12 +330,13 @@ func getConsumerConfiguration(ctx context.Context, group *errgroup.Group,
 	client *kong.Client, config Config, state *utils.KongRawState,
 ) {
 	group.Go(func() error {
-		consumers, err := GetAllConsumers(ctx, client, config.SelectorTags)
+		consumers, err := getAllConsumers(ctx, client, config.SelectorTags, config.PageRetries)
 		if err != nil {
 			return fmt.Errorf("consumers: %w", err)
 		}
+		consumers = selectTagged(config.tagFilter, consumers)
 		if config.LookUpSelectorTagsConsumers != nil {
-			globalConsumers, err := GetAllConsumers(ctx, client, config.LookUpSelectorTagsConsumers)
+			globalConsumers, err := getAllConsumers(ctx, client, config.LookUpSelectorTagsConsumers, config.PageRetries)
 			if err != nil {
 				return fmt.Errorf("error retrieving global consumers: %w", err)
 			}
@@ -259,40 +360,44 @@ func getConsumerConfiguration(ctx context.Context, group *errgroup.Group,
 	})
 
 	group.Go(func() error {
-		keyAuths, err := GetAllKeyAuths(ctx, client, config.SelectorTags)
+		keyAuths, err := getAllKeyAuths(ctx, client, config.SelectorTags, config.PageRetries)
 		if err != nil {
 			return fmt.Errorf("key-auths: %w", err)
 		}
+		keyAuths = selectTagged(config.tagFilter, keyAuths)
 
 		state.KeyAuths = keyAuths
 		return nil
 	})
 
 	group.Go(func() error {
-		hmacAuths, err := GetAllHMACAuths(ctx, client, config.SelectorTags)
+		hmacAuths, err := getAllHMACAuths(ctx, client, config.SelectorTags, config.PageRetries)
 		if err != nil {
 			return fmt.Errorf("hmac-auths: %w", err)
 		}
+		hmacAuths = selectTagged(config.tagFilter, hmacAuths)
 
 		state.HMACAuths = hmacAuths
 		return nil
 	})
 
 	group.Go(func() error {
-		jwtAuths, err := GetAllJWTAuths(ctx, client, config.SelectorTags)
+		jwtAuths, err := getAllJWTAuths(ctx, client, config.SelectorTags, config.PageRetries)
 		if err != nil {
 			return fmt.Errorf("jwts: %w", err)
 		}
+		jwtAuths = selectTagged(config.tagFilter, jwtAuths)
 
 		state.JWTAuths = jwtAuths
 		return nil
 	})
 
 	group.Go(func() error {
-		basicAuths, err := GetAllBasicAuths(ctx, client, config.SelectorTags)
+		basicAuths, err := getAllBasicAuths(ctx, client, config.SelectorTags, config.PageRetries)
 		if err != nil {
 			return fmt.Errorf("basic-auths: %w", err)
 		}
+		basicAuths = selectTagged(config.tagFilter, basicAuths)
 
 		var options []*kong.BasicAuthOptions
 		for _, basicAuth := range basicAuths {
@@ -308,20 +413,22 @@ func getConsumerConfiguration(ctx context.Context, group *errgroup.Group,
 	// OAuth2 credentials are not supported in Konnect.
 	if config.KonnectControlPlane == "" {
 		group.Go(func() error {
-			oauth2Creds, err := GetAllOauth2Creds(ctx, client, config.SelectorTags)
+			oauth2Creds, err := getAllOauth2Creds(ctx, client, config.SelectorTags, config.PageRetries)
 			if err != nil {
 				return fmt.Errorf("oauth2: %w", err)
 			}
+			oauth2Creds = selectTagged(config.tagFilter, oauth2Creds)
 			state.Oauth2Creds = oauth2Creds
 			return nil
 		})
 	}
 
 	group.Go(func() error {
-		aclGroups, err := GetAllACLGroups(ctx, client, config.SelectorTags)
+		aclGroups, err := getAllACLGroups(ctx, client, config.SelectorTags, config.PageRetries)
 		if err != nil {
 			return fmt.Errorf("acls: %w", err)
 		}
+		aclGroups = selectTagged(config.tagFilter, aclGroups)
 		state.ACLGroups = aclGroups
 		return nil
 	})
@@ -336,7 +443,7 @@ func getConsumerConfiguration(ctx context.Context, group *errgroup.Group,
 		// This feature would only benefit a user who uses tagged consumers but
 		// then managed mtls-auth credentials out-of-band. We expect such users
 		// to be rare or non-existent.
-		mtlsAuths, err := GetAllMTLSAuths(ctx, client, nil)
+		mtlsAuths, err := getAllMTLSAuths(ctx, client, nil, config.PageRetries)
 		if err != nil {
 			return fmt.Errorf("mtls-auths: %w", err)
 		}
@@ -349,10 +456,11 @@ func getProxyConfiguration(ctx context.Context, group *errgroup.Group,
 	client *kong.Client, config Config, state *utils.KongRawState,
 ) {
 	group.Go(func() error {
-		services, err := GetAllServices(ctx, client, config.SelectorTags)
+		services, err := getAllServices(ctx, client, config.SelectorTags, config.PageRetries)
 		if err != nil {
 			return fmt.Errorf("services: %w", err)
 		}
+		services = selectTagged(config.tagFilter, services)
 
 		services, err = excludeKonnectManagedEntities(services)
 		if err != nil {
@@ -360,7 +468,7 @@ func getProxyConfiguration(ctx context.Context, group *errgroup.Group,
 		}
 
 		if config.LookUpSelectorTagsServices != nil {
-			globalServices, err := GetAllServices(ctx, client, config.LookUpSelectorTagsServices)
+			globalServices, err := getAllServices(ctx, client, config.LookUpSelectorTagsServices, config.PageRetries)
 			if err != nil {
 				return fmt.Errorf("error retrieving global services: %w", err)
 			}
@@ -384,10 +492,11 @@ func getProxyConfiguration(ctx context.Context, group *errgroup.Group,
 	})
 
 	group.Go(func() error {
-		routes, err := GetAllRoutes(ctx, client, config.SelectorTags)
+		routes, err := getAllRoutes(ctx, client, config.SelectorTags, config.PageRetries)
 		if err != nil {
 			return fmt.Errorf("routes: %w", err)
 		}
+		routes = selectTagged(config.tagFilter, routes)
 
 		routes, err = excludeKonnectManagedEntities(routes)
 		if err != nil {
@@ -395,7 +504,7 @@ func getProxyConfiguration(ctx context.Context, group *errgroup.Group,
 		}
 
 		if config.LookUpSelectorTagsRoutes != nil {
-			globalRoutes, err := GetAllRoutes(ctx, client, config.LookUpSelectorTagsRoutes)
+			globalRoutes, err := getAllRoutes(ctx, client, config.LookUpSelectorTagsRoutes, config.PageRetries)
 			if err != nil {
 				return fmt.Errorf("error retrieving global routes: %w", err)
 			}
@@ -419,10 +528,11 @@ func getProxyConfiguration(ctx context.Context, group *errgroup.Group,
 	})
 
 	group.Go(func() error {
-		plugins, err := GetAllPlugins(ctx, client, config.SelectorTags)
+		plugins, err := getAllPlugins(ctx, client, config.SelectorTags, config.PageRetries)
 		if err != nil {
 			return fmt.Errorf("plugins: %w", err)
 		}
+		plugins = selectTagged(config.tagFilter, plugins)
 
 		plugins = excludeKonnectManagedPlugins(plugins)
 
@@ -437,7 +547,7 @@ func getProxyConfiguration(ctx context.Context, group *errgroup.Group,
 
 	group.Go(func() error {
 		state.FilterChains = make([]*kong.FilterChain, 0)
-		filterChains, err := GetAllFilterChains(ctx, client, config.SelectorTags)
+		filterChains, err := getAllFilterChains(ctx, client, config.SelectorTags, config.PageRetries)
 		if err != nil {
 			if kongErr, ok := errors.AsType[*kong.APIError](err); ok {
 				// GET /filter-chains returns:
@@ -450,6 +560,7 @@ func getProxyConfiguration(ctx context.Context, group *errgroup.Group,
 			}
 			return fmt.Errorf("filter chains: %w", err)
 		}
+		filterChains = selectTagged(config.tagFilter, filterChains)
 
 		filterChains, err = excludeKonnectManagedEntities(filterChains)
 		if err != nil {
@@ -461,10 +572,11 @@ func getProxyConfiguration(ctx context.Context, group *errgroup.Group,
 	})
 
 	group.Go(func() error {
-		certificates, err := GetAllCertificates(ctx, client, config.SelectorTags)
+		certificates, err := getAllCertificates(ctx, client, config.SelectorTags, config.PageRetries)
 		if err != nil {
 			return fmt.Errorf("certificates: %w", err)
 		}
+		certificates = selectTagged(config.tagFilter, certificates)
 
 		certificates, err = excludeKonnectManagedEntities(certificates)
 		if err != nil {
@@ -477,10 +589,11 @@ func getProxyConfiguration(ctx context.Context, group *errgroup.Group,
 
 	if !config.SkipCACerts {
 		group.Go(func() error {
-			caCerts, err := GetAllCACertificates(ctx, client, config.SelectorTags)
+			caCerts, err := getAllCACertificates(ctx, client, config.SelectorTags, config.PageRetries)
 			if err != nil {
 				return fmt.Errorf("ca-certificates: %w", err)
 			}
+			caCerts = selectTagged(config.tagFilter, caCerts)
 
 			caCerts, err = excludeKonnectManagedEntities(caCerts)
 			if err != nil {
@@ -493,10 +606,11 @@ func getProxyConfiguration(ctx context.Context, group *errgroup.Group,
 	}
 
 	group.Go(func() error {
-		snis, err := GetAllSNIs(ctx, client, config.SelectorTags)
+		snis, err := getAllSNIs(ctx, client, config.SelectorTags, config.PageRetries)
 		if err != nil {
 			return fmt.Errorf("snis: %w", err)
 		}
+		snis = selectTagged(config.tagFilter, snis)
 
 		snis, err = excludeKonnectManagedEntities(snis)
 		if err != nil {
@@ -508,10 +622,11 @@ func getProxyConfiguration(ctx context.Context, group *errgroup.Group,
 	})
 
 	group.Go(func() error {
-		upstreams, err := GetAllUpstreams(ctx, client, config.SelectorTags)
+		upstreams, err := getAllUpstreams(ctx, client, config.SelectorTags, config.PageRetries)
 		if err != nil {
 			return fmt.Errorf("upstreams: %w", err)
 		}
+		upstreams = selectTagged(config.tagFilter, upstreams)
 
 		upstreams, err = excludeKonnectManagedEntities(upstreams)
 		if err != nil {
@@ -520,10 +635,11 @@ func getProxyConfiguration(ctx context.Context, group *errgroup.Group,
 
 		state.Upstreams = upstreams
 		if config.KonnectControlPlane == "" {
-			targets, err := GetAllTargets(ctx, client, upstreams, config.SelectorTags)
+			targets, err := getAllTargets(ctx, client, upstreams, config.SelectorTags, config.PageRetries)
 			if err != nil {
 				return fmt.Errorf("targets: %w", err)
 			}
+			targets = selectTagged(config.tagFilter, targets)
 			state.Targets = targets
 		}
 		return nil
@@ -531,10 +647,11 @@ func getProxyConfiguration(ctx context.Context, group *errgroup.Group,
 
 	if config.KonnectControlPlane != "" {
 		group.Go(func() error {
-			targets, err := GetAllTargetsFromKonnect(ctx, client, config.SelectorTags)
+			targets, err := getAllTargetsFromKonnect(ctx, client, config.SelectorTags, config.PageRetries)
 			if err != nil {
 				return fmt.Errorf("targets: %w", err)
 			}
+			targets = selectTagged(config.tagFilter, targets)
 
 			targets, err = excludeKonnectManagedEntities(targets)
 			if err != nil {
@@ -546,10 +663,11 @@ func getProxyConfiguration(ctx context.Context, group *errgroup.Group,
 	}
 
 	group.Go(func() error {
-		vaults, err := GetAllVaults(ctx, client, config.SelectorTags)
+		vaults, err := getAllVaults(ctx, client, config.SelectorTags, config.PageRetries)
 		if err != nil {
 			return fmt.Errorf("vaults: %w", err)
 		}
+		vaults = selectTagged(config.tagFilter, vaults)
 
 		vaults, err = excludeKonnectManagedEntities(vaults)
 		if err != nil {
@@ -561,10 +679,11 @@ func getProxyConfiguration(ctx context.Context, group *errgroup.Group,
 	})
 
 	group.Go(func() error {
-		partials, err := GetAllPartials(ctx, client, config.SelectorTags)
+		partials, err := getAllPartials(ctx, client, config.SelectorTags, config.PageRetries)
 		if err != nil {
 			return fmt.Errorf("partials: %w", err)
 		}
+		partials = selectTagged(config.tagFilter, partials)
 
 		partials, err = excludeKonnectManagedEntities(partials)
 		if err != nil {
@@ -572,7 +691,7 @@ func getProxyConfiguration(ctx context.Context, group *errgroup.Group,
 		}
 
 		if config.LookUpSelectorTagsPartials != nil {
-			globalPartials, err := GetAllPartials(ctx, client, config.LookUpSelectorTagsPartials)
+			globalPartials, err := getAllPartials(ctx, client, config.LookUpSelectorTagsPartials, config.PageRetries)
 			if err != nil {
 				return fmt.Errorf("error retrieving global partials: %w", err)
 			}
@@ -596,10 +715,11 @@ func getProxyConfiguration(ctx context.Context, group *errgroup.Group,
 	})
 
 	group.Go(func() error {
-		keys, err := GetAllKeys(ctx, client, config.SelectorTags)
+		keys, err := getAllKeys(ctx, client, config.SelectorTags, config.PageRetries)
 		if err != nil {
 			return fmt.Errorf("keys: %w", err)
 		}
+		keys = selectTagged(config.tagFilter, keys)
 
 		keys, err = excludeKonnectManagedEntities(keys)
 		if err != nil {
@@ -611,10 +731,11 @@ func getProxyConfiguration(ctx context.Context, group *errgroup.Group,
 	})
 
 	group.Go(func() error {
-		keySets, err := GetAllKeySets(ctx, client, config.SelectorTags)
+		keySets, err := getAllKeySets(ctx, client, config.SelectorTags, config.PageRetries)
 		if err != nil {
 			return fmt.Errorf("key-sets: %w", err)
 		}
+		keySets = selectTagged(config.tagFilter, keySets)
 
 		keySets, err = excludeKonnectManagedEntities(keySets)
 		if err != nil {
@@ -627,10 +748,11 @@ func getProxyConfiguration(ctx context.Context, group *errgroup.Group,
 
 	if config.IncludePluginDefinitions {
 		group.Go(func() error {
-			clonedPluginDefinitions, err := GetAllClonedPluginDefinitions(ctx, client, config.SelectorTags)
+			clonedPluginDefinitions, err := getAllClonedPluginDefinitions(ctx, client, config.SelectorTags, config.PageRetries)
 			if err != nil {
 				return fmt.Errorf("cloned_plugins: %w", err)
 			}
+			clonedPluginDefinitions = selectTagged(config.tagFilter, clonedPluginDefinitions)
 
 			clonedPluginDefinitions, err = excludeKonnectManagedEntities(clonedPluginDefinitions)
 			if err != nil {
@@ -642,10 +764,11 @@ func getProxyConfiguration(ctx context.Context, group *errgroup.Group,
 		})
 
 		group.Go(func() error {
-			customPluginDefinitions, err := GetAllCustomPluginDefinitions(ctx, client, config.SelectorTags)
+			customPluginDefinitions, err := getAllCustomPluginDefinitions(ctx, client, config.SelectorTags, config.PageRetries)
 			if err != nil {
 				return fmt.Errorf("custom_plugins: %w", err)
 			}
+			customPluginDefinitions = selectTagged(config.tagFilter, customPluginDefinitions)
 
 			customPluginDefinitions, err = excludeKonnectManagedEntities(customPluginDefinitions)
 			if err != nil {
@@ -659,10 +782,11 @@ func getProxyConfiguration(ctx context.Context, group *errgroup.Group,
 
 	if config.IncludeLicenses {
 		group.Go(func() error {
-			licenses, err := GetAllLicenses(ctx, client, config.SelectorTags)
+			licenses, err := getAllLicenses(ctx, client, config.SelectorTags, config.PageRetries)
 			if err != nil {
 				return fmt.Errorf("licenses: %w", err)
 			}
+			licenses = selectTagged(config.tagFilter, licenses)
 
 			licenses, err = excludeKonnectManagedEntities(licenses)
 			if err != nil {
@@ -674,12 +798,38 @@ func getProxyConfiguration(ctx context.Context, group *errgroup.Group,
 		})
 	}
 
+	if config.IncludeWorkspaces && config.KonnectControlPlane == "" {
+		group.Go(func() error {
+			workspaces, err := GetAllWorkspaces(ctx, client)
+			if err != nil {
+				return fmt.Errorf("workspaces: %w", err)
+			}
+			state.Workspaces = workspaces
+			return nil
+		})
+	}
+
+	// Event hooks cannot be tagged, so they are only exported along with the
+	// whole workspace. Konnect has no event hooks.
+	if config.IncludeEventHooks && len(config.SelectorTags) == 0 && config.SelectorTagExpression == nil &&
+		config.KonnectControlPlane == "" {
+		group.Go(func() error {
+			eventHooks, err := GetAllEventHooks(ctx, client)
+			if err != nil {
+				return fmt.Errorf("event hooks: %w", err)
+			}
+			state.EventHooks = eventHooks
+			return nil
+		})
+	}
+
 	// If SkipCustomEntitiesWithSelectorTags is true and SelectorTags is not empty,
 	// we want to skip custom entities. This is because custom entities don't support
 	// tagging and including them in the state results in errors while attempting a
 	// deck sync or apply.
 	var skipCustomEntities bool
-	if config.SkipCustomEntitiesWithSelectorTags && len(config.SelectorTags) > 0 {
+	if config.SkipCustomEntitiesWithSelectorTags &&
+		(len(config.SelectorTags) > 0 || config.SelectorTagExpression != nil) {
 		skipCustomEntities = true
 	}
 
@@ -696,6 +846,10 @@ func getProxyConfiguration(ctx context.Context, group *errgroup.Group,
 			}
 		}
 
+		registry := config.SchemaRegistry
+		if registry == nil {
+			registry = schema.NewRegistry(client, config.KonnectControlPlane != "")
+		}
 		customEntityLock := sync.Mutex{}
 		for _, entityType := range config.CustomEntityTypes {
 			t := entityType
@@ -721,10 +875,24 @@ func getProxyConfiguration(ctx context.Context, group *errgroup.Group,
 
 			group.Go(func() error {
 				// Fetch all entities with the given type.
-				entities, err := GetAllCustomEntitiesWithType(ctx, client, t)
+				entities, err := getAllCustomEntitiesWithType(ctx, client, t, config.PageRetries)
 				if err != nil {
 					return fmt.Errorf("custom entity %s: %w", t, err)
 				}
+				// The entities of custom DAOs are handled from the definition
+				// derived from their schema.
+				if len(entities) > 0 && IsGenericCustomEntityType(t) {
+					definition, err := registry.GetEntityDefinition(ctx, t)
+					if err != nil {
+						return fmt.Errorf("custom entity %s: %w", t, err)
+					}
+					customEntityLock.Lock()
+					if state.CustomEntityDefinitions == nil {
+						state.CustomEntityDefinitions = map[string]*schema.EntityDefinition{}
+					}
+					state.CustomEntityDefinitions[t] = definition
+					customEntityLock.Unlock()
+				}
 				// Add custom entities to rawstate.
 				customEntityLock.Lock()
 				state.CustomEntities = append(state.CustomEntities, entities...)
@@ -757,7 +925,7 @@ func tryRegisterEntityType(client *kong.Client, typ custom.Type) error {
 }
 
 func getEnterpriseRBACConfiguration(ctx context.Context, group *errgroup.Group,
-	client *kong.Client, state *utils.KongRawState,
+	client *kong.Client, retries int, state *utils.KongRawState,
 ) {
 	group.Go(func() error {
 		roles, err := GetAllRBACRoles(ctx, client)
@@ -776,6 +944,35 @@ func getEnterpriseRBACConfiguration(ctx context.Context, group *errgroup.Group,
 		state.RBACEndpointPermissions = eps
 		return nil
 	})
+
+	group.Go(func() error {
+		eps, err := GetAllRBACEntityPermissions(ctx, client)
+		if err != nil {
+			return fmt.Errorf("entity permissions: %w", err)
+		}
+		state.RBACEntityPermissions = eps
+		return nil
+	})
+
+	group.Go(func() error {
+		users, roles, err := GetAllRBACUsers(ctx, client)
+		if err != nil {
+			return fmt.Errorf("rbac users: %w", err)
+		}
+		state.RBACUsers = users
+		state.RBACUserRoles = roles
+		return nil
+	})
+
+	group.Go(func() error {
+		admins, roles, err := getAllAdmins(ctx, client, retries)
+		if err != nil {
+			return fmt.Errorf("admins: %w", err)
+		}
+		state.Admins = admins
+		state.AdminRoles = roles
+		return nil
+	})
 }
 
 // Get queries all the entities using client and returns
@@ -786,12 +983,29 @@ func Get(ctx context.Context, client *kong.Client, config Config) (*utils.KongRa
 	if err := validateConfig(config); err != nil {
 		return nil, err
 	}
+	// the progress of a dump with a checkpoint is recorded while streaming it.
+	if config.CheckpointFile != "" {
+		err := Stream(ctx, client, config, func(page *utils.KongRawState) error {
+			appendRawState(&state, page)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &state, nil
+	}
+	if config.SelectorTagExpression != nil {
+		config.SelectorTags, config.tagFilter = selectorTags(config.SelectorTagExpression)
+	}
 
 	group, newCtx := errgroup.WithContext(ctx)
+	if config.Concurrency > 0 {
+		group.SetLimit(config.Concurrency)
+	}
 
 	// dump only rbac resources
 	if config.RBACResourcesOnly {
-		getEnterpriseRBACConfiguration(newCtx, group, client, &state)
+		getEnterpriseRBACConfiguration(newCtx, group, client, config.PageRetries, &state)
 	} else {
 		// regular case
 		getProxyConfiguration(newCtx, group, client, config, &state)
@@ -828,11 +1042,17 @@ func Get(ctx context.Context, client *kong.Client, config Config) (*utils.KongRa
 // GetAllKeys queries Kong for all the Keys using client.
 func GetAllKeys(
 	ctx context.Context, client *kong.Client, tags []string,
+) ([]*kong.Key, error) {
+	return getAllKeys(ctx, client, tags, 0)
+}
+
+func getAllKeys(
+	ctx context.Context, client *kong.Client, tags []string, retries int,
 ) ([]*kong.Key, error) {
 	var keys []*kong.Key
 	opt := newOpt(tags)
 	for {
-		s, nextopt, err := client.Keys.List(ctx, opt)
+		s, nextopt, err := listPage(ctx, retries, opt, client.Keys.List)
 		if kong.IsNotFoundErr(err) || kong.IsForbiddenErr(err) {
 			return keys, nil
 		}
@@ -854,11 +1074,17 @@ func GetAllKeys(
 // GetAllKeySets queries Kong for all the KeySets using client.
 func GetAllKeySets(
 	ctx context.Context, client *kong.Client, tags []string,
+) ([]*kong.KeySet, error) {
+	return getAllKeySets(ctx, client, tags, 0)
+}
+
+func getAllKeySets(
+	ctx context.Context, client *kong.Client, tags []string, retries int,
 ) ([]*kong.KeySet, error) {
 	var sets []*kong.KeySet
 	opt := newOpt(tags)
 	for {
-		s, nextopt, err := client.KeySets.List(ctx, opt)
+		s, nextopt, err := listPage(ctx, retries, opt, client.KeySets.List)
 		if kong.IsNotFoundErr(err) || kong.IsForbiddenErr(err) {
 			return sets, nil
 		}
@@ -880,11 +1106,17 @@ func GetAllKeySets(
 // GetAllClonedPluginDefinitions queries Kong for all the ClonedPluginDefinitions using client.
 func GetAllClonedPluginDefinitions(
 	ctx context.Context, client *kong.Client, tags []string,
+) ([]*kong.ClonedPluginDefinition, error) {
+	return getAllClonedPluginDefinitions(ctx, client, tags, 0)
+}
+
+func getAllClonedPluginDefinitions(
+	ctx context.Context, client *kong.Client, tags []string, retries int,
 ) ([]*kong.ClonedPluginDefinition, error) {
 	var cpds []*kong.ClonedPluginDefinition
 	opt := newOpt(tags)
 	for {
-		s, nextopt, err := client.ClonedPlugins.List(ctx, opt)
+		s, nextopt, err := listPage(ctx, retries, opt, client.ClonedPlugins.List)
 		if kong.IsNotFoundErr(err) || kong.IsForbiddenErr(err) {
 			return cpds, nil
 		}
@@ -906,11 +1138,17 @@ func GetAllClonedPluginDefinitions(
 // GetAllCustomPluginDefinitions queries Kong for all the CustomPluginDefinitions using client.
 func GetAllCustomPluginDefinitions(
 	ctx context.Context, client *kong.Client, tags []string,
+) ([]*kong.CustomPluginDefinition, error) {
+	return getAllCustomPluginDefinitions(ctx, client, tags, 0)
+}
+
+func getAllCustomPluginDefinitions(
+	ctx context.Context, client *kong.Client, tags []string, retries int,
 ) ([]*kong.CustomPluginDefinition, error) {
 	var cpds []*kong.CustomPluginDefinition
 	opt := newOpt(tags)
 	for {
-		s, nextopt, err := client.CustomPlugins.List(ctx, opt)
+		s, nextopt, err := listPage(ctx, retries, opt, client.CustomPlugins.List)
 		if kong.IsNotFoundErr(err) || kong.IsForbiddenErr(err) {
 			return cpds, nil
 		}
@@ -932,12 +1170,18 @@ func GetAllCustomPluginDefinitions(
 // GetAllPartials queries Kong for all the partials using client.
 func GetAllPartials(ctx context.Context, client *kong.Client,
 	tags []string,
+) ([]*kong.Partial, error) {
+	return getAllPartials(ctx, client, tags, 0)
+}
+
+func getAllPartials(ctx context.Context, client *kong.Client,
+	tags []string, retries int,
 ) ([]*kong.Partial, error) {
 	var partials []*kong.Partial
 	opt := newOpt(tags)
 
 	for {
-		s, nextopt, err := client.Partials.List(ctx, opt)
+		s, nextopt, err := listPage(ctx, retries, opt, client.Partials.List)
 		if kong.IsNotFoundErr(err) || kong.IsForbiddenErr(err) {
 			return partials, nil
 		}
@@ -959,12 +1203,18 @@ func GetAllPartials(ctx context.Context, client *kong.Client,
 // GetAllServices queries Kong for all the services using client.
 func GetAllServices(ctx context.Context, client *kong.Client,
 	tags []string,
+) ([]*kong.Service, error) {
+	return getAllServices(ctx, client, tags, 0)
+}
+
+func getAllServices(ctx context.Context, client *kong.Client,
+	tags []string, retries int,
 ) ([]*kong.Service, error) {
 	var services []*kong.Service
 	opt := newOpt(tags)
 
 	for {
-		s, nextopt, err := client.Services.List(ctx, opt)
+		s, nextopt, err := listPage(ctx, retries, opt, client.Services.List)
 		if err != nil {
 			return nil, err
 		}
@@ -983,12 +1233,18 @@ func GetAllServices(ctx context.Context, client *kong.Client,
 // GetAllRoutes queries Kong for all the routes using client.
 func GetAllRoutes(ctx context.Context, client *kong.Client,
 	tags []string,
+) ([]*kong.Route, error) {
+	return getAllRoutes(ctx, client, tags, 0)
+}
+
+func getAllRoutes(ctx context.Context, client *kong.Client,
+	tags []string, retries int,
 ) ([]*kong.Route, error) {
 	var routes []*kong.Route
 	opt := newOpt(tags)
 
 	for {
-		s, nextopt, err := client.Routes.List(ctx, opt)
+		s, nextopt, err := listPage(ctx, retries, opt, client.Routes.List)
 		if err != nil {
 			return nil, err
 		}
@@ -1007,12 +1263,18 @@ func GetAllRoutes(ctx context.Context, client *kong.Client,
 // GetAllPlugins queries Kong for all the plugins using client.
 func GetAllPlugins(ctx context.Context,
 	client *kong.Client, tags []string,
+) ([]*kong.Plugin, error) {
+	return getAllPlugins(ctx, client, tags, 0)
+}
+
+func getAllPlugins(ctx context.Context,
+	client *kong.Client, tags []string, retries int,
 ) ([]*kong.Plugin, error) {
 	var plugins []*kong.Plugin
 	opt := newOpt(tags)
 
 	for {
-		s, nextopt, err := client.Plugins.List(ctx, opt)
+		s, nextopt, err := listPage(ctx, retries, opt, client.Plugins.List)
 		if err != nil {
 			return nil, err
 		}
@@ -1031,12 +1293,18 @@ func GetAllPlugins(ctx context.Context,
 // GetAllFilterChains queries Kong for all the filter chains using client.
 func GetAllFilterChains(ctx context.Context,
 	client *kong.Client, tags []string,
+) ([]*kong.FilterChain, error) {
+	return getAllFilterChains(ctx, client, tags, 0)
+}
+
+func getAllFilterChains(ctx context.Context,
+	client *kong.Client, tags []string, retries int,
 ) ([]*kong.FilterChain, error) {
 	var filterChains []*kong.FilterChain
 	opt := newOpt(tags)
 
 	for {
-		s, nextopt, err := client.FilterChains.List(ctx, opt)
+		s, nextopt, err := listPage(ctx, retries, opt, client.FilterChains.List)
 		if err != nil {
 			return nil, err
 		}
@@ -1055,12 +1323,18 @@ func GetAllFilterChains(ctx context.Context,
 // GetAllCertificates queries Kong for all the certificates using client.
 func GetAllCertificates(ctx context.Context, client *kong.Client,
 	tags []string,
+) ([]*kong.Certificate, error) {
+	return getAllCertificates(ctx, client, tags, 0)
+}
+
+func getAllCertificates(ctx context.Context, client *kong.Client,
+	tags []string, retries int,
 ) ([]*kong.Certificate, error) {
 	var certificates []*kong.Certificate
 	opt := newOpt(tags)
 
 	for {
-		s, nextopt, err := client.Certificates.List(ctx, opt)
+		s, nextopt, err := listPage(ctx, retries, opt, client.Certificates.List)
 		if err != nil {
 			return nil, err
 		}
@@ -1084,12 +1358,19 @@ func GetAllCertificates(ctx context.Context, client *kong.Client,
 func GetAllCACertificates(ctx context.Context,
 	client *kong.Client,
 	tags []string,
+) ([]*kong.CACertificate, error) {
+	return getAllCACertificates(ctx, client, tags, 0)
+}
+
+func getAllCACertificates(ctx context.Context,
+	client *kong.Client,
+	tags []string, retries int,
 ) ([]*kong.CACertificate, error) {
 	var caCertificates []*kong.CACertificate
 	opt := newOpt(tags)
 
 	for {
-		s, nextopt, err := client.CACertificates.List(ctx, opt)
+		s, nextopt, err := listPage(ctx, retries, opt, client.CACertificates.List)
 		// Compatibility for Kong < 1.3
 		// This core entitiy was not present in the past
 		// and the Admin API request will error with 404 Not Found
@@ -1119,12 +1400,18 @@ func GetAllCACertificates(ctx context.Context,
 // GetAllSNIs queries Kong for all the SNIs using client.
 func GetAllSNIs(ctx context.Context,
 	client *kong.Client, tags []string,
+) ([]*kong.SNI, error) {
+	return getAllSNIs(ctx, client, tags, 0)
+}
+
+func getAllSNIs(ctx context.Context,
+	client *kong.Client, tags []string, retries int,
 ) ([]*kong.SNI, error) {
 	var snis []*kong.SNI
 	opt := newOpt(tags)
 
 	for {
-		s, nextopt, err := client.SNIs.List(ctx, opt)
+		s, nextopt, err := listPage(ctx, retries, opt, client.SNIs.List)
 		if err != nil {
 			return nil, err
 		}
@@ -1144,12 +1431,18 @@ func GetAllSNIs(ctx context.Context,
 // Please use this method with caution if you have a lot of consumers.
 func GetAllConsumers(ctx context.Context,
 	client *kong.Client, tags []string,
+) ([]*kong.Consumer, error) {
+	return getAllConsumers(ctx, client, tags, 0)
+}
+
+func getAllConsumers(ctx context.Context,
+	client *kong.Client, tags []string, retries int,
 ) ([]*kong.Consumer, error) {
 	var consumers []*kong.Consumer
 	opt := newOpt(tags)
 
 	for {
-		s, nextopt, err := client.Consumers.List(ctx, opt)
+		s, nextopt, err := listPage(ctx, retries, opt, client.Consumers.List)
 		if err != nil {
 			return nil, err
 		}
@@ -1168,12 +1461,18 @@ func GetAllConsumers(ctx context.Context,
 // GetAllUpstreams queries Kong for all the Upstreams using client.
 func GetAllUpstreams(ctx context.Context,
 	client *kong.Client, tags []string,
+) ([]*kong.Upstream, error) {
+	return getAllUpstreams(ctx, client, tags, 0)
+}
+
+func getAllUpstreams(ctx context.Context,
+	client *kong.Client, tags []string, retries int,
 ) ([]*kong.Upstream, error) {
 	var upstreams []*kong.Upstream
 	opt := newOpt(tags)
 
 	for {
-		s, nextopt, err := client.Upstreams.List(ctx, opt)
+		s, nextopt, err := listPage(ctx, retries, opt, client.Upstreams.List)
 		if err != nil {
 			return nil, err
 		}
@@ -1192,12 +1491,18 @@ func GetAllUpstreams(ctx context.Context,
 // GetAllConsumerGroups queries Kong for all the ConsumerGroups using client.
 func GetAllConsumerGroups(ctx context.Context,
 	client *kong.Client, tags []string, tagType int,
+) ([]*kong.ConsumerGroupObject, error) {
+	return getAllConsumerGroups(ctx, client, tags, tagType, 0)
+}
+
+func getAllConsumerGroups(ctx context.Context,
+	client *kong.Client, tags []string, tagType int, retries int,
 ) ([]*kong.ConsumerGroupObject, error) {
 	var consumerGroupObjects []*kong.ConsumerGroupObject
 	opt := newOpt(tags)
 
 	for {
-		cgs, nextopt, err := client.ConsumerGroups.List(ctx, opt)
+		cgs, nextopt, err := listPage(ctx, retries, opt, client.ConsumerGroups.List)
 		if err != nil {
 			return nil, err
 		}
@@ -1241,12 +1546,18 @@ func GetAllConsumerGroups(ctx context.Context,
 // with a consumer-group
 func GetAllConsumerGroupsDefault(ctx context.Context,
 	client *kong.Client, tags []string, tagType int,
+) ([]*kong.ConsumerGroupObject, error) {
+	return getAllConsumerGroupsDefault(ctx, client, tags, tagType, 0)
+}
+
+func getAllConsumerGroupsDefault(ctx context.Context,
+	client *kong.Client, tags []string, tagType int, retries int,
 ) ([]*kong.ConsumerGroupObject, error) {
 	var consumerGroupObjects []*kong.ConsumerGroupObject
 	opt := newOpt(tags)
 
 	for {
-		cgs, nextopt, err := client.ConsumerGroups.List(ctx, opt)
+		cgs, nextopt, err := listPage(ctx, retries, opt, client.ConsumerGroups.List)
 		if err != nil {
 			return nil, err
 		}
@@ -1290,12 +1601,18 @@ func GetAllConsumerGroupsDefault(ctx context.Context,
 // with a consumer-group
 func GetAllConsumerGroupsWithoutConsumersDefault(ctx context.Context,
 	client *kong.Client, tags []string, _ int,
+) ([]*kong.ConsumerGroupObject, error) {
+	return getAllConsumerGroupsWithoutConsumersDefault(ctx, client, tags, 0, 0)
+}
+
+func getAllConsumerGroupsWithoutConsumersDefault(ctx context.Context,
+	client *kong.Client, tags []string, _ int, retries int,
 ) ([]*kong.ConsumerGroupObject, error) {
 	var consumerGroupObjects []*kong.ConsumerGroupObject
 	opt := newOpt(tags)
 
 	for {
-		cgs, nextopt, err := client.ConsumerGroups.List(ctx, opt)
+		cgs, nextopt, err := listPage(ctx, retries, opt, client.ConsumerGroups.List)
 		if err != nil {
 			return nil, err
 		}
@@ -1328,12 +1645,18 @@ func GetAllConsumerGroupsWithoutConsumersDefault(ctx context.Context,
 // skipping consumers, using client.
 func GetAllConsumerGroupsWithoutConsumers(ctx context.Context,
 	client *kong.Client, tags []string, _ int,
+) ([]*kong.ConsumerGroupObject, error) {
+	return getAllConsumerGroupsWithoutConsumers(ctx, client, tags, 0, 0)
+}
+
+func getAllConsumerGroupsWithoutConsumers(ctx context.Context,
+	client *kong.Client, tags []string, _ int, retries int,
 ) ([]*kong.ConsumerGroupObject, error) {
 	var consumerGroupObjects []*kong.ConsumerGroupObject
 	opt := newOpt(tags)
 
 	for {
-		cgs, nextopt, err := client.ConsumerGroups.List(ctx, opt)
+		cgs, nextopt, err := listPage(ctx, retries, opt, client.ConsumerGroups.List)
 		if err != nil {
 			return nil, err
 		}
@@ -1368,13 +1691,22 @@ func GetAllConsumerGroupsWithoutConsumers(ctx context.Context,
 // to list all targets of all upstreams.
 func GetAllTargets(ctx context.Context, client *kong.Client,
 	upstreams []*kong.Upstream, tags []string,
+) ([]*kong.Target, error) {
+	return getAllTargets(ctx, client, upstreams, tags, 0)
+}
+
+func getAllTargets(ctx context.Context, client *kong.Client,
+	upstreams []*kong.Upstream, tags []string, retries int,
 ) ([]*kong.Target, error) {
 	var targets []*kong.Target
 	opt := newOpt(tags)
 
 	for _, upstream := range upstreams {
 		for {
-			t, nextopt, err := client.Targets.List(ctx, upstream.ID, opt)
+			t, nextopt, err := listPage(ctx, retries, opt,
+				func(ctx context.Context, opt *kong.ListOpt) ([]*kong.Target, *kong.ListOpt, error) {
+					return client.Targets.List(ctx, upstream.ID, opt)
+				})
 			if err != nil {
 				return nil, err
 			}
@@ -1395,11 +1727,17 @@ func GetAllTargets(ctx context.Context, client *kong.Client,
 // GetAllTargetsFromKonnect queries Konnect for *all* Targets across *all* upstreams using a
 // Konnect-only `/targets` endpoint.
 func GetAllTargetsFromKonnect(ctx context.Context, client *kong.Client, tags []string) ([]*kong.Target, error) {
+	return getAllTargetsFromKonnect(ctx, client, tags, 0)
+}
+
+func getAllTargetsFromKonnect(ctx context.Context, client *kong.Client, tags []string,
+	retries int,
+) ([]*kong.Target, error) {
 	var targets []*kong.Target
 	opt := newOpt(tags)
 
 	for {
-		s, nextopt, err := client.Targets.ListAllTargets(ctx, opt)
+		s, nextopt, err := listPage(ctx, retries, opt, client.Targets.ListAllTargets)
 		if err != nil {
 			return nil, err
 		}
@@ -1419,12 +1757,18 @@ func GetAllTargetsFromKonnect(ctx context.Context, client *kong.Client, tags []s
 // GetAllVaults queries Kong for all the Vaults using client.
 func GetAllVaults(
 	ctx context.Context, client *kong.Client, tags []string,
+) ([]*kong.Vault, error) {
+	return getAllVaults(ctx, client, tags, 0)
+}
+
+func getAllVaults(
+	ctx context.Context, client *kong.Client, tags []string, retries int,
 ) ([]*kong.Vault, error) {
 	var vaults []*kong.Vault
 	opt := newOpt(tags)
 
 	for {
-		s, nextopt, err := client.Vaults.List(ctx, opt)
+		s, nextopt, err := listPage(ctx, retries, opt, client.Vaults.List)
 		if kong.IsNotFoundErr(err) || kong.IsForbiddenErr(err) {
 			return vaults, nil
 		}
@@ -1447,12 +1791,18 @@ func GetAllVaults(
 // GetAllKeyAuths queries Kong for all key-auth credentials using client.
 func GetAllKeyAuths(ctx context.Context,
 	client *kong.Client, tags []string,
+) ([]*kong.KeyAuth, error) {
+	return getAllKeyAuths(ctx, client, tags, 0)
+}
+
+func getAllKeyAuths(ctx context.Context,
+	client *kong.Client, tags []string, retries int,
 ) ([]*kong.KeyAuth, error) {
 	var keyAuths []*kong.KeyAuth
 	opt := newOpt(tags)
 
 	for {
-		s, nextopt, err := client.KeyAuths.List(ctx, opt)
+		s, nextopt, err := listPage(ctx, retries, opt, client.KeyAuths.List)
 		if kong.IsNotFoundErr(err) {
 			return keyAuths, nil
 		}
@@ -1474,12 +1824,18 @@ func GetAllKeyAuths(ctx context.Context,
 // GetAllHMACAuths queries Kong for all hmac-auth credentials using client.
 func GetAllHMACAuths(ctx context.Context,
 	client *kong.Client, tags []string,
+) ([]*kong.HMACAuth, error) {
+	return getAllHMACAuths(ctx, client, tags, 0)
+}
+
+func getAllHMACAuths(ctx context.Context,
+	client *kong.Client, tags []string, retries int,
 ) ([]*kong.HMACAuth, error) {
 	var hmacAuths []*kong.HMACAuth
 	opt := newOpt(tags)
 
 	for {
-		s, nextopt, err := client.HMACAuths.List(ctx, opt)
+		s, nextopt, err := listPage(ctx, retries, opt, client.HMACAuths.List)
 		if kong.IsNotFoundErr(err) {
 			return hmacAuths, nil
 		}
@@ -1501,12 +1857,18 @@ func GetAllHMACAuths(ctx context.Context,
 // GetAllJWTAuths queries Kong for all jwt credentials using client.
 func GetAllJWTAuths(ctx context.Context,
 	client *kong.Client, tags []string,
+) ([]*kong.JWTAuth, error) {
+	return getAllJWTAuths(ctx, client, tags, 0)
+}
+
+func getAllJWTAuths(ctx context.Context,
+	client *kong.Client, tags []string, retries int,
 ) ([]*kong.JWTAuth, error) {
 	var jwtAuths []*kong.JWTAuth
 	opt := newOpt(tags)
 
 	for {
-		s, nextopt, err := client.JWTAuths.List(ctx, opt)
+		s, nextopt, err := listPage(ctx, retries, opt, client.JWTAuths.List)
 		if kong.IsNotFoundErr(err) {
 			return jwtAuths, nil
 		}
@@ -1528,12 +1890,18 @@ func GetAllJWTAuths(ctx context.Context,
 // GetAllBasicAuths queries Kong for all basic-auth credentials using client.
 func GetAllBasicAuths(ctx context.Context,
 	client *kong.Client, tags []string,
+) ([]*kong.BasicAuth, error) {
+	return getAllBasicAuths(ctx, client, tags, 0)
+}
+
+func getAllBasicAuths(ctx context.Context,
+	client *kong.Client, tags []string, retries int,
 ) ([]*kong.BasicAuth, error) {
 	var basicAuths []*kong.BasicAuth
 	opt := newOpt(tags)
 
 	for {
-		s, nextopt, err := client.BasicAuths.List(ctx, opt)
+		s, nextopt, err := listPage(ctx, retries, opt, client.BasicAuths.List)
 		if kong.IsNotFoundErr(err) {
 			return basicAuths, nil
 		}
@@ -1555,12 +1923,18 @@ func GetAllBasicAuths(ctx context.Context,
 // GetAllOauth2Creds queries Kong for all oauth2 credentials using client.
 func GetAllOauth2Creds(ctx context.Context, client *kong.Client,
 	tags []string,
+) ([]*kong.Oauth2Credential, error) {
+	return getAllOauth2Creds(ctx, client, tags, 0)
+}
+
+func getAllOauth2Creds(ctx context.Context, client *kong.Client,
+	tags []string, retries int,
 ) ([]*kong.Oauth2Credential, error) {
 	var oauth2Creds []*kong.Oauth2Credential
 	opt := newOpt(tags)
 
 	for {
-		s, nextopt, err := client.Oauth2Credentials.List(ctx, opt)
+		s, nextopt, err := listPage(ctx, retries, opt, client.Oauth2Credentials.List)
 		if kong.IsNotFoundErr(err) {
 			return oauth2Creds, nil
 		}
@@ -1582,12 +1956,18 @@ func GetAllOauth2Creds(ctx context.Context, client *kong.Client,
 // GetAllACLGroups queries Kong for all ACL groups using client.
 func GetAllACLGroups(ctx context.Context,
 	client *kong.Client, tags []string,
+) ([]*kong.ACLGroup, error) {
+	return getAllACLGroups(ctx, client, tags, 0)
+}
+
+func getAllACLGroups(ctx context.Context,
+	client *kong.Client, tags []string, retries int,
 ) ([]*kong.ACLGroup, error) {
 	var aclGroups []*kong.ACLGroup
 	opt := newOpt(tags)
 
 	for {
-		s, nextopt, err := client.ACLs.List(ctx, opt)
+		s, nextopt, err := listPage(ctx, retries, opt, client.ACLs.List)
 		if kong.IsNotFoundErr(err) {
 			return aclGroups, nil
 		}
@@ -1609,12 +1989,18 @@ func GetAllACLGroups(ctx context.Context,
 // GetAllMTLSAuths queries Kong for all basic-auth credentials using client.
 func GetAllMTLSAuths(ctx context.Context,
 	client *kong.Client, tags []string,
+) ([]*kong.MTLSAuth, error) {
+	return getAllMTLSAuths(ctx, client, tags, 0)
+}
+
+func getAllMTLSAuths(ctx context.Context,
+	client *kong.Client, tags []string, retries int,
 ) ([]*kong.MTLSAuth, error) {
 	var mtlsAuths []*kong.MTLSAuth
 	opt := newOpt(tags)
 
 	for {
-		s, nextopt, err := client.MTLSAuths.List(ctx, opt)
+		s, nextopt, err := listPage(ctx, retries, opt, client.MTLSAuths.List)
 		if kong.IsNotFoundErr(err) {
 			return mtlsAuths, nil
 		}
@@ -1687,15 +2073,153 @@ func GetAllRBACREndpointPermissions(ctx context.Context,
 	return eps, nil
 }
 
+// GetAllRBACEntityPermissions queries Kong for the RBACEntityPermissions of
+// all the RBACRoles using client.
+func GetAllRBACEntityPermissions(ctx context.Context,
+	client *kong.Client,
+) ([]*kong.RBACEntityPermission, error) {
+	eps := []*kong.RBACEntityPermission{}
+	roles, err := client.RBACRoles.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	for _, r := range roles {
+		reps, err := client.RBACEntityPermissions.ListAllForRole(ctx, r.ID)
+		if err != nil {
+			return nil, err
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		for _, ep := range reps {
+			ep.Role = &kong.RBACRole{ID: r.ID}
+		}
+		eps = append(eps, reps...)
+	}
+
+	return eps, nil
+}
+
+// assignedRoles returns the names of roles, leaving out the default role Kong
+// creates for each RBAC user and admin.
+func assignedRoles(roles []*kong.RBACRole) []string {
+	var res []string
+	for _, r := range roles {
+		if r.Name == nil || (r.IsDefault != nil && *r.IsDefault) {
+			continue
+		}
+		res = append(res, *r.Name)
+	}
+	return res
+}
+
+// GetAllRBACUsers queries Kong for all the RBACUsers using client, along with
+// the names of their roles by user ID.
+func GetAllRBACUsers(ctx context.Context,
+	client *kong.Client,
+) ([]*kong.RBACUser, map[string][]string, error) {
+	users, err := client.RBACUsers.ListAll(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	userRoles := make(map[string][]string, len(users))
+	for _, u := range users {
+		roles, err := client.RBACUsers.ListRoles(ctx, u.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		userRoles[*u.ID] = assignedRoles(roles)
+	}
+	return users, userRoles, nil
+}
+
+// GetAllAdmins queries Kong for all the Kong Manager Admins using client,
+// along with the names of their roles by admin ID.
+func GetAllAdmins(ctx context.Context,
+	client *kong.Client,
+) ([]*kong.Admin, map[string][]string, error) {
+	return getAllAdmins(ctx, client, 0)
+}
+
+func getAllAdmins(ctx context.Context,
+	client *kong.Client, retries int,
+) ([]*kong.Admin, map[string][]string, error) {
+	var admins []*kong.Admin
+	opt := &kong.ListOpt{Size: DefaultPageSize}
+	for {
+		s, nextopt, err := listPage(ctx, retries, opt, client.Admins.List)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		admins = append(admins, s...)
+		if nextopt == nil {
+			break
+		}
+		opt = nextopt
+	}
+	adminRoles := make(map[string][]string, len(admins))
+	for _, a := range admins {
+		roles, err := client.Admins.ListRoles(ctx, a.ID, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		adminRoles[*a.ID] = assignedRoles(roles)
+	}
+	return admins, adminRoles, nil
+}
+
+// GetAllWorkspaces queries Kong for all the Workspaces using client. The
+// workspaces are listed with a request to the root of the Admin API, so that
+// all of them are returned whatever the workspace of client.
+func GetAllWorkspaces(ctx context.Context, client *kong.Client) ([]*kong.Workspace, error) {
+	workspaces, err := utils.ListWorkspaces(ctx, client)
+	if kong.IsNotFoundErr(err) {
+		return nil, nil
+	}
+	return workspaces, err
+}
+
+// GetAllEventHooks queries Kong for all the EventHooks using client.
+// No event hooks are returned if Kong does not support them, which is the
+// case of Kong Gateway OSS and of Kong Enterprise in free mode.
+func GetAllEventHooks(ctx context.Context, client *kong.Client) ([]*utils.EventHook, error) {
+	eventHooks, err := utils.ListEventHooks(ctx, client)
+	if kong.IsNotFoundErr(err) || kong.IsForbiddenErr(err) {
+		return nil, nil
+	}
+	return eventHooks, err
+}
+
 // GetAllLicenses queries Kong for all the Licenses using client.
 func GetAllLicenses(
 	ctx context.Context, client *kong.Client, tags []string,
+) ([]*kong.License, error) {
+	return getAllLicenses(ctx, client, tags, 0)
+}
+
+func getAllLicenses(
+	ctx context.Context, client *kong.Client, tags []string, retries int,
 ) ([]*kong.License, error) {
 	var licenses []*kong.License
 	opt := newOpt(tags)
 
 	for {
-		s, nextopt, err := client.Licenses.List(ctx, opt)
+		s, nextopt, err := listPage(ctx, retries, opt, client.Licenses.List)
 		if kong.IsNotFoundErr(err) {
 			return licenses, nil
 		}
@@ -1718,12 +2242,21 @@ func GetAllLicenses(
 // GetAllCustomEntitiesWithType quries Kong for all Custom entities with the given type.
 func GetAllCustomEntitiesWithType(
 	ctx context.Context, client *kong.Client, entityType string,
+) ([]custom.Entity, error) {
+	return getAllCustomEntitiesWithType(ctx, client, entityType, 0)
+}
+
+func getAllCustomEntitiesWithType(
+	ctx context.Context, client *kong.Client, entityType string, retries int,
 ) ([]custom.Entity, error) {
 	entities := []custom.Entity{}
 	opt := newOpt(nil)
 	e := custom.NewEntityObject(custom.Type(entityType))
 	for {
-		s, nextOpt, err := client.CustomEntities.List(ctx, opt, e)
+		s, nextOpt, err := listPage(ctx, retries, opt,
+			func(ctx context.Context, opt *kong.ListOpt) ([]custom.Entity, *kong.ListOpt, error) {
+				return client.CustomEntities.List(ctx, opt, e)
+			})
 		if kong.IsNotFoundErr(err) || kong.IsForbiddenErr(err) {
 			return entities, nil
 		}
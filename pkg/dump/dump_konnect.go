@@ -4,126 +4,193 @@ import (
 	"context"
 	"fmt"
 	"reflect"
-	"sync"
 
 	"github.com/kong/go-database-reconciler/pkg/konnect"
 	"github.com/kong/go-database-reconciler/pkg/utils"
 	"github.com/kong/go-kong/kong"
-	"golang.org/x/sync/errgroup"
-	"golang.org/x/sync/semaphore"
 )
 
 type KonnectConfig struct {
 	// ID of the Kong Control Plane being managed.
 	ControlPlaneID string
+
+	// IncludeControlPlanes fetches the control planes of the organization
+	// and the members of control plane groups. Control planes are not
+	// fetched by default since the ones missing from the target state would
+	// be deleted.
+	IncludeControlPlanes bool
+
+	// Concurrency is the maximum number of requests sent to Konnect at the
+	// same time. It defaults to 10.
+	Concurrency int
 }
 
+const defaultKonnectConcurrency = 10
+
+func (c KonnectConfig) concurrency() int {
+	if c.Concurrency <= 0 {
+		return defaultKonnectConcurrency
+	}
+	return c.Concurrency
+}
+
+// GetFromKonnect fetches the Konnect entities of the organization.
+//
+// Requests are sent by a pool of config.Concurrency workers shared by all
+// entities. A failing request does not stop the others, and the errors of all
+// of them are returned.
 func GetFromKonnect(ctx context.Context, konnectClient *konnect.Client,
 	config KonnectConfig,
 ) (*utils.KonnectRawState, error) {
-	var res utils.KonnectRawState
-	var servicePackages []*konnect.ServicePackage
-	var relations []*konnect.ControlPlaneServiceRelation
+	d := &konnectDump{
+		client: konnectClient,
+		pool:   utils.NewPool(ctx, config.concurrency()),
+	}
+	d.getServicePackages()
+	d.getAPIProducts()
+	d.pool.Go(func(ctx context.Context) error {
+		var err error
+		d.portals, err = konnectClient.Portals.ListAll(ctx)
+		return err
+	})
+	if config.IncludeControlPlanes {
+		d.getControlPlanes()
+	}
+	d.pool.Go(func(ctx context.Context) error {
+		var err error
+		d.relations, err = konnectClient.ControlPlaneRelations.ListAll(ctx)
+		return err
+	})
 
-	group, ctx := errgroup.WithContext(ctx)
-	// group1 fetches service packages and their versions
-	group.Go(func() error {
+	err := d.pool.Wait()
+	if err != nil {
+		return nil, err
+	}
+
+	return &utils.KonnectRawState{
+		ServicePackages: filterNonKongPackages(config.ControlPlaneID,
+			d.servicePackages, d.relations),
+		Documents:           d.documents.Items(),
+		APIProducts:         d.apiProducts,
+		APIProductVersions:  d.apiProductVersions.Items(),
+		APIProductDocuments: d.apiProductDocuments.Items(),
+		Portals:             d.portals,
+		ControlPlanes:       d.controlPlanes,
+	}, nil
+}
+
+// konnectDump holds the entities fetched by the tasks of pool. Each field is
+// written by a single task, or is a Collector.
+type konnectDump struct {
+	client *konnect.Client
+	pool   *utils.Pool
+
+	servicePackages     []*konnect.ServicePackage
+	relations           []*konnect.ControlPlaneServiceRelation
+	documents           utils.Collector[*konnect.Document]
+	apiProducts         []*konnect.APIProduct
+	apiProductVersions  utils.Collector[*konnect.APIProductVersion]
+	apiProductDocuments utils.Collector[*konnect.APIProductDocument]
+	portals             []*konnect.Portal
+	controlPlanes       []*konnect.ControlPlaneV2
+}
+
+// getServicePackages fetches service packages, along with their versions
+// and the documents of both.
+func (d *konnectDump) getServicePackages() {
+	d.pool.Go(func(ctx context.Context) error {
 		var err error
-		// fetch service packages
-		servicePackages, err = konnectClient.ServicePackages.ListAll(ctx)
+		d.servicePackages, err = d.client.ServicePackages.ListAll(ctx)
 		if err != nil {
 			return err
 		}
-
-		// versions of service packages are fetched concurrently
-		errChan := make(chan error)
-		var err2 error
-
-		m := &sync.Mutex{}
-		m.Lock()
-		go func() {
-			defer m.Unlock()
-			// only the last error matters
-			for err := range errChan {
-				err2 = err
-			}
-		}()
-
-		const concurrency = 10
-
-		semaphore := semaphore.NewWeighted(concurrency)
-		for i := 0; i < len(servicePackages); i++ {
-			// control the number of outstanding go routines, also controlling
-			// the number of parallel requests
-			err := semaphore.Acquire(ctx, 2)
-			if err != nil {
-				return fmt.Errorf("acquire semaphore: %w", err)
-			}
-			go func(i int) {
-				defer semaphore.Release(1)
-				versions, err := konnectClient.ServiceVersions.ListForPackage(ctx, servicePackages[i].ID)
+		for _, servicePackage := range d.servicePackages {
+			d.pool.Go(func(ctx context.Context) error {
+				versions, err := d.client.ServiceVersions.ListForPackage(ctx, servicePackage.ID)
 				if err != nil {
-					errChan <- err
-					return
+					return err
 				}
-				servicePackages[i].Versions = versions
-			}(i)
-			go func(i int) {
-				defer semaphore.Release(1)
-				documents, err := konnectClient.Documents.ListAllForParent(ctx, servicePackages[i])
-				if err != nil {
-					errChan <- err
-					return
+				servicePackage.Versions = versions
+				for _, version := range versions {
+					d.pool.Go(func(ctx context.Context) error {
+						documents, err := d.client.Documents.ListAllForParent(ctx, &version)
+						if err != nil {
+							return err
+						}
+						d.documents.Add(documents...)
+						return nil
+					})
 				}
-				res.Documents = append(res.Documents, documents...)
-			}(i)
-		}
-		for i := 0; i < len(servicePackages); i++ {
-			for _, version := range servicePackages[i].Versions {
-				err := semaphore.Acquire(ctx, 1)
+				return nil
+			})
+			d.pool.Go(func(ctx context.Context) error {
+				documents, err := d.client.Documents.ListAllForParent(ctx, servicePackage)
 				if err != nil {
-					return fmt.Errorf("acquire semaphore: %w", err)
+					return err
 				}
-				go func(version konnect.ServiceVersion) {
-					defer semaphore.Release(1)
-					documents, err := konnectClient.Documents.ListAllForParent(ctx, &version)
-					if err != nil {
-						errChan <- err
-						return
-					}
-					res.Documents = append(res.Documents, documents...)
-				}(version)
-			}
+				d.documents.Add(documents...)
+				return nil
+			})
 		}
-		err = semaphore.Acquire(ctx, concurrency)
+		return nil
+	})
+}
+
+// getAPIProducts fetches API products, along with their versions and
+// documents.
+func (d *konnectDump) getAPIProducts() {
+	d.pool.Go(func(ctx context.Context) error {
+		var err error
+		d.apiProducts, err = d.client.APIProducts.ListAll(ctx)
 		if err != nil {
-			return fmt.Errorf("acquire semaphore: %w", err)
+			return err
 		}
-		close(errChan)
-		semaphore.Release(concurrency)
-		m.Lock()
-		defer m.Unlock()
-		if err2 != nil {
-			return err2
+		for _, product := range d.apiProducts {
+			d.pool.Go(func(ctx context.Context) error {
+				versions, err := d.client.APIProductVersions.ListAllForProduct(ctx, product)
+				if err != nil {
+					return err
+				}
+				d.apiProductVersions.Add(versions...)
+				return nil
+			})
+			d.pool.Go(func(ctx context.Context) error {
+				documents, err := d.client.APIProductDocuments.ListAllForProduct(ctx, product)
+				if err != nil {
+					return err
+				}
+				d.apiProductDocuments.Add(documents...)
+				return nil
+			})
 		}
 		return nil
 	})
+}
 
-	// group2 fetches CP-service relations
-	group.Go(func() error {
+// getControlPlanes fetches control planes, along with the members of control
+// plane groups.
+func (d *konnectDump) getControlPlanes() {
+	d.pool.Go(func(ctx context.Context) error {
 		var err error
-		relations, err = konnectClient.ControlPlaneRelations.ListAll(ctx)
-		return err
+		d.controlPlanes, err = d.client.ControlPlanesV2.ListAll(ctx)
+		if err != nil {
+			return err
+		}
+		for _, cp := range d.controlPlanes {
+			if !cp.IsGroup() {
+				continue
+			}
+			d.pool.Go(func(ctx context.Context) error {
+				members, err := d.client.ControlPlanesV2.ListGroupMembers(ctx, *cp.ID)
+				if err != nil {
+					return err
+				}
+				cp.Members = members
+				return nil
+			})
+		}
+		return nil
 	})
-
-	err := group.Wait()
-	if err != nil {
-		return nil, err
-	}
-
-	res.ServicePackages = filterNonKongPackages(config.ControlPlaneID,
-		servicePackages, relations)
-	return &res, nil
 }
 
 func filterNonKongPackages(controlPlaneID string, packages []*konnect.ServicePackage,
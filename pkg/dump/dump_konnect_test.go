@@ -1,11 +1,21 @@
 package dump
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/kong/go-database-reconciler/pkg/konnect"
+	"github.com/kong/go-database-reconciler/pkg/utils"
 	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_kongServiceIDs(t *testing.T) {
@@ -447,3 +457,110 @@ func Test_excludeKonnectManagedEntities(t *testing.T) {
 		})
 	}
 }
+
+// fakeKonnect serves service packages with a version and a document each, and
+// records the maximum number of requests in flight.
+type fakeKonnect struct {
+	packages int
+	// failing are the IDs of the service packages whose documents cannot be
+	// listed.
+	failing map[string]bool
+	// block holds requests for documents until they are canceled.
+	block bool
+
+	inFlight, maxInFlight atomic.Int32
+}
+
+func (f *fakeKonnect) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/service_packages", func(w http.ResponseWriter, _ *http.Request) {
+		var data []string
+		for i := range f.packages {
+			data = append(data, fmt.Sprintf(`{"id":"sp%d","name":"sp%d"}`, i, i))
+		}
+		fmt.Fprintf(w, `{"data":[%s],"page":1,"pageCount":1}`, strings.Join(data, ","))
+	})
+	mux.HandleFunc("GET /api/service_packages/{id}/service_versions", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `[{"id":"%s-v1","version":"v1"}]`, r.PathValue("id"))
+	})
+	documents := func(w http.ResponseWriter, r *http.Request) {
+		if f.block {
+			<-r.Context().Done()
+			return
+		}
+		if f.failing[r.PathValue("id")] {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintf(w, `{"data":[{"id":"%s-doc"}],"page":1,"pageCount":1}`, r.PathValue("id"))
+	}
+	mux.HandleFunc("GET /api/service_packages/{id}/documents", documents)
+	mux.HandleFunc("GET /api/service_versions/{id}/documents", documents)
+	mux.HandleFunc("GET /api/control_plane_service_relations", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"data":[],"page":1,"pageCount":1}`)
+	})
+	empty := func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"data":[],"meta":{"page":{"total":0}}}`)
+	}
+	mux.HandleFunc("GET /v2/api-products", empty)
+	mux.HandleFunc("GET /v2/portals", empty)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := f.inFlight.Add(1)
+		defer f.inFlight.Add(-1)
+		for {
+			m := f.maxInFlight.Load()
+			if n <= m || f.maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		// keep requests in flight long enough to overlap
+		time.Sleep(time.Millisecond)
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func newFakeKonnectClient(t *testing.T, f *fakeKonnect) *konnect.Client {
+	server := httptest.NewServer(f.handler())
+	t.Cleanup(server.Close)
+	client, err := konnect.NewClient(nil, konnect.ClientOpts{BaseURL: server.URL})
+	require.NoError(t, err)
+	return client
+}
+
+func TestGetFromKonnect(t *testing.T) {
+	f := &fakeKonnect{packages: 30}
+	client := newFakeKonnectClient(t, f)
+
+	res, err := GetFromKonnect(context.Background(), client, KonnectConfig{Concurrency: 4})
+	require.NoError(t, err)
+	require.Len(t, res.ServicePackages, 30)
+	for _, sp := range res.ServicePackages {
+		require.Len(t, sp.Versions, 1)
+	}
+	// a document for each package and each version
+	assert.Len(t, res.Documents, 60)
+	assert.LessOrEqual(t, f.maxInFlight.Load(), int32(4))
+	assert.Greater(t, f.maxInFlight.Load(), int32(1))
+}
+
+func TestGetFromKonnectErrors(t *testing.T) {
+	f := &fakeKonnect{packages: 5, failing: map[string]bool{"sp1": true, "sp3": true}}
+	client := newFakeKonnectClient(t, f)
+
+	_, err := GetFromKonnect(context.Background(), client, KonnectConfig{})
+	var errs utils.ErrArray
+	require.ErrorAs(t, err, &errs)
+	assert.Len(t, errs.Errors, 2)
+}
+
+func TestGetFromKonnectCanceled(t *testing.T) {
+	f := &fakeKonnect{packages: 50, block: true}
+	client := newFakeKonnectClient(t, f)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	_, err := GetFromKonnect(ctx, client, KonnectConfig{Concurrency: 2})
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
@@ -2,6 +2,10 @@ package dump
 
 import (
 	"testing"
+
+	"github.com/kong/go-database-reconciler/pkg/utils"
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
 )
 
 func Test_validateConfig(t *testing.T) {
@@ -42,6 +46,26 @@ func Test_validateConfig(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "invalid config mixing RBAC and a selector tag expression",
+			args: args{
+				config: Config{
+					SelectorTagExpression: utils.MustParseTagExpression("foo OR bar"),
+					RBACResourcesOnly:     true,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid config mixing selector tags and a selector tag expression",
+			args: args{
+				config: Config{
+					SelectorTags:          []string{"foo"},
+					SelectorTagExpression: utils.MustParseTagExpression("foo OR bar"),
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalid config mixing RBAC and SkipConsumers",
 			args: args{
@@ -61,3 +85,35 @@ func Test_validateConfig(t *testing.T) {
 		})
 	}
 }
+
+func Test_selectorTags(t *testing.T) {
+	tests := []struct {
+		expression string
+		wantTags   []string
+		wantFilter bool
+	}{
+		{expression: "foo", wantTags: []string{"foo"}},
+		{expression: "foo AND bar", wantTags: []string{"foo", "bar"}},
+		{expression: "foo OR bar", wantTags: []string{"foo/bar"}},
+		{expression: "managed AND NOT legacy", wantTags: []string{"managed"}, wantFilter: true},
+		{expression: "(foo OR bar) AND NOT legacy", wantFilter: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			tags, filter := selectorTags(utils.MustParseTagExpression(tt.expression))
+			assert.Equal(t, tt.wantTags, tags)
+			assert.Equal(t, tt.wantFilter, filter != nil)
+		})
+	}
+}
+
+func Test_selectTagged(t *testing.T) {
+	services := []*kong.Service{
+		{Name: new("managed"), Tags: kong.StringSlice("managed")},
+		{Name: new("legacy"), Tags: kong.StringSlice("managed", "legacy")},
+		{Name: new("untagged")},
+	}
+	assert.Len(t, selectTagged(nil, services), 3)
+	selected := selectTagged(utils.MustParseTagExpression("managed AND NOT legacy"), services)
+	assert.Equal(t, []*kong.Service{services[0]}, selected)
+}
@@ -0,0 +1,57 @@
+package dump
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/kong/go-kong/kong"
+)
+
+// newPageBackOff returns the backoff between attempts to list a page.
+// It is a variable for testing purpose.
+var newPageBackOff = defaultPageBackOff
+
+func defaultPageBackOff() backoff.BackOff {
+	exponentialBackoff := backoff.NewExponentialBackOff()
+	exponentialBackoff.InitialInterval = 1 * time.Second
+	exponentialBackoff.MaxInterval = 30 * time.Second
+	exponentialBackoff.MaxElapsedTime = 0
+	return exponentialBackoff
+}
+
+// listPage lists a page of entities, retrying requests which failed with a
+// network error, a 429 or a 5xx status code up to retries times.
+func listPage[T any](ctx context.Context, retries int, opt *kong.ListOpt,
+	list func(context.Context, *kong.ListOpt) ([]T, *kong.ListOpt, error),
+) ([]T, *kong.ListOpt, error) {
+	if retries <= 0 {
+		return list(ctx, opt)
+	}
+
+	var (
+		page    []T
+		nextopt *kong.ListOpt
+	)
+	err := backoff.Retry(func() error {
+		var err error
+		page, nextopt, err = list(ctx, opt)
+		if err != nil && !isRetryable(ctx, err) {
+			return backoff.Permanent(err)
+		}
+		return err
+	}, backoff.WithContext(backoff.WithMaxRetries(newPageBackOff(), uint64(retries)), ctx))
+	return page, nextopt, err
+}
+
+func isRetryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if kongErr, ok := errors.AsType[*kong.APIError](err); ok {
+		return kongErr.Code() == http.StatusTooManyRequests || kongErr.Code() >= http.StatusInternalServerError
+	}
+	return true
+}
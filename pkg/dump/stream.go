@@ -0,0 +1,365 @@
+package dump
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"reflect"
+
+	"github.com/kong/go-database-reconciler/pkg/schema"
+	"github.com/kong/go-database-reconciler/pkg/utils"
+	"github.com/kong/go-kong/kong"
+	"golang.org/x/sync/errgroup"
+)
+
+// DefaultPageSize is the number of entities requested per page when listing
+// entities from Kong.
+const DefaultPageSize = 1000
+
+// PageFunc is called by Stream with each page of entities dumped from Kong.
+// Each page holds a single kind of entities.
+type PageFunc func(page *utils.KongRawState) error
+
+var errStopStream = errors.New("stream stopped")
+
+// Stream dumps Kong like Get, but passes consumers and credentials to fn page by
+// page as they are listed instead of holding all of them in memory.
+//
+// Consumers are streamed first, followed by consumer-groups and credentials.
+// All other entities are then passed to fn in a single page. Each page only
+// refers to entities streamed before it, so that pages can be added to a
+// state.Builder as they arrive.
+//
+// If config.CheckpointFile is set, the progress of the dump is recorded after
+// each page, and a dump failing midway resumes after the last page passed to fn
+// without error. The pages passed to fn before the failure are kept with the
+// checkpoint and passed again first, so that fn receives the whole dump even
+// when the dump is resumed by another process: a resumed dump must be consumed
+// from scratch, e.g. by a new state.Builder.
+func Stream(ctx context.Context, client *kong.Client, config Config, fn PageFunc) error {
+	if err := validateConfig(config); err != nil {
+		return err
+	}
+	if config.PageSize < 0 {
+		return fmt.Errorf("dump: config: PageSize must not be negative")
+	}
+	if config.RBACResourcesOnly {
+		raw, err := Get(ctx, client, config)
+		if err != nil {
+			return err
+		}
+		return fn(raw)
+	}
+
+	s := &streamer{client: client, config: config, fn: fn}
+	if config.CheckpointFile != "" {
+		checkpoint, err := loadCheckpoint(config.CheckpointFile, config)
+		if err != nil {
+			return err
+		}
+		s.checkpoint = checkpoint
+	}
+	if config.SelectorTagExpression != nil {
+		s.config.SelectorTags, s.config.tagFilter = selectorTags(config.SelectorTagExpression)
+	}
+	if config.SkipDefaults {
+		s.registry = config.SchemaRegistry
+		if s.registry == nil {
+			s.registry = schema.NewRegistry(client, config.KonnectControlPlane != "")
+		}
+	}
+	if config.LookUpSelectorTagsConsumers != nil {
+		s.seenConsumers = map[string]struct{}{}
+	}
+
+	err := s.checkpoint.replay(func(page *utils.KongRawState) error {
+		if s.seenConsumers != nil {
+			for _, c := range page.Consumers {
+				s.seenConsumers[*c.ID] = struct{}{}
+			}
+		}
+		return fn(page)
+	})
+	if err != nil {
+		return err
+	}
+
+	if !config.SkipConsumers {
+		if err := s.consumers(ctx); err != nil {
+			return err
+		}
+		if err := s.consumerGroups(ctx); err != nil {
+			return err
+		}
+		if err := s.credentials(ctx); err != nil {
+			return err
+		}
+	}
+	if err := s.proxyConfiguration(ctx); err != nil {
+		return err
+	}
+	return s.checkpoint.remove()
+}
+
+// Pages returns an iterator over the pages of entities streamed by Stream.
+// Iteration stops after the first error.
+func Pages(ctx context.Context, client *kong.Client, config Config) iter.Seq2[*utils.KongRawState, error] {
+	return func(yield func(*utils.KongRawState, error) bool) {
+		err := Stream(ctx, client, config, func(page *utils.KongRawState) error {
+			if !yield(page, nil) {
+				return errStopStream
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, errStopStream) {
+			yield(nil, err)
+		}
+	}
+}
+
+type streamer struct {
+	client     *kong.Client
+	config     Config
+	registry   *schema.Registry
+	checkpoint *Checkpoint
+	fn         PageFunc
+
+	// seenConsumers holds the IDs of the streamed consumers when consumers
+	// found with lookup tags need to be deduplicated.
+	seenConsumers map[string]struct{}
+}
+
+func (s *streamer) newOpt(tags []string) *kong.ListOpt {
+	opt := newOpt(tags)
+	if s.config.PageSize > 0 {
+		opt.Size = s.config.PageSize
+	}
+	return opt
+}
+
+// send passes page to fn, once defaults are removed from its entities if
+// requested.
+func (s *streamer) send(ctx context.Context, page *utils.KongRawState) error {
+	if s.registry != nil {
+		group, groupCtx := errgroup.WithContext(ctx)
+		RemoveDefaultsFromState(groupCtx, group, page, s.registry)
+		if err := group.Wait(); err != nil {
+			return err
+		}
+	}
+	return s.fn(page)
+}
+
+// emit sends page and keeps it with the checkpoint, so that a resumed dump
+// passes it again to fn. Its progress must be recorded next.
+func (s *streamer) emit(ctx context.Context, page *utils.KongRawState) error {
+	if err := s.send(ctx, page); err != nil {
+		return err
+	}
+	return s.checkpoint.appendPage(page)
+}
+
+// streamPages calls fn with every non-empty page of entities of kind returned
+// by list, recording the progress in the checkpoint after each page.
+func streamPages[T any](ctx context.Context, s *streamer, kind string, tags []string,
+	list func(context.Context, *kong.ListOpt) ([]T, *kong.ListOpt, error),
+	fn func([]T) error,
+) error {
+	if s.checkpoint.done(kind) {
+		return nil
+	}
+	opt := s.newOpt(tags)
+	opt.Offset = s.checkpoint.offset(kind)
+	for {
+		page, nextopt, err := listPage(ctx, s.config.PageRetries, opt, list)
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if len(page) > 0 {
+			if err := fn(page); err != nil {
+				return err
+			}
+		}
+		if nextopt == nil {
+			return s.checkpoint.record(kind, "")
+		}
+		if err := s.checkpoint.record(kind, nextopt.Offset); err != nil {
+			return err
+		}
+		opt = nextopt
+	}
+}
+
+func (s *streamer) consumers(ctx context.Context) error {
+	emit := func(consumers []*kong.Consumer) error {
+		if s.seenConsumers != nil {
+			consumers = deduplicateByID(s.seenConsumers, consumers)
+		}
+		if len(consumers) == 0 {
+			return nil
+		}
+		return s.emit(ctx, &utils.KongRawState{Consumers: consumers})
+	}
+
+	err := streamPages(ctx, s, "consumers", s.config.SelectorTags, s.client.Consumers.List,
+		func(consumers []*kong.Consumer) error {
+			return emit(selectTagged(s.config.tagFilter, consumers))
+		})
+	if err != nil {
+		return fmt.Errorf("consumers: %w", err)
+	}
+	if s.config.LookUpSelectorTagsConsumers != nil {
+		err := streamPages(ctx, s, "lookup-consumers", s.config.LookUpSelectorTagsConsumers,
+			s.client.Consumers.List, emit)
+		if err != nil {
+			return fmt.Errorf("error retrieving global consumers: %w", err)
+		}
+	}
+	return nil
+}
+
+// appendRawState appends the entities of page to raw.
+func appendRawState(raw, page *utils.KongRawState) {
+	dst := reflect.ValueOf(raw).Elem()
+	src := reflect.ValueOf(page).Elem()
+	for i := range dst.NumField() {
+		field := src.Field(i)
+		switch field.Kind() {
+		case reflect.Slice:
+			dst.Field(i).Set(reflect.AppendSlice(dst.Field(i), field))
+		case reflect.Map:
+			if field.Len() == 0 {
+				continue
+			}
+			if dst.Field(i).IsNil() {
+				dst.Field(i).Set(reflect.MakeMap(field.Type()))
+			}
+			for _, key := range field.MapKeys() {
+				dst.Field(i).SetMapIndex(key, field.MapIndex(key))
+			}
+		}
+	}
+}
+
+func deduplicateByID(seen map[string]struct{}, consumers []*kong.Consumer) []*kong.Consumer {
+	res := consumers[:0]
+	for _, c := range consumers {
+		if _, ok := seen[*c.ID]; ok {
+			continue
+		}
+		seen[*c.ID] = struct{}{}
+		res = append(res, c)
+	}
+	return res
+}
+
+func (s *streamer) consumerGroups(ctx context.Context) error {
+	if s.checkpoint.done("consumer-groups") {
+		return nil
+	}
+	var raw utils.KongRawState
+	group, groupCtx := errgroup.WithContext(ctx)
+	getConsumerGroupsConfiguration(groupCtx, group, s.client, s.config, &raw)
+	if err := group.Wait(); err != nil {
+		return err
+	}
+	if len(raw.ConsumerGroups) > 0 {
+		if err := s.emit(ctx, &raw); err != nil {
+			return err
+		}
+	}
+	return s.checkpoint.record("consumer-groups", "")
+}
+
+func (s *streamer) credentials(ctx context.Context) error {
+	tags := s.config.SelectorTags
+	filter := s.config.tagFilter
+
+	err := streamPages(ctx, s, "key-auths", tags, s.client.KeyAuths.List, func(page []*kong.KeyAuth) error {
+		return s.emit(ctx, &utils.KongRawState{KeyAuths: selectTagged(filter, page)})
+	})
+	if err != nil {
+		return fmt.Errorf("key-auths: %w", err)
+	}
+
+	err = streamPages(ctx, s, "hmac-auths", tags, s.client.HMACAuths.List, func(page []*kong.HMACAuth) error {
+		return s.emit(ctx, &utils.KongRawState{HMACAuths: selectTagged(filter, page)})
+	})
+	if err != nil {
+		return fmt.Errorf("hmac-auths: %w", err)
+	}
+
+	err = streamPages(ctx, s, "jwts", tags, s.client.JWTAuths.List, func(page []*kong.JWTAuth) error {
+		return s.emit(ctx, &utils.KongRawState{JWTAuths: selectTagged(filter, page)})
+	})
+	if err != nil {
+		return fmt.Errorf("jwts: %w", err)
+	}
+
+	err = streamPages(ctx, s, "basic-auths", tags, s.client.BasicAuths.List, func(page []*kong.BasicAuth) error {
+		page = selectTagged(filter, page)
+		options := make([]*kong.BasicAuthOptions, 0, len(page))
+		for _, basicAuth := range page {
+			options = append(options, &kong.BasicAuthOptions{BasicAuth: *basicAuth})
+		}
+		return s.emit(ctx, &utils.KongRawState{BasicAuths: options})
+	})
+	if err != nil {
+		return fmt.Errorf("basic-auths: %w", err)
+	}
+
+	// OAuth2 credentials are not supported in Konnect.
+	if s.config.KonnectControlPlane == "" {
+		err = streamPages(ctx, s, "oauth2", tags, s.client.Oauth2Credentials.List,
+			func(page []*kong.Oauth2Credential) error {
+				return s.emit(ctx, &utils.KongRawState{Oauth2Creds: selectTagged(filter, page)})
+			})
+		if err != nil && !kong.IsNotFoundErr(err) {
+			return fmt.Errorf("oauth2: %w", err)
+		}
+	}
+
+	err = streamPages(ctx, s, "acls", tags, s.client.ACLs.List, func(page []*kong.ACLGroup) error {
+		return s.emit(ctx, &utils.KongRawState{ACLGroups: selectTagged(filter, page)})
+	})
+	if err != nil {
+		return fmt.Errorf("acls: %w", err)
+	}
+
+	// mTLS-auth credentials are not filtered by tags, see getConsumerConfiguration.
+	err = streamPages(ctx, s, "mtls-auths", nil, s.client.MTLSAuths.List, func(page []*kong.MTLSAuth) error {
+		return s.emit(ctx, &utils.KongRawState{MTLSAuths: page})
+	})
+	if err != nil && !kong.IsNotFoundErr(err) {
+		if kongErr, ok := errors.AsType[*kong.APIError](err); !ok || kongErr.Code() != http.StatusForbidden {
+			return fmt.Errorf("mtls-auths: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *streamer) proxyConfiguration(ctx context.Context) error {
+	if s.checkpoint.done("proxy") {
+		return nil
+	}
+	var raw utils.KongRawState
+	group, groupCtx := errgroup.WithContext(ctx)
+	if s.config.Concurrency > 0 {
+		group.SetLimit(s.config.Concurrency)
+	}
+	getProxyConfiguration(groupCtx, group, s.client, s.config, &raw)
+	if err := group.Wait(); err != nil {
+		return err
+	}
+	// the configuration of the proxy is the last page: it is not kept with
+	// the checkpoint, which is removed once the page is passed to fn.
+	if err := s.send(ctx, &raw); err != nil {
+		return err
+	}
+	return s.checkpoint.record("proxy", "")
+}
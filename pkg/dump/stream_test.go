@@ -0,0 +1,305 @@
+package dump
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/kong/go-database-reconciler/pkg/state"
+	"github.com/kong/go-database-reconciler/pkg/utils"
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeKong serves paginated lists of entities, and an empty list for any
+// endpoint without entities.
+type fakeKong struct {
+	entities map[string][]map[string]any
+	// failures is the number of requests to fail with a 503, by path.
+	failures map[string]int
+	delay    time.Duration
+
+	mu          sync.Mutex
+	pageSizes   map[string][]int
+	inFlight    int
+	maxInFlight int
+}
+
+func (f *fakeKong) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.inFlight++
+	f.maxInFlight = max(f.maxInFlight, f.inFlight)
+	fail := f.failures[r.URL.Path] > 0
+	if fail {
+		f.failures[r.URL.Path]--
+	}
+	f.mu.Unlock()
+	defer func() {
+		f.mu.Lock()
+		f.inFlight--
+		f.mu.Unlock()
+	}()
+	time.Sleep(f.delay)
+
+	w.Header().Set("Content-Type", "application/json")
+	if fail {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"message": "unavailable"}`))
+		return
+	}
+	if r.URL.Path == "/" {
+		_, _ = w.Write([]byte(`{"version": "3.9.0"}`))
+		return
+	}
+	entities := f.entities[r.URL.Path]
+	size, _ := strconv.Atoi(r.URL.Query().Get("size"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	end := min(offset+size, len(entities))
+	resp := map[string]any{"data": entities[offset:end]}
+	if end < len(entities) {
+		resp["offset"] = strconv.Itoa(end)
+	}
+	f.mu.Lock()
+	if f.pageSizes == nil {
+		f.pageSizes = map[string][]int{}
+	}
+	f.pageSizes[r.URL.Path] = append(f.pageSizes[r.URL.Path], end-offset)
+	f.mu.Unlock()
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func newFakeKong(t *testing.T) (*fakeKong, *kong.Client) {
+	t.Helper()
+	f := &fakeKong{failures: map[string]int{}, entities: map[string][]map[string]any{
+		"/services": {{"id": "s1", "name": "svc", "host": "example.com"}},
+		"/plugins": {{
+			"id": "p1", "name": "rate-limiting", "consumer": map[string]any{"id": "c3"},
+			"config": map[string]any{"minute": 10},
+		}},
+	}}
+	for i := range 5 {
+		f.entities["/consumers"] = append(f.entities["/consumers"], map[string]any{
+			"id": fmt.Sprintf("c%d", i), "username": fmt.Sprintf("user-%d", i),
+		})
+		f.entities["/key-auths"] = append(f.entities["/key-auths"], map[string]any{
+			"id": fmt.Sprintf("k%d", i), "key": fmt.Sprintf("key-%d", i),
+			"consumer": map[string]any{"id": fmt.Sprintf("c%d", i)},
+		})
+	}
+	server := httptest.NewServer(f)
+	t.Cleanup(server.Close)
+	client, err := kong.NewClient(new(server.URL), server.Client())
+	require.NoError(t, err)
+	return f, client
+}
+
+func TestStream(t *testing.T) {
+	f, client := newFakeKong(t)
+
+	var pages []*utils.KongRawState
+	builder, err := state.NewBuilder()
+	require.NoError(t, err)
+	err = Stream(context.Background(), client, Config{PageSize: 2}, func(page *utils.KongRawState) error {
+		pages = append(pages, page)
+		return builder.Add(page)
+	})
+	require.NoError(t, err)
+
+	// 3 pages of consumers, 3 pages of key-auths and all other entities.
+	require.Len(t, pages, 7)
+	for _, page := range pages[:3] {
+		assert.NotEmpty(t, page.Consumers)
+	}
+	for _, page := range pages[3:6] {
+		assert.NotEmpty(t, page.KeyAuths)
+		assert.Empty(t, page.Consumers)
+	}
+	assert.Len(t, pages[6].Services, 1)
+	assert.Len(t, pages[6].Plugins, 1)
+	assert.Equal(t, []int{2, 2, 1}, f.pageSizes["/consumers"])
+
+	ks := builder.State()
+	keyAuths, err := ks.KeyAuths.GetAll()
+	require.NoError(t, err)
+	assert.Len(t, keyAuths, 5)
+	plugin, err := ks.Plugins.Get("p1")
+	require.NoError(t, err)
+	assert.Equal(t, "user-3", *plugin.Consumer.Username)
+
+	t.Run("page size defaults to DefaultPageSize", func(t *testing.T) {
+		f, client := newFakeKong(t)
+		var count int
+		for page, err := range Pages(context.Background(), client, Config{}) {
+			require.NoError(t, err)
+			require.NotNil(t, page)
+			count++
+		}
+		assert.Equal(t, 3, count)
+		assert.Equal(t, []int{5}, f.pageSizes["/consumers"])
+	})
+
+	t.Run("iteration can stop early", func(t *testing.T) {
+		f, client := newFakeKong(t)
+		for page, err := range Pages(context.Background(), client, Config{PageSize: 2}) {
+			require.NoError(t, err)
+			assert.Len(t, page.Consumers, 2)
+			break
+		}
+		assert.Equal(t, []int{2}, f.pageSizes["/consumers"])
+		assert.Empty(t, f.pageSizes["/key-auths"])
+	})
+
+	t.Run("errors are returned", func(t *testing.T) {
+		_, client := newFakeKong(t)
+		err := Stream(context.Background(), client, Config{PageSize: -1}, nil)
+		require.EqualError(t, err, "dump: config: PageSize must not be negative")
+
+		err = Stream(context.Background(), client, Config{}, func(*utils.KongRawState) error {
+			return errors.New("boom")
+		})
+		require.EqualError(t, err, "consumers: boom")
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+		client, err = kong.NewClient(new(server.URL), server.Client())
+		require.NoError(t, err)
+		var errs []error
+		for page, err := range Pages(context.Background(), client, Config{}) {
+			assert.Nil(t, page)
+			errs = append(errs, err)
+		}
+		require.Len(t, errs, 1)
+		require.ErrorContains(t, errs[0], "consumers: HTTP status 500")
+	})
+}
+
+func TestStreamRetriesPages(t *testing.T) {
+	newPageBackOff = func() backoff.BackOff { return &backoff.ZeroBackOff{} }
+	t.Cleanup(func() { newPageBackOff = defaultPageBackOff })
+
+	f, client := newFakeKong(t)
+	f.failures["/consumers"] = 2
+	f.failures["/services"] = 1
+	var consumers int
+	err := Stream(context.Background(), client, Config{PageRetries: 2}, func(page *utils.KongRawState) error {
+		consumers += len(page.Consumers)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 5, consumers)
+
+	f.failures["/consumers"] = 3
+	err = Stream(context.Background(), client, Config{PageRetries: 2}, func(*utils.KongRawState) error {
+		return nil
+	})
+	require.ErrorContains(t, err, "consumers: HTTP status 503")
+
+	t.Run("Get retries pages", func(t *testing.T) {
+		f, client := newFakeKong(t)
+		f.failures["/key-auths"] = 1
+		raw, err := Get(context.Background(), client, Config{PageRetries: 1})
+		require.NoError(t, err)
+		assert.Len(t, raw.KeyAuths, 5)
+
+		f.failures["/key-auths"] = 1
+		_, err = Get(context.Background(), client, Config{})
+		require.ErrorContains(t, err, "key-auths: HTTP status 503")
+	})
+}
+
+func TestStreamResumesFromCheckpoint(t *testing.T) {
+	f, client := newFakeKong(t)
+	checkpointFile := filepath.Join(t.TempDir(), "dump.checkpoint")
+	config := Config{PageSize: 2, CheckpointFile: checkpointFile, SelectorTags: []string{"team-a"}}
+
+	var keys []string
+	err := Stream(context.Background(), client, config, func(page *utils.KongRawState) error {
+		if len(page.KeyAuths) > 0 && len(keys) == 2 {
+			return errors.New("boom")
+		}
+		for _, k := range page.KeyAuths {
+			keys = append(keys, *k.Key)
+		}
+		return nil
+	})
+	require.EqualError(t, err, "key-auths: boom")
+
+	checkpoint, err := ReadCheckpoint(checkpointFile)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"consumers", "consumer-groups"}, checkpoint.Completed)
+	assert.Equal(t, "key-auths", checkpoint.Kind)
+	assert.Equal(t, "2", checkpoint.Offset)
+
+	require.ErrorContains(t, Stream(context.Background(), client, Config{CheckpointFile: checkpointFile}, nil),
+		"was recorded by a dump selecting other entities")
+
+	// the resumed dump passes the pages kept with the checkpoint first, so
+	// that it can be consumed from scratch, e.g. by another process.
+	f.pageSizes = nil
+	keys = nil
+	var consumers, services int
+	err = Stream(context.Background(), client, config, func(page *utils.KongRawState) error {
+		for _, k := range page.KeyAuths {
+			keys = append(keys, *k.Key)
+		}
+		consumers += len(page.Consumers)
+		services += len(page.Services)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"key-0", "key-1", "key-2", "key-3", "key-4"}, keys)
+	assert.Equal(t, 5, consumers)
+	assert.Equal(t, 1, services)
+	assert.Empty(t, f.pageSizes["/consumers"])
+	assert.NoFileExists(t, checkpointFile)
+	assert.NoFileExists(t, checkpointFile+".pages")
+}
+
+func TestGetResumesFromCheckpoint(t *testing.T) {
+	f, client := newFakeKong(t)
+	checkpointFile := filepath.Join(t.TempDir(), "dump.checkpoint")
+	config := Config{PageSize: 2, CheckpointFile: checkpointFile}
+
+	f.failures["/hmac-auths"] = 1
+	_, err := Get(context.Background(), client, config)
+	require.ErrorContains(t, err, "hmac-auths: HTTP status 503")
+	checkpoint, err := ReadCheckpoint(checkpointFile)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"consumers", "consumer-groups", "key-auths"}, checkpoint.Completed)
+
+	f.pageSizes = nil
+	raw, err := Get(context.Background(), client, config)
+	require.NoError(t, err)
+	assert.Len(t, raw.Consumers, 5)
+	assert.Len(t, raw.KeyAuths, 5)
+	assert.Len(t, raw.Services, 1)
+	assert.Empty(t, f.pageSizes["/consumers"])
+	assert.Empty(t, f.pageSizes["/key-auths"])
+	assert.NoFileExists(t, checkpointFile)
+
+	_, err = Get(context.Background(), client, Config{RBACResourcesOnly: true, CheckpointFile: checkpointFile})
+	require.EqualError(t, err, "dump: config: CheckpointFile cannot be set when RBACResourcesOnly is set")
+}
+
+func TestGetConcurrency(t *testing.T) {
+	f, client := newFakeKong(t)
+	f.delay = 10 * time.Millisecond
+	_, err := Get(context.Background(), client, Config{Concurrency: 2})
+	require.NoError(t, err)
+	assert.LessOrEqual(t, f.maxInFlight, 2)
+
+	_, err = Get(context.Background(), client, Config{Concurrency: -1})
+	require.EqualError(t, err, "dump: config: Concurrency and PageRetries must not be negative")
+}
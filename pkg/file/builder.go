@@ -7,15 +7,18 @@ import (
 	"net/netip"
 	"reflect"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 
 	"github.com/blang/semver/v4"
+	"github.com/kong/go-database-reconciler/pkg/atc"
 	"github.com/kong/go-database-reconciler/pkg/konnect"
 	"github.com/kong/go-database-reconciler/pkg/schema"
 	"github.com/kong/go-database-reconciler/pkg/state"
 	"github.com/kong/go-database-reconciler/pkg/utils"
 	"github.com/kong/go-kong/kong"
+	"github.com/kong/go-kong/kong/custom"
 )
 
 const (
@@ -23,10 +26,6 @@ const (
 	openIDConnectPluginName        = "openid-connect"
 )
 
-var openIDConnectRequiredConfigFields = []string{
-	"cache_tokens_salt",
-}
-
 const (
 	primaryRelationConsumer      = "consumer"
 	primaryRelationConsumerGroup = "consumer-group"
@@ -43,6 +42,7 @@ type stateBuilder struct {
 	kongVersion     semver.Version
 
 	selectTags               []string
+	selectTagExpression      *utils.TagExpression
 	lookupTagsConsumerGroups []string
 	lookupTagsConsumers      []string
 	lookupTagsRoutes         []string
@@ -51,6 +51,9 @@ type stateBuilder struct {
 	skipCACerts              bool
 	skipDefaults             bool
 	includeLicenses          bool
+	includeWorkspaces        bool
+	includeEventHooks        bool
+	customEntityTypes        []string
 	intermediate             *state.KongState
 
 	client *kong.Client
@@ -63,6 +66,9 @@ type stateBuilder struct {
 	isKonnect bool
 
 	checkRoutePaths bool
+	// checkRouteConflicts enables the analysis of the traditional routes
+	// for conflicting and unreachable routes.
+	checkRouteConflicts bool
 
 	isConsumerGroupScopedPluginSupported bool
 
@@ -76,6 +82,9 @@ type stateBuilder struct {
 
 	skipHashForBasicAuth bool
 	diagnosticPolicy     utils.DiagnosticPolicy
+	// linter runs the lint rules against the target state, in place of the
+	// checks built into the builder.
+	linter Linter
 	// Track consumer IDs to avoid duplicates in rawState
 	consumerIDsInRawState map[string]bool
 
@@ -158,6 +167,12 @@ func (b *stateBuilder) build() (*utils.KongRawState, *utils.KonnectRawState, err
 
 	// konnect
 	b.konnect()
+	b.portals()
+	b.apiProducts()
+	b.controlPlanes()
+
+	b.checkSelectTagExpression()
+	b.lint()
 
 	// result
 	if b.err != nil {
@@ -166,6 +181,84 @@ func (b *stateBuilder) build() (*utils.KongRawState, *utils.KonnectRawState, err
 	return b.rawState, b.konnectRawState, nil
 }
 
+// checkSelectTagExpression ensures all entities of the target state, once
+// stamped with the required tags of the select tag expression, are selected by
+// the expression. Other entities would not be seen by subsequent syncs.
+func (b *stateBuilder) checkSelectTagExpression() {
+	if b.err != nil || b.selectTagExpression == nil {
+		return
+	}
+
+	var unselected []string
+	for _, entity := range taggableEntities(b.rawState) {
+		obj := reflect.ValueOf(entity)
+		if obj.IsNil() || !obj.Elem().FieldByName("Tags").IsValid() ||
+			b.selectTagExpression.MatchesObject(entity) {
+			continue
+		}
+		name := obj.Elem().Type().Name()
+		for _, field := range []string{"Name", "Username", "ID"} {
+			if v := obj.Elem().FieldByName(field); v.Kind() == reflect.Pointer && !v.IsNil() {
+				name += " " + v.Elem().String()
+				break
+			}
+		}
+		unselected = append(unselected, strings.ToLower(name[:1])+name[1:])
+	}
+	if len(unselected) > 0 {
+		b.err = fmt.Errorf("%d entities are not selected by the select tags expression %q: %s",
+			len(unselected), b.selectTagExpression.String(), strings.Join(unselected, ", "))
+	}
+}
+
+// appendEntities appends entities to res.
+func appendEntities[T any](res []any, entities []*T) []any {
+	for _, e := range entities {
+		res = append(res, e)
+	}
+	return res
+}
+
+// taggableEntities returns the entities of raw which can be tagged, as
+// pointers to their struct.
+func taggableEntities(raw *utils.KongRawState) []any {
+	var res []any
+	res = appendEntities(res, raw.Services)
+	res = appendEntities(res, raw.Routes)
+	res = appendEntities(res, raw.Plugins)
+	res = appendEntities(res, raw.FilterChains)
+	res = appendEntities(res, raw.Upstreams)
+	res = appendEntities(res, raw.Targets)
+	res = appendEntities(res, raw.Certificates)
+	res = appendEntities(res, raw.SNIs)
+	res = appendEntities(res, raw.CACertificates)
+	res = appendEntities(res, raw.Consumers)
+	for _, cg := range raw.ConsumerGroups {
+		if cg != nil {
+			res = append(res, cg.ConsumerGroup)
+		}
+	}
+	res = appendEntities(res, raw.Vaults)
+	res = appendEntities(res, raw.Partials)
+	res = appendEntities(res, raw.KeyAuths)
+	res = appendEntities(res, raw.HMACAuths)
+	res = appendEntities(res, raw.JWTAuths)
+	res = appendEntities(res, raw.BasicAuths)
+	res = appendEntities(res, raw.ACLGroups)
+	res = appendEntities(res, raw.Oauth2Creds)
+	res = appendEntities(res, raw.MTLSAuths)
+	res = appendEntities(res, raw.DegraphqlRoutes)
+	res = appendEntities(res, raw.GraphqlRateLimitingCostDecorations)
+	res = appendEntities(res, raw.RBACRoles)
+	res = appendEntities(res, raw.RBACUsers)
+	res = appendEntities(res, raw.Admins)
+	res = appendEntities(res, raw.Keys)
+	res = appendEntities(res, raw.KeySets)
+	res = appendEntities(res, raw.ClonedPluginDefinitions)
+	res = appendEntities(res, raw.CustomPluginDefinitions)
+	return res
+}
+
 func (b *stateBuilder) keys() {
 	if b.err != nil {
 		return
@@ -1317,6 +1410,227 @@ func (b *stateBuilder) konnect() {
 	}
 }
 
+func (b *stateBuilder) portals() {
+	if b.err != nil {
+		return
+	}
+
+	for _, p := range b.targetContent.Portals {
+		portal := p.Portal.DeepCopy()
+		if utils.Empty(portal.Name) {
+			b.err = fmt.Errorf("portal name is required")
+			return
+		}
+		if utils.Empty(portal.ID) {
+			current, err := b.currentState.Portals.Get(*portal.Name)
+			if errors.Is(err, state.ErrNotFound) {
+				portal.ID = uuid()
+			} else if err != nil {
+				b.err = err
+				return
+			} else {
+				portal.ID = new(*current.ID)
+			}
+		}
+		b.konnectRawState.Portals = append(b.konnectRawState.Portals, portal)
+	}
+}
+
+// portalID returns the ID of the portal named or identified by nameOrID,
+// looking it up in the target state first.
+func (b *stateBuilder) portalID(nameOrID string) (string, error) {
+	for _, p := range b.konnectRawState.Portals {
+		if *p.ID == nameOrID || *p.Name == nameOrID {
+			return *p.ID, nil
+		}
+	}
+	current, err := b.currentState.Portals.Get(nameOrID)
+	if err != nil {
+		return "", err
+	}
+	return *current.ID, nil
+}
+
+// serviceID returns the ID of the service named or identified by nameOrID in
+// the target state.
+func (b *stateBuilder) serviceID(nameOrID string) (string, error) {
+	for _, s := range b.rawState.Services {
+		if kong.StringValue(s.ID) == nameOrID || kong.StringValue(s.Name) == nameOrID {
+			return *s.ID, nil
+		}
+	}
+	return "", state.ErrNotFound
+}
+
+func (b *stateBuilder) controlPlanes() {
+	if b.err != nil {
+		return
+	}
+
+	for _, c := range b.targetContent.ControlPlanes {
+		if utils.Empty(c.Name) {
+			b.err = fmt.Errorf("control-plane name is required")
+			return
+		}
+		cp := &konnect.ControlPlaneV2{
+			ID:          c.ID,
+			Name:        c.Name,
+			Description: c.Description,
+			Labels:      c.Labels,
+		}
+		if c.ClusterType != nil || c.AuthType != nil {
+			cp.Config = &konnect.ControlPlaneConfig{
+				ClusterType: c.ClusterType,
+				AuthType:    c.AuthType,
+			}
+		}
+		if utils.Empty(cp.ID) {
+			current, err := b.currentState.ControlPlanes.Get(*cp.Name)
+			if errors.Is(err, state.ErrNotFound) {
+				cp.ID = uuid()
+			} else if err != nil {
+				b.err = err
+				return
+			} else {
+				cp.ID = new(*current.ID)
+			}
+		}
+		if len(c.Members) > 0 && !cp.IsGroup() {
+			b.err = fmt.Errorf("control-plane %q has members but its cluster_type is not %q",
+				*cp.Name, konnect.ClusterTypeControlPlaneGroup)
+			return
+		}
+		b.konnectRawState.ControlPlanes = append(b.konnectRawState.ControlPlanes, cp)
+	}
+
+	// members are resolved once all control planes of the file have an ID
+	for i, c := range b.targetContent.ControlPlanes {
+		cp := b.konnectRawState.ControlPlanes[i]
+		for _, member := range c.Members {
+			id, err := b.controlPlaneID(member)
+			if err != nil {
+				b.err = fmt.Errorf("member %q of control-plane %q: %w", member, *cp.Name, err)
+				return
+			}
+			cp.Members = append(cp.Members, id)
+		}
+	}
+}
+
+// controlPlaneID returns the ID of the control plane named or identified by
+// nameOrID, looking it up in the target state first.
+func (b *stateBuilder) controlPlaneID(nameOrID string) (string, error) {
+	for _, cp := range b.konnectRawState.ControlPlanes {
+		if *cp.ID == nameOrID || *cp.Name == nameOrID {
+			return *cp.ID, nil
+		}
+	}
+	current, err := b.currentState.ControlPlanes.Get(nameOrID)
+	if err != nil {
+		return "", err
+	}
+	return *current.ID, nil
+}
+
+func (b *stateBuilder) apiProducts() {
+	if b.err != nil {
+		return
+	}
+
+	for _, targetProduct := range b.targetContent.APIProducts {
+		if utils.Empty(targetProduct.Name) {
+			b.err = fmt.Errorf("api-product name is required")
+			return
+		}
+		product := &konnect.APIProduct{
+			ID:          targetProduct.ID,
+			Name:        targetProduct.Name,
+			Description: targetProduct.Description,
+			Labels:      targetProduct.Labels,
+		}
+		if utils.Empty(product.ID) {
+			current, err := b.currentState.APIProducts.Get(*product.Name)
+			if errors.Is(err, state.ErrNotFound) {
+				product.ID = uuid()
+			} else if err != nil {
+				b.err = err
+				return
+			} else {
+				product.ID = new(*current.ID)
+			}
+		}
+		for _, portal := range targetProduct.Portals {
+			id, err := b.portalID(portal)
+			if err != nil {
+				b.err = fmt.Errorf("portal %q of api-product %q: %w", portal, *product.Name, err)
+				return
+			}
+			product.PortalIDs = append(product.PortalIDs, id)
+		}
+		b.konnectRawState.APIProducts = append(b.konnectRawState.APIProducts, product)
+
+		for _, targetVersion := range targetProduct.Versions {
+			if utils.Empty(targetVersion.Name) {
+				b.err = fmt.Errorf("name of api-product-version of api-product %q is required", *product.Name)
+				return
+			}
+			version := &konnect.APIProductVersion{
+				ID:         targetVersion.ID,
+				Name:       targetVersion.Name,
+				APIProduct: product,
+			}
+			if utils.Empty(version.ID) {
+				current, err := b.currentState.APIProductVersions.Get(*product.ID, *version.Name)
+				if errors.Is(err, state.ErrNotFound) {
+					version.ID = uuid()
+				} else if err != nil {
+					b.err = err
+					return
+				} else {
+					version.ID = new(*current.ID)
+				}
+			}
+			if !utils.Empty(targetVersion.GatewayService) {
+				id, err := b.serviceID(*targetVersion.GatewayService)
+				if err != nil {
+					b.err = fmt.Errorf("service %q of api-product-version %q: %w",
+						*targetVersion.GatewayService, *version.Name, err)
+					return
+				}
+				version.GatewayService = &konnect.GatewayServiceRef{ID: &id}
+			}
+			b.konnectRawState.APIProductVersions = append(b.konnectRawState.APIProductVersions, version)
+		}
+
+		for _, targetDoc := range targetProduct.Documents {
+			if utils.Empty(targetDoc.Slug) {
+				b.err = fmt.Errorf("slug of api-product-document of api-product %q is required", *product.Name)
+				return
+			}
+			doc := &konnect.APIProductDocument{
+				ID:         targetDoc.ID,
+				Title:      targetDoc.Title,
+				Slug:       targetDoc.Slug,
+				Status:     targetDoc.Status,
+				Content:    targetDoc.Content,
+				APIProduct: product,
+			}
+			if utils.Empty(doc.ID) {
+				current, err := b.currentState.APIProductDocuments.Get(*product.ID, *doc.Slug)
+				if errors.Is(err, state.ErrNotFound) {
+					doc.ID = uuid()
+				} else if err != nil {
+					b.err = err
+					return
+				} else {
+					doc.ID = new(*current.ID)
+				}
+			}
+			b.konnectRawState.APIProductDocuments = append(b.konnectRawState.APIProductDocuments, doc)
+		}
+	}
+}
+
 func (b *stateBuilder) services() {
 	if b.err != nil {
 		return
@@ -1461,6 +1775,11 @@ func (b *stateBuilder) routes() {
 		}
 	}
 
+	// routes are checked by the rules of the linter instead, if any.
+	if b.linter != nil {
+		return
+	}
+
 	// check routes' paths format
 	if b.checkRoutePaths {
 		unsupportedRoutes := []string{}
@@ -1488,10 +1807,84 @@ func (b *stateBuilder) routes() {
 			}
 		}
 	}
+	// route conflicts are reported whatever the version of Kong: paths
+	// without the regex marker are compared as plain prefixes.
+	if b.checkRouteConflicts {
+		if b.err = b.analyzeRoutes(); b.err != nil {
+			return
+		}
+	}
+	b.err = b.validateRouteExpressions()
+}
+
+// validateRouteExpressions reports expression routes whose expression
+// cannot be parsed or is not valid for the expressions router schema.
+func (b *stateBuilder) validateRouteExpressions() error {
+	allRoutes, err := b.intermediate.Routes.GetAll()
+	if err != nil {
+		return err
+	}
+	var invalidRoutes []string
+	for _, r := range allRoutes {
+		if utils.Empty(r.Expression) {
+			continue
+		}
+		if err := atc.Validate(*r.Expression); err != nil {
+			invalidRoutes = append(invalidRoutes, fmt.Sprintf("route %s: %v", r.Console(), err))
+		}
+	}
+	if len(invalidRoutes) == 0 {
+		return nil
+	}
+	return b.emitDiagnostic(utils.DiagnosticCodeRouteExpression,
+		routeAnalysisMessage(utils.DiagnosticCodeRouteExpression, invalidRoutes))
+}
+
+// analyzeRoutes reports routes which conflict with each other or can never
+// be matched given the traditional router's evaluation order.
+func (b *stateBuilder) analyzeRoutes() error {
+	diagnostics, err := state.AnalyzeRoutes(b.intermediate.Routes)
+	if err != nil {
+		return err
+	}
+	byCode := map[utils.DiagnosticCode][]string{}
+	for _, d := range diagnostics {
+		byCode[d.Code] = append(byCode[d.Code], d.String())
+	}
+	for _, code := range []utils.DiagnosticCode{
+		utils.DiagnosticCodeRouteConflict,
+		utils.DiagnosticCodeRouteUnreachable,
+	} {
+		if len(byCode[code]) == 0 {
+			continue
+		}
+		if err := b.emitDiagnostic(code, routeAnalysisMessage(code, byCode[code])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var routeProblemKinds = map[utils.DiagnosticCode]string{
+	utils.DiagnosticCodeRouteConflict:    "conflicting routes",
+	utils.DiagnosticCodeRouteUnreachable: "unreachable routes",
+	utils.DiagnosticCodeRouteExpression:  "routes with an invalid expression",
+}
+
+func routeAnalysisMessage(code utils.DiagnosticCode, problems []string) string {
+	problemsLen := len(problems)
+	// do not consider more than 10 sample problems to print out.
+	if problemsLen > 10 {
+		problems = problems[:10]
+	}
+	return fmt.Sprintf("%d %s were detected. Some of these are (not an exhaustive list):\n\n%s",
+		problemsLen, routeProblemKinds[code], strings.Join(problems, "\n"))
 }
 
 func (b *stateBuilder) enterprise() {
 	b.rbacRoles()
+	b.rbacUsers()
+	b.admins()
 	b.vaults()
 	b.customEntities()
 	// In Konnect, licenses are managed by Konnect cloud,
@@ -1499,6 +1892,121 @@ func (b *stateBuilder) enterprise() {
 	if b.includeLicenses && !b.isKonnect {
 		b.licenses()
 	}
+	// Konnect has no workspaces.
+	if b.includeWorkspaces && !b.isKonnect {
+		b.workspaces()
+	}
+	// Konnect has no event hooks either.
+	if b.includeEventHooks && !b.isKonnect {
+		b.eventHooks()
+	}
+}
+
+// eventHookSources returns the sources of event hooks of Kong, or nil if
+// they cannot be listed, in which case only the handlers of event hooks are
+// validated.
+func (b *stateBuilder) eventHookSources() (utils.EventHookSources, error) {
+	if b.client == nil {
+		return nil, nil
+	}
+	sources, err := utils.ListEventHookSources(b.ctx, b.client)
+	if kong.IsNotFoundErr(err) || kong.IsForbiddenErr(err) {
+		return nil, nil
+	}
+	return sources, err
+}
+
+func (b *stateBuilder) eventHooks() {
+	if b.err != nil || len(b.targetContent.EventHooks) == 0 {
+		return
+	}
+	// Event hooks cannot be tagged, so they would not be seen by subsequent
+	// syncs limited to the select tags.
+	if len(b.selectTags) > 0 || b.selectTagExpression != nil {
+		b.err = fmt.Errorf("event hooks cannot be tagged and cannot be synced with select_tags")
+		return
+	}
+
+	sources, err := b.eventHookSources()
+	if err != nil {
+		b.err = fmt.Errorf("listing event hook sources: %w", err)
+		return
+	}
+	currentHooks, err := b.currentState.EventHooks.GetAll()
+	if err != nil {
+		b.err = err
+		return
+	}
+	declared := make(map[string]bool)
+	for _, h := range b.targetContent.EventHooks {
+		if !utils.Empty(h.ID) {
+			declared[*h.ID] = true
+		}
+	}
+
+	for i := range b.targetContent.EventHooks {
+		// the IDs are only set on the hooks of the raw state, leaving the
+		// content of the caller untouched
+		h := b.targetContent.EventHooks[i].DeepCopy()
+		if err := utils.ValidateEventHook(&h.EventHook, sources); err != nil {
+			b.err = fmt.Errorf("event hook %s: %w", h.sortKey(), err)
+			return
+		}
+		// Event hooks have no unique field: the ID of the only current event
+		// hook with the same source, event and handler is reused, if any.
+		if utils.Empty(h.ID) {
+			var matches []*state.EventHook
+			for _, current := range currentHooks {
+				if !declared[*current.ID] && sameEventHookTrigger(&h.EventHook, &current.EventHook) {
+					matches = append(matches, current)
+				}
+			}
+			if len(matches) == 1 {
+				h.ID = new(*matches[0].ID)
+				h.CreatedAt = matches[0].CreatedAt
+			} else {
+				h.ID = uuid()
+			}
+			declared[*h.ID] = true
+		}
+
+		b.rawState.EventHooks = append(b.rawState.EventHooks, &h.EventHook)
+	}
+}
+
+// sameEventHookTrigger returns true if event hooks h1 and h2 run the same
+// handler on the same event.
+func sameEventHookTrigger(h1, h2 *utils.EventHook) bool {
+	equal := func(a, b *string) bool {
+		return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+	}
+	return equal(h1.Source, h2.Source) && equal(h1.Event, h2.Event) && equal(h1.Handler, h2.Handler)
+}
+
+func (b *stateBuilder) workspaces() {
+	if b.err != nil {
+		return
+	}
+
+	for _, w := range b.targetContent.Workspaces {
+		if utils.Empty(w.Name) {
+			b.err = fmt.Errorf("workspace name is required")
+			return
+		}
+		if utils.Empty(w.ID) {
+			workspace, err := b.currentState.Workspaces.Get(*w.Name)
+			if errors.Is(err, state.ErrNotFound) {
+				w.ID = uuid()
+			} else if err != nil {
+				b.err = err
+				return
+			} else {
+				w.ID = new(*workspace.ID)
+			}
+		}
+
+		b.rawState.Workspaces = append(b.rawState.Workspaces, &w.Workspace)
+	}
 }
 
 func (b *stateBuilder) vaults() {
@@ -1569,6 +2077,100 @@ func (b *stateBuilder) rbacRoles() {
 			ep.Role = &kong.RBACRole{ID: new(*r.ID)}
 			b.rawState.RBACEndpointPermissions = append(b.rawState.RBACEndpointPermissions, &ep.RBACEndpointPermission)
 		}
+		// rbac entity permissions for the role
+		for _, ep := range r.EntityPermissions {
+			if utils.Empty(ep.EntityID) {
+				b.err = fmt.Errorf("rbac role %q: entity_id is required in entity permissions", *r.Name)
+				return
+			}
+			ep.Role = &kong.RBACRole{ID: new(*r.ID)}
+			b.rawState.RBACEntityPermissions = append(b.rawState.RBACEntityPermissions, &ep.RBACEntityPermission)
+		}
+	}
+}
+
+// checkRBACRoles returns an error if one of roles, assigned to the RBAC user
+// or admin entity, is not declared in the rbac_roles of the target content.
+func (b *stateBuilder) checkRBACRoles(entity string, roles []string) error {
+	for _, role := range roles {
+		if !slices.ContainsFunc(b.targetContent.RBACRoles, func(r FRBACRole) bool {
+			return r.Name != nil && *r.Name == role
+		}) {
+			return fmt.Errorf("%s: role %q is not declared in rbac_roles", entity, role)
+		}
+	}
+	return nil
+}
+
+func (b *stateBuilder) rbacUsers() {
+	if b.err != nil {
+		return
+	}
+
+	for _, u := range b.targetContent.RBACUsers {
+		if utils.Empty(u.Name) {
+			b.err = fmt.Errorf("rbac user name is required")
+			return
+		}
+		if err := b.checkRBACRoles(fmt.Sprintf("rbac user %q", *u.Name), u.Roles); err != nil {
+			b.err = err
+			return
+		}
+		user, err := b.currentState.RBACUsers.Get(*u.Name)
+		if utils.Empty(u.ID) {
+			if errors.Is(err, state.ErrNotFound) {
+				u.ID = uuid()
+			} else if err != nil {
+				b.err = err
+				return
+			} else {
+				u.ID = new(*user.ID)
+			}
+		}
+		if user != nil {
+			u.CreatedAt = user.CreatedAt
+		}
+		b.rawState.RBACUsers = append(b.rawState.RBACUsers, &u.RBACUser)
+		if b.rawState.RBACUserRoles == nil {
+			b.rawState.RBACUserRoles = map[string][]string{}
+		}
+		b.rawState.RBACUserRoles[*u.ID] = u.Roles
+	}
+}
+
+func (b *stateBuilder) admins() {
+	if b.err != nil {
+		return
+	}
+
+	for _, a := range b.targetContent.Admins {
+		if utils.Empty(a.Username) {
+			b.err = fmt.Errorf("admin username is required")
+			return
+		}
+		if err := b.checkRBACRoles(fmt.Sprintf("admin %q", *a.Username), a.Roles); err != nil {
+			b.err = err
+			return
+		}
+		admin, err := b.currentState.Admins.Get(*a.Username)
+		if utils.Empty(a.ID) {
+			if errors.Is(err, state.ErrNotFound) {
+				a.ID = uuid()
+			} else if err != nil {
+				b.err = err
+				return
+			} else {
+				a.ID = new(*admin.ID)
+			}
+		}
+		if admin != nil {
+			a.CreatedAt = admin.CreatedAt
+		}
+		b.rawState.Admins = append(b.rawState.Admins, &a.Admin)
+		if b.rawState.AdminRoles == nil {
+			b.rawState.AdminRoles = map[string][]string{}
+		}
+		b.rawState.AdminRoles[*a.ID] = a.Roles
 	}
 }
 
@@ -1811,60 +2413,22 @@ func (b *stateBuilder) filterChains() {
 }
 
 func (b *stateBuilder) validatePlugin(p FPlugin) error {
-	if b.isConsumerGroupScopedPluginSupported && *p.Name == ratelimitingAdvancedPluginName {
-		// check if deprecated consumer-groups configuration is present in the config
-		var consumerGroupsFound bool
-		if groups, ok := p.Config["consumer_groups"]; ok {
-			// if groups is an array of length > 0, then consumer_groups is set
-			if groupsArray, ok := groups.([]any); ok && len(groupsArray) > 0 {
-				consumerGroupsFound = true
-			}
-		}
-		var enforceConsumerGroupsFound bool
-		if enforceConsumerGroups, ok := p.Config["enforce_consumer_groups"]; ok {
-			if enforceConsumerGroupsBool, ok := enforceConsumerGroups.(bool); ok && enforceConsumerGroupsBool {
-				enforceConsumerGroupsFound = true
-			}
-		}
-		if consumerGroupsFound || enforceConsumerGroupsFound {
-			return b.emitDiagnostic(utils.DiagnosticCodeRLAConsumerGroups, utils.ErrorConsumerGroupUpgrade.Error())
-		}
+	// plugins are checked by the rules of the linter instead, if any.
+	if b.linter != nil {
+		return nil
+	}
+	if b.isConsumerGroupScopedPluginSupported && *p.Name == ratelimitingAdvancedPluginName &&
+		utils.UsesRLAConsumerGroups(p.Config) {
+		return b.emitDiagnostic(utils.DiagnosticCodeRLAConsumerGroups, utils.ErrorConsumerGroupUpgrade.Error())
 	}
 	if p.Name != nil && *p.Name == openIDConnectPluginName {
-		var missingFields []string
-		for _, field := range openIDConnectRequiredConfigFields {
-			value, ok := p.Config[field]
-			if !ok || isEmptyPluginConfigValue(value) {
-				missingFields = append(missingFields, field)
-			}
-		}
-		if len(missingFields) > 0 {
-			return b.emitDiagnostic(
-				utils.DiagnosticCodeOIDCMissingConfig,
-				fmt.Sprintf(
-					"openid-connect plugin requires explicit non-empty config values for %s "+
-						"to avoid regenerating session credentials during sync",
-					strings.Join(missingFields, ", "),
-				),
-			)
+		if missingFields := utils.MissingOpenIDConnectConfig(p.Config); len(missingFields) > 0 {
+			return b.emitDiagnostic(utils.DiagnosticCodeOIDCMissingConfig, utils.OpenIDConnectMissingConfigMessage(missingFields))
 		}
 	}
 	return nil
 }
 
-func isEmptyPluginConfigValue(value any) bool {
-	switch v := value.(type) {
-	case nil:
-		return true
-	case string:
-		return v == ""
-	case *string:
-		return v == nil || *v == ""
-	default:
-		return false
-	}
-}
-
 // strip_path schema default value is 'true', but it cannot be set when
 // protocols include 'grpc' and/or 'grpcs'. When users explicitly set
 // strip_path to 'true' with grpc/s protocols, deck returns a schema violation error.
@@ -2266,7 +2830,9 @@ func (b *stateBuilder) customEntities() {
 
 	var customEntities []FCustomEntity
 	for _, e := range b.targetContent.CustomEntities {
-		if !supportedCustomEntities[*e.Type] {
+		// Other custom entities are handled generically, as long as their
+		// type is dumped so that they are matched with the ones of Kong.
+		if !supportedCustomEntities[*e.Type] && !slices.Contains(b.customEntityTypes, *e.Type) {
 			b.err = fmt.Errorf("custom entity %v is not supported", *e.Type)
 			return
 		}
@@ -2284,8 +2850,139 @@ func (b *stateBuilder) ingestCustomEntities(customEntities []FCustomEntity) {
 			b.ingestDeGraphqlRoute(e)
 		case graphqlRateLimitingCostDecorationsType:
 			b.ingestGraphqlRateLimitingCostDecoration(e)
+		default:
+			b.ingestCustomEntity(e)
+		}
+	}
+}
+
+// customEntityDefinition returns the definition of the custom entities of
+// type entityType, derived from their schema.
+func (b *stateBuilder) customEntityDefinition(entityType string) (*schema.EntityDefinition, error) {
+	if b.schemaRegistry == nil {
+		if b.client == nil {
+			return nil, fmt.Errorf("custom entity %v is not supported without a Kong client", entityType)
+		}
+		b.schemaRegistry = schema.NewRegistry(b.client, b.isKonnect)
+	}
+	return b.schemaRegistry.GetEntityDefinition(b.ctx, entityType)
+}
+
+// customEntityReferenceID returns the ID of the entity of type reference
+// referenced by value, a foreign key of a custom entity given by id or, for
+// the entities having one, by name.
+func (b *stateBuilder) customEntityReferenceID(reference string, value any) (string, error) {
+	ref, ok := value.(map[string]any)
+	if !ok {
+		return "", fmt.Errorf("reference to %s should be a map with an id or a name", reference)
+	}
+	if id, ok := ref["id"].(string); ok && id != "" {
+		return id, nil
+	}
+	name, ok := ref["name"].(string)
+	if !ok || name == "" {
+		return "", fmt.Errorf("reference to %s should be a map with an id or a name", reference)
+	}
+
+	var id *string
+	var err error
+	switch reference {
+	case "services":
+		var s *state.Service
+		if s, err = b.intermediate.Services.Get(name); err == nil {
+			id = s.ID
+		}
+	case "routes":
+		var r *state.Route
+		if r, err = b.intermediate.Routes.Get(name); err == nil {
+			id = r.ID
+		}
+	case "consumers":
+		var c *state.Consumer
+		if c, err = b.intermediate.Consumers.GetByIDOrUsername(name); err == nil {
+			id = c.ID
+		}
+	case "consumer_groups":
+		var cg *state.ConsumerGroup
+		if cg, err = b.intermediate.ConsumerGroups.Get(name); err == nil {
+			id = cg.ID
+		}
+	case "key_sets":
+		var ks *state.KeySet
+		if ks, err = b.intermediate.KeySets.Get(name); err == nil {
+			id = ks.ID
+		}
+	case "partials":
+		var p *state.Partial
+		if p, err = b.intermediate.Partials.Get(name); err == nil {
+			id = p.ID
+		}
+	default:
+		return "", fmt.Errorf("%s cannot be referenced by name, only by id", reference)
+	}
+	if errors.Is(err, state.ErrNotFound) {
+		return "", fmt.Errorf("%s %v not found", reference, name)
+	}
+	if err != nil {
+		return "", err
+	}
+	return *id, nil
+}
+
+// ingestCustomEntity ingests e, a custom entity of a type handled generically
+// from its schema. Its foreign keys are resolved to references by ID and, if it
+// has no ID, the ID of the current entity with the same natural key is reused.
+func (b *stateBuilder) ingestCustomEntity(e FCustomEntity) {
+	if b.err != nil {
+		return
+	}
+	definition, err := b.customEntityDefinition(*e.Type)
+	if err != nil {
+		b.err = fmt.Errorf("custom entity %v: %w", *e.Type, err)
+		return
+	}
+
+	fields := kong.Configuration(e.Fields).DeepCopy()
+	if fields == nil {
+		fields = kong.Configuration{}
+	}
+	for field, reference := range definition.ForeignKeys {
+		if fields[field] == nil {
+			continue
+		}
+		id, err := b.customEntityReferenceID(reference, fields[field])
+		if err != nil {
+			b.err = fmt.Errorf("custom entity %v: %s: %w", *e.Type, field, err)
+			return
+		}
+		fields[field] = map[string]any{"id": id}
+	}
+
+	id := e.ID
+	if utils.Empty(id) {
+		if key, ok := definition.NaturalKeyOf(fields); ok {
+			current, err := b.currentState.CustomEntities.GetByNaturalKey(*e.Type, key)
+			if err == nil {
+				id = new(current.ID)
+			} else if !errors.Is(err, state.ErrNotFound) {
+				b.err = err
+				return
+			}
+		}
+		if utils.Empty(id) {
+			id = uuid()
 		}
 	}
+
+	object := custom.Object(fields)
+	object["id"] = *id
+	entity := custom.NewEntityObject(custom.Type(*e.Type))
+	entity.SetObject(object)
+	b.rawState.CustomEntities = append(b.rawState.CustomEntities, entity)
+	if b.rawState.CustomEntityDefinitions == nil {
+		b.rawState.CustomEntityDefinitions = map[string]*schema.EntityDefinition{}
+	}
+	b.rawState.CustomEntityDefinitions[*e.Type] = definition
 }
 
 func (b *stateBuilder) ingestDeGraphqlRoute(degraphqlRouteEntity FCustomEntity) {
@@ -3,13 +3,17 @@ package file
 import (
 	"context"
 	"encoding/hex"
+	"encoding/json"
 	"math/rand"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"reflect"
 	"testing"
 
 	"github.com/blang/semver/v4"
 	"github.com/kong/go-database-reconciler/pkg/konnect"
+	"github.com/kong/go-database-reconciler/pkg/schema"
 	"github.com/kong/go-database-reconciler/pkg/state"
 	"github.com/kong/go-database-reconciler/pkg/utils"
 	"github.com/kong/go-kong/kong"
@@ -766,6 +770,66 @@ func Test_stateBuilder_services(t *testing.T) {
 	}
 }
 
+func Test_stateBuilder_selectTagExpression(t *testing.T) {
+	testRand = rand.New(rand.NewSource(42))
+	content := func(services ...FService) *Content {
+		return &Content{Info: &Info{Defaults: kongDefaults}, Services: services}
+	}
+
+	b := &stateBuilder{
+		targetContent:       content(FService{Service: kong.Service{Name: new("foo")}}),
+		currentState:        emptyState(),
+		selectTags:          []string{"managed"},
+		selectTagExpression: utils.MustParseTagExpression("managed AND NOT legacy"),
+	}
+	rawState, _, err := b.build()
+	require.NoError(t, err)
+	assert.Equal(t, kong.StringSlice("managed"), rawState.Services[0].Tags)
+
+	b = &stateBuilder{
+		targetContent: content(
+			FService{Service: kong.Service{Name: new("foo")}},
+			FService{Service: kong.Service{Name: new("bar"), Tags: kong.StringSlice("legacy")}},
+		),
+		currentState:        emptyState(),
+		selectTags:          []string{"managed"},
+		selectTagExpression: utils.MustParseTagExpression("managed AND NOT legacy"),
+	}
+	_, _, err = b.build()
+	require.EqualError(t, err,
+		`1 entities are not selected by the select tags expression "managed AND NOT legacy": service bar`)
+
+	// plugins are checked too, although they reference a consumer group.
+	pluginContent := content()
+	pluginContent.Plugins = []FPlugin{
+		{Plugin: kong.Plugin{Name: new("key-auth"), Tags: kong.StringSlice("legacy")}},
+	}
+	b = &stateBuilder{
+		targetContent:       pluginContent,
+		currentState:        emptyState(),
+		selectTags:          []string{"managed"},
+		selectTagExpression: utils.MustParseTagExpression("managed AND NOT legacy"),
+	}
+	_, _, err = b.build()
+	require.EqualError(t, err,
+		`1 entities are not selected by the select tags expression "managed AND NOT legacy": plugin key-auth`)
+
+	// the roles of RBAC users are not entities
+	rbacContent := content()
+	rbacContent.RBACRoles = []FRBACRole{{RBACRole: kong.RBACRole{Name: new("deployer")}}}
+	rbacContent.RBACUsers = []FRBACUser{
+		{RBACUser: kong.RBACUser{Name: new("ci")}, Roles: []string{"deployer"}},
+	}
+	b = &stateBuilder{
+		targetContent:       rbacContent,
+		currentState:        emptyState(),
+		selectTags:          []string{"managed"},
+		selectTagExpression: utils.MustParseTagExpression("managed AND NOT legacy"),
+	}
+	_, _, err = b.build()
+	require.NoError(t, err)
+}
+
 func Test_stateBuilder_ingestRoute(t *testing.T) {
 	testRand = rand.New(rand.NewSource(42))
 	type fields struct {
@@ -6870,3 +6934,384 @@ func Test_stateBuilder_customPluginDefinitions(t *testing.T) {
 		})
 	}
 }
+
+func Test_stateBuilder_apiProducts(t *testing.T) {
+	testRand = rand.New(rand.NewSource(42))
+	ctx := context.Background()
+	currentState, err := state.NewKongState()
+	require.NoError(t, err)
+	require.NoError(t, currentState.Portals.Add(state.Portal{
+		Portal: konnect.Portal{ID: new("portal-id"), Name: new("dev")},
+	}))
+	require.NoError(t, currentState.APIProducts.Add(state.APIProduct{
+		APIProduct: konnect.APIProduct{ID: new("product-id"), Name: new("foo")},
+	}))
+	require.NoError(t, currentState.APIProductDocuments.Add(state.APIProductDocument{
+		APIProductDocument: konnect.APIProductDocument{
+			ID:         new("document-id"),
+			Slug:       new("intro"),
+			APIProduct: &konnect.APIProduct{ID: new("product-id")},
+		},
+	}))
+
+	b := &stateBuilder{
+		targetContent: &Content{
+			Services: []FService{
+				{Service: kong.Service{ID: new("service-id"), Name: new("svc1"), Host: new("example.com")}},
+			},
+			Portals: []FPortal{{Portal: konnect.Portal{Name: new("dev")}}},
+			APIProducts: []FAPIProduct{
+				{
+					Name:    new("foo"),
+					Portals: []string{"dev"},
+					Versions: []FAPIProductVersion{
+						{Name: new("v1"), GatewayService: new("svc1")},
+					},
+					Documents: []FAPIProductDocument{
+						{Slug: new("intro"), Title: new("Introduction"), Content: new("# Hello")},
+					},
+				},
+			},
+		},
+		currentState: currentState,
+	}
+	d, _ := utils.GetDefaulter(ctx, defaulterTestOpts)
+	b.defaulter = d
+	_, konnectRawState, err := b.build()
+	require.NoError(t, err)
+
+	require.Len(t, konnectRawState.Portals, 1)
+	assert.Equal(t, "portal-id", *konnectRawState.Portals[0].ID)
+
+	require.Len(t, konnectRawState.APIProducts, 1)
+	product := konnectRawState.APIProducts[0]
+	assert.Equal(t, "product-id", *product.ID)
+	assert.Equal(t, []string{"portal-id"}, product.PortalIDs)
+
+	require.Len(t, konnectRawState.APIProductVersions, 1)
+	version := konnectRawState.APIProductVersions[0]
+	assert.NotEmpty(t, *version.ID)
+	assert.Same(t, product, version.APIProduct)
+	assert.Equal(t, &konnect.GatewayServiceRef{ID: new("service-id")}, version.GatewayService)
+
+	require.Len(t, konnectRawState.APIProductDocuments, 1)
+	assert.Equal(t, "document-id", *konnectRawState.APIProductDocuments[0].ID)
+
+	b.targetContent.APIProducts[0].Portals = []string{"missing"}
+	_, _, err = b.build()
+	require.ErrorIs(t, err, state.ErrNotFound)
+}
+
+func Test_stateBuilder_controlPlanes(t *testing.T) {
+	testRand = rand.New(rand.NewSource(42))
+	ctx := context.Background()
+	currentState, err := state.NewKongState()
+	require.NoError(t, err)
+	require.NoError(t, currentState.ControlPlanes.Add(state.ControlPlane{
+		ControlPlaneV2: konnect.ControlPlaneV2{ID: new("us-id"), Name: new("us")},
+	}))
+
+	b := &stateBuilder{
+		targetContent: &Content{
+			ControlPlanes: []FControlPlane{
+				{
+					Name:        new("global"),
+					ClusterType: new(konnect.ClusterTypeControlPlaneGroup),
+					Members:     []string{"us", "eu"},
+				},
+				{Name: new("eu"), Labels: map[string]string{"region": "eu"}},
+			},
+		},
+		currentState: currentState,
+	}
+	d, _ := utils.GetDefaulter(ctx, defaulterTestOpts)
+	b.defaulter = d
+	_, konnectRawState, err := b.build()
+	require.NoError(t, err)
+
+	require.Len(t, konnectRawState.ControlPlanes, 2)
+	group, eu := konnectRawState.ControlPlanes[0], konnectRawState.ControlPlanes[1]
+	assert.True(t, group.IsGroup())
+	assert.NotEmpty(t, *eu.ID)
+	// members are resolved against the file first, then the current state
+	assert.Equal(t, []string{"us-id", *eu.ID}, group.Members)
+	assert.Nil(t, eu.Config)
+
+	b.targetContent.ControlPlanes[0].ClusterType = new(konnect.ClusterTypeHybrid)
+	_, _, err = b.build()
+	require.ErrorContains(t, err, `control-plane "global" has members`)
+}
+
+func Test_stateBuilder_workspaces(t *testing.T) {
+	testRand = rand.New(rand.NewSource(42))
+	ctx := context.Background()
+	currentState, err := state.NewKongState()
+	require.NoError(t, err)
+	require.NoError(t, currentState.Workspaces.Add(state.Workspace{
+		Workspace: kong.Workspace{ID: new("foo-id"), Name: new("foo")},
+	}))
+
+	b := &stateBuilder{
+		targetContent: &Content{
+			Workspaces: []FWorkspace{
+				{Workspace: kong.Workspace{Name: new("foo"), Comment: new("team foo")}},
+				{Workspace: kong.Workspace{
+					Name: new("bar"),
+					Meta: map[string]any{"color": "#ff0000"},
+				}},
+			},
+		},
+		currentState: currentState,
+	}
+	d, _ := utils.GetDefaulter(ctx, defaulterTestOpts)
+	b.defaulter = d
+
+	// workspaces are opt-in
+	rawState, _, err := b.build()
+	require.NoError(t, err)
+	assert.Empty(t, rawState.Workspaces)
+
+	b.includeWorkspaces = true
+	rawState, _, err = b.build()
+	require.NoError(t, err)
+	require.Len(t, rawState.Workspaces, 2)
+	// the ID of an existing workspace is reused
+	assert.Equal(t, "foo-id", *rawState.Workspaces[0].ID)
+	assert.Equal(t, "team foo", *rawState.Workspaces[0].Comment)
+	assert.NotEmpty(t, *rawState.Workspaces[1].ID)
+	assert.Equal(t, "#ff0000", rawState.Workspaces[1].Meta["color"])
+
+	b.targetContent.Workspaces = append(b.targetContent.Workspaces, FWorkspace{})
+	_, _, err = b.build()
+	require.EqualError(t, err, "workspace name is required")
+}
+
+func Test_stateBuilder_rbacUsersAndAdmins(t *testing.T) {
+	testRand = rand.New(rand.NewSource(42))
+	ctx := context.Background()
+	currentState, err := state.NewKongState()
+	require.NoError(t, err)
+	require.NoError(t, currentState.RBACUsers.Add(state.RBACUser{
+		RBACUser: kong.RBACUser{ID: new("user-id"), Name: new("ci")},
+	}))
+
+	b := &stateBuilder{
+		targetContent: &Content{
+			RBACRoles: []FRBACRole{
+				{
+					RBACRole: kong.RBACRole{Name: new("deployer")},
+					EntityPermissions: []*FRBACEntityPermission{
+						{RBACEntityPermission: kong.RBACEntityPermission{
+							EntityID:   new("service-id"),
+							EntityType: new("services"),
+							Actions:    kong.StringSlice("read", "update"),
+						}},
+					},
+				},
+			},
+			RBACUsers: []FRBACUser{
+				{RBACUser: kong.RBACUser{Name: new("ci"), UserToken: new("secret")}, Roles: []string{"deployer"}},
+			},
+			Admins: []FAdmin{
+				{Admin: kong.Admin{Username: new("alice")}, Roles: []string{"deployer"}},
+			},
+		},
+		currentState: currentState,
+	}
+	d, _ := utils.GetDefaulter(ctx, defaulterTestOpts)
+	b.defaulter = d
+
+	rawState, _, err := b.build()
+	require.NoError(t, err)
+	require.Len(t, rawState.RBACRoles, 1)
+	roleID := *rawState.RBACRoles[0].ID
+	require.Len(t, rawState.RBACEntityPermissions, 1)
+	assert.Equal(t, roleID, *rawState.RBACEntityPermissions[0].Role.ID)
+
+	// the ID of an existing user is reused
+	require.Len(t, rawState.RBACUsers, 1)
+	assert.Equal(t, "user-id", *rawState.RBACUsers[0].ID)
+	assert.Equal(t, map[string][]string{"user-id": {"deployer"}}, rawState.RBACUserRoles)
+
+	require.Len(t, rawState.Admins, 1)
+	adminID := *rawState.Admins[0].ID
+	assert.NotEmpty(t, adminID)
+	assert.Equal(t, map[string][]string{adminID: {"deployer"}}, rawState.AdminRoles)
+
+	content := b.targetContent.DeepCopy()
+	content.Admins[0].Roles = []string{"unknown"}
+	b = &stateBuilder{targetContent: content, currentState: currentState, defaulter: d}
+	_, _, err = b.build()
+	require.EqualError(t, err, `admin "alice": role "unknown" is not declared in rbac_roles`)
+
+	content = b.targetContent.DeepCopy()
+	content.Admins = nil
+	content.RBACRoles[0].EntityPermissions[0].EntityID = nil
+	b = &stateBuilder{targetContent: content, currentState: currentState, defaulter: d}
+	_, _, err = b.build()
+	require.EqualError(t, err, `rbac role "deployer": entity_id is required in entity permissions`)
+}
+
+func Test_stateBuilder_eventHooks(t *testing.T) {
+	testRand = rand.New(rand.NewSource(42))
+	ctx := context.Background()
+	currentState, err := state.NewKongState()
+	require.NoError(t, err)
+	require.NoError(t, currentState.EventHooks.Add(state.EventHook{
+		EventHook: utils.EventHook{
+			ID:      new("hook-id"),
+			Source:  new("crud"),
+			Event:   new("consumers"),
+			Handler: new("webhook"),
+			Config:  kong.Configuration{"url": "https://example.com/old"},
+		},
+	}))
+
+	b := &stateBuilder{
+		targetContent: &Content{
+			EventHooks: []FEventHook{
+				{EventHook: utils.EventHook{
+					Source:  new("crud"),
+					Event:   new("consumers"),
+					Handler: new("webhook"),
+					Config:  kong.Configuration{"url": "https://example.com/new"},
+				}},
+				{EventHook: utils.EventHook{
+					Source:  new("crud"),
+					Event:   new("consumers"),
+					Handler: new("webhook"),
+					Config:  kong.Configuration{"url": "https://example.com/other"},
+				}},
+			},
+		},
+		currentState:      currentState,
+		includeEventHooks: true,
+	}
+	d, _ := utils.GetDefaulter(ctx, defaulterTestOpts)
+	b.defaulter = d
+
+	rawState, _, err := b.build()
+	require.NoError(t, err)
+	require.Len(t, rawState.EventHooks, 2)
+	// the ID of the only current hook on the same event is reused, once
+	assert.Equal(t, "hook-id", *rawState.EventHooks[0].ID)
+	assert.NotEqual(t, "hook-id", *rawState.EventHooks[1].ID)
+	for _, h := range b.targetContent.EventHooks {
+		assert.Nil(t, h.ID)
+	}
+
+	content := b.targetContent.DeepCopy()
+	content.EventHooks[0].Handler = new("email")
+	b = &stateBuilder{targetContent: content, currentState: currentState, defaulter: d, includeEventHooks: true}
+	_, _, err = b.build()
+	require.ErrorContains(t, err, `invalid handler "email"`)
+
+	content = b.targetContent.DeepCopy()
+	content.EventHooks = content.EventHooks[1:]
+	b = &stateBuilder{
+		targetContent: content, currentState: currentState, defaulter: d,
+		selectTags: []string{"team-a"}, includeEventHooks: true,
+	}
+	_, _, err = b.build()
+	require.EqualError(t, err, "event hooks cannot be tagged and cannot be synced with select_tags")
+
+	// event hooks are ignored unless included
+	b = &stateBuilder{targetContent: content, currentState: currentState, defaulter: d}
+	rawState, _, err = b.build()
+	require.NoError(t, err)
+	assert.Empty(t, rawState.EventHooks)
+}
+
+func Test_stateBuilder_genericCustomEntities(t *testing.T) {
+	testRand = rand.New(rand.NewSource(42))
+	ctx := context.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/schemas/my_entities" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"primary_key":  []string{"id"},
+			"endpoint_key": "name",
+			"fields": []map[string]any{
+				{"id": map[string]any{"type": "string", "uuid": true}},
+				{"name": map[string]any{"type": "string", "unique": true}},
+				{"service": map[string]any{"type": "foreign", "reference": "services"}},
+				{"enabled": map[string]any{"type": "boolean", "default": true}},
+			},
+		})
+	}))
+	defer server.Close()
+	client, err := kong.NewClient(new(server.URL), server.Client())
+	require.NoError(t, err)
+
+	definition, err := schema.NewRegistry(client, false).GetEntityDefinition(ctx, "my_entities")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"name"}, definition.NaturalKey)
+	assert.Equal(t, map[string]string{"service": "services"}, definition.ForeignKeys)
+
+	currentState, err := state.NewKongState()
+	require.NoError(t, err)
+	require.NoError(t, currentState.CustomEntities.Add(state.CustomEntity{
+		Type:       "my_entities",
+		ID:         "entity-id",
+		Fields:     kong.Configuration{"name": "a", "enabled": true},
+		Definition: definition,
+	}))
+
+	targetContent := &Content{
+		Services: []FService{
+			{Service: kong.Service{ID: new(testServiceID), Name: new("svc1"), Host: new("example.com")}},
+		},
+		CustomEntities: []FCustomEntity{
+			{
+				Type: new("my_entities"),
+				Fields: CustomEntityConfiguration{
+					"name":    "a",
+					"service": map[string]any{"name": "svc1"},
+				},
+			},
+			{
+				Type:   new("my_entities"),
+				Fields: CustomEntityConfiguration{"name": "b"},
+			},
+		},
+	}
+	d, _ := utils.GetDefaulter(ctx, defaulterTestOpts)
+	b := &stateBuilder{
+		targetContent:     targetContent,
+		currentState:      currentState,
+		defaulter:         d,
+		client:            client,
+		customEntityTypes: []string{"my_entities"},
+	}
+	rawState, _, err := b.build()
+	require.NoError(t, err)
+	require.Len(t, rawState.CustomEntities, 2)
+	// the ID of the current entity with the same name is reused
+	assert.Equal(t, "entity-id", rawState.CustomEntities[0].Object()["id"])
+	assert.Equal(t, map[string]any{"id": testServiceID}, rawState.CustomEntities[0].Object()["service"])
+	assert.NotEqual(t, "entity-id", rawState.CustomEntities[1].Object()["id"])
+	assert.Equal(t, definition, rawState.CustomEntityDefinitions["my_entities"])
+
+	b = &stateBuilder{
+		targetContent: targetContent.DeepCopy(),
+		currentState:  currentState,
+		defaulter:     d,
+		client:        client,
+	}
+	_, _, err = b.build()
+	require.EqualError(t, err, "custom entity my_entities is not supported")
+
+	content := targetContent.DeepCopy()
+	content.CustomEntities[0].Fields["service"] = map[string]any{"name": "svc2"}
+	b = &stateBuilder{
+		targetContent:     content,
+		currentState:      currentState,
+		defaulter:         d,
+		client:            client,
+		customEntityTypes: []string{"my_entities"},
+	}
+	_, _, err = b.build()
+	require.EqualError(t, err, "custom entity my_entities: service: services svc2 not found")
+}
@@ -137,6 +137,12 @@ func main() {
 	// RBAC resources
 	schema.Definitions["FRBACRole"].Required = []string{nameField}
 	schema.Definitions["FRBACEndpointPermission"].Required = []string{"workspace", "endpoint"}
+	schema.Definitions["FRBACEntityPermission"].Required = []string{"entity_id", "entity_type"}
+	schema.Definitions["FRBACUser"].Required = []string{nameField}
+	schema.Definitions["FAdmin"].Required = []string{"username"}
+
+	// event hooks
+	schema.Definitions["FEventHook"].Required = []string{"source", "handler"}
 
 	// partials
 	schema.Definitions["FPartial"].Required = []string{"type"}
@@ -5,9 +5,40 @@ import (
 	"fmt"
 
 	"github.com/kong/go-database-reconciler/pkg/cprint"
+	"github.com/kong/go-database-reconciler/pkg/state"
 	"github.com/kong/go-database-reconciler/pkg/utils"
 )
 
+// Linter runs lint rules against the content of a file and the state built
+// out of it, such as the rules of a lint.Registry. It reports warnings itself
+// and returns an error if any diagnostic has error severity.
+// Diagnostics with a code in suppressed are not reported.
+type Linter interface {
+	Lint(content *Content, ks *state.KongState, policy utils.DiagnosticPolicy,
+		suppressed []utils.DiagnosticCode) error
+}
+
+// lint runs the linter of the builder, if any, against the target state.
+// Rules which do not apply to the version of Kong are suppressed.
+func (b *stateBuilder) lint() {
+	if b.err != nil || b.linter == nil {
+		return
+	}
+	ks, err := state.Get(b.rawState)
+	if err != nil {
+		b.err = fmt.Errorf("building state to lint: %w", err)
+		return
+	}
+	var suppressed []utils.DiagnosticCode
+	if !b.checkRoutePaths {
+		suppressed = append(suppressed, utils.DiagnosticCodeRouteRegexPathFormat)
+	}
+	if !b.isConsumerGroupScopedPluginSupported {
+		suppressed = append(suppressed, utils.DiagnosticCodeRLAConsumerGroups)
+	}
+	b.err = b.linter.Lint(b.targetContent, ks, b.diagnosticPolicy, suppressed)
+}
+
 func (b *stateBuilder) emitDiagnostic(code utils.DiagnosticCode, msg string) error {
 	if b.diagnosticPolicy.ResolveSeverity(code) == utils.SeverityWarning {
 		cprint.UpdatePrintlnStdErr(msg)
@@ -3,7 +3,9 @@ package file
 import (
 	"testing"
 
+	"github.com/kong/go-database-reconciler/pkg/state"
 	"github.com/kong/go-database-reconciler/pkg/utils"
+	"github.com/kong/go-kong/kong"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -39,3 +41,65 @@ func TestStateBuilderEmitException(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestStateBuilderAnalyzeRoutes(t *testing.T) {
+	ks, err := state.NewKongState()
+	require.NoError(t, err)
+	for _, id := range []string{"a", "b"} {
+		require.NoError(t, ks.Routes.Add(state.Route{Route: kong.Route{
+			ID: new(id), Name: new(id), Paths: []*string{new("/foo")},
+		}}))
+	}
+
+	b := &stateBuilder{intermediate: ks}
+	require.NoError(t, b.analyzeRoutes())
+
+	b.diagnosticPolicy = utils.NewDiagnosticPolicy([]utils.DiagnosticCode{utils.DiagnosticCodeRouteConflict}, nil)
+	err = b.analyzeRoutes()
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "warning (route-conflict): 1 conflicting routes were detected")
+	assert.ErrorContains(t, err, "route a and route b match the same requests")
+
+	// routes are analyzed only when enabled, whatever the version of Kong.
+	newBuilder := func(checkRouteConflicts bool) *stateBuilder {
+		currentState, err := state.NewKongState()
+		require.NoError(t, err)
+		return &stateBuilder{
+			targetContent: &Content{
+				Routes: []FRoute{
+					{Route: kong.Route{ID: new("a"), Name: new("a"), Paths: []*string{new("/foo")}}},
+					{Route: kong.Route{ID: new("b"), Name: new("b"), Paths: []*string{new("/foo")}}},
+				},
+			},
+			currentState:        currentState,
+			defaulter:           utils.NewNoOpDefaulter(),
+			checkRouteConflicts: checkRouteConflicts,
+			diagnosticPolicy: utils.NewDiagnosticPolicy(
+				[]utils.DiagnosticCode{utils.DiagnosticCodeRouteConflict}, nil),
+		}
+	}
+	_, _, err = newBuilder(false).build()
+	require.NoError(t, err)
+	_, _, err = newBuilder(true).build()
+	assert.ErrorContains(t, err, "route a and route b match the same requests")
+}
+
+func TestStateBuilderValidateRouteExpressions(t *testing.T) {
+	ks, err := state.NewKongState()
+	require.NoError(t, err)
+	require.NoError(t, ks.Routes.Add(state.Route{Route: kong.Route{
+		ID: new("valid"), Name: new("valid"), Expression: new(`http.path ^= "/foo"`),
+	}}))
+	require.NoError(t, ks.Routes.Add(state.Route{Route: kong.Route{
+		ID: new("invalid"), Name: new("invalid"), Expression: new(`http.pth ^= "/foo"`),
+	}}))
+
+	b := &stateBuilder{intermediate: ks}
+	require.NoError(t, b.validateRouteExpressions())
+
+	b.diagnosticPolicy = utils.NewDiagnosticPolicy([]utils.DiagnosticCode{utils.DiagnosticCodeRouteExpression}, nil)
+	err = b.validateRouteExpressions()
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "1 routes with an invalid expression were detected")
+	assert.ErrorContains(t, err, `route invalid: unknown field "http.pth" (at offset 0)`)
+}
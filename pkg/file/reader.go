@@ -26,6 +26,13 @@ type RenderConfig struct {
 	CurrentState     *state.KongState
 	KongVersion      semver.Version
 	DiagnosticPolicy utils.DiagnosticPolicy
+	// AnalyzeRoutes reports routes which conflict with each other or can
+	// never be matched. The analysis compares every pair of routes and is
+	// therefore disabled by default.
+	AnalyzeRoutes bool
+	// Linter, if set, runs its lint rules against the target state in place
+	// of the checks built into the builder.
+	Linter Linter
 }
 
 // GetContentFromFiles reads in a file with a slice of filenames and constructs
@@ -59,6 +66,17 @@ func GetContentFromFilesWithEnvVars(filenames []string, mode RenderEnvVarsMode)
 	return getContent(filenames, mode)
 }
 
+// GetContentByWorkspace reads state files like GetContentFromFilesWithEnvVars,
+// but instead of rejecting files targeting different workspaces, it merges
+// the files of each workspace into a separate Content keyed by _workspace.
+// Files without a _workspace are keyed by the empty string.
+func GetContentByWorkspace(filenames []string, mode RenderEnvVarsMode) (map[string]*Content, error) {
+	if len(filenames) == 0 {
+		return nil, ErrorFilenameEmpty
+	}
+	return getContentByWorkspace(filenames, mode)
+}
+
 // GetForKonnect processes the fileContent and renders a RawState and KonnectRawState
 func GetForKonnect(ctx context.Context, fileContent *Content,
 	opt RenderConfig, client *kong.Client,
@@ -72,7 +90,10 @@ func GetForKonnect(ctx context.Context, fileContent *Content,
 	builder.ctx = ctx
 	builder.disableDynamicDefaults = true
 	builder.includeLicenses = false
+	builder.includeWorkspaces = false
 	builder.diagnosticPolicy = opt.DiagnosticPolicy
+	builder.linter = opt.Linter
+	builder.checkRouteConflicts = opt.AnalyzeRoutes
 
 	if fileContent.Transform != nil && !*fileContent.Transform {
 		return nil, nil, ErrorTransformFalseNotSupported
@@ -100,17 +121,27 @@ func Get(ctx context.Context, fileContent *Content, opt RenderConfig, dumpConfig
 	builder.skipCACerts = dumpConfig.SkipCACerts
 	builder.isKonnect = dumpConfig.KonnectControlPlane != ""
 	builder.includeLicenses = dumpConfig.IncludeLicenses
+	builder.includeWorkspaces = dumpConfig.IncludeWorkspaces
+	builder.includeEventHooks = dumpConfig.IncludeEventHooks
+	builder.customEntityTypes = dumpConfig.CustomEntityTypes
 	builder.isPartialApply = dumpConfig.IsPartialApply
 	builder.isConsumerGroupPolicyOverrideSet = dumpConfig.IsConsumerGroupPolicyOverrideSet
 	builder.skipHashForBasicAuth = dumpConfig.SkipHashForBasicAuth
 	builder.skipDefaults = dumpConfig.SkipDefaults
 	builder.schemaRegistry = dumpConfig.SchemaRegistry
 	builder.diagnosticPolicy = dumpConfig.DiagnosticPolicy
+	builder.linter = opt.Linter
+	builder.checkRouteConflicts = opt.AnalyzeRoutes
 
 	if len(dumpConfig.SelectorTags) > 0 {
 		builder.selectTags = dumpConfig.SelectorTags
 	}
 
+	if dumpConfig.SelectorTagExpression != nil {
+		builder.selectTags = dumpConfig.SelectorTagExpression.RequiredTags()
+		builder.selectTagExpression = dumpConfig.SelectorTagExpression
+	}
+
 	if len(dumpConfig.LookUpSelectorTagsConsumers) > 0 {
 		builder.lookupTagsConsumers = dumpConfig.LookUpSelectorTagsConsumers
 	}
@@ -58,6 +58,51 @@ func getContent(filenames []string, mode RenderEnvVarsMode) (*Content, error) {
 	return &res, nil
 }
 
+// getContentByWorkspace is the same as getContent, but merges the content of
+// the files of each workspace separately.
+func getContentByWorkspace(filenames []string, mode RenderEnvVarsMode) (map[string]*Content, error) {
+	res := map[string]*Content{}
+	runtimeGroups := map[string][]string{}
+	var errs []error
+	for _, fileOrDir := range filenames {
+		readers, err := getReaders(fileOrDir)
+		if err != nil {
+			return nil, err
+		}
+
+		for filename, r := range readers {
+			content, err := readContent(r, mode)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("reading file %s: %w", filename, err))
+				continue
+			}
+			workspace := content.Workspace
+			if content.Konnect != nil && len(content.Konnect.RuntimeGroupName) > 0 {
+				runtimeGroups[workspace] = append(runtimeGroups[workspace], content.Konnect.RuntimeGroupName)
+			}
+			if res[workspace] == nil {
+				res[workspace] = &Content{}
+			}
+			err = mergo.Merge(res[workspace], content, mergo.WithAppendSlice)
+			if err != nil {
+				return nil, fmt.Errorf("merging file contents: %w", err)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return nil, utils.ErrArray{Errors: errs}
+	}
+	for workspace, content := range res {
+		if err := validateRuntimeGroups(runtimeGroups[workspace]); err != nil {
+			return nil, err
+		}
+		if err := validateEmptyContent(*content); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
 // getReaders returns back a map of filename:io.Reader representing all the
 // YAML and JSON files in a directory. If fileOrDir is a single file, then it
 // returns back the reader for the file.
@@ -685,3 +685,34 @@ versions:
 		t.Errorf("yamlUnmarshal() expected type: %T, got: %T", map[string]any{}, element)
 	}
 }
+
+func Test_getContentByWorkspace(t *testing.T) {
+	got, err := getContentByWorkspace([]string{"testdata/differentworkspace"}, EnvVarsExpand)
+	if err != nil {
+		t.Fatalf("getContentByWorkspace() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("getContentByWorkspace() returned %d workspaces, want 2", len(got))
+	}
+	for workspace, service := range map[string]string{"foo": "svc1", "bar": "svc2"} {
+		content := got[workspace]
+		if content == nil || content.Workspace != workspace {
+			t.Fatalf("missing content for workspace %s", workspace)
+		}
+		if len(content.Services) != 1 || *content.Services[0].Name != service {
+			t.Errorf("workspace %s: got services %v, want %s", workspace, content.Services, service)
+		}
+	}
+
+	got, err = getContentByWorkspace([]string{"testdata/sameworkspace"}, EnvVarsExpand)
+	if err != nil {
+		t.Fatalf("getContentByWorkspace() error = %v", err)
+	}
+	if len(got) != 1 || len(got["bar"].Services) != 2 {
+		t.Errorf("getContentByWorkspace() = %v, want both services merged in workspace bar", got)
+	}
+
+	if _, err := getContentByWorkspace([]string{"testdata/does-not-exist"}, EnvVarsExpand); err == nil {
+		t.Error("getContentByWorkspace() expected an error for a missing directory")
+	}
+}
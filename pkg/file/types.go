@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/kong/go-database-reconciler/pkg/konnect"
 	"github.com/kong/go-database-reconciler/pkg/utils"
 	"github.com/kong/go-kong/kong"
 )
@@ -749,6 +750,7 @@ func (u FConsumerGroupObject) sortKey() string {
 type FRBACRole struct {
 	kong.RBACRole       `yaml:",inline,omitempty"`
 	EndpointPermissions []*FRBACEndpointPermission `json:"endpoint_permissions,omitempty" yaml:"endpoint_permissions,omitempty"` //nolint:lll
+	EntityPermissions   []*FRBACEntityPermission   `json:"entity_permissions,omitempty" yaml:"entity_permissions,omitempty"`     //nolint:lll
 }
 
 // FRBACEndpointPermission is a wrapper type for RBACEndpointPermission.
@@ -783,6 +785,77 @@ func (frbac FRBACEndpointPermission) MarshalJSON() ([]byte, error) {
 	return json.Marshal(m)
 }
 
+// FRBACEntityPermission is a wrapper type for RBACEntityPermission.
+// +k8s:deepcopy-gen=true
+type FRBACEntityPermission struct {
+	kong.RBACEntityPermission `yaml:",inline,omitempty"`
+}
+
+func (frbac FRBACEntityPermission) MarshalJSON() ([]byte, error) {
+	m := map[string]any{}
+	if frbac.EntityID != nil {
+		m["entity_id"] = frbac.EntityID
+	}
+	if frbac.EntityType != nil {
+		m["entity_type"] = frbac.EntityType
+	}
+	if frbac.Actions != nil {
+		m["actions"] = frbac.Actions
+	}
+	if frbac.CreatedAt != nil {
+		m["created_at"] = frbac.CreatedAt
+	}
+	if frbac.Negative != nil {
+		m["negative"] = frbac.Negative
+	}
+	if frbac.Role != nil {
+		m["role"] = frbac.Role
+	}
+	if frbac.Comment != nil {
+		m["comment"] = frbac.Comment
+	}
+	return json.Marshal(m)
+}
+
+// FRBACUser represents an RBAC user in Kong Enterprise, along with the names
+// of its roles. Kong only returns a hash of the user_token: declaring the
+// user_token_ident Kong returns for it lets changes to the token be detected.
+// +k8s:deepcopy-gen=true
+type FRBACUser struct {
+	kong.RBACUser `yaml:",inline,omitempty"`
+	Roles         []string `json:"roles,omitempty" yaml:"roles,omitempty"`
+}
+
+// sortKey is used for sorting.
+func (u FRBACUser) sortKey() string {
+	if u.Name != nil {
+		return *u.Name
+	}
+	if u.ID != nil {
+		return *u.ID
+	}
+	return ""
+}
+
+// FAdmin represents a Kong Manager admin in Kong Enterprise, along with the
+// names of its roles.
+// +k8s:deepcopy-gen=true
+type FAdmin struct {
+	kong.Admin `yaml:",inline,omitempty"`
+	Roles      []string `json:"roles,omitempty" yaml:"roles,omitempty"`
+}
+
+// sortKey is used for sorting.
+func (a FAdmin) sortKey() string {
+	if a.Username != nil {
+		return *a.Username
+	}
+	if a.ID != nil {
+		return *a.ID
+	}
+	return ""
+}
+
 // KongDefaults represents default values that are filled in
 // for entities with corresponding missing properties.
 // +k8s:deepcopy-gen=true
@@ -797,13 +870,26 @@ type KongDefaults struct {
 // +k8s:deepcopy-gen=true
 type Info struct {
 	SelectorTags                 []string            `json:"select_tags,omitempty" yaml:"select_tags,omitempty"`
-	LookUpSelectorTags           *LookUpSelectorTags `json:"default_lookup_tags,omitempty" yaml:"default_lookup_tags,omitempty"` //nolint
+	SelectorTagsExpression       string              `json:"select_tags_expression,omitempty" yaml:"select_tags_expression,omitempty"` //nolint
+	LookUpSelectorTags           *LookUpSelectorTags `json:"default_lookup_tags,omitempty" yaml:"default_lookup_tags,omitempty"`       //nolint
 	Defaults                     KongDefaults        `json:"defaults" yaml:"defaults,omitempty"`
 	ConsumerGroupPolicyOverrides bool                `json:"consumer_group_policy_overrides,omitempty" yaml:"consumer_group_policy_overrides,omitempty"` //nolint
 	SkipHashForBasicAuth         bool                `json:"skip_hash_for_basic_auth,omitempty" yaml:"skip_hash_for_basic_auth,omitempty"`               //nolint
 	IncludePluginDefinitions     bool                `json:"include_plugin_definitions,omitempty" yaml:"include_plugin_definitions,omitempty"`           //nolint
 }
 
+// SelectorTagExpression returns the parsed select_tags_expression of the file,
+// or nil if it is not set.
+func (i *Info) SelectorTagExpression() (*utils.TagExpression, error) {
+	if i == nil || i.SelectorTagsExpression == "" {
+		return nil, nil
+	}
+	if len(i.SelectorTags) > 0 {
+		return nil, fmt.Errorf("select_tags and select_tags_expression cannot be set together")
+	}
+	return utils.ParseTagExpression(i.SelectorTagsExpression)
+}
+
 // LookUpSelectorTags contains tags to lookup
 // for corresponding entities already in Kong.
 // +k8s:deepcopy-gen=true
@@ -820,6 +906,9 @@ type LookUpSelectorTags struct {
 type Konnect struct {
 	RuntimeGroupName string `json:"runtime_group_name,omitempty" yaml:"runtime_group_name,omitempty"`
 	ControlPlaneName string `json:"control_plane_name,omitempty" yaml:"control_plane_name,omitempty"`
+	// ControlPlaneNames are the control planes the file is synced to, when it
+	// is synced to several control planes at once.
+	ControlPlaneNames []string `json:"control_plane_names,omitempty" yaml:"control_plane_names,omitempty"`
 }
 
 // Kong represents Kong implementation of a Service in Konnect.
@@ -925,9 +1014,82 @@ func (c FLicense) sortKey() string {
 	return ""
 }
 
+// FWorkspace represents a workspace in Kong Enterprise, along with its
+// comment, meta such as its color and thumbnail, and its config such as the
+// settings of its Dev Portal.
+type FWorkspace struct {
+	kong.Workspace `yaml:",inline,omitempty"`
+}
+
+// DeepCopyInto copies the receiver into out. It is written by hand since
+// go-kong does not generate deepcopy functions for kong.Workspace.
+func (w *FWorkspace) DeepCopyInto(out *FWorkspace) {
+	*out = *w
+	if w.CreatedAt != nil {
+		out.CreatedAt = new(*w.CreatedAt)
+	}
+	if w.ID != nil {
+		out.ID = new(*w.ID)
+	}
+	if w.Name != nil {
+		out.Name = new(*w.Name)
+	}
+	if w.Comment != nil {
+		out.Comment = new(*w.Comment)
+	}
+	if w.Config != nil {
+		out.Config = kong.Configuration(w.Config).DeepCopy()
+	}
+	if w.Meta != nil {
+		out.Meta = kong.Configuration(w.Meta).DeepCopy()
+	}
+}
+
+// DeepCopy copies the receiver, creating a new FWorkspace.
+func (w *FWorkspace) DeepCopy() *FWorkspace {
+	if w == nil {
+		return nil
+	}
+	out := new(FWorkspace)
+	w.DeepCopyInto(out)
+	return out
+}
+
+// sortKey is used for sorting.
+func (w FWorkspace) sortKey() string {
+	if w.Name != nil {
+		return *w.Name
+	}
+	if w.ID != nil {
+		return *w.ID
+	}
+	return ""
+}
+
+// FEventHook represents an event hook in Kong Enterprise.
+// +k8s:deepcopy-gen=true
+type FEventHook struct {
+	utils.EventHook `yaml:",inline,omitempty"`
+}
+
+// sortKey is used for sorting.
+func (h FEventHook) sortKey() string {
+	var key string
+	for _, field := range []*string{h.Source, h.Event, h.Handler, h.ID} {
+		if field != nil {
+			key += *field
+		}
+		key += ":"
+	}
+	return key
+}
+
 // This struct could be used for any custom entity for plugins
 // Based on "Type", the entity can be serialized into its
 // apt struct.
+// Types other than degraphql_routes and graphql_ratelimiting_cost_decorations
+// are handled generically from their schema: their fields are kept as is, but
+// for their foreign keys, which reference another entity by id or by name.
 // +k8s:deepcopy-gen=true
 type FCustomEntity struct {
 	ID     *string                   `json:"id,omitempty" yaml:"id,omitempty"`
@@ -983,7 +1145,7 @@ func (f *FCustomEntity) UnmarshalJSON(b []byte) error {
 		}
 		return copyToGqlRateLimitingCostDecoration(entity, f)
 	default:
-		return fmt.Errorf("unknown entity type: %s", temp["type"])
+		return copyToGenericFCustomEntity(temp, f)
 	}
 }
 
@@ -1004,10 +1166,41 @@ func (f *FCustomEntity) UnmarshalYAML(unmarshal func(any) error) error {
 		}
 		return copyFromGqlRateLimitingCostDecoration(entity, f)
 	default:
-		return fmt.Errorf("unknown entity type: %s", *f.Type)
+		var entity map[string]any
+		if err := unmarshal(&entity); err != nil {
+			return err
+		}
+		return copyToGenericFCustomEntity(entity, f)
 	}
 }
 
+// copyToGenericFCustomEntity copies entity, of a type handled generically from
+// its schema, to fcEntity. Its fields are kept as is.
+func copyToGenericFCustomEntity(entity map[string]any, fcEntity *FCustomEntity) error {
+	entityType, ok := entity["type"].(string)
+	if !ok {
+		return fmt.Errorf("type field should be a string")
+	}
+	fcEntity.Type = new(entityType)
+
+	if entity["id"] != nil {
+		id, ok := entity["id"].(string)
+		if !ok {
+			return fmt.Errorf("id field should be a string")
+		}
+		fcEntity.ID = new(id)
+	}
+
+	if entity["fields"] != nil {
+		fields, ok := entity["fields"].(map[string]any)
+		if !ok {
+			return fmt.Errorf("fields field should be a map")
+		}
+		fcEntity.Fields = fields
+	}
+	return nil
+}
+
 // sortKey is used for sorting.
 func (f FCustomEntity) sortKey() string {
 	if f.ID != nil {
@@ -1344,6 +1537,115 @@ func (f FCustomPluginDefinition) sortKey() string {
 	return ""
 }
 
+// FPortal represents a Dev Portal in Konnect.
+// +k8s:deepcopy-gen=true
+type FPortal struct {
+	konnect.Portal `yaml:",inline,omitempty"`
+}
+
+// FAPIProductVersion represents a version of an API product in Konnect.
+// +k8s:deepcopy-gen=true
+type FAPIProductVersion struct {
+	ID   *string `json:"id,omitempty" yaml:"id,omitempty"`
+	Name *string `json:"name,omitempty" yaml:"name,omitempty"`
+	// GatewayService is the name or ID of the Service implementing the
+	// version, in the control plane being synced.
+	GatewayService *string `json:"gateway_service,omitempty" yaml:"gateway_service,omitempty"`
+}
+
+// FAPIProductDocument represents a document of an API product in Konnect.
+// +k8s:deepcopy-gen=true
+type FAPIProductDocument struct {
+	ID      *string `json:"id,omitempty" yaml:"id,omitempty"`
+	Title   *string `json:"title,omitempty" yaml:"title,omitempty"`
+	Slug    *string `json:"slug,omitempty" yaml:"slug,omitempty"`
+	Status  *string `json:"status,omitempty" yaml:"status,omitempty"`
+	Content *string `json:"content,omitempty" yaml:"content,omitempty"`
+}
+
+// FAPIProduct represents an API product and its versions and documents.
+// +k8s:deepcopy-gen=true
+type FAPIProduct struct {
+	ID          *string           `json:"id,omitempty" yaml:"id,omitempty"`
+	Name        *string           `json:"name,omitempty" yaml:"name,omitempty"`
+	Description *string           `json:"description,omitempty" yaml:"description,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	// Portals are the names or IDs of the portals the API product is
+	// published to.
+	Portals   []string              `json:"portals,omitempty" yaml:"portals,omitempty"`
+	Versions  []FAPIProductVersion  `json:"versions,omitempty" yaml:"versions,omitempty"`
+	Documents []FAPIProductDocument `json:"documents,omitempty" yaml:"documents,omitempty"`
+}
+
+// FControlPlane represents a control plane or a control plane group in
+// Konnect.
+// +k8s:deepcopy-gen=true
+type FControlPlane struct {
+	ID          *string           `json:"id,omitempty" yaml:"id,omitempty"`
+	Name        *string           `json:"name,omitempty" yaml:"name,omitempty"`
+	Description *string           `json:"description,omitempty" yaml:"description,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	ClusterType *string           `json:"cluster_type,omitempty" yaml:"cluster_type,omitempty"`
+	AuthType    *string           `json:"auth_type,omitempty" yaml:"auth_type,omitempty"`
+	// Members are the names or IDs of the members of a control plane group.
+	Members []string `json:"members,omitempty" yaml:"members,omitempty"`
+}
+
+// sortKey is used for sorting.
+func (p FPortal) sortKey() string {
+	if p.Name != nil {
+		return *p.Name
+	}
+	if p.ID != nil {
+		return *p.ID
+	}
+	return ""
+}
+
+// sortKey is used for sorting.
+func (v FAPIProductVersion) sortKey() string {
+	if v.Name != nil {
+		return *v.Name
+	}
+	if v.ID != nil {
+		return *v.ID
+	}
+	return ""
+}
+
+// sortKey is used for sorting.
+func (d FAPIProductDocument) sortKey() string {
+	if d.Slug != nil {
+		return *d.Slug
+	}
+	if d.ID != nil {
+		return *d.ID
+	}
+	return ""
+}
+
+// sortKey is used for sorting.
+func (p FAPIProduct) sortKey() string {
+	if p.Name != nil {
+		return *p.Name
+	}
+	if p.ID != nil {
+		return *p.ID
+	}
+	return ""
+}
+
+// sortKey is used for sorting.
+func (c FControlPlane) sortKey() string {
+	if c.Name != nil {
+		return *c.Name
+	}
+	if c.ID != nil {
+		return *c.ID
+	}
+	return ""
+}
+
 //go:generate go run ./codegen/main.go
 
 // Content represents a serialized Kong state.
@@ -1366,15 +1668,26 @@ type Content struct {
 	CACertificates []FCACertificate       `json:"ca_certificates,omitempty" yaml:"ca_certificates,omitempty"`
 
 	RBACRoles []FRBACRole `json:"rbac_roles,omitempty" yaml:"rbac_roles,omitempty"`
+	RBACUsers []FRBACUser `json:"rbac_users,omitempty" yaml:"rbac_users,omitempty"`
+	Admins    []FAdmin    `json:"admins,omitempty" yaml:"admins,omitempty"`
 
 	PluginConfigs map[string]kong.Configuration `json:"_plugin_configs,omitempty" yaml:"_plugin_configs,omitempty"`
 
 	ServicePackages []FServicePackage `json:"service_packages,omitempty" yaml:"service_packages,omitempty"`
 
+	APIProducts []FAPIProduct `json:"api_products,omitempty" yaml:"api_products,omitempty"`
+	Portals     []FPortal     `json:"portals,omitempty" yaml:"portals,omitempty"`
+
+	ControlPlanes []FControlPlane `json:"control_planes,omitempty" yaml:"control_planes,omitempty"`
+
 	Vaults []FVault `json:"vaults,omitempty" yaml:"vaults,omitempty"`
 
 	Licenses []FLicense `json:"licenses,omitempty" yaml:"licenses,omitempty"`
 
+	Workspaces []FWorkspace `json:"workspaces,omitempty" yaml:"workspaces,omitempty"`
+
+	EventHooks []FEventHook `json:"event_hooks,omitempty" yaml:"event_hooks,omitempty"`
+
 	CustomEntities []FCustomEntity `json:"custom_entities,omitempty" yaml:"custom_entities,omitempty"`
 
 	Partials []FPartial `json:"partials,omitempty" yaml:"partials,omitempty"`
@@ -605,3 +605,19 @@ func Test_unwrapURL(t *testing.T) {
 		})
 	}
 }
+
+func TestInfoSelectorTagExpression(t *testing.T) {
+	var info *Info
+	expression, err := info.SelectorTagExpression()
+	require.NoError(t, err)
+	assert.Nil(t, expression)
+
+	info = &Info{SelectorTagsExpression: "team-a or team-b"}
+	expression, err = info.SelectorTagExpression()
+	require.NoError(t, err)
+	assert.Equal(t, "team-a OR team-b", expression.String())
+
+	info.SelectorTags = []string{"team-a"}
+	_, err = info.SelectorTagExpression()
+	require.EqualError(t, err, "select_tags and select_tags_expression cannot be set together")
+}
@@ -20,6 +20,7 @@ import (
 type WriteConfig struct {
 	Workspace                        string
 	SelectTags                       []string
+	SelectTagExpression              *utils.TagExpression
 	Filename                         string
 	FileFormat                       Format
 	WithID                           bool
@@ -134,6 +135,12 @@ func KongStateToContent(kongState *state.KongState, config WriteConfig) (*Conten
 		}
 	}
 
+	if config.SelectTagExpression != nil {
+		file.Info = &Info{
+			SelectorTagsExpression: config.SelectTagExpression.String(),
+		}
+	}
+
 	if config.IsConsumerGroupPolicyOverrideSet {
 		if file.Info == nil {
 			file.Info = &Info{
@@ -213,6 +220,26 @@ func KongStateToContent(kongState *state.KongState, config WriteConfig) (*Conten
 		return nil, err
 	}
 
+	err = populateWorkspaces(kongState, file, config)
+	if err != nil {
+		return nil, err
+	}
+
+	err = populateEventHooks(kongState, file)
+	if err != nil {
+		return nil, err
+	}
+
+	err = populateRBACUsers(kongState, file, config)
+	if err != nil {
+		return nil, err
+	}
+
+	err = populateAdmins(kongState, file, config)
+	if err != nil {
+		return nil, err
+	}
+
 	err = populateDegraphqlRoutes(kongState, file)
 	if err != nil {
 		return nil, err
@@ -223,6 +250,11 @@ func KongStateToContent(kongState *state.KongState, config WriteConfig) (*Conten
 		return nil, err
 	}
 
+	err = populateCustomEntities(kongState, file, config)
+	if err != nil {
+		return nil, err
+	}
+
 	err = populatePartials(kongState, file)
 	if err != nil {
 		return nil, err
@@ -268,6 +300,21 @@ func KonnectStateToFile(kongState *state.KongState, config WriteConfig) error {
 		return err
 	}
 
+	err = populatePortals(kongState, file, config)
+	if err != nil {
+		return err
+	}
+
+	err = populateAPIProducts(kongState, file, config)
+	if err != nil {
+		return err
+	}
+
+	err = populateControlPlanes(kongState, file, config)
+	if err != nil {
+		return err
+	}
+
 	// do not populate service-less routes
 	// we do not know if konnect supports these or not
 
@@ -396,6 +443,135 @@ func populateServicePackages(kongState *state.KongState, file *Content,
 	return nil
 }
 
+func populatePortals(kongState *state.KongState, file *Content,
+	config WriteConfig,
+) error {
+	portals, err := kongState.Portals.GetAll()
+	if err != nil {
+		return err
+	}
+	for _, p := range portals {
+		portal := FPortal{Portal: p.Portal}
+		utils.ZeroOutID(&portal, portal.Name, config.WithID)
+		file.Portals = append(file.Portals, portal)
+	}
+	sort.SliceStable(file.Portals, func(i, j int) bool {
+		return compareOrder(file.Portals[i], file.Portals[j])
+	})
+	return nil
+}
+
+func populateControlPlanes(kongState *state.KongState, file *Content,
+	config WriteConfig,
+) error {
+	controlPlanes, err := kongState.ControlPlanes.GetAll()
+	if err != nil {
+		return err
+	}
+	for _, cp := range controlPlanes {
+		c := FControlPlane{
+			ID:          cp.ID,
+			Name:        cp.Name,
+			Description: cp.Description,
+			Labels:      cp.Labels,
+		}
+		if cp.Config != nil {
+			c.ClusterType = cp.Config.ClusterType
+			c.AuthType = cp.Config.AuthType
+		}
+		for _, id := range cp.Members {
+			member, err := kongState.ControlPlanes.Get(id)
+			if err != nil {
+				return fmt.Errorf("member %q of control-plane %q: %w", id, *cp.Name, err)
+			}
+			c.Members = append(c.Members, *member.Name)
+		}
+		sort.Strings(c.Members)
+		utils.ZeroOutID(&c, c.Name, config.WithID)
+		file.ControlPlanes = append(file.ControlPlanes, c)
+	}
+	sort.SliceStable(file.ControlPlanes, func(i, j int) bool {
+		return compareOrder(file.ControlPlanes[i], file.ControlPlanes[j])
+	})
+	return nil
+}
+
+func populateAPIProducts(kongState *state.KongState, file *Content,
+	config WriteConfig,
+) error {
+	products, err := kongState.APIProducts.GetAll()
+	if err != nil {
+		return err
+	}
+	for _, ap := range products {
+		p := FAPIProduct{
+			ID:          ap.ID,
+			Name:        ap.Name,
+			Description: ap.Description,
+			Labels:      ap.Labels,
+		}
+		// portals are referred to by name
+		for _, id := range ap.PortalIDs {
+			portal, err := kongState.Portals.Get(id)
+			if err != nil {
+				return fmt.Errorf("portal %q of api-product %q: %w", id, *ap.Name, err)
+			}
+			p.Portals = append(p.Portals, *portal.Name)
+		}
+		sort.Strings(p.Portals)
+
+		versions, err := kongState.APIProductVersions.GetAllByAPIProductID(*ap.ID)
+		if err != nil {
+			return err
+		}
+		for _, v := range versions {
+			fVersion := FAPIProductVersion{
+				ID:   v.ID,
+				Name: v.Name,
+			}
+			if v.GatewayService != nil && !utils.Empty(v.GatewayService.ID) {
+				fVersion.GatewayService = v.GatewayService.ID
+				// services are referred to by name, if they are part of the state
+				s, err := kongState.Services.Get(*v.GatewayService.ID)
+				if err == nil && !utils.Empty(s.Name) {
+					fVersion.GatewayService = s.Name
+				}
+			}
+			utils.ZeroOutID(&fVersion, fVersion.Name, config.WithID)
+			p.Versions = append(p.Versions, fVersion)
+		}
+		sort.SliceStable(p.Versions, func(i, j int) bool {
+			return compareOrder(p.Versions[i], p.Versions[j])
+		})
+
+		documents, err := kongState.APIProductDocuments.GetAllByAPIProductID(*ap.ID)
+		if err != nil {
+			return err
+		}
+		for _, d := range documents {
+			fDocument := FAPIProductDocument{
+				ID:      d.ID,
+				Title:   d.Title,
+				Slug:    d.Slug,
+				Status:  d.Status,
+				Content: d.Content,
+			}
+			utils.ZeroOutID(&fDocument, fDocument.Slug, config.WithID)
+			p.Documents = append(p.Documents, fDocument)
+		}
+		sort.SliceStable(p.Documents, func(i, j int) bool {
+			return compareOrder(p.Documents[i], p.Documents[j])
+		})
+
+		utils.ZeroOutID(&p, p.Name, config.WithID)
+		file.APIProducts = append(file.APIProducts, p)
+	}
+	sort.SliceStable(file.APIProducts, func(i, j int) bool {
+		return compareOrder(file.APIProducts[i], file.APIProducts[j])
+	})
+	return nil
+}
+
 func populateServices(kongState *state.KongState, file *Content,
 	config WriteConfig,
 ) error {
@@ -941,6 +1117,19 @@ func populateConsumers(kongState *state.KongState, file *Content,
 			}
 			return strings.Compare(e1, e2) < 0
 		})
+		entityPermissions, err := kongState.RBACEntityPermissions.GetAllByRoleID(*r.ID)
+		if err != nil {
+			return err
+		}
+		for _, ep := range entityPermissions {
+			ep.Role = nil
+			utils.ZeroOutTimestamps(ep)
+			r.EntityPermissions = append(
+				r.EntityPermissions, &FRBACEntityPermission{RBACEntityPermission: ep.RBACEntityPermission})
+		}
+		sort.SliceStable(r.EntityPermissions, func(i, j int) bool {
+			return strings.Compare(*r.EntityPermissions[i].EntityID, *r.EntityPermissions[j].EntityID) < 0
+		})
 		utils.ZeroOutID(&r, r.Name, config.WithID)
 		utils.ZeroOutTimestamps(&r)
 		file.RBACRoles = append(file.RBACRoles, r)
@@ -1034,6 +1223,91 @@ func populateLicenses(kongState *state.KongState, file *Content,
 	return nil
 }
 
+func populateWorkspaces(kongState *state.KongState, file *Content,
+	config WriteConfig,
+) error {
+	workspaces, err := kongState.Workspaces.GetAll()
+	if err != nil {
+		return err
+	}
+	for _, w := range workspaces {
+		w := FWorkspace{Workspace: w.Workspace}
+		utils.ZeroOutID(&w, w.Name, config.WithID)
+		utils.ZeroOutTimestamps(&w)
+		file.Workspaces = append(file.Workspaces, w)
+	}
+	sort.SliceStable(file.Workspaces, func(i, j int) bool {
+		return compareOrder(file.Workspaces[i], file.Workspaces[j])
+	})
+	return nil
+}
+
+// populateEventHooks adds the event hooks to file. Their IDs are always kept,
+// since event hooks have no other unique field.
+func populateEventHooks(kongState *state.KongState, file *Content) error {
+	hooks, err := kongState.EventHooks.GetAll()
+	if err != nil {
+		return err
+	}
+	for _, h := range hooks {
+		h := FEventHook{EventHook: h.EventHook}
+		utils.ZeroOutTimestamps(&h)
+		file.EventHooks = append(file.EventHooks, h)
+	}
+	sort.SliceStable(file.EventHooks, func(i, j int) bool {
+		return compareOrder(file.EventHooks[i], file.EventHooks[j])
+	})
+	return nil
+}
+
+// populateRBACUsers adds the RBAC users to file, without their tokens: Kong
+// only returns a hash of them.
+func populateRBACUsers(kongState *state.KongState, file *Content,
+	config WriteConfig,
+) error {
+	users, err := kongState.RBACUsers.GetAll()
+	if err != nil {
+		return err
+	}
+	for _, u := range users {
+		u := FRBACUser{RBACUser: u.RBACUser, Roles: u.Roles}
+		u.UserToken = nil
+		u.UserTokenIdent = nil
+		sort.Strings(u.Roles)
+		utils.ZeroOutID(&u, u.Name, config.WithID)
+		utils.ZeroOutTimestamps(&u)
+		file.RBACUsers = append(file.RBACUsers, u)
+	}
+	sort.SliceStable(file.RBACUsers, func(i, j int) bool {
+		return compareOrder(file.RBACUsers[i], file.RBACUsers[j])
+	})
+	return nil
+}
+
+// populateAdmins adds the Kong Manager admins to file, without their
+// credentials.
+func populateAdmins(kongState *state.KongState, file *Content,
+	config WriteConfig,
+) error {
+	admins, err := kongState.Admins.GetAll()
+	if err != nil {
+		return err
+	}
+	for _, a := range admins {
+		a := FAdmin{Admin: a.Admin, Roles: a.Roles}
+		a.Password = nil
+		a.Token = nil
+		sort.Strings(a.Roles)
+		utils.ZeroOutID(&a, a.Username, config.WithID)
+		utils.ZeroOutTimestamps(&a)
+		file.Admins = append(file.Admins, a)
+	}
+	sort.SliceStable(file.Admins, func(i, j int) bool {
+		return compareOrder(file.Admins[i], file.Admins[j])
+	})
+	return nil
+}
+
 func populateDegraphqlRoutes(kongState *state.KongState, file *Content) error {
 	degraphqlRoutes, err := kongState.DegraphqlRoutes.GetAll()
 	if err != nil {
@@ -1086,6 +1360,102 @@ func populateGraphqlRateLimitingCostDecorations(kongState *state.KongState, file
 	return nil
 }
 
+// customEntityReferenceName returns the name of the entity of type reference
+// with ID id, for the entities which can be referenced by name.
+func customEntityReferenceName(kongState *state.KongState, reference, id string) (*string, error) {
+	var name *string
+	var err error
+	switch reference {
+	case "services":
+		var s *state.Service
+		if s, err = kongState.Services.Get(id); err == nil {
+			name = s.Name
+		}
+	case "routes":
+		var r *state.Route
+		if r, err = kongState.Routes.Get(id); err == nil {
+			name = r.Name
+		}
+	case "consumers":
+		var c *state.Consumer
+		if c, err = kongState.Consumers.GetByIDOrUsername(id); err == nil {
+			name = c.Username
+		}
+	case "consumer_groups":
+		var cg *state.ConsumerGroup
+		if cg, err = kongState.ConsumerGroups.Get(id); err == nil {
+			name = cg.Name
+		}
+	case "key_sets":
+		var ks *state.KeySet
+		if ks, err = kongState.KeySets.Get(id); err == nil {
+			name = ks.Name
+		}
+	case "partials":
+		var p *state.Partial
+		if p, err = kongState.Partials.Get(id); err == nil {
+			name = p.Name
+		}
+	}
+	if errors.Is(err, state.ErrNotFound) {
+		return nil, nil
+	}
+	return name, err
+}
+
+func populateCustomEntities(kongState *state.KongState, file *Content,
+	config WriteConfig,
+) error {
+	entities, err := kongState.CustomEntities.GetAll()
+	if err != nil {
+		return err
+	}
+	var customEntities []FCustomEntity
+	for _, e := range entities {
+		fields := CustomEntityConfiguration(e.Fields.DeepCopy())
+		delete(fields, "created_at")
+		delete(fields, "updated_at")
+		f := FCustomEntity{
+			ID:     new(e.ID),
+			Type:   new(e.Type),
+			Fields: fields,
+		}
+		if e.Definition != nil {
+			for field, reference := range e.Definition.ForeignKeys {
+				ref, ok := fields[field].(map[string]any)
+				if !ok || config.WithID {
+					continue
+				}
+				id, ok := ref["id"].(string)
+				if !ok {
+					continue
+				}
+				name, err := customEntityReferenceName(kongState, reference, id)
+				if err != nil {
+					return err
+				}
+				if !utils.Empty(name) {
+					fields[field] = map[string]any{"name": *name}
+				}
+			}
+			// Entities are matched by natural key when they have no ID.
+			if len(e.Definition.NaturalKey) > 0 && !config.WithID {
+				f.ID = nil
+			}
+		}
+		customEntities = append(customEntities, f)
+	}
+	// Sort by type and then by fields, as entities without ID have no sort key.
+	sort.SliceStable(customEntities, func(i, j int) bool {
+		if *customEntities[i].Type != *customEntities[j].Type {
+			return *customEntities[i].Type < *customEntities[j].Type
+		}
+		return fmt.Sprint(customEntities[i].Fields) < fmt.Sprint(customEntities[j].Fields)
+	})
+	file.CustomEntities = append(file.CustomEntities, customEntities...)
+	return nil
+}
+
 func populatePartials(kongState *state.KongState, file *Content) error {
 	partials, err := kongState.Partials.GetAll()
 	if err != nil {
@@ -40,7 +40,7 @@ func (in *Content) DeepCopyInto(out *Content) {
 	if in.Konnect != nil {
 		in, out := &in.Konnect, &out.Konnect
 		*out = new(Konnect)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Services != nil {
 		in, out := &in.Services, &out.Services
@@ -112,6 +112,20 @@ func (in *Content) DeepCopyInto(out *Content) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.RBACUsers != nil {
+		in, out := &in.RBACUsers, &out.RBACUsers
+		*out = make([]FRBACUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Admins != nil {
+		in, out := &in.Admins, &out.Admins
+		*out = make([]FAdmin, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.PluginConfigs != nil {
 		in, out := &in.PluginConfigs, &out.PluginConfigs
 		*out = make(map[string]kong.Configuration, len(*in))
@@ -126,6 +140,27 @@ func (in *Content) DeepCopyInto(out *Content) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.APIProducts != nil {
+		in, out := &in.APIProducts, &out.APIProducts
+		*out = make([]FAPIProduct, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Portals != nil {
+		in, out := &in.Portals, &out.Portals
+		*out = make([]FPortal, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ControlPlanes != nil {
+		in, out := &in.ControlPlanes, &out.ControlPlanes
+		*out = make([]FControlPlane, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Vaults != nil {
 		in, out := &in.Vaults, &out.Vaults
 		*out = make([]FVault, len(*in))
@@ -140,6 +175,20 @@ func (in *Content) DeepCopyInto(out *Content) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Workspaces != nil {
+		in, out := &in.Workspaces, &out.Workspaces
+		*out = make([]FWorkspace, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EventHooks != nil {
+		in, out := &in.EventHooks, &out.EventHooks
+		*out = make([]FEventHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.CustomEntities != nil {
 		in, out := &in.CustomEntities, &out.CustomEntities
 		*out = make([]FCustomEntity, len(*in))
@@ -212,6 +261,157 @@ func (in *DegraphqlRoute) DeepCopy() *DegraphqlRoute {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FAPIProduct) DeepCopyInto(out *FAPIProduct) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(string)
+		**out = **in
+	}
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.Description != nil {
+		in, out := &in.Description, &out.Description
+		*out = new(string)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Portals != nil {
+		in, out := &in.Portals, &out.Portals
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Versions != nil {
+		in, out := &in.Versions, &out.Versions
+		*out = make([]FAPIProductVersion, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Documents != nil {
+		in, out := &in.Documents, &out.Documents
+		*out = make([]FAPIProductDocument, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FAPIProduct.
+func (in *FAPIProduct) DeepCopy() *FAPIProduct {
+	if in == nil {
+		return nil
+	}
+	out := new(FAPIProduct)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FAPIProductDocument) DeepCopyInto(out *FAPIProductDocument) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(string)
+		**out = **in
+	}
+	if in.Title != nil {
+		in, out := &in.Title, &out.Title
+		*out = new(string)
+		**out = **in
+	}
+	if in.Slug != nil {
+		in, out := &in.Slug, &out.Slug
+		*out = new(string)
+		**out = **in
+	}
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(string)
+		**out = **in
+	}
+	if in.Content != nil {
+		in, out := &in.Content, &out.Content
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FAPIProductDocument.
+func (in *FAPIProductDocument) DeepCopy() *FAPIProductDocument {
+	if in == nil {
+		return nil
+	}
+	out := new(FAPIProductDocument)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FAPIProductVersion) DeepCopyInto(out *FAPIProductVersion) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(string)
+		**out = **in
+	}
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.GatewayService != nil {
+		in, out := &in.GatewayService, &out.GatewayService
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FAPIProductVersion.
+func (in *FAPIProductVersion) DeepCopy() *FAPIProductVersion {
+	if in == nil {
+		return nil
+	}
+	out := new(FAPIProductVersion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FAdmin) DeepCopyInto(out *FAdmin) {
+	*out = *in
+	in.Admin.DeepCopyInto(&out.Admin)
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FAdmin.
+func (in *FAdmin) DeepCopy() *FAdmin {
+	if in == nil {
+		return nil
+	}
+	out := new(FAdmin)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FCACertificate) DeepCopyInto(out *FCACertificate) {
 	*out = *in
@@ -455,6 +655,59 @@ func (in *FConsumerGroupObject) DeepCopy() *FConsumerGroupObject {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FControlPlane) DeepCopyInto(out *FControlPlane) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(string)
+		**out = **in
+	}
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.Description != nil {
+		in, out := &in.Description, &out.Description
+		*out = new(string)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ClusterType != nil {
+		in, out := &in.ClusterType, &out.ClusterType
+		*out = new(string)
+		**out = **in
+	}
+	if in.AuthType != nil {
+		in, out := &in.AuthType, &out.AuthType
+		*out = new(string)
+		**out = **in
+	}
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FControlPlane.
+func (in *FControlPlane) DeepCopy() *FControlPlane {
+	if in == nil {
+		return nil
+	}
+	out := new(FControlPlane)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FCustomEntity) DeepCopyInto(out *FCustomEntity) {
 	*out = *in
@@ -535,6 +788,23 @@ func (in *FDocument) DeepCopy() *FDocument {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FEventHook) DeepCopyInto(out *FEventHook) {
+	*out = *in
+	in.EventHook.DeepCopyInto(&out.EventHook)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FEventHook.
+func (in *FEventHook) DeepCopy() *FEventHook {
+	if in == nil {
+		return nil
+	}
+	out := new(FEventHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FFilterChain) DeepCopyInto(out *FFilterChain) {
 	*out = *in
@@ -642,6 +912,23 @@ func (in *FPlugin) DeepCopy() *FPlugin {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FPortal) DeepCopyInto(out *FPortal) {
+	*out = *in
+	in.Portal.DeepCopyInto(&out.Portal)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FPortal.
+func (in *FPortal) DeepCopy() *FPortal {
+	if in == nil {
+		return nil
+	}
+	out := new(FPortal)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FRBACEndpointPermission) DeepCopyInto(out *FRBACEndpointPermission) {
 	*out = *in
@@ -659,6 +946,23 @@ func (in *FRBACEndpointPermission) DeepCopy() *FRBACEndpointPermission {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FRBACEntityPermission) DeepCopyInto(out *FRBACEntityPermission) {
+	*out = *in
+	in.RBACEntityPermission.DeepCopyInto(&out.RBACEntityPermission)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FRBACEntityPermission.
+func (in *FRBACEntityPermission) DeepCopy() *FRBACEntityPermission {
+	if in == nil {
+		return nil
+	}
+	out := new(FRBACEntityPermission)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FRBACRole) DeepCopyInto(out *FRBACRole) {
 	*out = *in
@@ -674,6 +978,17 @@ func (in *FRBACRole) DeepCopyInto(out *FRBACRole) {
 			}
 		}
 	}
+	if in.EntityPermissions != nil {
+		in, out := &in.EntityPermissions, &out.EntityPermissions
+		*out = make([]*FRBACEntityPermission, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(FRBACEntityPermission)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
 	return
 }
 
@@ -687,6 +1002,28 @@ func (in *FRBACRole) DeepCopy() *FRBACRole {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FRBACUser) DeepCopyInto(out *FRBACUser) {
+	*out = *in
+	in.RBACUser.DeepCopyInto(&out.RBACUser)
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FRBACUser.
+func (in *FRBACUser) DeepCopy() *FRBACUser {
+	if in == nil {
+		return nil
+	}
+	out := new(FRBACUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FRoute) DeepCopyInto(out *FRoute) {
 	*out = *in
@@ -1047,6 +1384,11 @@ func (in *KongDefaults) DeepCopy() *KongDefaults {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Konnect) DeepCopyInto(out *Konnect) {
 	*out = *in
+	if in.ControlPlaneNames != nil {
+		in, out := &in.ControlPlaneNames, &out.ControlPlaneNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -0,0 +1,133 @@
+package konnect
+
+import (
+	"context"
+	"fmt"
+)
+
+type APIProductDocumentService service
+
+// Create creates a document of an API Product in Konnect.
+func (s *APIProductDocumentService) Create(ctx context.Context,
+	doc *APIProductDocument,
+) (*APIProductDocument, error) {
+	if doc == nil {
+		return nil, fmt.Errorf("cannot create a nil api-product-document")
+	}
+	if doc.APIProduct == nil || emptyString(doc.APIProduct.ID) {
+		return nil, fmt.Errorf("api-product-document must have an API Product")
+	}
+
+	req, err := s.client.NewRequest("POST", doc.APIProduct.URL()+"/documents", nil, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var createdDoc APIProductDocument
+	_, err = s.client.Do(ctx, req, &createdDoc)
+	if err != nil {
+		return nil, err
+	}
+	createdDoc.APIProduct = doc.APIProduct
+	return &createdDoc, nil
+}
+
+// Get fetches a document of an API Product, including its content.
+func (s *APIProductDocumentService) Get(ctx context.Context, product *APIProduct,
+	id *string,
+) (*APIProductDocument, error) {
+	if product == nil || emptyString(product.ID) {
+		return nil, fmt.Errorf("product ID cannot be nil")
+	}
+	if emptyString(id) {
+		return nil, fmt.Errorf("id cannot be nil for Get operation")
+	}
+
+	endpoint := fmt.Sprintf("%s/documents/%s", product.URL(), *id)
+	req, err := s.client.NewRequest("GET", endpoint, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc APIProductDocument
+	_, err = s.client.Do(ctx, req, &doc)
+	if err != nil {
+		return nil, err
+	}
+	doc.APIProduct = product
+	return &doc, nil
+}
+
+// Delete deletes a document of an API Product in Konnect.
+func (s *APIProductDocumentService) Delete(ctx context.Context, doc *APIProductDocument) error {
+	if doc == nil || emptyString(doc.ID) {
+		return fmt.Errorf("id cannot be nil for Delete operation")
+	}
+	if doc.APIProduct == nil || emptyString(doc.APIProduct.ID) {
+		return fmt.Errorf("api-product-document must have an API Product")
+	}
+
+	endpoint := fmt.Sprintf("%s/documents/%s", doc.APIProduct.URL(), *doc.ID)
+	req, err := s.client.NewRequest("DELETE", endpoint, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
+
+// Update updates a document of an API Product in Konnect.
+func (s *APIProductDocumentService) Update(ctx context.Context,
+	doc *APIProductDocument,
+) (*APIProductDocument, error) {
+	if doc == nil {
+		return nil, fmt.Errorf("cannot update a nil api-product-document")
+	}
+	if emptyString(doc.ID) {
+		return nil, fmt.Errorf("ID cannot be nil for Update operation")
+	}
+	if doc.APIProduct == nil || emptyString(doc.APIProduct.ID) {
+		return nil, fmt.Errorf("api-product-document must have an API Product")
+	}
+
+	endpoint := fmt.Sprintf("%s/documents/%s", doc.APIProduct.URL(), *doc.ID)
+	req, err := s.client.NewRequest("PATCH", endpoint, nil, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var updatedDoc APIProductDocument
+	_, err = s.client.Do(ctx, req, &updatedDoc)
+	if err != nil {
+		return nil, err
+	}
+	updatedDoc.APIProduct = doc.APIProduct
+	return &updatedDoc, nil
+}
+
+// ListAllForProduct fetches all documents of an API Product.
+// The list endpoint omits the content of the documents, which are then
+// fetched one by one.
+func (s *APIProductDocumentService) ListAllForProduct(ctx context.Context,
+	product *APIProduct,
+) ([]*APIProductDocument, error) {
+	if product == nil || emptyString(product.ID) {
+		return nil, fmt.Errorf("product ID cannot be nil")
+	}
+	var docs []*APIProductDocument
+	for doc, err := range Paginate[*APIProductDocument](ctx, s.client, ListRequest{
+		Endpoint:   product.URL() + "/documents",
+		Pagination: PageNumberPagination,
+	}) {
+		if err != nil {
+			return nil, err
+		}
+		fullDoc, err := s.Get(ctx, product, doc.ID)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, fullDoc)
+	}
+	return docs, nil
+}
@@ -0,0 +1,86 @@
+package konnect
+
+import (
+	"context"
+	"fmt"
+)
+
+type APIProductService service
+
+// Create creates an API Product in Konnect.
+func (s *APIProductService) Create(ctx context.Context, product *APIProduct) (*APIProduct, error) {
+	if product == nil {
+		return nil, fmt.Errorf("cannot create a nil api-product")
+	}
+
+	req, err := s.client.NewRequest("POST", "/v2/api-products", nil, withPortalIDs(product))
+	if err != nil {
+		return nil, err
+	}
+
+	var createdProduct APIProduct
+	_, err = s.client.Do(ctx, req, &createdProduct)
+	if err != nil {
+		return nil, err
+	}
+	return &createdProduct, nil
+}
+
+// Delete deletes an API Product in Konnect.
+func (s *APIProductService) Delete(ctx context.Context, id *string) error {
+	if emptyString(id) {
+		return fmt.Errorf("id cannot be nil for Delete operation")
+	}
+
+	endpoint := fmt.Sprintf("/v2/api-products/%v", *id)
+	req, err := s.client.NewRequest("DELETE", endpoint, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
+
+// Update updates an API Product in Konnect.
+func (s *APIProductService) Update(ctx context.Context, product *APIProduct) (*APIProduct, error) {
+	if product == nil {
+		return nil, fmt.Errorf("cannot update a nil api-product")
+	}
+
+	if emptyString(product.ID) {
+		return nil, fmt.Errorf("ID cannot be nil for Update operation")
+	}
+
+	req, err := s.client.NewRequest("PATCH", product.URL(), nil, withPortalIDs(product))
+	if err != nil {
+		return nil, err
+	}
+
+	var updatedProduct APIProduct
+	_, err = s.client.Do(ctx, req, &updatedProduct)
+	if err != nil {
+		return nil, err
+	}
+	return &updatedProduct, nil
+}
+
+// ListAll fetches all API Products.
+func (s *APIProductService) ListAll(ctx context.Context) ([]*APIProduct, error) {
+	return collect(Paginate[*APIProduct](ctx, s.client, ListRequest{
+		Endpoint:   "/v2/api-products",
+		Pagination: PageNumberPagination,
+	}))
+}
+
+// withPortalIDs returns product with an empty list of portals rather than
+// a nil one, so that the API Product is unpublished from all portals rather
+// than the request being rejected.
+func withPortalIDs(product *APIProduct) *APIProduct {
+	if product.PortalIDs != nil {
+		return product
+	}
+	res := *product
+	res.PortalIDs = []string{}
+	return &res
+}
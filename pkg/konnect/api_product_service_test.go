@@ -0,0 +1,85 @@
+package konnect
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIProductServiceListAll(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/api-products", r.URL.Path)
+		assert.Equal(t, "100", r.URL.Query().Get("page[size]"))
+		page := r.URL.Query().Get("page[number]")
+		// 101 products span two pages
+		fmt.Fprintf(w, `{"data":[{"id":"product-%s","name":"product-%s"}],"meta":{"page":{"total":101}}}`,
+			page, page)
+	}))
+	defer server.Close()
+	client, err := NewClient(nil, ClientOpts{BaseURL: server.URL})
+	require.NoError(t, err)
+
+	products, err := client.APIProducts.ListAll(context.Background())
+	require.NoError(t, err)
+	require.Len(t, products, 2)
+	assert.Equal(t, "product-1", *products[0].ID)
+	assert.Equal(t, "product-2", *products[1].ID)
+}
+
+func TestAPIProductVersionServiceCreate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/v2/api-products/product-id/product-versions", r.URL.Path)
+		var version APIProductVersion
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&version))
+		assert.NoError(t, json.NewEncoder(w).Encode(version))
+	}))
+	defer server.Close()
+	client, err := NewClient(nil, ClientOpts{BaseURL: server.URL})
+	require.NoError(t, err)
+	client.SetControlPlaneID("cp-id")
+
+	product := &APIProduct{ID: new("product-id")}
+	version := &APIProductVersion{
+		ID:             new("version-id"),
+		Name:           new("v1"),
+		GatewayService: &GatewayServiceRef{ID: new("service-id")},
+		APIProduct:     product,
+	}
+	created, err := client.APIProductVersions.Create(context.Background(), version)
+	require.NoError(t, err)
+	// the Service defaults to the control plane of the client
+	assert.Equal(t, &GatewayServiceRef{ControlPlaneID: new("cp-id"), ID: new("service-id")},
+		created.GatewayService)
+	assert.Same(t, product, created.APIProduct)
+	assert.Nil(t, version.GatewayService.ControlPlaneID)
+}
+
+func TestAPIProductDocumentServiceListAllForProduct(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/api-products/product-id/documents":
+			fmt.Fprint(w, `{"data":[{"id":"doc-id","slug":"intro"}],"meta":{"page":{"total":1}}}`)
+		case "/v2/api-products/product-id/documents/doc-id":
+			fmt.Fprint(w, `{"id":"doc-id","slug":"intro","content":"# Hello"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client, err := NewClient(nil, ClientOpts{BaseURL: server.URL})
+	require.NoError(t, err)
+
+	product := &APIProduct{ID: new("product-id")}
+	docs, err := client.APIProductDocuments.ListAllForProduct(context.Background(), product)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "# Hello", *docs[0].Content)
+	assert.Same(t, product, docs[0].APIProduct)
+}
@@ -0,0 +1,117 @@
+package konnect
+
+import (
+	"context"
+	"fmt"
+)
+
+type APIProductVersionService service
+
+// Create creates a version of an API Product in Konnect.
+// If the version is linked to a Service without a control plane, the Service
+// is assumed to belong to the control plane of the client.
+func (s *APIProductVersionService) Create(ctx context.Context,
+	version *APIProductVersion,
+) (*APIProductVersion, error) {
+	if version == nil {
+		return nil, fmt.Errorf("cannot create a nil api-product-version")
+	}
+	if version.APIProduct == nil || emptyString(version.APIProduct.ID) {
+		return nil, fmt.Errorf("api-product-version must have an API Product")
+	}
+
+	endpoint := version.APIProduct.URL() + "/product-versions"
+	req, err := s.client.NewRequest("POST", endpoint, nil, s.withControlPlane(version))
+	if err != nil {
+		return nil, err
+	}
+
+	var createdVersion APIProductVersion
+	_, err = s.client.Do(ctx, req, &createdVersion)
+	if err != nil {
+		return nil, err
+	}
+	createdVersion.APIProduct = version.APIProduct
+	return &createdVersion, nil
+}
+
+// Delete deletes a version of an API Product in Konnect.
+func (s *APIProductVersionService) Delete(ctx context.Context, version *APIProductVersion) error {
+	if version == nil || emptyString(version.ID) {
+		return fmt.Errorf("id cannot be nil for Delete operation")
+	}
+	if version.APIProduct == nil || emptyString(version.APIProduct.ID) {
+		return fmt.Errorf("api-product-version must have an API Product")
+	}
+
+	endpoint := fmt.Sprintf("%s/product-versions/%s", version.APIProduct.URL(), *version.ID)
+	req, err := s.client.NewRequest("DELETE", endpoint, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
+
+// Update updates a version of an API Product in Konnect.
+func (s *APIProductVersionService) Update(ctx context.Context,
+	version *APIProductVersion,
+) (*APIProductVersion, error) {
+	if version == nil {
+		return nil, fmt.Errorf("cannot update a nil api-product-version")
+	}
+	if emptyString(version.ID) {
+		return nil, fmt.Errorf("ID cannot be nil for Update operation")
+	}
+	if version.APIProduct == nil || emptyString(version.APIProduct.ID) {
+		return nil, fmt.Errorf("api-product-version must have an API Product")
+	}
+
+	endpoint := fmt.Sprintf("%s/product-versions/%s", version.APIProduct.URL(), *version.ID)
+	req, err := s.client.NewRequest("PATCH", endpoint, nil, s.withControlPlane(version))
+	if err != nil {
+		return nil, err
+	}
+
+	var updatedVersion APIProductVersion
+	_, err = s.client.Do(ctx, req, &updatedVersion)
+	if err != nil {
+		return nil, err
+	}
+	updatedVersion.APIProduct = version.APIProduct
+	return &updatedVersion, nil
+}
+
+// ListAllForProduct fetches all versions of an API Product.
+func (s *APIProductVersionService) ListAllForProduct(ctx context.Context,
+	product *APIProduct,
+) ([]*APIProductVersion, error) {
+	if product == nil || emptyString(product.ID) {
+		return nil, fmt.Errorf("product ID cannot be nil")
+	}
+	versions, err := collect(Paginate[*APIProductVersion](ctx, s.client, ListRequest{
+		Endpoint:   product.URL() + "/product-versions",
+		Pagination: PageNumberPagination,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	for _, version := range versions {
+		version.APIProduct = product
+	}
+	return versions, nil
+}
+
+func (s *APIProductVersionService) withControlPlane(version *APIProductVersion) *APIProductVersion {
+	if version.GatewayService == nil || !emptyString(version.GatewayService.ControlPlaneID) ||
+		s.controlPlaneID == "" {
+		return version
+	}
+	res := *version
+	res.GatewayService = &GatewayServiceRef{
+		ControlPlaneID: &s.controlPlaneID,
+		ID:             version.GatewayService.ID,
+	}
+	return &res
+}
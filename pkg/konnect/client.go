@@ -31,9 +31,14 @@ type Client struct {
 	Documents             *DocumentService
 	ControlPlanes         *ControlPlaneService
 	ControlPlaneRelations *ControlPlaneRelationsService
+	ControlPlanesV2       *ControlPlaneV2Service
+	APIProducts           *APIProductService
+	APIProductVersions    *APIProductVersionService
+	APIProductDocuments   *APIProductDocumentService
+	Portals               *PortalService
 	logger                io.Writer
 	debug                 bool
-	token                 string
+	credentials           CredentialProvider
 
 	RuntimeGroups *RuntimeGroupService
 }
@@ -41,6 +46,14 @@ type Client struct {
 // ClientOpts contains configuration options for a new Client.
 type ClientOpts struct {
 	BaseURL string
+
+	// CredentialProvider, if set, supplies the bearer token of each request.
+	// A request rejected with a 401 is retried once with a refreshed token.
+	CredentialProvider CredentialProvider
+
+	// RetryPolicy, if set, retries requests rate limited or failing with a
+	// transient error. See NewRetryTransport.
+	RetryPolicy *RetryPolicy
 }
 
 // NewClient returns a Client which talks to Konnect's API.
@@ -48,6 +61,12 @@ func NewClient(httpClient *http.Client, opts ClientOpts) (*Client, error) {
 	if httpClient == nil {
 		httpClient = http.DefaultClient
 	}
+	if opts.RetryPolicy != nil {
+		// the client may be shared, such as http.DefaultClient
+		retryClient := *httpClient
+		retryClient.Transport = NewRetryTransport(httpClient.Transport, *opts.RetryPolicy)
+		httpClient = &retryClient
+	}
 	client := new(Client)
 	client.client = httpClient
 	url, err := url.ParseRequestURI(opts.BaseURL)
@@ -55,6 +74,7 @@ func NewClient(httpClient *http.Client, opts ClientOpts) (*Client, error) {
 		return nil, fmt.Errorf("parsing URL: %w", err)
 	}
 	client.baseURL = url.String()
+	client.credentials = opts.CredentialProvider
 
 	client.common.client = client
 	client.Auth = (*AuthService)(&client.common)
@@ -63,6 +83,11 @@ func NewClient(httpClient *http.Client, opts ClientOpts) (*Client, error) {
 	client.Documents = (*DocumentService)(&client.common)
 	client.ControlPlanes = (*ControlPlaneService)(&client.common)
 	client.ControlPlaneRelations = (*ControlPlaneRelationsService)(&client.common)
+	client.ControlPlanesV2 = (*ControlPlaneV2Service)(&client.common)
+	client.APIProducts = (*APIProductService)(&client.common)
+	client.APIProductVersions = (*APIProductVersionService)(&client.common)
+	client.APIProductDocuments = (*APIProductDocumentService)(&client.common)
+	client.Portals = (*PortalService)(&client.common)
 	client.logger = os.Stderr
 
 	client.RuntimeGroups = (*RuntimeGroupService)(&client.common)
@@ -91,22 +116,31 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v any) (*http.Respon
 	}
 	req = req.WithContext(ctx)
 
-	// log the request
-	err = c.logRequest(req)
+	resp, token, err := c.send(ctx, req, "")
 	if err != nil {
 		return nil, err
 	}
-
-	// Make the request
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("making HTTP request: %w", err)
-	}
-
-	// log the response
-	err = c.logResponse(resp)
-	if err != nil {
-		return nil, err
+	if resp.StatusCode == http.StatusUnauthorized && c.credentials != nil && (req.Body == nil || req.GetBody != nil) {
+		// The token may have expired: retry once with a refreshed one.
+		refreshed, err := c.credentials.Refresh(ctx, token)
+		if err != nil {
+			_ = resp.Body.Close()
+			return nil, fmt.Errorf("refreshing Konnect credentials: %w", err)
+		}
+		if refreshed != token {
+			_ = resp.Body.Close()
+			retry := req.Clone(ctx)
+			if req.GetBody != nil {
+				retry.Body, err = req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+			}
+			resp, _, err = c.send(ctx, retry, refreshed)
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	// check for API errors
@@ -139,6 +173,40 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v any) (*http.Respon
 	return resp, err
 }
 
+// send authenticates req, with token if set or with the token of the
+// credential provider, and makes the request. It returns the token used.
+func (c *Client) send(ctx context.Context, req *http.Request, token string) (*http.Response, string, error) {
+	if c.credentials != nil {
+		if token == "" {
+			var err error
+			token, err = c.credentials.Token(ctx)
+			if err != nil {
+				return nil, "", fmt.Errorf("getting Konnect credentials: %w", err)
+			}
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	// log the request
+	err := c.logRequest(req)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Make the request
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("making HTTP request: %w", err)
+	}
+
+	// log the response
+	err = c.logResponse(resp)
+	if err != nil {
+		return nil, "", err
+	}
+	return resp, token, nil
+}
+
 // SetDebugMode enables or disables logging of
 // the request to the logger set by SetLogger().
 // By default, debug logging is disabled.
@@ -122,16 +122,7 @@ func (s *ControlPlaneRelationsService) List(ctx context.Context,
 func (s *ControlPlaneRelationsService) ListAll(ctx context.Context) ([]*ControlPlaneServiceRelation,
 	error,
 ) {
-	var relations, data []*ControlPlaneServiceRelation
-	var err error
-	opt := &ListOpt{Size: pageSize}
-
-	for opt != nil {
-		data, opt, err = s.List(ctx, opt)
-		if err != nil {
-			return nil, err
-		}
-		relations = append(relations, data...)
-	}
-	return relations, nil
+	return collect(Paginate[*ControlPlaneServiceRelation](ctx, s.client, ListRequest{
+		Endpoint: "/api/control_plane_service_relations",
+	}))
 }
@@ -0,0 +1,165 @@
+package konnect
+
+import (
+	"context"
+	"fmt"
+)
+
+type ControlPlaneV2Service service
+
+// controlPlaneRequest is the body of the requests creating and updating
+// control planes, in which the config is flattened.
+type controlPlaneRequest struct {
+	ID          *string           `json:"id,omitempty"`
+	Name        *string           `json:"name,omitempty"`
+	Description *string           `json:"description,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	ClusterType *string           `json:"cluster_type,omitempty"`
+	AuthType    *string           `json:"auth_type,omitempty"`
+}
+
+func newControlPlaneRequest(cp *ControlPlaneV2) *controlPlaneRequest {
+	req := &controlPlaneRequest{
+		ID:          cp.ID,
+		Name:        cp.Name,
+		Description: cp.Description,
+		Labels:      cp.Labels,
+	}
+	if cp.Config != nil {
+		req.ClusterType = cp.Config.ClusterType
+		req.AuthType = cp.Config.AuthType
+	}
+	return req
+}
+
+// Create creates a control plane in Konnect.
+// The members of a control plane group are not added, see AddGroupMembers.
+func (s *ControlPlaneV2Service) Create(ctx context.Context, cp *ControlPlaneV2) (*ControlPlaneV2, error) {
+	if cp == nil {
+		return nil, fmt.Errorf("cannot create a nil control-plane")
+	}
+
+	req, err := s.client.NewRequest("POST", "/v2/control-planes", nil, newControlPlaneRequest(cp))
+	if err != nil {
+		return nil, err
+	}
+
+	var createdCP ControlPlaneV2
+	_, err = s.client.Do(ctx, req, &createdCP)
+	if err != nil {
+		return nil, err
+	}
+	return &createdCP, nil
+}
+
+// Delete deletes a control plane in Konnect.
+func (s *ControlPlaneV2Service) Delete(ctx context.Context, id *string) error {
+	if emptyString(id) {
+		return fmt.Errorf("id cannot be nil for Delete operation")
+	}
+
+	endpoint := fmt.Sprintf("/v2/control-planes/%v", *id)
+	req, err := s.client.NewRequest("DELETE", endpoint, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
+
+// Update updates a control plane in Konnect.
+// The cluster type of a control plane cannot be changed.
+func (s *ControlPlaneV2Service) Update(ctx context.Context, cp *ControlPlaneV2) (*ControlPlaneV2, error) {
+	if cp == nil {
+		return nil, fmt.Errorf("cannot update a nil control-plane")
+	}
+
+	if emptyString(cp.ID) {
+		return nil, fmt.Errorf("ID cannot be nil for Update operation")
+	}
+
+	body := newControlPlaneRequest(cp)
+	body.ID = nil
+	body.ClusterType = nil
+	endpoint := fmt.Sprintf("/v2/control-planes/%v", *cp.ID)
+	req, err := s.client.NewRequest("PATCH", endpoint, nil, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var updatedCP ControlPlaneV2
+	_, err = s.client.Do(ctx, req, &updatedCP)
+	if err != nil {
+		return nil, err
+	}
+	return &updatedCP, nil
+}
+
+// ListAll fetches all control planes, without the members of control plane
+// groups.
+func (s *ControlPlaneV2Service) ListAll(ctx context.Context) ([]*ControlPlaneV2, error) {
+	return collect(Paginate[*ControlPlaneV2](ctx, s.client, ListRequest{
+		Endpoint:   "/v2/control-planes",
+		Pagination: PageNumberPagination,
+	}))
+}
+
+// ListGroupMembers fetches the IDs of the members of a control plane group.
+func (s *ControlPlaneV2Service) ListGroupMembers(ctx context.Context, groupID string) ([]string, error) {
+	if groupID == "" {
+		return nil, fmt.Errorf("group ID cannot be empty")
+	}
+	endpoint := fmt.Sprintf("/v2/control-planes/%s/group-memberships", groupID)
+	type member struct {
+		ID string `json:"id"`
+	}
+	var members []string
+	for m, err := range Paginate[member](ctx, s.client, ListRequest{
+		Endpoint:   endpoint,
+		Pagination: CursorPagination,
+	}) {
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, m.ID)
+	}
+	return members, nil
+}
+
+// AddGroupMembers adds control planes to a control plane group.
+func (s *ControlPlaneV2Service) AddGroupMembers(ctx context.Context, groupID string, ids []string) error {
+	return s.changeGroupMembers(ctx, groupID, "add", ids)
+}
+
+// RemoveGroupMembers removes control planes from a control plane group.
+func (s *ControlPlaneV2Service) RemoveGroupMembers(ctx context.Context, groupID string, ids []string) error {
+	return s.changeGroupMembers(ctx, groupID, "remove", ids)
+}
+
+func (s *ControlPlaneV2Service) changeGroupMembers(ctx context.Context, groupID, action string,
+	ids []string,
+) error {
+	if groupID == "" {
+		return fmt.Errorf("group ID cannot be empty")
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	type member struct {
+		ID string `json:"id"`
+	}
+	body := struct {
+		Members []member `json:"members"`
+	}{}
+	for _, id := range ids {
+		body.Members = append(body.Members, member{ID: id})
+	}
+	endpoint := fmt.Sprintf("/v2/control-planes/%s/group-memberships/%s", groupID, action)
+	req, err := s.client.NewRequest("POST", endpoint, nil, body)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
@@ -0,0 +1,83 @@
+package konnect
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestControlPlaneV2ServiceCreate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/v2/control-planes", r.URL.Path)
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		// the config is flattened in the request
+		assert.JSONEq(t, `{"name":"global","cluster_type":"CLUSTER_TYPE_CONTROL_PLANE_GROUP"}`, string(body))
+		fmt.Fprint(w, `{"id":"cp-id","name":"global","config":{"cluster_type":"CLUSTER_TYPE_CONTROL_PLANE_GROUP"}}`)
+	}))
+	defer server.Close()
+	client, err := NewClient(nil, ClientOpts{BaseURL: server.URL})
+	require.NoError(t, err)
+
+	created, err := client.ControlPlanesV2.Create(context.Background(), &ControlPlaneV2{
+		Name:   new("global"),
+		Config: &ControlPlaneConfig{ClusterType: new(ClusterTypeControlPlaneGroup)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "cp-id", *created.ID)
+	assert.True(t, created.IsGroup())
+}
+
+func TestControlPlaneV2ServiceGroupMembers(t *testing.T) {
+	var changes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/control-planes/group-id/group-memberships":
+			// memberships are paginated with a cursor
+			if r.URL.Query().Get("page[after]") == "" {
+				fmt.Fprint(w, `{"data":[{"id":"us-id"}],`+
+					`"meta":{"page":{"next":"/v2/control-planes/group-id/group-memberships?page%5Bafter%5D=c1"}}}`)
+				return
+			}
+			assert.Equal(t, "c1", r.URL.Query().Get("page[after]"))
+			fmt.Fprint(w, `{"data":[{"id":"eu-id"}],"meta":{"page":{"next":null}}}`)
+		case "/v2/control-planes/group-id/group-memberships/add",
+			"/v2/control-planes/group-id/group-memberships/remove":
+			assert.Equal(t, http.MethodPost, r.Method)
+			var body struct {
+				Members []struct {
+					ID string `json:"id"`
+				} `json:"members"`
+			}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			for _, member := range body.Members {
+				changes = append(changes, r.URL.Path[len("/v2/control-planes/group-id/group-memberships/"):]+" "+member.ID)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client, err := NewClient(nil, ClientOpts{BaseURL: server.URL})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	members, err := client.ControlPlanesV2.ListGroupMembers(ctx, "group-id")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"us-id", "eu-id"}, members)
+
+	require.NoError(t, client.ControlPlanesV2.AddGroupMembers(ctx, "group-id", []string{"apac-id"}))
+	require.NoError(t, client.ControlPlanesV2.RemoveGroupMembers(ctx, "group-id", []string{"us-id"}))
+	// nothing to change, no request is sent
+	require.NoError(t, client.ControlPlanesV2.AddGroupMembers(ctx, "group-id", nil))
+	assert.Equal(t, []string{"add apac-id", "remove us-id"}, changes)
+}
@@ -0,0 +1,219 @@
+package konnect
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CredentialProvider supplies the token used to authenticate requests to
+// Konnect. It is consulted for each request, so that long-running operations
+// keep working when short-lived tokens are rotated.
+//
+// Implementations must be safe for concurrent use.
+type CredentialProvider interface {
+	// Token returns the token to authenticate a request with.
+	Token(ctx context.Context) (string, error)
+	// Refresh returns a fresh token after Konnect rejected the token
+	// rejected returned by Token. A request is retried once if the fresh
+	// token differs from the rejected one.
+	Refresh(ctx context.Context, rejected string) (string, error)
+}
+
+type credentialTransport struct {
+	next     http.RoundTripper
+	provider CredentialProvider
+}
+
+// NewCredentialTransport returns a transport sending requests with next, or
+// http.DefaultTransport if nil, authenticated with a bearer token supplied by
+// provider. Like Client, it retries a request rejected with a 401 once with a
+// refreshed token, if its body can be sent again.
+//
+// It authenticates the clients of the Admin API of control planes, which are
+// not Konnect clients.
+func NewCredentialTransport(next http.RoundTripper, provider CredentialProvider) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &credentialTransport{next: next, provider: provider}
+}
+
+func (t *credentialTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	token, err := t.provider.Token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting Konnect credentials: %w", err)
+	}
+	resp, err := t.next.RoundTrip(authenticate(req, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized ||
+		req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return resp, err
+	}
+
+	// The token may have expired: retry once with a refreshed one.
+	refreshed, err := t.provider.Refresh(ctx, token)
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("refreshing Konnect credentials: %w", err)
+	}
+	if refreshed == token {
+		return resp, nil
+	}
+	// drain the body so that the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	_ = resp.Body.Close()
+	retry := authenticate(req, refreshed)
+	if req.GetBody != nil {
+		retry.Body, err = req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return t.next.RoundTrip(retry)
+}
+
+// authenticate returns a copy of req authenticated with token, as a
+// RoundTripper must not modify its request.
+func authenticate(req *http.Request, token string) *http.Request {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
+
+// StaticToken is a CredentialProvider always returning the same token, such
+// as a personal access token.
+type StaticToken string
+
+// Token returns t.
+func (t StaticToken) Token(context.Context) (string, error) {
+	return string(t), nil
+}
+
+// Refresh returns t, which cannot be refreshed.
+func (t StaticToken) Refresh(context.Context, string) (string, error) {
+	return string(t), nil
+}
+
+// FileCredentialProvider reads the token from a file, such as a mounted
+// secret, and reads it again whenever the file is modified or replaced.
+type FileCredentialProvider struct {
+	path string
+
+	mu      sync.Mutex
+	token   string
+	modTime time.Time
+	size    int64
+}
+
+// NewFileCredentialProvider returns a FileCredentialProvider reading the token
+// from path. Leading and trailing whitespace of the file is ignored.
+func NewFileCredentialProvider(path string) *FileCredentialProvider {
+	return &FileCredentialProvider{path: path}
+}
+
+// Token returns the token read from the file, reading the file again if it
+// changed since it was last read.
+func (p *FileCredentialProvider) Token(context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return "", fmt.Errorf("reading Konnect token: %w", err)
+	}
+	if p.token != "" && info.ModTime().Equal(p.modTime) && info.Size() == p.size {
+		return p.token, nil
+	}
+	return p.read(info)
+}
+
+// Refresh reads the file again.
+func (p *FileCredentialProvider) Refresh(context.Context, string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return "", fmt.Errorf("reading Konnect token: %w", err)
+	}
+	return p.read(info)
+}
+
+func (p *FileCredentialProvider) read(info os.FileInfo) (string, error) {
+	b, err := os.ReadFile(p.path)
+	if err != nil {
+		return "", fmt.Errorf("reading Konnect token: %w", err)
+	}
+	token := strings.TrimSpace(string(b))
+	if token == "" {
+		return "", fmt.Errorf("reading Konnect token: %s is empty", p.path)
+	}
+	p.token, p.modTime, p.size = token, info.ModTime(), info.Size()
+	return token, nil
+}
+
+// CommandCredentialProvider runs a command printing the token on its standard
+// output, such as the CLI of an identity broker.
+type CommandCredentialProvider struct {
+	name string
+	args []string
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// NewCommandCredentialProvider returns a CommandCredentialProvider running
+// the command name with args. The token is cached for ttl, or until Konnect
+// rejects it if ttl is zero.
+func NewCommandCredentialProvider(ttl time.Duration, name string, args ...string) *CommandCredentialProvider {
+	return &CommandCredentialProvider{name: name, args: args, ttl: ttl}
+}
+
+// Token returns the cached token, running the command if there is none or if
+// it expired.
+func (p *CommandCredentialProvider) Token(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.token != "" && (p.ttl == 0 || time.Now().Before(p.expires)) {
+		return p.token, nil
+	}
+	return p.run(ctx)
+}
+
+// Refresh runs the command again, unless the token was already refreshed
+// since rejected was returned.
+func (p *CommandCredentialProvider) Refresh(ctx context.Context, rejected string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.token != "" && p.token != rejected {
+		return p.token, nil
+	}
+	return p.run(ctx)
+}
+
+func (p *CommandCredentialProvider) run(ctx context.Context) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.name, p.args...) //nolint:gosec
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if stderr.Len() > 0 {
+			return "", fmt.Errorf("running %s: %w: %s", p.name, err, strings.TrimSpace(stderr.String()))
+		}
+		return "", fmt.Errorf("running %s: %w", p.name, err)
+	}
+	token := strings.TrimSpace(string(out))
+	if token == "" {
+		return "", fmt.Errorf("running %s: no token printed", p.name)
+	}
+	p.token, p.expires = token, time.Now().Add(p.ttl)
+	return token, nil
+}
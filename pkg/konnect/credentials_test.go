@@ -0,0 +1,159 @@
+package konnect
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileCredentialProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte("first\n"), 0o600))
+	p := NewFileCredentialProvider(path)
+
+	token, err := p.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "first", token)
+
+	// Secrets are rotated by replacing the file.
+	rotated := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(rotated, []byte("second-token"), 0o600))
+	require.NoError(t, os.Rename(rotated, path))
+	token, err = p.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "second-token", token)
+
+	require.NoError(t, os.WriteFile(path, nil, 0o600))
+	_, err = p.Refresh(context.Background(), token)
+	require.Error(t, err)
+}
+
+func TestCommandCredentialProvider(t *testing.T) {
+	counter := filepath.Join(t.TempDir(), "counter")
+	// Each run prints a new token.
+	p := NewCommandCredentialProvider(0, "sh", "-c", `echo x >> "$0"; wc -l < "$0"`, counter)
+
+	token, err := p.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "1", token)
+	token, err = p.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "1", token)
+
+	token, err = p.Refresh(context.Background(), "1")
+	require.NoError(t, err)
+	assert.Equal(t, "2", token)
+	// The token was already refreshed since "1" was rejected.
+	token, err = p.Refresh(context.Background(), "1")
+	require.NoError(t, err)
+	assert.Equal(t, "2", token)
+
+	expiring := NewCommandCredentialProvider(time.Nanosecond, "sh", "-c", `echo x >> "$0"; wc -l < "$0"`, counter)
+	token, err = expiring.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "3", token)
+	time.Sleep(time.Millisecond)
+	token, err = expiring.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "4", token)
+
+	_, err = NewCommandCredentialProvider(0, "sh", "-c", "echo denied >&2; exit 1").Token(context.Background())
+	require.ErrorContains(t, err, "denied")
+}
+
+// rotatingProvider returns a stale token until it is refreshed.
+type rotatingProvider struct{ refreshes atomic.Int32 }
+
+func (p *rotatingProvider) Token(context.Context) (string, error) {
+	if p.refreshes.Load() == 0 {
+		return "stale", nil
+	}
+	return "fresh", nil
+}
+
+func (p *rotatingProvider) Refresh(context.Context, string) (string, error) {
+	p.refreshes.Add(1)
+	return "fresh", nil
+}
+
+func TestClientCredentialProvider(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("Authorization") != "Bearer fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message": "Unauthorized"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"name": "svc"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	provider := &rotatingProvider{}
+	client, err := NewClient(nil, ClientOpts{BaseURL: server.URL, CredentialProvider: provider})
+	require.NoError(t, err)
+	req, err := client.NewRequest(http.MethodPost, "/services", nil, map[string]string{"name": "svc"})
+	require.NoError(t, err)
+	var res map[string]string
+	_, err = client.Do(context.Background(), req, &res)
+	require.NoError(t, err)
+	assert.Equal(t, "svc", res["name"])
+	assert.Equal(t, int32(2), requests.Load())
+	assert.Equal(t, int32(1), provider.refreshes.Load())
+
+	// A token that cannot be refreshed is not retried.
+	client, err = NewClient(nil, ClientOpts{BaseURL: server.URL, CredentialProvider: StaticToken("static")})
+	require.NoError(t, err)
+	req, err = client.NewRequest(http.MethodGet, "/services", nil, nil)
+	require.NoError(t, err)
+	_, err = client.Do(context.Background(), req, nil)
+	assert.True(t, IsUnauthorizedErr(err))
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestCredentialTransport(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("Authorization") != "Bearer fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+
+	provider := &rotatingProvider{}
+	client := &http.Client{Transport: NewCredentialTransport(nil, provider)}
+	req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("svc"))
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "svc", string(body))
+	assert.Equal(t, int32(2), requests.Load())
+	assert.Equal(t, int32(1), provider.refreshes.Load())
+	assert.Empty(t, req.Header.Get("Authorization"))
+
+	// A token that cannot be refreshed is not retried.
+	client = &http.Client{Transport: NewCredentialTransport(nil, StaticToken("static"))}
+	resp, err = client.Get(server.URL)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, int32(3), requests.Load())
+}
@@ -2,7 +2,6 @@ package konnect
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 )
@@ -91,55 +90,14 @@ func (d *DocumentService) Update(ctx context.Context, doc *Document) (*Document,
 	return &updatedDoc, nil
 }
 
-// listByPath fetches a list of Documents in Konnect on a specific path.
-// This is a helper method for listing all documents for specific entities.
-func (d *DocumentService) listByPath(ctx context.Context, path string, opt *ListOpt) ([]*Document, *ListOpt, error) {
-	data, next, err := d.client.list(ctx, path, opt)
-	if err != nil {
-		return nil, nil, err
-	}
-	var docs []*Document
-
-	for _, object := range data {
-		b, err := object.MarshalJSON()
-		if err != nil {
-			return nil, nil, err
-		}
-		var doc Document
-		err = json.Unmarshal(b, &doc)
-		if err != nil {
-			return nil, nil, err
-		}
-		docs = append(docs, &doc)
-	}
-
-	return docs, next, nil
-}
-
-// ListAll fetches all Documents in Kong.
-func (d *DocumentService) listAllByPath(ctx context.Context, path string) ([]*Document, error) {
-	var docs, data []*Document
-	var err error
-	opt := &ListOpt{Size: pageSize}
-
-	for opt != nil {
-		data, opt, err = d.listByPath(ctx, path, opt)
-		if err != nil {
-			return nil, err
-		}
-		docs = append(docs, data...)
-	}
-	return docs, nil
-}
-
 // ListAllForParent fetches all Documents in Konnect for a parent entity.
 func (d *DocumentService) ListAllForParent(ctx context.Context, parent ParentInfoer) ([]*Document, error) {
 	if parent == nil {
 		return nil, fmt.Errorf("parent cannot be nil")
 	}
-	var docs []*Document
-	var err error
-	docs, err = d.listAllByPath(ctx, parent.URL()+"/documents")
+	docs, err := collect(Paginate[*Document](ctx, d.client, ListRequest{
+		Endpoint: parent.URL() + "/documents",
+	}))
 	if err != nil {
 		return nil, err
 	}
@@ -14,31 +14,57 @@ func hasError(res *http.Response) error {
 	}
 
 	body, _ := io.ReadAll(res.Body) // TODO error in error?
-	return &APIError{
-		httpCode: res.StatusCode,
-		message:  messageFromBody(body),
+	apiErr := errorFromBody(body)
+	apiErr.httpCode = res.StatusCode
+	if requestID := res.Header.Get("X-Kong-Request-Id"); requestID != "" {
+		apiErr.requestID = requestID
 	}
+	return apiErr
 }
 
-func messageFromBody(b []byte) string {
+// errorFromBody parses the body of an error response, either of the v1 API
+// or a problem details object of the v2 API.
+func errorFromBody(b []byte) *APIError {
 	s := struct {
-		Message string
+		Message string `json:"message"`
+		Code    string `json:"code"`
+
+		// v2 API
+		Title    string `json:"title"`
+		Detail   string `json:"detail"`
+		Instance string `json:"instance"`
 	}{}
 
 	if err := json.Unmarshal(b, &s); err != nil {
-		return fmt.Sprintf("<failed to parse response body: %v>", err)
+		return &APIError{message: fmt.Sprintf("<failed to parse response body: %v>", err)}
 	}
 
-	return s.Message
+	apiErr := &APIError{
+		message:   s.Message,
+		errorCode: s.Code,
+		requestID: s.Instance,
+	}
+	if apiErr.message == "" {
+		apiErr.message = s.Detail
+	}
+	if apiErr.message == "" {
+		apiErr.message = s.Title
+	}
+	return apiErr
 }
 
-// APIError is used for Kong Admin API errors.
+// APIError is used for Konnect API errors.
 type APIError struct {
-	httpCode int
-	message  string
+	httpCode  int
+	message   string
+	errorCode string
+	requestID string
 }
 
 func (e *APIError) Error() string {
+	if e.requestID != "" {
+		return fmt.Sprintf("HTTP status %d (message: %q, request ID: %q)", e.httpCode, e.message, e.requestID)
+	}
 	return fmt.Sprintf("HTTP status %d (message: %q)", e.httpCode, e.message)
 }
 
@@ -47,6 +73,22 @@ func (e *APIError) Code() int {
 	return e.httpCode
 }
 
+// Message returns the message of the error sent by Konnect.
+func (e *APIError) Message() string {
+	return e.message
+}
+
+// ErrorCode returns the error code sent by Konnect, if any.
+func (e *APIError) ErrorCode() string {
+	return e.errorCode
+}
+
+// RequestID returns the ID of the failed request, if Konnect sent one. It
+// identifies the request when reporting the error to Kong.
+func (e *APIError) RequestID() string {
+	return e.requestID
+}
+
 // IsNotFoundErr returns true if the error or it's cause is
 // a 404 response from Kong.
 func IsNotFoundErr(e error) bool {
@@ -0,0 +1,62 @@
+package konnect
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHasError(t *testing.T) {
+	tests := []struct {
+		name      string
+		header    http.Header
+		body      string
+		message   string
+		errorCode string
+		requestID string
+		errString string
+	}{
+		{
+			name:      "v1 API",
+			body:      `{"message":"name is required","code":"invalid_name"}`,
+			message:   "name is required",
+			errorCode: "invalid_name",
+			errString: `HTTP status 400 (message: "name is required")`,
+		},
+		{
+			name:      "v2 API",
+			body:      `{"status":400,"title":"Bad Request","detail":"name is required","instance":"kong:trace:123"}`,
+			message:   "name is required",
+			requestID: "kong:trace:123",
+			errString: `HTTP status 400 (message: "name is required", request ID: "kong:trace:123")`,
+		},
+		{
+			name:      "request ID header",
+			header:    http.Header{"X-Kong-Request-Id": {"req-1"}},
+			body:      `{"title":"Bad Request","instance":"kong:trace:123"}`,
+			message:   "Bad Request",
+			requestID: "req-1",
+			errString: `HTTP status 400 (message: "Bad Request", request ID: "req-1")`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := hasError(&http.Response{
+				StatusCode: http.StatusBadRequest,
+				Header:     tt.header,
+				Body:       io.NopCloser(strings.NewReader(tt.body)),
+			})
+			var apiErr *APIError
+			require.ErrorAs(t, err, &apiErr)
+			assert.Equal(t, http.StatusBadRequest, apiErr.Code())
+			assert.Equal(t, tt.message, apiErr.Message())
+			assert.Equal(t, tt.errorCode, apiErr.ErrorCode())
+			assert.Equal(t, tt.requestID, apiErr.RequestID())
+			assert.EqualError(t, err, tt.errString)
+		})
+	}
+}
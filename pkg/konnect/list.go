@@ -3,6 +3,11 @@ package konnect
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"iter"
+	"net/url"
+	"slices"
+	"strconv"
 )
 
 // ListOpt aids in paginating through list endpoints.
@@ -23,38 +28,202 @@ const (
 func (c *Client) list(ctx context.Context,
 	endpoint string, opt *ListOpt,
 ) ([]json.RawMessage, *ListOpt, error) {
-	pageSize := 100
+	req := ListRequest{Endpoint: endpoint}
+	var token pageToken
 	if opt != nil {
-		if opt.Size > 100 {
-			opt.Size = pageSize
-		} else {
-			pageSize = opt.Size
+		req.PageSize = opt.Size
+		token.number = opt.Page
+	}
+	page, err := c.fetchPage(ctx, req, token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// convenient for end user to use this opt till it's nil
+	var next *ListOpt
+	if page.next != nil {
+		next = &ListOpt{
+			Page: page.next.number,
+			Size: req.pageSize(),
 		}
 	}
+	return page.data, next, nil
+}
+
+// Pagination is the way a list endpoint of Konnect is paginated.
+type Pagination int
+
+const (
+	// PagePagination paginates the v1 API with the page and size query
+	// parameters. Responses hold the number of the page and the page count.
+	PagePagination Pagination = iota
+	// PageNumberPagination paginates the v2 API with the page[number] and
+	// page[size] query parameters. Responses hold the total number of items.
+	PageNumberPagination
+	// CursorPagination paginates the v2 API with the page[after] query
+	// parameter. Responses hold the URL of the next page.
+	CursorPagination
+)
+
+// ListRequest describes a list endpoint of Konnect to iterate over with
+// Paginate.
+type ListRequest struct {
+	// Endpoint is relative to the base URL of the client.
+	Endpoint   string
+	Pagination Pagination
+
+	// PageSize is the number of items fetched per request. It defaults to,
+	// and cannot exceed, 100.
+	PageSize int
+
+	// Filter holds query parameters sent with each request, such as
+	// filter[name][eq] in the v2 API.
+	Filter url.Values
+}
+
+func (r ListRequest) pageSize() int {
+	if r.PageSize <= 0 || r.PageSize > pageSize {
+		return pageSize
+	}
+	return r.PageSize
+}
+
+// pageToken identifies a page of a list endpoint. The zero value is the
+// first page.
+type pageToken struct {
+	number int
+	cursor string
+}
+
+type page struct {
+	data []json.RawMessage
+	// next is nil after the last page.
+	next *pageToken
+}
 
-	req, err := c.NewRequest("GET", endpoint, opt, nil)
+// fetchPage fetches the page of req identified by token.
+func (c *Client) fetchPage(ctx context.Context, req ListRequest, token pageToken) (*page, error) {
+	values := url.Values{}
+	for key, value := range req.Filter {
+		values[key] = slices.Clone(value)
+	}
+	size := req.pageSize()
+	switch req.Pagination {
+	case PagePagination:
+		values.Set("size", strconv.Itoa(size))
+		if token.number > 0 {
+			values.Set("page", strconv.Itoa(token.number))
+		}
+	case PageNumberPagination:
+		token.number = max(token.number, 1)
+		values.Set("page[size]", strconv.Itoa(size))
+		values.Set("page[number]", strconv.Itoa(token.number))
+	case CursorPagination:
+		values.Set("page[size]", strconv.Itoa(size))
+		if token.cursor != "" {
+			values.Set("page[after]", token.cursor)
+		}
+	default:
+		return nil, fmt.Errorf("unknown pagination %d", req.Pagination)
+	}
+
+	httpReq, err := c.NewRequest("GET", req.Endpoint, nil, nil)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
+	httpReq.URL.RawQuery = values.Encode()
 	var list struct {
-		Data      []json.RawMessage `json:"data"`
-		Page      int               `json:"page"`
-		PageCount int               `json:"pageCount"`
-	}
+		Data []json.RawMessage `json:"data"`
+
+		// v1 API
+		Page      int `json:"page"`
+		PageCount int `json:"pageCount"`
 
-	_, err = c.Do(ctx, req, &list)
+		// v2 API
+		Meta struct {
+			Page struct {
+				Total int    `json:"total"`
+				Next  string `json:"next"`
+			} `json:"page"`
+		} `json:"meta"`
+	}
+	_, err = c.Do(ctx, httpReq, &list)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	// convenient for end user to use this opt till it's nil
-	var next *ListOpt
-	if len(list.Data) > 0 && list.Page != list.PageCount {
-		next = &ListOpt{
-			Page: list.Page + 1,
-			Size: pageSize,
+	res := &page{data: list.Data}
+	if len(list.Data) == 0 {
+		return res, nil
+	}
+	switch req.Pagination {
+	case PagePagination:
+		if list.Page != list.PageCount {
+			res.next = &pageToken{number: list.Page + 1}
+		}
+	case PageNumberPagination:
+		if token.number*size < list.Meta.Page.Total {
+			res.next = &pageToken{number: token.number + 1}
+		}
+	case CursorPagination:
+		if list.Meta.Page.Next != "" {
+			next, err := url.Parse(list.Meta.Page.Next)
+			if err != nil {
+				return nil, fmt.Errorf("parsing next page %q: %w", list.Meta.Page.Next, err)
+			}
+			if cursor := next.Query().Get("page[after]"); cursor != "" {
+				res.next = &pageToken{cursor: cursor}
+			}
 		}
 	}
+	return res, nil
+}
 
-	return list.Data, next, nil
+// Paginate iterates over the items of a list endpoint of Konnect, fetching
+// its pages as they are needed. Items are decoded into T.
+//
+// The iteration stops at the first error, which is yielded along with the
+// zero value of T. Breaking out of the iteration stops fetching pages.
+func Paginate[T any](ctx context.Context, c *Client, req ListRequest) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		var token pageToken
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+			page, err := c.fetchPage(ctx, req, token)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			for _, object := range page.data {
+				var item T
+				if err := json.Unmarshal(object, &item); err != nil {
+					yield(zero, err)
+					return
+				}
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if page.next == nil {
+				return
+			}
+			token = *page.next
+		}
+	}
+}
+
+// collect returns the items of seq, or the first error it yields.
+func collect[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	var res []T
+	for item, err := range seq {
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, item)
+	}
+	return res, nil
 }
@@ -0,0 +1,105 @@
+package konnect
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type listItem struct {
+	ID string `json:"id"`
+}
+
+func TestPaginate(t *testing.T) {
+	var requests []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Query())
+		switch r.URL.Path {
+		case "/api/items":
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			page = max(page, 1)
+			fmt.Fprintf(w, `{"data":[{"id":"v1-%d"}],"page":%d,"pageCount":3}`, page, page)
+		case "/v2/items":
+			number := r.URL.Query().Get("page[number]")
+			fmt.Fprintf(w, `{"data":[{"id":"v2-%s"}],"meta":{"page":{"total":3}}}`, number)
+		case "/v2/cursor":
+			switch r.URL.Query().Get("page[after]") {
+			case "":
+				fmt.Fprint(w, `{"data":[{"id":"c-1"}],"meta":{"page":{"next":"/v2/cursor?page%5Bafter%5D=a"}}}`)
+			case "a":
+				fmt.Fprint(w, `{"data":[{"id":"c-2"}],"meta":{"page":{"next":null}}}`)
+			}
+		}
+	}))
+	defer server.Close()
+	client, err := NewClient(nil, ClientOpts{BaseURL: server.URL})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	items, err := collect(Paginate[listItem](ctx, client, ListRequest{Endpoint: "/api/items"}))
+	require.NoError(t, err)
+	assert.Equal(t, []listItem{{"v1-1"}, {"v1-2"}, {"v1-3"}}, items)
+	assert.Equal(t, "100", requests[0].Get("size"))
+	assert.Equal(t, "3", requests[2].Get("page"))
+
+	requests = nil
+	items, err = collect(Paginate[listItem](ctx, client, ListRequest{
+		Endpoint:   "/v2/items",
+		Pagination: PageNumberPagination,
+		PageSize:   1,
+		Filter:     url.Values{"filter[name][eq]": {"foo"}},
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, []listItem{{"v2-1"}, {"v2-2"}, {"v2-3"}}, items)
+	for _, request := range requests {
+		assert.Equal(t, "foo", request.Get("filter[name][eq]"))
+		assert.Equal(t, "1", request.Get("page[size]"))
+	}
+
+	items, err = collect(Paginate[listItem](ctx, client, ListRequest{
+		Endpoint:   "/v2/cursor",
+		Pagination: CursorPagination,
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, []listItem{{"c-1"}, {"c-2"}}, items)
+
+	// breaking out of the iteration stops fetching pages
+	requests = nil
+	for item, err := range Paginate[*listItem](ctx, client, ListRequest{Endpoint: "/api/items"}) {
+		require.NoError(t, err)
+		assert.Equal(t, "v1-1", item.ID)
+		break
+	}
+	assert.Len(t, requests, 1)
+}
+
+func TestPaginateCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"data":[{"id":"1"}],"page":1,"pageCount":2}`)
+	}))
+	defer server.Close()
+	client, err := NewClient(nil, ClientOpts{BaseURL: server.URL})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var ids []string
+	var iterErr error
+	for item, err := range Paginate[listItem](ctx, client, ListRequest{Endpoint: "/api/items"}) {
+		if err != nil {
+			iterErr = err
+			break
+		}
+		ids = append(ids, item.ID)
+		cancel()
+	}
+	assert.Equal(t, []string{"1"}, ids)
+	require.ErrorIs(t, iterErr, context.Canceled)
+}
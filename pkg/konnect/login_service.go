@@ -121,16 +121,17 @@ func (s *AuthService) LoginV2(ctx context.Context, email,
 		authResponse AuthResponse
 	)
 
-	if token != "" {
-		s.client.token = token
-	} else if email != "" && password != "" {
+	switch {
+	case token != "":
+		s.client.credentials = StaticToken(token)
+	case email != "" && password != "":
 		authResponse, err = s.sessionAuth(ctx, email, password)
 		if err != nil {
 			return AuthResponse{}, err
 		}
-	} else {
+	case s.client.credentials == nil:
 		return AuthResponse{}, errors.New(
-			"at least one of email/password or personal access token must be provided",
+			"at least one of email/password, personal access token or credential provider must be provided",
 		)
 	}
 
@@ -0,0 +1,75 @@
+package konnect
+
+import (
+	"context"
+	"fmt"
+)
+
+type PortalService service
+
+// Create creates a Portal in Konnect.
+func (s *PortalService) Create(ctx context.Context, portal *Portal) (*Portal, error) {
+	if portal == nil {
+		return nil, fmt.Errorf("cannot create a nil portal")
+	}
+
+	req, err := s.client.NewRequest("POST", "/v2/portals", nil, portal)
+	if err != nil {
+		return nil, err
+	}
+
+	var createdPortal Portal
+	_, err = s.client.Do(ctx, req, &createdPortal)
+	if err != nil {
+		return nil, err
+	}
+	return &createdPortal, nil
+}
+
+// Delete deletes a Portal in Konnect.
+func (s *PortalService) Delete(ctx context.Context, id *string) error {
+	if emptyString(id) {
+		return fmt.Errorf("id cannot be nil for Delete operation")
+	}
+
+	endpoint := fmt.Sprintf("/v2/portals/%v", *id)
+	req, err := s.client.NewRequest("DELETE", endpoint, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
+
+// Update updates a Portal in Konnect.
+func (s *PortalService) Update(ctx context.Context, portal *Portal) (*Portal, error) {
+	if portal == nil {
+		return nil, fmt.Errorf("cannot update a nil portal")
+	}
+
+	if emptyString(portal.ID) {
+		return nil, fmt.Errorf("ID cannot be nil for Update operation")
+	}
+
+	endpoint := fmt.Sprintf("/v2/portals/%v", *portal.ID)
+	req, err := s.client.NewRequest("PATCH", endpoint, nil, portal)
+	if err != nil {
+		return nil, err
+	}
+
+	var updatedPortal Portal
+	_, err = s.client.Do(ctx, req, &updatedPortal)
+	if err != nil {
+		return nil, err
+	}
+	return &updatedPortal, nil
+}
+
+// ListAll fetches all Portals.
+func (s *PortalService) ListAll(ctx context.Context) ([]*Portal, error) {
+	return collect(Paginate[*Portal](ctx, s.client, ListRequest{
+		Endpoint:   "/v2/portals",
+		Pagination: PageNumberPagination,
+	}))
+}
@@ -0,0 +1,154 @@
+package konnect
+
+import (
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how requests to Konnect are retried after a rate limit
+// (429) or a transient error: a 502, 503 or 504 response, or a failure to
+// reach Konnect.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of times a request is retried. Zero
+	// disables retries.
+	MaxRetries int
+
+	// MinWait and MaxWait bound the wait before each retry, which grows
+	// exponentially with jitter. A Retry-After header sent by Konnect takes
+	// precedence, up to MaxWait.
+	MinWait time.Duration
+	MaxWait time.Duration
+
+	// Methods are the HTTP methods of the requests retried. They default to
+	// the idempotent methods GET, HEAD, PUT and DELETE.
+	Methods []string
+}
+
+// DefaultRetryPolicy returns the RetryPolicy used unless configured
+// otherwise.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: 5,
+		MinWait:    time.Second,
+		MaxWait:    30 * time.Second,
+	}
+}
+
+var defaultRetryMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete,
+}
+
+func (p RetryPolicy) retriesMethod(method string) bool {
+	methods := p.Methods
+	if methods == nil {
+		methods = defaultRetryMethods
+	}
+	return slices.Contains(methods, method)
+}
+
+// backoff returns the wait before retry number attempt, starting at 0.
+func (p RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+			return min(wait, p.MaxWait)
+		}
+	}
+	wait := p.MinWait << min(attempt, 30)
+	if wait <= 0 || wait > p.MaxWait {
+		wait = p.MaxWait
+	}
+	// wait between half and all of the backoff, so that clients rate
+	// limited together do not retry together
+	half := wait / 2
+	if half <= 0 {
+		return wait
+	}
+	return half + rand.N(half+1)
+}
+
+// retryAfter parses the value of a Retry-After header, either a number of
+// seconds or an HTTP date.
+func retryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0), true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+type retryTransport struct {
+	next   http.RoundTripper
+	policy RetryPolicy
+}
+
+// NewRetryTransport returns a transport sending requests with next, or
+// http.DefaultTransport if nil, and retrying them according to policy.
+//
+// Requests with a body are retried only if it can be sent again, which is the
+// case of the requests built by Client.NewRequest. The wait before a retry is
+// interrupted when the context of the request is done.
+func NewRetryTransport(next http.RoundTripper, policy RetryPolicy) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &retryTransport{next: next, policy: policy}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	retryable := t.policy.MaxRetries > 0 && t.policy.retriesMethod(req.Method) &&
+		(req.Body == nil || req.Body == http.NoBody || req.GetBody != nil)
+	if !retryable {
+		return t.next.RoundTrip(req)
+	}
+
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(ctx)
+			req.Body = body
+		}
+		resp, err := t.next.RoundTrip(req)
+		if ctx.Err() != nil || attempt == t.policy.MaxRetries {
+			return resp, err
+		}
+		if err == nil && !isRetryableStatus(resp.StatusCode) {
+			return resp, nil
+		}
+
+		wait := t.policy.backoff(attempt, resp)
+		if resp != nil {
+			// drain the body so that the connection can be reused
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+			_ = resp.Body.Close()
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package konnect
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRetryTestClient(t *testing.T, policy RetryPolicy, handler http.HandlerFunc) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	client, err := NewClient(nil, ClientOpts{BaseURL: server.URL, RetryPolicy: &policy})
+	require.NoError(t, err)
+	return client
+}
+
+var testRetryPolicy = RetryPolicy{MaxRetries: 3, MinWait: time.Millisecond, MaxWait: 10 * time.Millisecond}
+
+func TestRetryTransport(t *testing.T) {
+	var attempts atomic.Int32
+	client := newRetryTestClient(t, testRetryPolicy, func(w http.ResponseWriter, _ *http.Request) {
+		switch attempts.Add(1) {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			_, _ = w.Write([]byte(`{"id":"portal-id"}`))
+		}
+	})
+
+	req, err := client.NewRequest(http.MethodGet, "/v2/portals/portal-id", nil, nil)
+	require.NoError(t, err)
+	var portal Portal
+	_, err = client.Do(context.Background(), req, &portal)
+	require.NoError(t, err)
+	assert.Equal(t, "portal-id", *portal.ID)
+	assert.Equal(t, int32(3), attempts.Load())
+}
+
+func TestRetryTransportMethods(t *testing.T) {
+	var attempts atomic.Int32
+	var bodies []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write(body)
+	}
+
+	// POST is not idempotent and is not retried by default
+	client := newRetryTestClient(t, testRetryPolicy, handler)
+	_, err := client.Portals.Create(context.Background(), &Portal{Name: new("dev")})
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.Code())
+	assert.Equal(t, int32(1), attempts.Load())
+
+	attempts.Store(0)
+	bodies = nil
+	policy := testRetryPolicy
+	policy.Methods = []string{http.MethodPost}
+	client = newRetryTestClient(t, policy, handler)
+	created, err := client.Portals.Create(context.Background(), &Portal{Name: new("dev")})
+	require.NoError(t, err)
+	assert.Equal(t, "dev", *created.Name)
+	// the body is sent again
+	require.Len(t, bodies, 2)
+	assert.Equal(t, bodies[0], bodies[1])
+}
+
+func TestRetryTransportGivesUp(t *testing.T) {
+	var attempts atomic.Int32
+	client := newRetryTestClient(t, testRetryPolicy, func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		w.Header().Set("X-Kong-Request-Id", "req-1")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"message":"unavailable"}`))
+	})
+
+	_, err := client.Portals.ListAll(context.Background())
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.Code())
+	assert.Equal(t, "req-1", apiErr.RequestID())
+	assert.Equal(t, int32(4), attempts.Load())
+}
+
+func TestRetryTransportCanceled(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 3, MinWait: time.Hour, MaxWait: time.Hour}
+	client := newRetryTestClient(t, policy, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.Portals.ListAll(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{MinWait: time.Second, MaxWait: 8 * time.Second}
+	for attempt, maxWait := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 8 * time.Second} {
+		wait := policy.backoff(attempt, nil)
+		assert.GreaterOrEqual(t, wait, maxWait/2)
+		assert.LessOrEqual(t, wait, maxWait)
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": {"5"}}}
+	assert.Equal(t, 5*time.Second, policy.backoff(0, resp))
+	resp.Header.Set("Retry-After", "120")
+	assert.Equal(t, 8*time.Second, policy.backoff(0, resp))
+	resp.Header.Set("Retry-After", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	assert.Equal(t, 8*time.Second, policy.backoff(0, resp))
+}
@@ -103,16 +103,7 @@ func (s *ServicePackageService) List(ctx context.Context,
 func (s *ServicePackageService) ListAll(ctx context.Context) ([]*ServicePackage,
 	error,
 ) {
-	var servicePackages, data []*ServicePackage
-	var err error
-	opt := &ListOpt{Size: pageSize}
-
-	for opt != nil {
-		data, opt, err = s.List(ctx, opt)
-		if err != nil {
-			return nil, err
-		}
-		servicePackages = append(servicePackages, data...)
-	}
-	return servicePackages, nil
+	return collect(Paginate[*ServicePackage](ctx, s.client, ListRequest{
+		Endpoint: "/api/service_packages",
+	}))
 }
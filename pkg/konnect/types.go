@@ -147,3 +147,109 @@ type AuthResponse struct {
 	LastName     string `json:"last_name"`
 	FullName     string `json:"full_name"`
 }
+
+// Portal represents a Dev Portal in Konnect.
+// +k8s:deepcopy-gen=true
+type Portal struct {
+	ID                      *string `json:"id,omitempty"`
+	Name                    *string `json:"name,omitempty"`
+	DisplayName             *string `json:"display_name,omitempty"`
+	Description             *string `json:"description,omitempty"`
+	IsPublic                *bool   `json:"is_public,omitempty"`
+	RBACEnabled             *bool   `json:"rbac_enabled,omitempty"`
+	AutoApproveDevelopers   *bool   `json:"auto_approve_developers,omitempty"`
+	AutoApproveApplications *bool   `json:"auto_approve_applications,omitempty"`
+}
+
+// APIProduct represents an API Product in Konnect, which replaces
+// Service Packages in the catalog.
+// +k8s:deepcopy-gen=true
+type APIProduct struct {
+	ID          *string           `json:"id,omitempty"`
+	Name        *string           `json:"name,omitempty"`
+	Description *string           `json:"description"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	// PortalIDs are the IDs of the Portals the API Product is published to.
+	PortalIDs []string `json:"portal_ids"`
+}
+
+func (p *APIProduct) URL() string {
+	return fmt.Sprintf("/v2/api-products/%s", *p.ID)
+}
+
+func (p *APIProduct) Key() string {
+	return "APIProduct" + ":" + *p.ID
+}
+
+// APIProductVersion represents a version of an API Product in Konnect.
+// +k8s:deepcopy-gen=true
+type APIProductVersion struct {
+	ID             *string            `json:"id,omitempty"`
+	Name           *string            `json:"name,omitempty"`
+	GatewayService *GatewayServiceRef `json:"gateway_service"`
+
+	// APIProduct is the API Product the version belongs to. The API does
+	// not return it, it is set when the version is listed.
+	APIProduct *APIProduct `json:"-"`
+}
+
+// GatewayServiceRef links an API Product version to a Service of a
+// control plane.
+// +k8s:deepcopy-gen=true
+type GatewayServiceRef struct {
+	ControlPlaneID *string `json:"control_plane_id,omitempty"`
+	ID             *string `json:"id,omitempty"`
+}
+
+// APIProductDocument represents a document of an API Product in Konnect.
+// +k8s:deepcopy-gen=true
+type APIProductDocument struct {
+	ID      *string `json:"id,omitempty"`
+	Title   *string `json:"title,omitempty"`
+	Slug    *string `json:"slug,omitempty"`
+	Status  *string `json:"status,omitempty"`
+	Content *string `json:"content,omitempty"`
+
+	// APIProduct is the API Product the document belongs to. The API does
+	// not return it, it is set when the document is listed.
+	APIProduct *APIProduct `json:"-"`
+}
+
+// Cluster types of control planes.
+const (
+	ClusterTypeControlPlane         = "CLUSTER_TYPE_CONTROL_PLANE"
+	ClusterTypeHybrid               = "CLUSTER_TYPE_HYBRID"
+	ClusterTypeK8SIngressController = "CLUSTER_TYPE_K8S_INGRESS_CONTROLLER"
+	ClusterTypeControlPlaneGroup    = "CLUSTER_TYPE_CONTROL_PLANE_GROUP"
+	ClusterTypeServerless           = "CLUSTER_TYPE_SERVERLESS"
+)
+
+// ControlPlaneV2 represents a control plane in the v2 API of Konnect.
+// Control plane groups are control planes of the ClusterTypeControlPlaneGroup
+// cluster type, whose members are other control planes.
+// +k8s:deepcopy-gen=true
+type ControlPlaneV2 struct {
+	ID          *string             `json:"id,omitempty"`
+	Name        *string             `json:"name,omitempty"`
+	Description *string             `json:"description,omitempty"`
+	Labels      map[string]string   `json:"labels,omitempty"`
+	Config      *ControlPlaneConfig `json:"config,omitempty"`
+
+	// Members are the IDs of the members of a control plane group. The API
+	// manages them separately from the group.
+	Members []string `json:"-"`
+}
+
+// IsGroup returns true if the control plane is a control plane group.
+func (c *ControlPlaneV2) IsGroup() bool {
+	return c.Config != nil && c.Config.ClusterType != nil &&
+		*c.Config.ClusterType == ClusterTypeControlPlaneGroup
+}
+
+// ControlPlaneConfig holds the settings of a control plane which cannot be
+// changed once it is created, or only through dedicated endpoints.
+// +k8s:deepcopy-gen=true
+type ControlPlaneConfig struct {
+	ClusterType *string `json:"cluster_type,omitempty"`
+	AuthType    *string `json:"auth_type,omitempty"`
+}
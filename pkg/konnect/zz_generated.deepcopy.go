@@ -20,6 +20,131 @@ limitations under the License.
 
 package konnect
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIProduct) DeepCopyInto(out *APIProduct) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(string)
+		**out = **in
+	}
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.Description != nil {
+		in, out := &in.Description, &out.Description
+		*out = new(string)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PortalIDs != nil {
+		in, out := &in.PortalIDs, &out.PortalIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIProduct.
+func (in *APIProduct) DeepCopy() *APIProduct {
+	if in == nil {
+		return nil
+	}
+	out := new(APIProduct)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIProductDocument) DeepCopyInto(out *APIProductDocument) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(string)
+		**out = **in
+	}
+	if in.Title != nil {
+		in, out := &in.Title, &out.Title
+		*out = new(string)
+		**out = **in
+	}
+	if in.Slug != nil {
+		in, out := &in.Slug, &out.Slug
+		*out = new(string)
+		**out = **in
+	}
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(string)
+		**out = **in
+	}
+	if in.Content != nil {
+		in, out := &in.Content, &out.Content
+		*out = new(string)
+		**out = **in
+	}
+	if in.APIProduct != nil {
+		in, out := &in.APIProduct, &out.APIProduct
+		*out = new(APIProduct)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIProductDocument.
+func (in *APIProductDocument) DeepCopy() *APIProductDocument {
+	if in == nil {
+		return nil
+	}
+	out := new(APIProductDocument)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIProductVersion) DeepCopyInto(out *APIProductVersion) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(string)
+		**out = **in
+	}
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.GatewayService != nil {
+		in, out := &in.GatewayService, &out.GatewayService
+		*out = new(GatewayServiceRef)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.APIProduct != nil {
+		in, out := &in.APIProduct, &out.APIProduct
+		*out = new(APIProduct)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIProductVersion.
+func (in *APIProductVersion) DeepCopy() *APIProductVersion {
+	if in == nil {
+		return nil
+	}
+	out := new(APIProductVersion)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ControlPlane) DeepCopyInto(out *ControlPlane) {
 	*out = *in
@@ -46,6 +171,32 @@ func (in *ControlPlane) DeepCopy() *ControlPlane {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlPlaneConfig) DeepCopyInto(out *ControlPlaneConfig) {
+	*out = *in
+	if in.ClusterType != nil {
+		in, out := &in.ClusterType, &out.ClusterType
+		*out = new(string)
+		**out = **in
+	}
+	if in.AuthType != nil {
+		in, out := &in.AuthType, &out.AuthType
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneConfig.
+func (in *ControlPlaneConfig) DeepCopy() *ControlPlaneConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlPlaneConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ControlPlaneServiceRelation) DeepCopyInto(out *ControlPlaneServiceRelation) {
 	*out = *in
@@ -98,6 +249,136 @@ func (in *ControlPlaneType) DeepCopy() *ControlPlaneType {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlPlaneV2) DeepCopyInto(out *ControlPlaneV2) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(string)
+		**out = **in
+	}
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.Description != nil {
+		in, out := &in.Description, &out.Description
+		*out = new(string)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = new(ControlPlaneConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneV2.
+func (in *ControlPlaneV2) DeepCopy() *ControlPlaneV2 {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlPlaneV2)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayServiceRef) DeepCopyInto(out *GatewayServiceRef) {
+	*out = *in
+	if in.ControlPlaneID != nil {
+		in, out := &in.ControlPlaneID, &out.ControlPlaneID
+		*out = new(string)
+		**out = **in
+	}
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayServiceRef.
+func (in *GatewayServiceRef) DeepCopy() *GatewayServiceRef {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayServiceRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Portal) DeepCopyInto(out *Portal) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(string)
+		**out = **in
+	}
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.DisplayName != nil {
+		in, out := &in.DisplayName, &out.DisplayName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Description != nil {
+		in, out := &in.Description, &out.Description
+		*out = new(string)
+		**out = **in
+	}
+	if in.IsPublic != nil {
+		in, out := &in.IsPublic, &out.IsPublic
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RBACEnabled != nil {
+		in, out := &in.RBACEnabled, &out.RBACEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AutoApproveDevelopers != nil {
+		in, out := &in.AutoApproveDevelopers, &out.AutoApproveDevelopers
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AutoApproveApplications != nil {
+		in, out := &in.AutoApproveApplications, &out.AutoApproveApplications
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Portal.
+func (in *Portal) DeepCopy() *Portal {
+	if in == nil {
+		return nil
+	}
+	out := new(Portal)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RuntimeGroup) DeepCopyInto(out *RuntimeGroup) {
 	*out = *in
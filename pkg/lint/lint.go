@@ -0,0 +1,329 @@
+package lint
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kong/go-database-reconciler/pkg/cprint"
+	"github.com/kong/go-database-reconciler/pkg/file"
+	"github.com/kong/go-database-reconciler/pkg/state"
+	"github.com/kong/go-database-reconciler/pkg/utils"
+)
+
+// IgnoreTagPrefix is the prefix of the entity tags which suppress
+// diagnostics on the entity: an entity tagged with lint-ignore:<code> is
+// not reported for that code.
+const IgnoreTagPrefix = "lint-ignore:"
+
+// Location identifies the entity a diagnostic applies to.
+type Location struct {
+	// Kind is the entity type, e.g. "route" or "plugin".
+	Kind string `json:"kind"`
+	// Name is the human-readable name of the entity, as returned by Console().
+	Name string `json:"name"`
+}
+
+func (l Location) String() string {
+	if l.Kind == "" {
+		return l.Name
+	}
+	return l.Kind + " " + l.Name
+}
+
+// Finding is a problem found by a rule.
+type Finding struct {
+	Location Location
+	Message  string
+	// Tags are the tags of the entity, used to honour lint-ignore tags.
+	Tags []*string
+}
+
+// Diagnostic is a finding of a rule, with its severity resolved.
+type Diagnostic struct {
+	Code     utils.DiagnosticCode `json:"code"`
+	Severity utils.Severity       `json:"severity"`
+	Message  string               `json:"message"`
+	Location Location             `json:"location"`
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s (%s): %s: %s", d.Severity, d.Code, d.Location, d.Message)
+}
+
+// Rule is a lint rule. A rule inspects the file content, the state built out
+// of it, or both.
+type Rule struct {
+	Code            utils.DiagnosticCode
+	DefaultSeverity utils.Severity
+	Description     string
+
+	CheckContent func(content *file.Content) ([]Finding, error)
+	CheckState   func(ks *state.KongState) ([]Finding, error)
+}
+
+// Input is what rules are run against. Either field may be nil, in which
+// case rules inspecting it are skipped.
+type Input struct {
+	Content *file.Content
+	State   *state.KongState
+}
+
+// Options configures a run of the rules.
+type Options struct {
+	// Policy overrides the default severity of the rules.
+	Policy utils.DiagnosticPolicy
+	// Suppressed lists codes which are not reported at all.
+	Suppressed []utils.DiagnosticCode
+}
+
+// Registry holds lint rules indexed by their code.
+// The codes of the rules are only known to the registry: they are not
+// registered as global diagnostic codes, and registries do not share them.
+type Registry struct {
+	mu    sync.RWMutex
+	rules map[utils.DiagnosticCode]Rule
+}
+
+var _ file.Linter = &Registry{}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{rules: map[utils.DiagnosticCode]Rule{}}
+}
+
+// Register adds a rule to the registry.
+// Rules reusing one of the diagnostic codes of the builder must use its
+// default severity.
+func (r *Registry) Register(rule Rule) error {
+	if strings.TrimSpace(string(rule.Code)) == "" || strings.Contains(string(rule.Code), ",") {
+		return fmt.Errorf("invalid rule code: %q", rule.Code)
+	}
+	if rule.CheckContent == nil && rule.CheckState == nil {
+		return fmt.Errorf("rule %s has nothing to check", rule.Code)
+	}
+	if rule.DefaultSeverity == "" {
+		rule.DefaultSeverity = utils.SeverityWarning
+	}
+	if rule.DefaultSeverity != utils.SeverityWarning && rule.DefaultSeverity != utils.SeverityError {
+		return fmt.Errorf("rule %s: invalid default severity %q", rule.Code, rule.DefaultSeverity)
+	}
+	if slices.Contains(utils.ValidDiagnosticCodes(), rule.Code) {
+		if severity := utils.DefaultSeverity(rule.Code); severity != rule.DefaultSeverity {
+			return fmt.Errorf("rule %s: default severity %s does not match the severity of the existing code (%s)",
+				rule.Code, rule.DefaultSeverity, severity)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.rules[rule.Code]; ok {
+		return fmt.Errorf("rule %s already registered", rule.Code)
+	}
+	r.rules[rule.Code] = rule
+	return nil
+}
+
+// ParseCodes parses a comma-separated list of codes, such as the codes of a
+// DiagnosticPolicy. Codes must be those of a rule of the registry or of the
+// builder.
+func (r *Registry) ParseCodes(value string) ([]utils.DiagnosticCode, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var res []utils.DiagnosticCode
+	for part := range strings.SplitSeq(value, ",") {
+		code := utils.DiagnosticCode(strings.TrimSpace(part))
+		if code == "" || slices.Contains(res, code) {
+			continue
+		}
+		if _, ok := r.rules[code]; !ok && !slices.Contains(utils.ValidDiagnosticCodes(), code) {
+			return nil, fmt.Errorf("unknown diagnostic code: %s", code)
+		}
+		res = append(res, code)
+	}
+	return res, nil
+}
+
+// Rules returns the rules of the registry, sorted by code.
+func (r *Registry) Rules() []Rule {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	res := make([]Rule, 0, len(r.rules))
+	for _, rule := range r.rules {
+		res = append(res, rule)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Code < res[j].Code
+	})
+	return res
+}
+
+// Run runs all the rules against the input and returns their diagnostics,
+// sorted by code and location.
+func (r *Registry) Run(input Input, opts Options) ([]Diagnostic, error) {
+	var res []Diagnostic
+	for _, rule := range r.Rules() {
+		if slices.Contains(opts.Suppressed, rule.Code) {
+			continue
+		}
+		var findings []Finding
+		if rule.CheckContent != nil && input.Content != nil {
+			f, err := rule.CheckContent(input.Content)
+			if err != nil {
+				return nil, fmt.Errorf("running rule %s: %w", rule.Code, err)
+			}
+			findings = append(findings, f...)
+		}
+		if rule.CheckState != nil && input.State != nil {
+			f, err := rule.CheckState(input.State)
+			if err != nil {
+				return nil, fmt.Errorf("running rule %s: %w", rule.Code, err)
+			}
+			findings = append(findings, f...)
+		}
+
+		severity := rule.DefaultSeverity
+		if opts.Policy.IsAlwaysError(rule.Code) {
+			severity = utils.SeverityError
+		} else if opts.Policy.IsAlwaysWarning(rule.Code) {
+			severity = utils.SeverityWarning
+		}
+		for _, f := range findings {
+			if isIgnored(f.Tags, rule.Code) {
+				continue
+			}
+			res = append(res, Diagnostic{
+				Code:     rule.Code,
+				Severity: severity,
+				Message:  f.Message,
+				Location: f.Location,
+			})
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].Code != res[j].Code {
+			return res[i].Code < res[j].Code
+		}
+		return res[i].Location.String() < res[j].Location.String()
+	})
+	return res, nil
+}
+
+func isIgnored(tags []*string, code utils.DiagnosticCode) bool {
+	for _, tag := range tags {
+		if tag != nil && *tag == IgnoreTagPrefix+string(code) {
+			return true
+		}
+	}
+	return false
+}
+
+// Errors returns the diagnostics with error severity as a utils.ErrArray,
+// or nil if there are none.
+func Errors(diagnostics []Diagnostic) error {
+	var errs utils.ErrArray
+	for _, d := range diagnostics {
+		if d.Severity == utils.SeverityError {
+			errs.Errors = append(errs.Errors, fmt.Errorf("%s: %s", d.Location, d.Message))
+		}
+	}
+	if len(errs.Errors) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// Lint runs the rules of the registry against the content of a file and the
+// state built out of it. Warnings are printed out on stderr, and diagnostics
+// with error severity are returned as a utils.ErrArray.
+// It implements file.Linter, so that the registry can be run by the builder.
+func (r *Registry) Lint(content *file.Content, ks *state.KongState, policy utils.DiagnosticPolicy,
+	suppressed []utils.DiagnosticCode,
+) error {
+	diagnostics, err := r.Run(Input{Content: content, State: ks}, Options{
+		Policy:     policy,
+		Suppressed: suppressed,
+	})
+	if err != nil {
+		return err
+	}
+	for _, d := range diagnostics {
+		if d.Severity == utils.SeverityWarning {
+			cprint.UpdatePrintlnStdErr(d.String())
+		}
+	}
+	return Errors(diagnostics)
+}
+
+// SuppressedByComments returns the codes listed in lint-ignore comments of
+// a raw declarative file, e.g.:
+//
+//	# lint-ignore: route-conflict, route-unreachable
+//
+// Codes which are not those of a rule of the registry or of the builder are
+// reported as an error.
+func (r *Registry) SuppressedByComments(raw []byte) ([]utils.DiagnosticCode, error) {
+	var res []utils.DiagnosticCode
+	for line := range strings.Lines(string(raw)) {
+		comment, ok := strings.CutPrefix(strings.TrimSpace(line), "#")
+		if !ok {
+			continue
+		}
+		codes, ok := strings.CutPrefix(strings.TrimSpace(comment), strings.TrimSuffix(IgnoreTagPrefix, ":"))
+		if !ok {
+			continue
+		}
+		codes, ok = strings.CutPrefix(strings.TrimSpace(codes), ":")
+		if !ok {
+			continue
+		}
+		parsed, err := r.ParseCodes(codes)
+		if err != nil {
+			return nil, err
+		}
+		for _, code := range parsed {
+			if !slices.Contains(res, code) {
+				res = append(res, code)
+			}
+		}
+	}
+	return res, nil
+}
+
+var defaultRegistry = newDefaultRegistry()
+
+func newDefaultRegistry() *Registry {
+	r := NewRegistry()
+	for _, rule := range builtinRules {
+		if err := r.Register(rule); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// Register adds a rule to the default registry, which holds the built-in
+// rules.
+func Register(rule Rule) error {
+	return defaultRegistry.Register(rule)
+}
+
+// Run runs the rules of the default registry.
+func Run(input Input, opts Options) ([]Diagnostic, error) {
+	return defaultRegistry.Run(input, opts)
+}
+
+// SuppressedByComments returns the codes listed in the lint-ignore comments of
+// a raw declarative file, checked against the default registry.
+func SuppressedByComments(raw []byte) ([]utils.DiagnosticCode, error) {
+	return defaultRegistry.SuppressedByComments(raw)
+}
+
+// Default returns the default registry, which holds the built-in rules and
+// the rules added with Register. It can be set as the Linter of a
+// file.RenderConfig.
+func Default() *Registry {
+	return defaultRegistry
+}
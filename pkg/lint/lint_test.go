@@ -0,0 +1,258 @@
+package lint
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/kong/go-database-reconciler/pkg/dump"
+	"github.com/kong/go-database-reconciler/pkg/file"
+	"github.com/kong/go-database-reconciler/pkg/state"
+	"github.com/kong/go-database-reconciler/pkg/utils"
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testState(t *testing.T, routes ...kong.Route) *state.KongState {
+	t.Helper()
+	ks, err := state.NewKongState()
+	require.NoError(t, err)
+	for _, r := range routes {
+		require.NoError(t, ks.Routes.Add(state.Route{Route: r}))
+	}
+	return ks
+}
+
+func TestRunBuiltinRules(t *testing.T) {
+	ks := testState(t,
+		kong.Route{ID: new("a"), Name: new("a"), Paths: []*string{new("/foo")}},
+		kong.Route{ID: new("b"), Name: new("b"), Paths: []*string{new("/foo")}},
+		kong.Route{ID: new("c"), Name: new("c"), Paths: []*string{new(`/users/\d+`)}},
+		kong.Route{ID: new("d"), Name: new("d"), Expression: new(`http.pth == "/"`)},
+		kong.Route{
+			ID: new("e"), Name: new("e"), Expression: new(`http.pth == "/e"`),
+			Tags: []*string{new("lint-ignore:route-invalid-expression")},
+		},
+	)
+	require.NoError(t, ks.Plugins.Add(state.Plugin{Plugin: kong.Plugin{
+		ID: new("rla"), Name: new("rate-limiting-advanced"),
+		Config: kong.Configuration{"enforce_consumer_groups": true},
+	}}))
+	require.NoError(t, ks.Plugins.Add(state.Plugin{Plugin: kong.Plugin{
+		ID: new("oidc"), Name: new("openid-connect"),
+		Config: kong.Configuration{"issuer": "https://example.com"},
+	}}))
+	require.NoError(t, ks.Plugins.Add(state.Plugin{Plugin: kong.Plugin{
+		ID: new("oidc-with-salt"), Name: new("openid-connect"),
+		Service: &kong.Service{ID: new("svc")},
+		Config:  kong.Configuration{"cache_tokens_salt": "salt"},
+	}}))
+
+	diagnostics, err := Run(Input{State: ks}, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, []Diagnostic{
+		{
+			Code:     utils.DiagnosticCodeOIDCMissingConfig,
+			Severity: utils.SeverityError,
+			Message:  utils.OpenIDConnectMissingConfigMessage([]string{"cache_tokens_salt"}),
+			Location: Location{Kind: "plugin", Name: "openid-connect (global)"},
+		},
+		{
+			Code:     utils.DiagnosticCodeRLAConsumerGroups,
+			Severity: utils.SeverityError,
+			Message:  utils.ErrorConsumerGroupUpgrade.Error(),
+			Location: Location{Kind: "plugin", Name: "rate-limiting-advanced (global)"},
+		},
+		{
+			Code:     utils.DiagnosticCodeRouteConflict,
+			Severity: utils.SeverityWarning,
+			Message:  "route a and route b match the same requests with the same priority (paths: /foo)",
+			Location: Location{Kind: "route", Name: "a"},
+		},
+		{
+			Code:     utils.DiagnosticCodeRouteExpression,
+			Severity: utils.SeverityWarning,
+			Message:  `invalid expression: unknown field "http.pth" (at offset 0)`,
+			Location: Location{Kind: "route", Name: "d"},
+		},
+		{
+			Code:     utils.DiagnosticCodeRouteRegexPathFormat,
+			Severity: utils.SeverityWarning,
+			Message:  `regex paths must be prefixed with ~: /users/\d+`,
+			Location: Location{Kind: "route", Name: "c"},
+		},
+	}, diagnostics)
+	var errs utils.ErrArray
+	require.ErrorAs(t, Errors(diagnostics), &errs)
+	require.Len(t, errs.Errors, 2)
+	require.EqualError(t, errs.Errors[1],
+		"plugin rate-limiting-advanced (global): "+utils.ErrorConsumerGroupUpgrade.Error())
+	assert.NoError(t, Errors(diagnostics[2:]))
+
+	t.Run("policy overrides severities", func(t *testing.T) {
+		diagnostics, err := Run(Input{State: ks}, Options{
+			Policy: utils.NewDiagnosticPolicy(
+				[]utils.DiagnosticCode{utils.DiagnosticCodeRouteConflict},
+				[]utils.DiagnosticCode{utils.DiagnosticCodeRLAConsumerGroups},
+			),
+			Suppressed: []utils.DiagnosticCode{
+				utils.DiagnosticCodeOIDCMissingConfig,
+				utils.DiagnosticCodeRouteExpression,
+				utils.DiagnosticCodeRouteRegexPathFormat,
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, diagnostics, 2)
+		assert.Equal(t, utils.SeverityWarning, diagnostics[0].Severity)
+		assert.Equal(t, utils.SeverityError, diagnostics[1].Severity)
+		assert.Equal(t, utils.DiagnosticCodeRouteConflict, diagnostics[1].Code)
+	})
+}
+
+func TestRegistry(t *testing.T) {
+	const code utils.DiagnosticCode = "service-missing-tags"
+	rule := Rule{
+		Code:            code,
+		DefaultSeverity: utils.SeverityError,
+		CheckContent: func(content *file.Content) ([]Finding, error) {
+			var res []Finding
+			for _, s := range content.Services {
+				if len(s.Tags) == 0 {
+					res = append(res, Finding{
+						Location: Location{Kind: "service", Name: *s.Name},
+						Message:  "services must be tagged",
+					})
+				}
+			}
+			return res, nil
+		},
+	}
+
+	r := NewRegistry()
+	require.NoError(t, r.Register(rule))
+	require.ErrorContains(t, r.Register(rule), "already registered")
+	// the code is now known to the registry only and can be used in policies.
+	codes, err := r.ParseCodes(string(code) + ", " + string(utils.DiagnosticCodeRouteConflict))
+	require.NoError(t, err)
+	assert.Equal(t, []utils.DiagnosticCode{code, utils.DiagnosticCodeRouteConflict}, codes)
+	_, err = utils.ParseDiagnosticCodes(string(code))
+	require.ErrorContains(t, err, "unknown diagnostic code")
+	_, err = NewRegistry().ParseCodes(string(code))
+	require.ErrorContains(t, err, "unknown diagnostic code")
+	// another registry may reuse the code with another default severity.
+	rule.DefaultSeverity = utils.SeverityWarning
+	require.NoError(t, NewRegistry().Register(rule))
+	rule.DefaultSeverity = utils.SeverityError
+	// codes of the builder must keep their default severity.
+	require.ErrorContains(t, NewRegistry().Register(Rule{
+		Code:            utils.DiagnosticCodeRouteConflict,
+		DefaultSeverity: utils.SeverityError,
+		CheckState:      routeAnalysisCheck(utils.DiagnosticCodeRouteConflict),
+	}), "does not match")
+
+	content := &file.Content{Services: []file.FService{
+		{Service: kong.Service{Name: new("tagged"), Tags: []*string{new("team-a")}}},
+		{Service: kong.Service{Name: new("untagged")}},
+	}}
+	diagnostics, err := r.Run(Input{Content: content, State: testState(t)}, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, []Diagnostic{{
+		Code:     code,
+		Severity: utils.SeverityError,
+		Message:  "services must be tagged",
+		Location: Location{Kind: "service", Name: "untagged"},
+	}}, diagnostics)
+	assert.Equal(t, "error (service-missing-tags): service untagged: services must be tagged", diagnostics[0].String())
+
+	t.Run("rule errors are returned", func(t *testing.T) {
+		r := NewRegistry()
+		require.NoError(t, r.Register(Rule{
+			Code: "failing-rule",
+			CheckState: func(*state.KongState) ([]Finding, error) {
+				return nil, errors.New("boom")
+			},
+		}))
+		_, err := r.Run(Input{State: testState(t)}, Options{})
+		require.EqualError(t, err, "running rule failing-rule: boom")
+	})
+
+	t.Run("rules without checks are rejected", func(t *testing.T) {
+		require.ErrorContains(t, NewRegistry().Register(Rule{Code: "empty-rule"}), "nothing to check")
+	})
+}
+
+func TestSuppressedByComments(t *testing.T) {
+	raw := []byte(`_format_version: "3.0"
+# lint-ignore: route-conflict, route-unreachable
+services:
+  - name: svc # not a suppression
+    #lint-ignore:route-conflict
+`)
+	codes, err := SuppressedByComments(raw)
+	require.NoError(t, err)
+	assert.Equal(t, []utils.DiagnosticCode{
+		utils.DiagnosticCodeRouteConflict,
+		utils.DiagnosticCodeRouteUnreachable,
+	}, codes)
+
+	_, err = SuppressedByComments([]byte("# lint-ignore: unknown-code"))
+	require.ErrorContains(t, err, "unknown diagnostic code")
+}
+
+func TestRegistryAsBuilderLinter(t *testing.T) {
+	ctx := context.Background()
+	currentState, err := state.NewKongState()
+	require.NoError(t, err)
+	content := &file.Content{
+		Routes: []file.FRoute{
+			{Route: kong.Route{ID: new("a"), Name: new("a"), Paths: []*string{new("/foo")}}},
+		},
+		Plugins: []file.FPlugin{
+			{Plugin: kong.Plugin{
+				ID: new("oidc"), Name: new("openid-connect"),
+				Config: kong.Configuration{"issuer": "https://example.com"},
+			}},
+		},
+	}
+	r := NewRegistry()
+	require.NoError(t, r.Register(Rule{
+		Code: "route-missing-tags",
+		CheckState: func(ks *state.KongState) ([]Finding, error) {
+			routes, err := ks.Routes.GetAll()
+			if err != nil {
+				return nil, err
+			}
+			var res []Finding
+			for _, route := range routes {
+				if len(route.Tags) == 0 {
+					res = append(res, Finding{
+						Location: Location{Kind: "route", Name: route.Console()},
+						Message:  "routes must be tagged",
+					})
+				}
+			}
+			return res, nil
+		},
+	}))
+	opts := file.RenderConfig{
+		CurrentState: currentState,
+		KongVersion:  semver.MustParse("3.4.0"),
+		Linter:       r,
+	}
+
+	// the checks built into the builder are replaced by the rules of the
+	// registry, which only reports a warning here.
+	_, err = file.Get(ctx, content, opts, dump.Config{}, nil)
+	require.NoError(t, err)
+
+	_, err = file.Get(ctx, content, opts, dump.Config{
+		DiagnosticPolicy: utils.NewDiagnosticPolicy([]utils.DiagnosticCode{"route-missing-tags"}, nil),
+	}, nil)
+	require.ErrorContains(t, err, "route a: routes must be tagged")
+
+	opts.Linter = Default()
+	_, err = file.Get(ctx, content, opts, dump.Config{}, nil)
+	require.ErrorContains(t, err, "plugin openid-connect (global): openid-connect plugin requires explicit")
+}
@@ -0,0 +1,164 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kong/go-database-reconciler/pkg/atc"
+	"github.com/kong/go-database-reconciler/pkg/state"
+	"github.com/kong/go-database-reconciler/pkg/utils"
+)
+
+const (
+	routeKind  = "route"
+	pluginKind = "plugin"
+)
+
+var builtinRules = []Rule{
+	{
+		Code:            utils.DiagnosticCodeRouteRegexPathFormat,
+		DefaultSeverity: utils.SeverityWarning,
+		Description:     "regex paths of routes must be prefixed with ~ with Kong 3.0 or above",
+		CheckState:      checkRouteRegexPathFormat,
+	},
+	{
+		Code:            utils.DiagnosticCodeRouteConflict,
+		DefaultSeverity: utils.SeverityWarning,
+		Description:     "routes must not match the same requests with the same priority",
+		CheckState:      routeAnalysisCheck(utils.DiagnosticCodeRouteConflict),
+	},
+	{
+		Code:            utils.DiagnosticCodeRouteUnreachable,
+		DefaultSeverity: utils.SeverityWarning,
+		Description:     "routes must not be shadowed by routes evaluated before them",
+		CheckState:      routeAnalysisCheck(utils.DiagnosticCodeRouteUnreachable),
+	},
+	{
+		Code:            utils.DiagnosticCodeRouteExpression,
+		DefaultSeverity: utils.SeverityWarning,
+		Description:     "expressions of routes must be valid for the expressions router",
+		CheckState:      checkRouteExpressions,
+	},
+	{
+		Code:            utils.DiagnosticCodeRLAConsumerGroups,
+		DefaultSeverity: utils.SeverityError,
+		Description:     "rate-limiting-advanced plugins must not use the deprecated consumer_groups configuration",
+		CheckState:      checkRLAConsumerGroups,
+	},
+	{
+		Code:            utils.DiagnosticCodeOIDCMissingConfig,
+		DefaultSeverity: utils.SeverityError,
+		Description:     "openid-connect plugins must set the config values Kong would otherwise regenerate",
+		CheckState:      checkOIDCMissingConfig,
+	},
+}
+
+func checkRouteRegexPathFormat(ks *state.KongState) ([]Finding, error) {
+	routes, err := ks.Routes.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	var res []Finding
+	for _, r := range routes {
+		if !utils.HasPathsWithRegex300AndAbove(r.Route) {
+			continue
+		}
+		var paths []string
+		for _, p := range r.Paths {
+			if !strings.HasPrefix(*p, "~/") && utils.IsPathRegexLike(*p) {
+				paths = append(paths, *p)
+			}
+		}
+		res = append(res, Finding{
+			Location: Location{Kind: routeKind, Name: r.Console()},
+			Message:  "regex paths must be prefixed with ~: " + strings.Join(paths, ", "),
+			Tags:     r.Tags,
+		})
+	}
+	return res, nil
+}
+
+func routeAnalysisCheck(code utils.DiagnosticCode) func(*state.KongState) ([]Finding, error) {
+	return func(ks *state.KongState) ([]Finding, error) {
+		diagnostics, err := state.AnalyzeRoutes(ks.Routes)
+		if err != nil {
+			return nil, err
+		}
+		var res []Finding
+		for _, d := range diagnostics {
+			if d.Code != code {
+				continue
+			}
+			route, err := ks.Routes.Get(d.Route)
+			if err != nil {
+				return nil, fmt.Errorf("looking up route %s: %w", d.Route, err)
+			}
+			res = append(res, Finding{
+				Location: Location{Kind: routeKind, Name: d.Route},
+				Message:  d.String(),
+				Tags:     route.Tags,
+			})
+		}
+		return res, nil
+	}
+}
+
+func checkRouteExpressions(ks *state.KongState) ([]Finding, error) {
+	routes, err := ks.Routes.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	var res []Finding
+	for _, r := range routes {
+		if utils.Empty(r.Expression) {
+			continue
+		}
+		if err := atc.Validate(*r.Expression); err != nil {
+			res = append(res, Finding{
+				Location: Location{Kind: routeKind, Name: r.Console()},
+				Message:  "invalid expression: " + err.Error(),
+				Tags:     r.Tags,
+			})
+		}
+	}
+	return res, nil
+}
+
+func checkRLAConsumerGroups(ks *state.KongState) ([]Finding, error) {
+	plugins, err := ks.Plugins.GetAllByName("rate-limiting-advanced")
+	if err != nil {
+		return nil, err
+	}
+	var res []Finding
+	for _, p := range plugins {
+		if !utils.UsesRLAConsumerGroups(p.Config) {
+			continue
+		}
+		res = append(res, Finding{
+			Location: Location{Kind: pluginKind, Name: p.Console()},
+			Message:  utils.ErrorConsumerGroupUpgrade.Error(),
+			Tags:     p.Tags,
+		})
+	}
+	return res, nil
+}
+
+func checkOIDCMissingConfig(ks *state.KongState) ([]Finding, error) {
+	plugins, err := ks.Plugins.GetAllByName("openid-connect")
+	if err != nil {
+		return nil, err
+	}
+	var res []Finding
+	for _, p := range plugins {
+		missingFields := utils.MissingOpenIDConnectConfig(p.Config)
+		if len(missingFields) == 0 {
+			continue
+		}
+		res = append(res, Finding{
+			Location: Location{Kind: pluginKind, Name: p.Console()},
+			Message:  utils.OpenIDConnectMissingConfigMessage(missingFields),
+			Tags:     p.Tags,
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,147 @@
+package policy
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/ext"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Expressions are written in CEL (https://cel.dev) and evaluated with
+// cel-go. On top of the standard definitions, they can use the string
+// extensions of cel-go (e.g. s.lowerAscii(), s.split(sep)), optional
+// field selection (e.g. self.?tags.orValue([])) and flatten(map), which
+// returns the leaves of nested maps and lists keyed by their dotted path,
+// e.g. "session.secret" or "headers.0".
+//
+// Entities are seen as decoded from JSON: numbers are doubles, and fields
+// which are not set are absent, so that they must be tested with has() or
+// selected as optional values.
+
+// newEnv returns the CEL environment declaring the variables available to
+// policies.
+var newEnv = sync.OnceValues(func() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("self", cel.DynType),
+		cel.Variable("plugins", cel.ListType(cel.DynType)),
+		cel.Variable("service", cel.DynType),
+		cel.OptionalTypes(),
+		ext.Strings(),
+		cel.Function("flatten",
+			cel.Overload("flatten_map",
+				[]*cel.Type{cel.MapType(cel.StringType, cel.DynType)},
+				cel.MapType(cel.StringType, cel.DynType),
+				cel.UnaryBinding(flattenValue),
+			),
+		),
+	)
+})
+
+// Expression is a compiled expression.
+type Expression struct {
+	source     string
+	outputType *cel.Type
+	program    cel.Program
+}
+
+// Compile parses and type-checks an expression.
+func Compile(source string) (*Expression, error) {
+	env, err := newEnv()
+	if err != nil {
+		return nil, fmt.Errorf("creating CEL environment: %w", err)
+	}
+	ast, issues := env.Compile(source)
+	if issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	// OptOptimize folds constants, including the patterns given to matches().
+	program, err := env.Program(ast, cel.EvalOptions(cel.OptOptimize))
+	if err != nil {
+		return nil, err
+	}
+	return &Expression{source: source, outputType: ast.OutputType(), program: program}, nil
+}
+
+// compileCondition compiles an expression which must produce a boolean.
+func compileCondition(source string) (*Expression, error) {
+	e, err := Compile(source)
+	if err != nil {
+		return nil, err
+	}
+	if !e.outputType.IsExactType(cel.BoolType) && !e.outputType.IsExactType(cel.DynType) {
+		return nil, fmt.Errorf("expression %q evaluates to %s, not a boolean", source, e.outputType)
+	}
+	return e, nil
+}
+
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Eval evaluates the expression with the given variables, and returns its
+// result as it would be decoded from JSON.
+func (e *Expression) Eval(vars map[string]any) (any, error) {
+	out, _, err := e.program.Eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	v, err := out.ConvertToNative(reflect.TypeFor[*structpb.Value]())
+	if err != nil {
+		return nil, err
+	}
+	return v.(*structpb.Value).AsInterface(), nil
+}
+
+// EvalBool evaluates the expression, which must produce a boolean.
+func (e *Expression) EvalBool(vars map[string]any) (bool, error) {
+	out, _, err := e.program.Eval(vars)
+	if err != nil {
+		return false, err
+	}
+	b, ok := out.(types.Bool)
+	if !ok {
+		return false, fmt.Errorf("expression %q evaluates to %s, not a boolean", e.source, out.Type().TypeName())
+	}
+	return bool(b), nil
+}
+
+func flattenValue(arg ref.Val) ref.Val {
+	v, err := arg.ConvertToNative(reflect.TypeFor[*structpb.Value]())
+	if err != nil {
+		return types.WrapErr(err)
+	}
+	res := map[string]any{}
+	flatten("", v.(*structpb.Value).AsInterface(), res)
+	return types.DefaultTypeAdapter.NativeToValue(res)
+}
+
+// flatten collects the leaves of nested maps and lists into res, keyed by
+// their dotted path, e.g. "session.secret" or "headers.0".
+func flatten(prefix string, v any, res map[string]any) {
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + "." + key
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		for k, item := range v {
+			flatten(join(k), item, res)
+		}
+	case []any:
+		for i, item := range v {
+			flatten(join(strconv.Itoa(i)), item, res)
+		}
+	default:
+		if prefix != "" {
+			res[prefix] = v
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpressionEval(t *testing.T) {
+	vars := map[string]any{
+		"self": map[string]any{
+			"name":      "svc",
+			"port":      float64(8080),
+			"protocols": []any{"http", "https"},
+			"tags":      []any{"team-a", "public"},
+			"config": map[string]any{
+				"session": map[string]any{"secret": "{vault://env/secret}", "ttl": float64(60)},
+				"headers": []any{"x-a"},
+			},
+		},
+		"plugins": []any{map[string]any{"name": "key-auth"}},
+		"service": nil,
+	}
+	tests := []struct {
+		expression string
+		want       any
+	}{
+		{`self.name == "svc"`, true},
+		{`self.name != 'svc'`, false},
+		{`"public" in self.tags && !("internal" in self.tags)`, true},
+		{`self.port > 8000 && self.port <= 8080 && int(self.port) % 2 == 0`, true},
+		{`self.port + 1.0`, float64(8081)},
+		{`self.protocols.all(p, p.startsWith("http"))`, true},
+		{`self.protocols.exists(p, p == "grpc")`, false},
+		{`self.protocols.exists_one(p, p.endsWith("s"))`, true},
+		{`self.protocols.filter(p, p != "http")`, []any{"https"}},
+		{`self.tags.map(t, t.upperAscii())`, []any{"TEAM-A", "PUBLIC"}},
+		{`size(self.tags) == 2 && self.tags.size() == 2 && size(self.name) == 3`, true},
+		{`self.tags[0].contains("team") && self.name.matches("^s.c$")`, true},
+		{`self.config["session"].ttl == 60`, true},
+		{`has(self.config.session) && !has(self.missing)`, true},
+		{`self.?missing.orValue("default")`, "default"},
+		{`flatten(self.config)`, map[string]any{
+			"session.secret": "{vault://env/secret}",
+			"session.ttl":    float64(60),
+			"headers.0":      "x-a",
+		}},
+		{
+			`flatten(self.config).exists(k, k.endsWith("secret") && !flatten(self.config)[k].startsWith("{vault://"))`,
+			false,
+		},
+		{`"session" in self.config`, true},
+		{`self.port > 80.0 ? "high" : "low"`, "high"},
+		{`string(int(self.port)) + ":" + self.name`, "8080:svc"},
+		{`plugins.exists(p, p.name == "key-auth") && service == null`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			e, err := Compile(tt.expression)
+			require.NoError(t, err)
+			got, err := e.Eval(vars)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExpressionErrors(t *testing.T) {
+	compileErrors := map[string]string{
+		`self.name ==`:            "Syntax error",
+		`self.name = "svc"`:       "Syntax error",
+		`lower(self.name)`:        "undeclared reference to 'lower'",
+		`other == 1`:              "undeclared reference to 'other'",
+		`"a" + 1`:                 "found no matching overload for '_+_'",
+		`self.name.startsWith(1)`: "found no matching overload for 'startsWith'",
+	}
+	for expression, want := range compileErrors {
+		t.Run(expression, func(t *testing.T) {
+			_, err := Compile(expression)
+			require.ErrorContains(t, err, want)
+		})
+	}
+
+	evalErrors := map[string]string{
+		`self.missing == 1`:          "no such key: missing",
+		`self.name > 1`:              "no such overload",
+		`self.tags[5]`:               "index out of bounds: 5",
+		`flatten(self.name).size()`:  "no such overload",
+		`self.name.matches(self.re)`: "error parsing regexp: missing closing ]",
+		`self.tags.all(t, t)`:        "no such overload",
+	}
+	vars := map[string]any{"self": map[string]any{"name": "svc", "tags": []any{"a"}, "re": "["}}
+	for expression, want := range evalErrors {
+		t.Run(expression, func(t *testing.T) {
+			e, err := Compile(expression)
+			require.NoError(t, err)
+			_, err = e.Eval(vars)
+			require.ErrorContains(t, err, want)
+		})
+	}
+
+	t.Run("non-boolean result", func(t *testing.T) {
+		e, err := Compile(`self.name`)
+		require.NoError(t, err)
+		_, err = e.EvalBool(vars)
+		require.EqualError(t, err, `expression "self.name" evaluates to string, not a boolean`)
+
+		_, err = compileCondition(`size(self.name)`)
+		require.EqualError(t, err, `expression "size(self.name)" evaluates to int, not a boolean`)
+	})
+}
@@ -0,0 +1,399 @@
+package policy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kong/go-database-reconciler/pkg/state"
+	"github.com/kong/go-database-reconciler/pkg/utils"
+	"github.com/kong/go-kong/kong"
+)
+
+// Policy is a rule that the entities of a given kind must satisfy.
+//
+// When and Rule are CEL expressions, evaluated once per entity with the
+// following variables:
+//
+//   - self: the entity, with the fields of its Admin API representation
+//   - plugins: for services, routes, consumers and consumer-groups, the
+//     plugins applied to the entity, including global plugins and, for
+//     routes, the plugins of their service
+//   - service: for routes, the service of the route, or null
+type Policy struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Kind is the entity type the policy applies to, e.g. "route".
+	Kind string `json:"kind" yaml:"kind"`
+	// When optionally restricts the policy to the entities for which it
+	// evaluates to true.
+	When string `json:"when,omitempty" yaml:"when,omitempty"`
+	// Rule must evaluate to true for every entity the policy applies to.
+	Rule string `json:"rule" yaml:"rule"`
+	// Message is reported for violations, defaults to the description.
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+// Violation is an entity that does not satisfy a policy.
+type Violation struct {
+	Policy string `json:"policy"`
+	Kind   string `json:"kind"`
+	// Entity is the human-readable name of the entity, as returned by Console().
+	Entity  string `json:"entity"`
+	Message string `json:"message"`
+}
+
+func (v *Violation) Error() string {
+	msg := fmt.Sprintf("policy %s violated by %s %s", v.Policy, v.Kind, v.Entity)
+	if v.Message != "" {
+		msg += ": " + v.Message
+	}
+	return msg
+}
+
+type compiledPolicy struct {
+	Policy
+	when, rule *Expression
+}
+
+// Engine evaluates a set of compiled policies.
+type Engine struct {
+	policies []compiledPolicy
+}
+
+// NewEngine compiles the policies, and fails if any of them is invalid.
+func NewEngine(policies []Policy) (*Engine, error) {
+	e := &Engine{}
+	var errs utils.ErrArray
+	for _, p := range policies {
+		c, err := compile(p)
+		if err != nil {
+			errs.Errors = append(errs.Errors, err)
+			continue
+		}
+		e.policies = append(e.policies, c)
+	}
+	if len(errs.Errors) > 0 {
+		return nil, errs
+	}
+	return e, nil
+}
+
+func compile(p Policy) (compiledPolicy, error) {
+	c := compiledPolicy{Policy: p}
+	if p.Name == "" {
+		return c, fmt.Errorf("policy name is required")
+	}
+	if _, ok := entityLoaders[p.Kind]; !ok {
+		return c, fmt.Errorf("policy %s: unsupported kind %q, supported kinds are: %s",
+			p.Name, p.Kind, strings.Join(SupportedKinds(), ", "))
+	}
+	var err error
+	if c.rule, err = compileCondition(p.Rule); err != nil {
+		return c, fmt.Errorf("policy %s: rule: %w", p.Name, err)
+	}
+	if p.When != "" {
+		if c.when, err = compileCondition(p.When); err != nil {
+			return c, fmt.Errorf("policy %s: when: %w", p.Name, err)
+		}
+	}
+	return c, nil
+}
+
+// SupportedKinds returns the entity kinds policies can apply to.
+func SupportedKinds() []string {
+	res := make([]string, 0, len(entityLoaders))
+	for kind := range entityLoaders {
+		res = append(res, kind)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Evaluate compiles the policies and evaluates them against the state.
+func Evaluate(ks *state.KongState, policies []Policy) ([]Violation, error) {
+	e, err := NewEngine(policies)
+	if err != nil {
+		return nil, err
+	}
+	return e.Evaluate(ks)
+}
+
+// Evaluate evaluates the policies against the state, usually the target state
+// of a sync, and returns the violations sorted by policy, kind and entity.
+// Errors evaluating an expression are returned as errors, not violations.
+func (e *Engine) Evaluate(ks *state.KongState) ([]Violation, error) {
+	if ks == nil {
+		return nil, fmt.Errorf("state is nil")
+	}
+	idx, err := newIndex(ks)
+	if err != nil {
+		return nil, err
+	}
+	var violations []Violation
+	for _, p := range e.policies {
+		entities, err := entityLoaders[p.Kind](ks, idx)
+		if err != nil {
+			return nil, fmt.Errorf("policy %s: listing %s entities: %w", p.Name, p.Kind, err)
+		}
+		for _, ent := range entities {
+			ok, err := p.check(ent.vars)
+			if err != nil {
+				return nil, fmt.Errorf("policy %s: evaluating %s %s: %w", p.Name, p.Kind, ent.console, err)
+			}
+			if ok {
+				continue
+			}
+			message := p.Message
+			if message == "" {
+				message = p.Description
+			}
+			violations = append(violations, Violation{
+				Policy:  p.Name,
+				Kind:    p.Kind,
+				Entity:  ent.console,
+				Message: message,
+			})
+		}
+	}
+	sort.SliceStable(violations, func(i, j int) bool {
+		a, b := violations[i], violations[j]
+		if a.Policy != b.Policy {
+			return a.Policy < b.Policy
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Entity < b.Entity
+	})
+	return violations, nil
+}
+
+// check returns false if the policy applies to the entity and its rule
+// does not hold.
+func (p compiledPolicy) check(vars map[string]any) (bool, error) {
+	if p.when != nil {
+		applies, err := p.when.EvalBool(vars)
+		if err != nil {
+			return false, fmt.Errorf("when: %w", err)
+		}
+		if !applies {
+			return true, nil
+		}
+	}
+	ok, err := p.rule.EvalBool(vars)
+	if err != nil {
+		return false, fmt.Errorf("rule: %w", err)
+	}
+	return ok, nil
+}
+
+// ViolationsError returns the violations as a utils.ErrArray, or nil if
+// there are none.
+func ViolationsError(violations []Violation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	var errs utils.ErrArray
+	for i := range violations {
+		errs.Errors = append(errs.Errors, &violations[i])
+	}
+	return errs
+}
+
+// entity is an entity to evaluate policies against.
+type entity struct {
+	console string
+	vars    map[string]any
+}
+
+// toValue converts an entity to its JSON representation, as seen by
+// expressions.
+func toValue(obj any) (any, error) {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var res any
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// index holds the plugins of the state, to resolve the plugins applied to
+// each entity.
+type index struct {
+	plugins []*state.Plugin
+	values  map[*state.Plugin]any
+}
+
+func newIndex(ks *state.KongState) (*index, error) {
+	plugins, err := ks.Plugins.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	idx := &index{plugins: plugins, values: map[*state.Plugin]any{}}
+	for _, p := range plugins {
+		v, err := toValue(p.Plugin)
+		if err != nil {
+			return nil, err
+		}
+		idx.values[p] = v
+	}
+	return idx, nil
+}
+
+func refID[T any](ref *T, id func(*T) *string) string {
+	if ref == nil {
+		return ""
+	}
+	return kong.StringValue(id(ref))
+}
+
+// pluginsFor returns the plugins for which applies returns true, as well
+// as global plugins.
+func (idx *index) pluginsFor(applies func(p *state.Plugin) bool) []any {
+	res := []any{}
+	for _, p := range idx.plugins {
+		global := p.Service == nil && p.Route == nil && p.Consumer == nil && p.ConsumerGroup == nil
+		if global || applies(p) {
+			res = append(res, idx.values[p])
+		}
+	}
+	return res
+}
+
+func serviceID(s *kong.Service) *string             { return s.ID }
+func routeID(r *kong.Route) *string                 { return r.ID }
+func consumerID(c *kong.Consumer) *string           { return c.ID }
+func consumerGroupID(c *kong.ConsumerGroup) *string { return c.ID }
+
+type loader func(ks *state.KongState, idx *index) ([]entity, error)
+
+// newEntities converts objects into entities, extra returns the variables
+// other than self.
+func newEntities[T state.ConsoleString](objs []T, value func(T) any, extra func(T) map[string]any) ([]entity, error) {
+	res := make([]entity, 0, len(objs))
+	for _, obj := range objs {
+		self, err := toValue(value(obj))
+		if err != nil {
+			return nil, err
+		}
+		vars := map[string]any{"self": self, "plugins": []any{}, "service": nil}
+		if extra != nil {
+			for k, v := range extra(obj) {
+				vars[k] = v
+			}
+		}
+		res = append(res, entity{console: obj.Console(), vars: vars})
+	}
+	return res, nil
+}
+
+func simpleLoader[T state.ConsoleString](getAll func(ks *state.KongState) ([]T, error), value func(T) any) loader {
+	return func(ks *state.KongState, _ *index) ([]entity, error) {
+		objs, err := getAll(ks)
+		if err != nil {
+			return nil, err
+		}
+		return newEntities(objs, value, nil)
+	}
+}
+
+var entityLoaders = map[string]loader{
+	"service": func(ks *state.KongState, idx *index) ([]entity, error) {
+		services, err := ks.Services.GetAll()
+		if err != nil {
+			return nil, err
+		}
+		return newEntities(services, func(s *state.Service) any { return s.Service },
+			func(s *state.Service) map[string]any {
+				id := kong.StringValue(s.ID)
+				return map[string]any{"plugins": idx.pluginsFor(func(p *state.Plugin) bool {
+					return p.Route == nil && refID(p.Service, serviceID) == id
+				})}
+			})
+	},
+	"route": func(ks *state.KongState, idx *index) ([]entity, error) {
+		routes, err := ks.Routes.GetAll()
+		if err != nil {
+			return nil, err
+		}
+		services := map[string]any{}
+		for _, r := range routes {
+			id := refID(r.Service, serviceID)
+			if _, ok := services[id]; id == "" || ok {
+				continue
+			}
+			s, err := ks.Services.Get(id)
+			if errors.Is(err, state.ErrNotFound) {
+				// dangling references are reported by state.Validate.
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("looking up service of route %s: %w", r.Console(), err)
+			}
+			if services[id], err = toValue(s.Service); err != nil {
+				return nil, err
+			}
+		}
+		return newEntities(routes, func(r *state.Route) any { return r.Route },
+			func(r *state.Route) map[string]any {
+				id, svcID := kong.StringValue(r.ID), refID(r.Service, serviceID)
+				return map[string]any{
+					"service": services[svcID],
+					"plugins": idx.pluginsFor(func(p *state.Plugin) bool {
+						if p.Route != nil {
+							return refID(p.Route, routeID) == id
+						}
+						return svcID != "" && refID(p.Service, serviceID) == svcID
+					}),
+				}
+			})
+	},
+	"consumer": func(ks *state.KongState, idx *index) ([]entity, error) {
+		consumers, err := ks.Consumers.GetAll()
+		if err != nil {
+			return nil, err
+		}
+		return newEntities(consumers, func(c *state.Consumer) any { return c.Consumer },
+			func(c *state.Consumer) map[string]any {
+				id := kong.StringValue(c.ID)
+				return map[string]any{"plugins": idx.pluginsFor(func(p *state.Plugin) bool {
+					return refID(p.Consumer, consumerID) == id
+				})}
+			})
+	},
+	"consumer-group": func(ks *state.KongState, idx *index) ([]entity, error) {
+		groups, err := ks.ConsumerGroups.GetAll()
+		if err != nil {
+			return nil, err
+		}
+		return newEntities(groups, func(c *state.ConsumerGroup) any { return c.ConsumerGroup },
+			func(c *state.ConsumerGroup) map[string]any {
+				id := kong.StringValue(c.ID)
+				return map[string]any{"plugins": idx.pluginsFor(func(p *state.Plugin) bool {
+					return refID(p.ConsumerGroup, consumerGroupID) == id
+				})}
+			})
+	},
+	"plugin": simpleLoader(func(ks *state.KongState) ([]*state.Plugin, error) { return ks.Plugins.GetAll() },
+		func(p *state.Plugin) any { return p.Plugin }),
+	"upstream": simpleLoader(func(ks *state.KongState) ([]*state.Upstream, error) { return ks.Upstreams.GetAll() },
+		func(u *state.Upstream) any { return u.Upstream }),
+	"target": simpleLoader(func(ks *state.KongState) ([]*state.Target, error) { return ks.Targets.GetAll() },
+		func(t *state.Target) any { return t.Target }),
+	"certificate": simpleLoader(
+		func(ks *state.KongState) ([]*state.Certificate, error) { return ks.Certificates.GetAll() },
+		func(c *state.Certificate) any { return c.Certificate }),
+	"ca-certificate": simpleLoader(
+		func(ks *state.KongState) ([]*state.CACertificate, error) { return ks.CACertificates.GetAll() },
+		func(c *state.CACertificate) any { return c.CACertificate }),
+	"sni": simpleLoader(func(ks *state.KongState) ([]*state.SNI, error) { return ks.SNIs.GetAll() },
+		func(s *state.SNI) any { return s.SNI }),
+	"vault": simpleLoader(func(ks *state.KongState) ([]*state.Vault, error) { return ks.Vaults.GetAll() },
+		func(v *state.Vault) any { return v.Vault }),
+}
@@ -0,0 +1,153 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/kong/go-database-reconciler/pkg/state"
+	"github.com/kong/go-database-reconciler/pkg/utils"
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var examplePolicies = []Policy{
+	{
+		Name:        "route-auth",
+		Description: "every route must be protected by an authentication plugin",
+		Kind:        "route",
+		Rule:        `plugins.exists(p, p.name in ["key-auth", "basic-auth", "jwt", "openid-connect"])`,
+	},
+	{
+		Name:    "public-https",
+		Kind:    "service",
+		When:    `"public" in self.tags`,
+		Rule:    `self.protocol != "http"`,
+		Message: "public services must not use plain http",
+	},
+	{
+		Name: "tier-rate-limiting",
+		Kind: "service",
+		When: `self.tags.exists(t, t.startsWith("tier:"))`,
+		Rule: `plugins.exists(p, p.name.startsWith("rate-limiting"))`,
+	},
+	{
+		Name: "no-plaintext-secrets",
+		Kind: "plugin",
+		Rule: `!has(self.config) || flatten(self.config).all(k, ` +
+			`!k.matches("(?i)(secret|password|token)(\\.[0-9]+)?$") || ` +
+			`string(flatten(self.config)[k]).startsWith("{vault://"))`,
+	},
+}
+
+func exampleState(t *testing.T) *state.KongState {
+	t.Helper()
+	ks, err := state.NewKongState()
+	require.NoError(t, err)
+	services := []kong.Service{
+		{ID: new("public-id"), Name: new("public"), Protocol: new("http"), Tags: []*string{new("public")}},
+		{ID: new("internal-id"), Name: new("internal"), Protocol: new("http"), Tags: []*string{new("tier:gold")}},
+	}
+	for _, s := range services {
+		require.NoError(t, ks.Services.Add(state.Service{Service: s}))
+	}
+	routes := []kong.Route{
+		{ID: new("open-id"), Name: new("open"), Service: &kong.Service{ID: new("public-id")}},
+		{ID: new("protected-id"), Name: new("protected"), Service: &kong.Service{ID: new("internal-id")}},
+	}
+	for _, r := range routes {
+		require.NoError(t, ks.Routes.Add(state.Route{Route: r}))
+	}
+	plugins := []kong.Plugin{
+		{
+			ID: new("key-auth-id"), Name: new("key-auth"),
+			Service: &kong.Service{ID: new("internal-id")},
+		},
+		{
+			ID: new("oidc-id"), Name: new("openid-connect"),
+			Route: &kong.Route{ID: new("protected-id")},
+			Config: kong.Configuration{
+				"client_secret": []any{"plaintext"},
+				"session": map[string]any{
+					"secret": "{vault://env/session-secret}",
+				},
+			},
+		},
+	}
+	for _, p := range plugins {
+		require.NoError(t, ks.Plugins.Add(state.Plugin{Plugin: p}))
+	}
+	return ks
+}
+
+func TestEvaluate(t *testing.T) {
+	violations, err := Evaluate(exampleState(t), examplePolicies)
+	require.NoError(t, err)
+	assert.Equal(t, []Violation{
+		{
+			Policy:  "no-plaintext-secrets",
+			Kind:    "plugin",
+			Entity:  "openid-connect for route protected-id",
+			Message: "",
+		},
+		{
+			Policy:  "public-https",
+			Kind:    "service",
+			Entity:  "public",
+			Message: "public services must not use plain http",
+		},
+		{
+			Policy:  "route-auth",
+			Kind:    "route",
+			Entity:  "open",
+			Message: "every route must be protected by an authentication plugin",
+		},
+		{
+			Policy: "tier-rate-limiting",
+			Kind:   "service",
+			Entity: "internal",
+		},
+	}, violations)
+
+	var errs utils.ErrArray
+	require.ErrorAs(t, ViolationsError(violations), &errs)
+	require.Len(t, errs.Errors, 4)
+	assert.EqualError(t, errs.Errors[1],
+		"policy public-https violated by service public: public services must not use plain http")
+	assert.NoError(t, ViolationsError(nil))
+
+	t.Run("route variables", func(t *testing.T) {
+		violations, err := Evaluate(exampleState(t), []Policy{{
+			Name: "service-plugins",
+			Kind: "route",
+			Rule: `service.name == "internal" && plugins.map(p, p.name) == ["key-auth", "openid-connect"]`,
+		}})
+		require.NoError(t, err)
+		require.Len(t, violations, 1)
+		assert.Equal(t, "open", violations[0].Entity)
+	})
+}
+
+func TestNewEngineErrors(t *testing.T) {
+	_, err := NewEngine([]Policy{
+		{Kind: "service", Rule: "true"},
+		{Name: "kind", Kind: "workspace", Rule: "true"},
+		{Name: "rule", Kind: "service", Rule: "self."},
+		{Name: "when", Kind: "service", When: "(", Rule: "true"},
+		{Name: "boolean", Kind: "service", Rule: "size(self.name)"},
+	})
+	var errs utils.ErrArray
+	require.ErrorAs(t, err, &errs)
+	require.Len(t, errs.Errors, 5)
+	assert.EqualError(t, errs.Errors[0], "policy name is required")
+	assert.ErrorContains(t, errs.Errors[1], `policy kind: unsupported kind "workspace"`)
+	assert.ErrorContains(t, errs.Errors[2], `policy rule: rule: ERROR: <input>:1:6: Syntax error`)
+	assert.ErrorContains(t, errs.Errors[3], `policy when: when: ERROR: <input>:1:2: Syntax error`)
+	assert.EqualError(t, errs.Errors[4],
+		`policy boolean: rule: expression "size(self.name)" evaluates to int, not a boolean`)
+
+	t.Run("evaluation errors", func(t *testing.T) {
+		_, err := Evaluate(exampleState(t), []Policy{{Name: "bad", Kind: "service", Rule: "self.name"}})
+		require.ErrorContains(t, err, "policy bad: evaluating service")
+		require.ErrorContains(t, err, "evaluates to string, not a boolean")
+	})
+}
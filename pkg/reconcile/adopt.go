@@ -0,0 +1,238 @@
+package reconcile
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/kong/go-database-reconciler/pkg/types"
+	"github.com/kong/go-database-reconciler/pkg/utils"
+	"github.com/kong/go-kong/kong"
+)
+
+// Adoption reports an entity of Kong adopted into management: the target
+// entity matching it by natural key reuses its ID, so the entity is updated
+// rather than deleted and created again.
+type Adoption struct {
+	Kind types.EntityType
+	// Key is the natural key the entity was matched by, such as the name of
+	// a service or the username of a consumer.
+	Key string
+	// ID is the ID of the entity in Kong.
+	ID string
+}
+
+// adopter matches the entities of a target state to the live entities of Kong
+// by natural key.
+type adopter struct {
+	target  *utils.KongRawState
+	current *utils.KongRawState
+	live    *utils.KongRawState
+
+	// managed holds the IDs of the entities of the current state.
+	managed map[string]bool
+	// ids maps the IDs of adopted target entities to the IDs of the live
+	// ones.
+	ids map[string]string
+
+	adopted []Adoption
+}
+
+// adopt reuses in target the IDs of the entities of live matching an entity
+// of target by natural key. live is the state of Kong regardless of selector
+// tags; the adopted entities missing from current, because they do not carry
+// the selector tags, are added to it so that they are updated rather than
+// created.
+//
+// Services, routes, upstreams and consumer groups are matched by name,
+// consumers by username or custom_id, targets by upstream and address, and
+// plugins by name and scope.
+func adopt(target, current, live *utils.KongRawState) []Adoption {
+	a := &adopter{
+		target:  target,
+		current: current,
+		live:    live,
+		managed: map[string]bool{},
+		ids:     map[string]string{},
+	}
+	forEachEntity(current, func(entity reflect.Value) {
+		if id := entityID(entity); id != "" {
+			a.managed[id] = true
+		}
+	})
+
+	adoptKind(a, types.Service, target.Services, live.Services, &current.Services,
+		func(s *kong.Service) string { return kong.StringValue(s.Name) })
+	adoptKind(a, types.Route, target.Routes, live.Routes, &current.Routes,
+		func(r *kong.Route) string { return kong.StringValue(r.Name) })
+	adoptKind(a, types.Upstream, target.Upstreams, live.Upstreams, &current.Upstreams,
+		func(u *kong.Upstream) string { return kong.StringValue(u.Name) })
+	adoptKind(a, types.Target, target.Targets, live.Targets, &current.Targets, targetKey)
+	adoptKind(a, types.Consumer, target.Consumers, live.Consumers, &current.Consumers, consumerKey)
+	a.adoptConsumerGroups()
+	adoptKind(a, types.Plugin, target.Plugins, live.Plugins, &current.Plugins, pluginKey)
+
+	forEachEntity(target, a.remapReferences)
+	return a.adopted
+}
+
+// adoptKind adopts the entities of live matching an entity of target by key.
+// Entities with an empty key are never adopted.
+func adoptKind[T any](a *adopter, kind types.EntityType, target, live []*T, current *[]*T,
+	key func(*T) string,
+) {
+	byKey := map[string]*T{}
+	for _, entity := range live {
+		if k := key(entity); k != "" {
+			byKey[k] = entity
+		}
+	}
+	for _, entity := range target {
+		// References of the entity may point to adopted entities, and are
+		// part of its natural key.
+		a.remapReferences(reflect.ValueOf(entity))
+		k := key(entity)
+		liveEntity, ok := byKey[k]
+		if k == "" || !ok {
+			continue
+		}
+		id := entityID(reflect.ValueOf(entity))
+		liveID := entityID(reflect.ValueOf(liveEntity))
+		if id == liveID && a.managed[liveID] {
+			continue
+		}
+		if id != liveID {
+			a.ids[id] = liveID
+			reflect.ValueOf(entity).Elem().FieldByName("ID").Set(reflect.ValueOf(new(liveID)))
+		}
+		if !a.managed[liveID] {
+			*current = append(*current, liveEntity)
+			a.managed[liveID] = true
+		}
+		a.adopted = append(a.adopted, Adoption{Kind: kind, Key: k, ID: liveID})
+	}
+}
+
+// adoptConsumerGroups adopts consumer groups by name. Their consumers and
+// plugins are adopted by adoptKind and remapReferences.
+func (a *adopter) adoptConsumerGroups() {
+	groups := func(objects []*kong.ConsumerGroupObject) []*kong.ConsumerGroup {
+		res := make([]*kong.ConsumerGroup, 0, len(objects))
+		for _, object := range objects {
+			if object.ConsumerGroup != nil {
+				res = append(res, object.ConsumerGroup)
+			}
+		}
+		return res
+	}
+	var adopted []*kong.ConsumerGroup
+	adoptKind(a, types.ConsumerGroup, groups(a.target.ConsumerGroups), groups(a.live.ConsumerGroups), &adopted,
+		func(g *kong.ConsumerGroup) string { return kong.StringValue(g.Name) })
+	for _, group := range adopted {
+		// Memberships and plugins of the group are not managed until the
+		// target state sets them.
+		a.current.ConsumerGroups = append(a.current.ConsumerGroups, &kong.ConsumerGroupObject{ConsumerGroup: group})
+	}
+}
+
+// remapReferences updates the references of entity to adopted entities.
+func (a *adopter) remapReferences(entity reflect.Value) {
+	if len(a.ids) == 0 {
+		return
+	}
+	v := reflect.Indirect(entity)
+	if group, ok := v.Addr().Interface().(*kong.ConsumerGroupObject); ok {
+		for _, consumer := range group.Consumers {
+			a.remapReferences(reflect.ValueOf(consumer))
+			a.remapID(reflect.ValueOf(consumer))
+		}
+		for _, plugin := range group.Plugins {
+			a.remapReferences(reflect.ValueOf(plugin))
+		}
+		return
+	}
+	for _, field := range referenceFields {
+		ref := v.FieldByName(field)
+		if ref.IsValid() && ref.Kind() == reflect.Pointer && !ref.IsNil() {
+			a.remapID(ref)
+		}
+	}
+}
+
+// remapID sets the ID of entity to the one of the entity it was adopted as.
+func (a *adopter) remapID(entity reflect.Value) {
+	if liveID, ok := a.ids[entityID(entity)]; ok {
+		entity.Elem().FieldByName("ID").Set(reflect.ValueOf(new(liveID)))
+	}
+}
+
+// forEachEntity calls fn with a pointer to each entity of raw.
+func forEachEntity(raw *utils.KongRawState, fn func(entity reflect.Value)) {
+	v := reflect.ValueOf(raw).Elem()
+	for i := range v.NumField() {
+		field := v.Field(i)
+		if field.Kind() != reflect.Slice {
+			continue
+		}
+		for j := range field.Len() {
+			entity := field.Index(j)
+			if entity.Kind() == reflect.Pointer && !entity.IsNil() && entity.Elem().Kind() == reflect.Struct {
+				fn(entity)
+			}
+		}
+	}
+}
+
+// entityID returns the ID of entity, a pointer to a Kong entity.
+func entityID(entity reflect.Value) string {
+	v := reflect.Indirect(entity)
+	if group, ok := v.Addr().Interface().(*kong.ConsumerGroupObject); ok {
+		if group.ConsumerGroup == nil {
+			return ""
+		}
+		return kong.StringValue(group.ConsumerGroup.ID)
+	}
+	id := v.FieldByName("ID")
+	if !id.IsValid() || id.Kind() != reflect.Pointer || id.IsNil() {
+		return ""
+	}
+	return id.Elem().String()
+}
+
+func targetKey(t *kong.Target) string {
+	if t.Upstream == nil || t.Upstream.ID == nil || t.Target == nil {
+		return ""
+	}
+	return *t.Upstream.ID + " " + *t.Target
+}
+
+func consumerKey(c *kong.Consumer) string {
+	if !utils.Empty(c.Username) {
+		return "username:" + *c.Username
+	}
+	if !utils.Empty(c.CustomID) {
+		return "custom_id:" + *c.CustomID
+	}
+	return ""
+}
+
+// pluginKey returns the name of p and the IDs of the entities it is scoped
+// to.
+func pluginKey(p *kong.Plugin) string {
+	if p.Name == nil {
+		return ""
+	}
+	scope := []string{*p.Name}
+	if p.Service != nil {
+		scope = append(scope, "service:"+kong.StringValue(p.Service.ID))
+	}
+	if p.Route != nil {
+		scope = append(scope, "route:"+kong.StringValue(p.Route.ID))
+	}
+	if p.Consumer != nil {
+		scope = append(scope, "consumer:"+kong.StringValue(p.Consumer.ID))
+	}
+	if p.ConsumerGroup != nil {
+		scope = append(scope, "consumer_group:"+kong.StringValue(p.ConsumerGroup.ID))
+	}
+	return strings.Join(scope, " ")
+}
@@ -0,0 +1,100 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kong/go-database-reconciler/pkg/file"
+	"github.com/kong/go-database-reconciler/pkg/types"
+	"github.com/kong/go-database-reconciler/pkg/utils"
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncAdopt(t *testing.T) {
+	fake, client := newFakeKongClient(t, map[string][]map[string]any{
+		"services": {{"id": "s1", "name": "svc", "host": "svc.example.com"}},
+		"routes": {{
+			"id": "r1", "name": "route", "paths": []string{"/"},
+			"service": map[string]any{"id": "s1"},
+		}},
+		"consumers": {{"id": "c1", "username": "alice"}},
+	})
+	content := &file.Content{
+		FormatVersion: "3.0",
+		Info:          &file.Info{SelectorTags: []string{"team:a"}},
+		Services: []file.FService{{
+			Service: kong.Service{Name: kong.String("svc"), Host: kong.String("svc.example.com")},
+			Routes: []*file.FRoute{{
+				Route: kong.Route{Name: kong.String("route"), Paths: kong.StringSlice("/")},
+			}},
+		}},
+		Consumers: []file.FConsumer{{Consumer: kong.Consumer{Username: kong.String("alice")}}},
+	}
+
+	// Without adoption, the entities lacking the selector tags are not seen
+	// and created again.
+	res := Sync(context.Background(), client, content, Options{DryRun: true})
+	require.NoError(t, res.Err())
+	assert.Equal(t, int32(3), res.Stats.CreateOps.Count())
+
+	res = Sync(context.Background(), client, content, Options{Adopt: true, OwnershipTag: "managed-by:ci"})
+	require.NoError(t, res.Err())
+	assert.Equal(t, []Adoption{
+		{Kind: types.Service, Key: "svc", ID: "s1"},
+		{Kind: types.Route, Key: "route", ID: "r1"},
+		{Kind: types.Consumer, Key: "username:alice", ID: "c1"},
+	}, res.Adopted)
+	assert.Equal(t, int32(0), res.Stats.CreateOps.Count())
+	assert.Equal(t, int32(3), res.Stats.UpdateOps.Count())
+	assert.Equal(t, int32(0), res.Stats.DeleteOps.Count())
+	assert.Empty(t, res.Changes.Warnings)
+
+	for _, kind := range []string{"services", "routes", "consumers"} {
+		entities := fake.entities[""][kind]
+		require.Len(t, entities, 1, kind)
+		assert.ElementsMatch(t, []any{"team:a", "managed-by:ci"}, entities[0]["tags"], kind)
+	}
+	assert.Equal(t, map[string]any{"id": "s1"}, fake.entities[""]["routes"][0]["service"])
+
+	// Once adopted, the entities are managed and nothing is left to adopt.
+	res = Sync(context.Background(), client, content, Options{Adopt: true, OwnershipTag: "managed-by:ci"})
+	require.NoError(t, res.Err())
+	assert.Empty(t, res.Adopted)
+	assert.Equal(t, int32(0), res.Stats.UpdateOps.Count())
+}
+
+func TestAdoptRemapsReferences(t *testing.T) {
+	live := &utils.KongRawState{
+		Services: []*kong.Service{{ID: new("s1"), Name: new("svc")}},
+		Plugins: []*kong.Plugin{{
+			ID: new("p1"), Name: new("cors"), Service: &kong.Service{ID: new("s1")},
+		}},
+	}
+	current := &utils.KongRawState{Services: live.Services, Plugins: live.Plugins}
+	// The target state was given other IDs, which would delete and recreate
+	// the service.
+	target := &utils.KongRawState{
+		Services: []*kong.Service{{ID: new("generated"), Name: new("svc")}},
+		Routes: []*kong.Route{{
+			ID: new("r1"), Name: new("route"), Service: &kong.Service{ID: new("generated")},
+		}},
+		Plugins: []*kong.Plugin{{
+			ID: new("p2"), Name: new("cors"), Service: &kong.Service{ID: new("generated")},
+		}},
+	}
+
+	adopted := adopt(target, current, live)
+	assert.Equal(t, []Adoption{
+		{Kind: types.Service, Key: "svc", ID: "s1"},
+		{Kind: types.Plugin, Key: "cors service:s1", ID: "p1"},
+	}, adopted)
+	assert.Equal(t, "s1", *target.Services[0].ID)
+	assert.Equal(t, "s1", *target.Routes[0].Service.ID)
+	assert.Equal(t, "p1", *target.Plugins[0].ID)
+	assert.Equal(t, "s1", *target.Plugins[0].Service.ID)
+	// The adopted entities were already managed.
+	assert.Len(t, current.Services, 1)
+	assert.Len(t, current.Plugins, 1)
+}
@@ -0,0 +1,159 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/kong/go-database-reconciler/pkg/diff"
+	"github.com/kong/go-database-reconciler/pkg/file"
+	"github.com/kong/go-database-reconciler/pkg/utils"
+	"github.com/kong/go-kong/kong"
+)
+
+// DriftEvent reports an entity of Kong that does not match the target state.
+type DriftEvent struct {
+	// Time is the time the drift was detected.
+	Time time.Time
+
+	// Action is the action that would bring the entity back to the target
+	// state: diff.CreateAction for an entity removed from Kong,
+	// diff.UpdateAction for a modified entity and diff.DeleteAction for an
+	// entity added to Kong.
+	Action diff.ReconcileAction
+	Entity diff.Entity
+	// Diff describes the modifications made to an updated entity.
+	Diff string
+
+	// Error is set, and the other fields are not, when drift could not be
+	// detected.
+	Error error
+}
+
+// DriftOptions configures a DriftDetector.
+type DriftOptions struct {
+	// Options configures how the target state is compared with Kong.
+	// DryRun and SilenceWarnings are always set, and Policies are not
+	// evaluated.
+	Options
+
+	// Interval is the time between two checks. It defaults to one minute.
+	Interval time.Duration
+
+	// Jitter is the maximum random delay added to Interval, so that several
+	// detectors do not dump Kong at the same time.
+	Jitter time.Duration
+}
+
+const defaultDriftInterval = time.Minute
+
+// ErrNoTarget is returned by DriftDetector.Detect if no target state was set.
+var ErrNoTarget = errors.New("no target state to detect drift against")
+
+// DriftDetector periodically compares Kong with the last applied target state,
+// without applying any change, and reports the entities that differ.
+type DriftDetector struct {
+	client *kong.Client
+	opts   DriftOptions
+
+	mu     sync.Mutex
+	target *file.Content
+}
+
+// NewDriftDetector returns a DriftDetector for the workspace of client.
+func NewDriftDetector(client *kong.Client, opts DriftOptions) *DriftDetector {
+	opts.DryRun = true
+	opts.SilenceWarnings = true
+	opts.Policies = nil
+	if opts.Interval <= 0 {
+		opts.Interval = defaultDriftInterval
+	}
+	return &DriftDetector{client: client, opts: opts}
+}
+
+// SetTarget sets the target state Kong is compared with, usually the content
+// last synced to Kong.
+func (d *DriftDetector) SetTarget(content *file.Content) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.target = content
+}
+
+// Detect dumps Kong once and returns an event for each entity that does not
+// match the target state.
+func (d *DriftDetector) Detect(ctx context.Context) ([]DriftEvent, error) {
+	d.mu.Lock()
+	target := d.target
+	d.mu.Unlock()
+	if target == nil {
+		return nil, ErrNoTarget
+	}
+
+	syncer, _, err := newSyncer(ctx, d.client, target, d.opts.Options, true)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	var events []DriftEvent
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for action := range syncer.GetResultChan() {
+			events = append(events, DriftEvent{
+				Time:   now,
+				Action: action.Action,
+				Entity: action.Entity,
+				Diff:   action.Diff,
+			})
+		}
+	}()
+	_, errs, _ := syncer.Solve(ctx, d.opts.parallelism(), true, true)
+	<-done
+	if len(errs) > 0 {
+		return nil, utils.ErrArray{Errors: errs}
+	}
+	return events, nil
+}
+
+// Run detects drift every interval, plus a random jitter, until ctx is done,
+// sending an event to events for each entity that does not match the target
+// state. A failed check is reported as an event with Error set, and the next
+// check is attempted at the next interval.
+//
+// The first check happens right away. Run does not close events.
+func (d *DriftDetector) Run(ctx context.Context, events chan<- DriftEvent) {
+	for {
+		detected, err := d.Detect(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			detected = []DriftEvent{{Time: time.Now(), Error: err}}
+		}
+		for _, event := range detected {
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		timer := time.NewTimer(d.delay())
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
+
+func (d *DriftDetector) delay() time.Duration {
+	delay := d.opts.Interval
+	if d.opts.Jitter > 0 {
+		delay += rand.N(d.opts.Jitter) //nolint:gosec
+	}
+	return delay
+}
@@ -0,0 +1,57 @@
+package reconcile
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kong/go-database-reconciler/pkg/diff"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriftDetector(t *testing.T) {
+	_, client := newFakeKongClient(t, map[string][]map[string]any{
+		"services": {
+			{"id": "s1", "name": "svc", "host": "edited.example.com"},
+			{"id": "s2", "name": "rogue", "host": "rogue.example.com"},
+		},
+	})
+	detector := NewDriftDetector(client, DriftOptions{Interval: 10 * time.Millisecond, Jitter: time.Millisecond})
+
+	_, err := detector.Detect(context.Background())
+	require.ErrorIs(t, err, ErrNoTarget)
+
+	detector.SetTarget(workspaceContent("", "svc"))
+	events, err := detector.Detect(context.Background())
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	slices.SortFunc(events, func(a, b DriftEvent) int { return strings.Compare(a.Entity.Name, b.Entity.Name) })
+	assert.Equal(t, diff.DeleteAction, events[0].Action)
+	assert.Equal(t, "rogue", events[0].Entity.Name)
+	assert.Equal(t, "service", events[0].Entity.Kind)
+	assert.Equal(t, diff.UpdateAction, events[1].Action)
+	assert.Equal(t, "svc", events[1].Entity.Name)
+	assert.Contains(t, events[1].Diff, "edited.example.com")
+	assert.False(t, events[1].Time.IsZero())
+
+	t.Run("run", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		ch := make(chan DriftEvent)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			detector.Run(ctx, ch)
+		}()
+		// Two checks report two events each.
+		for range 4 {
+			event := <-ch
+			require.NoError(t, event.Error)
+		}
+		cancel()
+		<-done
+	})
+}
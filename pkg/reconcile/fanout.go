@@ -0,0 +1,245 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/kong/go-database-reconciler/pkg/diff"
+	"github.com/kong/go-database-reconciler/pkg/file"
+	"github.com/kong/go-database-reconciler/pkg/utils"
+	"github.com/kong/go-kong/kong"
+)
+
+// RolloutStrategy controls how a configuration is rolled out to gateways by
+// SyncGateways.
+type RolloutStrategy struct {
+	// Canary is the number of gateways synced first. The other gateways are
+	// synced only if all the canary gateways were synced successfully.
+	Canary int
+
+	// MaxFailurePercentage stops the rollout once the percentage of gateways
+	// that failed to sync reaches it. Gateways being synced at that time are
+	// synced to completion, and the others are skipped. Zero means the rollout
+	// is never stopped.
+	MaxFailurePercentage float64
+}
+
+// FanOutOptions configures how a configuration is synced to several gateways.
+type FanOutOptions struct {
+	// Options configures the sync of each gateway. When Options.SchemaRegistry
+	// is nil, a registry is created for each gateway since gateways may run
+	// different versions of Kong or different plugins.
+	Options
+
+	// MaxInFlight is the maximum number of gateways synced at the same time.
+	// Zero means all gateways are synced concurrently.
+	MaxInFlight int
+
+	Rollout RolloutStrategy
+}
+
+// GatewayResult is the outcome of syncing one gateway.
+type GatewayResult struct {
+	// Address is the address of the Admin API of the gateway.
+	Address string
+
+	// Skipped is set if the gateway was not synced because the rollout was
+	// stopped. Result is nil in this case.
+	Skipped bool
+
+	*Result
+}
+
+// FanOutResult is the combined outcome of syncing several gateways.
+type FanOutResult struct {
+	// Gateways holds the result of each gateway, in the order gateways were
+	// passed to SyncGateways.
+	Gateways []*GatewayResult
+
+	// Stats is the sum of the Stats of all gateways.
+	Stats diff.Stats
+
+	// Stopped is set if the rollout was stopped before all gateways were
+	// synced.
+	Stopped bool
+}
+
+// Failed returns the number of gateways that failed to sync.
+func (r *FanOutResult) Failed() int {
+	failed := 0
+	for _, gateway := range r.Gateways {
+		if gateway.Result != nil && len(gateway.Errors) > 0 {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Errors returns the errors of all gateways, prefixed with the address of the
+// gateway they occurred in.
+func (r *FanOutResult) Errors() []error {
+	var errs []error
+	for _, gateway := range r.Gateways {
+		if gateway.Result == nil {
+			continue
+		}
+		for _, err := range gateway.Errors {
+			errs = append(errs, fmt.Errorf("gateway %s: %w", gateway.Address, err))
+		}
+	}
+	return errs
+}
+
+// Err returns the errors of all gateways as a utils.ErrArray, or nil if every
+// gateway was synced successfully. A stopped rollout is reported as an error.
+func (r *FanOutResult) Err() error {
+	errs := r.Errors()
+	if r.Stopped {
+		skipped := 0
+		for _, gateway := range r.Gateways {
+			if gateway.Skipped {
+				skipped++
+			}
+		}
+		errs = append(errs, fmt.Errorf("rollout stopped after %d failed gateways, %d gateways skipped",
+			r.Failed(), skipped))
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return utils.ErrArray{Errors: errs}
+}
+
+// SyncGateways renders content against each gateway and syncs it, following
+// the rollout strategy of opts.
+//
+// A gateway failing to sync does not stop the others unless the rollout
+// strategy says so; its errors are recorded in the returned FanOutResult. An
+// error is returned only if the gateways cannot be synced at all.
+func SyncGateways(ctx context.Context, content *file.Content, configs []utils.KongClientConfig,
+	opts FanOutOptions,
+) (*FanOutResult, error) {
+	options := make([]Options, len(configs))
+	for i := range options {
+		options[i] = opts.Options
+	}
+	return fanOut(ctx, content, configs, options, opts)
+}
+
+// fanOut syncs content to the gateways of configs, using options[i] to sync
+// the gateway of configs[i].
+func fanOut(ctx context.Context, content *file.Content, configs []utils.KongClientConfig,
+	options []Options, opts FanOutOptions,
+) (*FanOutResult, error) {
+	if opts.MaxInFlight < 0 || opts.Rollout.Canary < 0 {
+		return nil, fmt.Errorf("reconcile: MaxInFlight and Canary must not be negative")
+	}
+	if opts.Rollout.MaxFailurePercentage < 0 || opts.Rollout.MaxFailurePercentage > 100 {
+		return nil, fmt.Errorf("reconcile: MaxFailurePercentage must be between 0 and 100")
+	}
+
+	res := &FanOutResult{
+		Gateways: make([]*GatewayResult, len(configs)),
+		Stats: diff.Stats{
+			CreateOps: &utils.AtomicInt32Counter{},
+			UpdateOps: &utils.AtomicInt32Counter{},
+			DeleteOps: &utils.AtomicInt32Counter{},
+		},
+	}
+	// Clients are created upfront since GetKongClient sets the transport of
+	// a shared HTTP client.
+	clients := make([]*kong.Client, len(configs))
+	for i, config := range configs {
+		res.Gateways[i] = &GatewayResult{Address: config.Address}
+		client, err := utils.GetKongClient(config)
+		if err != nil {
+			res.Gateways[i].Result = newResult(config.Workspace)
+			res.Gateways[i].Errors = append(res.Gateways[i].Errors,
+				fmt.Errorf("creating client: %w", err))
+			continue
+		}
+		clients[i] = client
+	}
+
+	r := &rollout{opts: opts, options: options, clients: clients, content: content, res: res}
+	canary := min(opts.Rollout.Canary, len(configs))
+	r.run(ctx, 0, canary)
+	if r.failed > 0 && canary < len(configs) {
+		r.stopped = true
+	}
+	r.run(ctx, canary, len(configs))
+
+	for _, gateway := range res.Gateways {
+		if gateway.Result == nil {
+			gateway.Skipped = true
+			continue
+		}
+		res.Stats.CreateOps.Increment(gateway.Stats.CreateOps.Count())
+		res.Stats.UpdateOps.Increment(gateway.Stats.UpdateOps.Count())
+		res.Stats.DeleteOps.Increment(gateway.Stats.DeleteOps.Count())
+	}
+	res.Stopped = r.stopped
+	return res, nil
+}
+
+type rollout struct {
+	opts    FanOutOptions
+	options []Options
+	clients []*kong.Client
+	content *file.Content
+	res     *FanOutResult
+
+	mu      sync.Mutex
+	failed  int
+	stopped bool
+}
+
+// run syncs the gateways from start to end, with at most opts.MaxInFlight
+// gateways synced at the same time, until the rollout is stopped.
+func (r *rollout) run(ctx context.Context, start, end int) {
+	inFlight := end - start
+	if r.opts.MaxInFlight > 0 {
+		inFlight = min(inFlight, r.opts.MaxInFlight)
+	}
+	slots := make(chan struct{}, max(inFlight, 1))
+	var wg sync.WaitGroup
+	for i := start; i < end; i++ {
+		slots <- struct{}{}
+		r.mu.Lock()
+		stopped := r.stopped
+		r.mu.Unlock()
+		if stopped || ctx.Err() != nil {
+			<-slots
+			break
+		}
+		gateway := r.res.Gateways[i]
+		if gateway.Result != nil {
+			// The client of the gateway could not be created.
+			r.recordFailure()
+			<-slots
+			continue
+		}
+		wg.Go(func() {
+			defer func() { <-slots }()
+			// Sync fills the IDs of the entities of the content it renders,
+			// which differ between gateways.
+			result := Sync(ctx, r.clients[i], r.content.DeepCopy(), r.options[i])
+			gateway.Result = result
+			if len(result.Errors) > 0 {
+				r.recordFailure()
+			}
+		})
+	}
+	wg.Wait()
+}
+
+func (r *rollout) recordFailure() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failed++
+	threshold := r.opts.Rollout.MaxFailurePercentage
+	if threshold > 0 && float64(r.failed)*100 >= threshold*float64(len(r.clients)) {
+		r.stopped = true
+	}
+}
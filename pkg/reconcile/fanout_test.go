@@ -0,0 +1,155 @@
+package reconcile
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/kong/go-database-reconciler/pkg/file"
+	"github.com/kong/go-database-reconciler/pkg/utils"
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fanOutGateways(t *testing.T, healthy ...bool) ([]utils.KongClientConfig, []*fakeKong) {
+	configs := make([]utils.KongClientConfig, 0, len(healthy))
+	fakes := make([]*fakeKong, 0, len(healthy))
+	for _, ok := range healthy {
+		if !ok {
+			// Requests to a closed server fail right away.
+			server := httptest.NewServer(nil)
+			server.Close()
+			configs = append(configs, utils.KongClientConfig{Address: server.URL})
+			fakes = append(fakes, nil)
+			continue
+		}
+		fake, server := newFakeKong(t)
+		configs = append(configs, utils.KongClientConfig{Address: server.URL})
+		fakes = append(fakes, fake)
+	}
+	return configs, fakes
+}
+
+func TestSyncGateways(t *testing.T) {
+	configs, fakes := fanOutGateways(t, true, true, true)
+	res, err := SyncGateways(context.Background(), workspaceContent("", "svc"), configs,
+		FanOutOptions{MaxInFlight: 2})
+	require.NoError(t, err)
+	require.NoError(t, res.Err())
+	assert.False(t, res.Stopped)
+	assert.Equal(t, int32(3), res.Stats.CreateOps.Count())
+	for i, gateway := range res.Gateways {
+		assert.Equal(t, configs[i].Address, gateway.Address)
+		assert.False(t, gateway.Skipped)
+		assert.Equal(t, int32(1), gateway.Stats.CreateOps.Count())
+		assert.Equal(t, map[string]int{" services": 1}, fakes[i].writes)
+	}
+}
+
+func TestSyncGatewaysCanary(t *testing.T) {
+	configs, fakes := fanOutGateways(t, false, true, true)
+	res, err := SyncGateways(context.Background(), workspaceContent("", "svc"), configs,
+		FanOutOptions{Rollout: RolloutStrategy{Canary: 1}})
+	require.NoError(t, err)
+	assert.True(t, res.Stopped)
+	assert.Equal(t, 1, res.Failed())
+	require.Error(t, res.Err())
+	assert.Contains(t, res.Err().Error(), "rollout stopped after 1 failed gateways, 2 gateways skipped")
+	assert.False(t, res.Gateways[0].Skipped)
+	assert.True(t, res.Gateways[1].Skipped)
+	assert.True(t, res.Gateways[2].Skipped)
+	assert.Empty(t, fakes[1].writes)
+	assert.Empty(t, fakes[2].writes)
+	assert.Equal(t, int32(0), res.Stats.CreateOps.Count())
+}
+
+func TestSyncGatewaysMaxFailurePercentage(t *testing.T) {
+	configs, fakes := fanOutGateways(t, true, false, false, true)
+	res, err := SyncGateways(context.Background(), workspaceContent("", "svc"), configs, FanOutOptions{
+		MaxInFlight: 1,
+		Rollout:     RolloutStrategy{MaxFailurePercentage: 50},
+	})
+	require.NoError(t, err)
+	assert.True(t, res.Stopped)
+	assert.Equal(t, 2, res.Failed())
+	assert.Len(t, res.Errors(), 2)
+	assert.False(t, res.Gateways[2].Skipped)
+	assert.True(t, res.Gateways[3].Skipped)
+	assert.Equal(t, map[string]int{" services": 1}, fakes[0].writes)
+	assert.Empty(t, fakes[3].writes)
+	assert.Equal(t, int32(1), res.Stats.CreateOps.Count())
+
+	// Below the threshold, the rollout goes on.
+	configs, _ = fanOutGateways(t, true, false, true, true)
+	res, err = SyncGateways(context.Background(), workspaceContent("", "svc"), configs, FanOutOptions{
+		Rollout: RolloutStrategy{MaxFailurePercentage: 50},
+	})
+	require.NoError(t, err)
+	assert.False(t, res.Stopped)
+	assert.Equal(t, 1, res.Failed())
+	assert.Equal(t, int32(3), res.Stats.CreateOps.Count())
+
+	_, err = SyncGateways(context.Background(), workspaceContent("", "svc"), configs, FanOutOptions{
+		Rollout: RolloutStrategy{MaxFailurePercentage: 150},
+	})
+	require.EqualError(t, err, "reconcile: MaxFailurePercentage must be between 0 and 100")
+}
+
+// nestedContent returns a service with a nested route and plugin, whose
+// references to the service are filled when the content is rendered.
+func nestedContent() *file.Content {
+	return &file.Content{
+		Services: []file.FService{{
+			Service: kong.Service{Name: new("svc"), Host: new("svc.example.com")},
+			Routes: []*file.FRoute{{
+				Route: kong.Route{Name: new("route"), Paths: kong.StringSlice("/svc")},
+			}},
+			Plugins: []*file.FPlugin{{
+				Plugin: kong.Plugin{Name: new("key-auth")},
+			}},
+		}},
+	}
+}
+
+func TestSyncGatewaysNestedEntities(t *testing.T) {
+	var configs []utils.KongClientConfig
+	var fakes []*fakeKong
+	for _, id := range []string{"s1", "s2"} {
+		fake, fakeServer := newFakeKong(t)
+		fake.entities[""] = map[string][]map[string]any{
+			"services": {{"id": id, "name": "svc", "host": "svc.example.com"}},
+		}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasPrefix(r.URL.Path, "/schemas/") {
+				_, _ = w.Write([]byte(`{"fields": [{"config": {"type": "record", "fields": []}}]}`))
+				return
+			}
+			fakeServer.Config.Handler.ServeHTTP(w, r)
+		}))
+		t.Cleanup(server.Close)
+		configs = append(configs, utils.KongClientConfig{Address: server.URL})
+		fakes = append(fakes, fake)
+	}
+	content := nestedContent()
+
+	res, err := SyncGateways(context.Background(), content, configs, FanOutOptions{
+		Options:     Options{KongVersion: semver.MustParse("3.9.0")},
+		MaxInFlight: 2,
+	})
+	require.NoError(t, err)
+	require.NoError(t, res.Err())
+	for i, id := range []string{"s1", "s2"} {
+		routes := fakes[i].entities[""]["routes"]
+		require.Len(t, routes, 1)
+		assert.Equal(t, id, routes[0]["service"].(map[string]any)["id"])
+		plugins := fakes[i].entities[""]["plugins"]
+		require.Len(t, plugins, 1)
+		assert.Equal(t, id, plugins[0]["service"].(map[string]any)["id"])
+	}
+	// the content of the caller is left untouched
+	assert.Equal(t, nestedContent(), content)
+}
@@ -0,0 +1,72 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kong/go-database-reconciler/pkg/file"
+	"github.com/kong/go-database-reconciler/pkg/konnect"
+	"github.com/kong/go-database-reconciler/pkg/utils"
+)
+
+// SyncControlPlanes syncs content to several Konnect control planes in a
+// single run, following the rollout strategy of opts.
+//
+// controlPlanes are the names of the control planes to sync. When empty, the
+// _konnect.control_plane_names of content are used, or its
+// _konnect.control_plane_name. The control planes must exist: they are
+// managed by syncing the control_planes of a file with the Konnect Syncer.
+//
+// Each control plane is synced like a gateway by SyncGateways, with
+// Options.DumpConfig.KonnectControlPlane set to its name, and its
+// GatewayResult is reported with the address of its core entities. Since
+// Konnect does not report a Kong version, opts.KongVersion should be set.
+// The requests to each control plane are authenticated with
+// konnectConfig.CredentialProvider, or konnectConfig.Token if unset.
+func SyncControlPlanes(ctx context.Context, konnectClient *konnect.Client, konnectConfig utils.KonnectConfig,
+	content *file.Content, controlPlanes []string, opts FanOutOptions,
+) (*FanOutResult, error) {
+	if len(controlPlanes) == 0 && content.Konnect != nil {
+		controlPlanes = content.Konnect.ControlPlaneNames
+		if len(controlPlanes) == 0 && content.Konnect.ControlPlaneName != "" {
+			controlPlanes = []string{content.Konnect.ControlPlaneName}
+		}
+	}
+	if len(controlPlanes) == 0 {
+		return nil, fmt.Errorf("reconcile: no control plane to sync")
+	}
+
+	existing, err := konnectClient.ControlPlanesV2.ListAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing control planes: %w", err)
+	}
+	ids := make(map[string]string, len(existing))
+	for _, cp := range existing {
+		ids[*cp.Name] = *cp.ID
+	}
+
+	provider := konnectConfig.CredentialProvider
+	if provider == nil {
+		provider = konnect.StaticToken(konnectConfig.Token)
+	}
+
+	configs := make([]utils.KongClientConfig, len(controlPlanes))
+	options := make([]Options, len(controlPlanes))
+	for i, name := range controlPlanes {
+		id, ok := ids[name]
+		if !ok {
+			return nil, fmt.Errorf("control plane %q does not exist", name)
+		}
+		configs[i] = utils.KongClientConfig{
+			Address: utils.CleanAddress(konnectConfig.Address) +
+				"/v2/control-planes/" + id + "/core-entities",
+			Headers:            konnectConfig.Headers,
+			CredentialProvider: provider,
+			TLSConfig:          konnectConfig.TLSConfig,
+			Debug:              konnectConfig.Debug,
+		}
+		options[i] = opts.Options
+		options[i].DumpConfig.KonnectControlPlane = name
+	}
+	return fanOut(ctx, content, configs, options, opts)
+}
@@ -0,0 +1,129 @@
+package reconcile
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/kong/go-database-reconciler/pkg/file"
+	"github.com/kong/go-database-reconciler/pkg/konnect"
+	"github.com/kong/go-database-reconciler/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncControlPlanes(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/control-planes", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"data":[{"id":"cp1","name":"us"},{"id":"cp2","name":"eu"}],` +
+			`"meta":{"page":{"total":2}}}`))
+	})
+	var token atomic.Pointer[string]
+	token.Store(new("secret"))
+	fakes := map[string]*fakeKong{}
+	for _, id := range []string{"cp1", "cp2"} {
+		fake, fakeServer := newFakeKong(t)
+		fakes[id] = fake
+		prefix := "/v2/control-planes/" + id + "/core-entities"
+		mux.Handle(prefix+"/", http.StripPrefix(prefix, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "Bearer "+*token.Load(), r.Header.Get("Authorization"))
+			if strings.HasPrefix(r.URL.Path, "/v1/schemas/") {
+				_, _ = w.Write([]byte(`{}`))
+				return
+			}
+			fakeServer.Config.Handler.ServeHTTP(w, r)
+		})))
+	}
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	konnectConfig := utils.KonnectConfig{Address: server.URL, Token: "secret"}
+	client, err := konnect.NewClient(nil, konnect.ClientOpts{BaseURL: server.URL})
+	require.NoError(t, err)
+	content := workspaceContent("", "svc")
+	content.Konnect = &file.Konnect{ControlPlaneNames: []string{"us", "eu"}}
+	opts := FanOutOptions{Options: Options{KongVersion: semver.MustParse("3.9.0")}}
+
+	res, err := SyncControlPlanes(context.Background(), client, konnectConfig, content, nil, opts)
+	require.NoError(t, err)
+	require.NoError(t, res.Err())
+	require.Len(t, res.Gateways, 2)
+	assert.Equal(t, server.URL+"/v2/control-planes/cp1/core-entities", res.Gateways[0].Address)
+	assert.Equal(t, server.URL+"/v2/control-planes/cp2/core-entities", res.Gateways[1].Address)
+	assert.Equal(t, int32(2), res.Stats.CreateOps.Count())
+	assert.Equal(t, map[string]int{" services": 1}, fakes["cp1"].writes)
+	assert.Equal(t, map[string]int{" services": 1}, fakes["cp2"].writes)
+
+	_, err = SyncControlPlanes(context.Background(), client, konnectConfig, content, []string{"apac"}, opts)
+	require.EqualError(t, err, `control plane "apac" does not exist`)
+
+	// The token of the credential provider is read for each request, so
+	// that it can be rotated during the sync.
+	konnectConfig.CredentialProvider = tokenFunc(func() string { return *token.Load() })
+	token.Store(new("rotated"))
+	res, err = SyncControlPlanes(context.Background(), client, konnectConfig, content, []string{"us"}, opts)
+	require.NoError(t, err)
+	require.NoError(t, res.Err())
+	assert.Equal(t, int32(0), res.Stats.CreateOps.Count())
+}
+
+// tokenFunc is a konnect.CredentialProvider returning the token of a
+// function.
+type tokenFunc func() string
+
+func (f tokenFunc) Token(context.Context) (string, error) {
+	return f(), nil
+}
+
+func (f tokenFunc) Refresh(context.Context, string) (string, error) {
+	return f(), nil
+}
+
+func TestSyncControlPlanesNestedEntities(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/control-planes", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"data":[{"id":"cp1","name":"us"},{"id":"cp2","name":"eu"}],` +
+			`"meta":{"page":{"total":2}}}`))
+	})
+	fakes := map[string]*fakeKong{}
+	for _, id := range []string{"cp1", "cp2"} {
+		fake, fakeServer := newFakeKong(t)
+		// the service is the same on both control planes, with another ID
+		fake.entities[""] = map[string][]map[string]any{
+			"services": {{"id": id + "-svc", "name": "svc", "host": "svc.example.com"}},
+		}
+		fakes[id] = fake
+		prefix := "/v2/control-planes/" + id + "/core-entities"
+		mux.Handle(prefix+"/", http.StripPrefix(prefix, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasPrefix(r.URL.Path, "/v1/schemas/") || strings.HasPrefix(r.URL.Path, "/schemas/") {
+				_, _ = w.Write([]byte(`{"fields": [{"config": {"type": "record", "fields": []}}]}`))
+				return
+			}
+			fakeServer.Config.Handler.ServeHTTP(w, r)
+		})))
+	}
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := konnect.NewClient(nil, konnect.ClientOpts{BaseURL: server.URL})
+	require.NoError(t, err)
+	content := nestedContent()
+	opts := FanOutOptions{Options: Options{KongVersion: semver.MustParse("3.9.0")}, MaxInFlight: 2}
+
+	res, err := SyncControlPlanes(context.Background(), client, utils.KonnectConfig{Address: server.URL, Token: "secret"},
+		content, []string{"us", "eu"}, opts)
+	require.NoError(t, err)
+	require.NoError(t, res.Err())
+	for _, id := range []string{"cp1", "cp2"} {
+		routes := fakes[id].entities[""]["routes"]
+		require.Len(t, routes, 1)
+		assert.Equal(t, id+"-svc", routes[0]["service"].(map[string]any)["id"])
+		plugins := fakes[id].entities[""]["plugins"]
+		require.Len(t, plugins, 1)
+		assert.Equal(t, id+"-svc", plugins[0]["service"].(map[string]any)["id"])
+	}
+}
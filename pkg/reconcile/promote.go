@@ -0,0 +1,117 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kong/go-database-reconciler/pkg/diff"
+	"github.com/kong/go-database-reconciler/pkg/dump"
+	"github.com/kong/go-database-reconciler/pkg/file"
+	"github.com/kong/go-database-reconciler/pkg/state"
+	"github.com/kong/go-kong/kong"
+)
+
+// PromoteOptions configures the promotion of the configuration of a source
+// gateway to a destination gateway.
+type PromoteOptions struct {
+	// Options configures the sync of the destination gateway. Both gateways
+	// are dumped with Options.DumpConfig.
+	Options
+
+	// Rewrite is applied to the configuration of the source gateway before it
+	// is compared with the destination gateway.
+	Rewrite RewriteRules
+}
+
+// Plan holds the changes that make a destination gateway match a source
+// gateway.
+type Plan struct {
+	// Content is the configuration of the source gateway, once rewritten.
+	// Entities are identified by their natural key, such as the name of a
+	// service or the username of a consumer, rather than by their ID.
+	Content *file.Content
+
+	Stats   diff.Stats
+	Changes diff.EntityChanges
+
+	destination *kong.Client
+	opts        Options
+}
+
+// PlanPromotion dumps the source and destination gateways and computes the
+// changes that make the destination match the source, without applying them.
+func PlanPromotion(ctx context.Context, source, destination *kong.Client, opts PromoteOptions) (*Plan, error) {
+	content, err := SourceContent(ctx, source, opts)
+	if err != nil {
+		return nil, err
+	}
+	dryOpts := opts.Options
+	dryOpts.DryRun = true
+	res := Sync(ctx, destination, content, dryOpts)
+	if err := res.Err(); err != nil {
+		return nil, err
+	}
+	return &Plan{
+		Content:     content,
+		Stats:       res.Stats,
+		Changes:     res.Changes,
+		destination: destination,
+		opts:        opts.Options,
+	}, nil
+}
+
+// Apply syncs the destination gateway with the content of the plan. Changes
+// made to the destination since the plan was computed are taken into account,
+// so the changes applied may differ from the ones of the plan.
+func (p *Plan) Apply(ctx context.Context) *Result {
+	return Sync(ctx, p.destination, p.Content, p.opts)
+}
+
+// Promote makes the destination gateway match the source gateway, or only
+// computes the changes to do so if opts.DryRun is set.
+func Promote(ctx context.Context, source, destination *kong.Client, opts PromoteOptions) (*Result, error) {
+	content, err := SourceContent(ctx, source, opts)
+	if err != nil {
+		return nil, err
+	}
+	return Sync(ctx, destination, content, opts.Options), nil
+}
+
+// SourceContent dumps the source gateway and returns its configuration once
+// rewritten, as promoted to a destination gateway. The configuration is
+// rendered for opts.KongVersion, or for the version of the source gateway
+// when unset.
+func SourceContent(ctx context.Context, source *kong.Client, opts PromoteOptions) (*file.Content, error) {
+	if err := opts.Rewrite.validate(); err != nil {
+		return nil, err
+	}
+	kongVersion, err := getKongVersion(ctx, source, opts.KongVersion)
+	if err != nil {
+		return nil, fmt.Errorf("reading configuration from source gateway: %w", err)
+	}
+	writeConfig := file.WriteConfig{
+		KongVersion:                      kongVersion.String(),
+		IsConsumerGroupPolicyOverrideSet: opts.DumpConfig.IsConsumerGroupPolicyOverrideSet,
+	}
+	for _, tag := range opts.DumpConfig.SelectorTags {
+		writeConfig.SelectTags = append(writeConfig.SelectTags, opts.Rewrite.tag(tag))
+	}
+	if opts.DumpConfig.SelectorTagExpression != nil {
+		expression, err := opts.DumpConfig.SelectorTagExpression.MapTags(opts.Rewrite.tag)
+		if err != nil {
+			return nil, fmt.Errorf("rewrite rules: %w", err)
+		}
+		writeConfig.SelectTagExpression = expression
+	}
+
+	raw, err := dump.Get(ctx, source, opts.DumpConfig)
+	if err != nil {
+		return nil, fmt.Errorf("reading configuration from source gateway: %w", err)
+	}
+	opts.Rewrite.apply(raw)
+	sourceState, err := state.Get(raw)
+	if err != nil {
+		return nil, fmt.Errorf("building state of source gateway: %w", err)
+	}
+	return file.KongStateToContent(sourceState, writeConfig)
+}
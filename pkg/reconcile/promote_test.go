@@ -0,0 +1,129 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/kong/go-database-reconciler/pkg/dump"
+	"github.com/kong/go-database-reconciler/pkg/types"
+	"github.com/kong/go-database-reconciler/pkg/utils"
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFakeKongClient(t *testing.T, entities map[string][]map[string]any) (*fakeKong, *kong.Client) {
+	fake, server := newFakeKong(t)
+	fake.entities[""] = entities
+	client, err := utils.GetKongClient(utils.KongClientConfig{Address: server.URL})
+	require.NoError(t, err)
+	return fake, client
+}
+
+func TestPromote(t *testing.T) {
+	_, source := newFakeKongClient(t, map[string][]map[string]any{
+		"services": {{"id": "s1", "name": "svc", "host": "api.staging.internal", "tags": []string{"staging"}}},
+		"routes": {{
+			"id": "r1", "name": "r", "hosts": []string{"staging.example.com"},
+			"service": map[string]any{"id": "s1"}, "tags": []string{"staging"},
+		}},
+		"consumers": {{"id": "c1", "username": "alice", "tags": []string{"staging"}}},
+		"key-auths": {{"id": "k1", "key": "secret", "consumer": map[string]any{"id": "c1"}}},
+	})
+	destinationFake, destination := newFakeKongClient(t, map[string][]map[string]any{
+		"services": {{"id": "d1", "name": "svc", "host": "api.prod.internal", "tags": []string{"prod"}}},
+	})
+	opts := PromoteOptions{
+		Options: Options{
+			KongVersion: semver.MustParse("3.9.0"),
+			DumpConfig:  dump.Config{SelectorTags: []string{"staging"}},
+		},
+		Rewrite: RewriteRules{
+			Hosts: map[string]string{
+				"api.staging.internal": "api.prod.internal",
+				"staging.example.com":  "example.com",
+			},
+			Tags:         map[string]string{"staging": "prod"},
+			ExcludeKinds: []types.EntityType{types.Consumer},
+		},
+	}
+
+	plan, err := PlanPromotion(context.Background(), source, destination, opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"prod"}, plan.Content.Info.SelectorTags)
+	require.Len(t, plan.Content.Services, 1)
+	assert.Nil(t, plan.Content.Services[0].ID)
+	assert.Empty(t, plan.Content.Consumers)
+	assert.Equal(t, int32(1), plan.Stats.CreateOps.Count())
+	assert.Equal(t, int32(0), plan.Stats.UpdateOps.Count())
+	require.Len(t, plan.Changes.Creating, 1)
+	assert.Equal(t, "route", plan.Changes.Creating[0].Kind)
+	assert.Empty(t, destinationFake.writes)
+
+	res := plan.Apply(context.Background())
+	require.NoError(t, res.Err())
+	assert.Equal(t, map[string]int{" routes": 1}, destinationFake.writes)
+	route := destinationFake.entities[""]["routes"][0]
+	assert.Equal(t, "d1", route["service"].(map[string]any)["id"])
+	assert.Equal(t, []any{"example.com"}, route["hosts"])
+	assert.Equal(t, []any{"prod"}, route["tags"])
+}
+
+func TestSourceContentKongVersion(t *testing.T) {
+	_, source := newFakeKongClient(t, map[string][]map[string]any{
+		"services": {{"id": "s1", "name": "svc", "host": "api.internal"}},
+	})
+
+	content, err := SourceContent(context.Background(), source, PromoteOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "3.0", content.FormatVersion)
+	require.Len(t, content.Services, 1)
+
+	content, err = SourceContent(context.Background(), source, PromoteOptions{
+		Options: Options{KongVersion: semver.MustParse("2.8.0")},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "1.1", content.FormatVersion)
+}
+
+func TestRewriteRules(t *testing.T) {
+	raw := &utils.KongRawState{
+		Services: []*kong.Service{{ID: new("s1"), Name: new("svc"), Host: new("a.internal")}},
+		Routes: []*kong.Route{
+			{ID: new("r1"), Service: &kong.Service{ID: new("s1")}, Tags: kong.StringSlice("a", "b")},
+			{ID: new("r2"), Hosts: kong.StringSlice("a.internal", "c.internal")},
+		},
+		Plugins: []*kong.Plugin{
+			{Name: new("p1"), Route: &kong.Route{ID: new("r1")}},
+			{Name: new("p2"), Route: &kong.Route{ID: new("r2")}},
+			{Name: new("p3"), Consumer: &kong.Consumer{ID: new("c1")}, Tags: kong.StringSlice("a")},
+		},
+		Targets: []*kong.Target{{Target: new("a.internal:8000")}, {Target: new("b.internal:8000")}},
+		ConsumerGroups: []*kong.ConsumerGroupObject{{
+			ConsumerGroup: &kong.ConsumerGroup{Name: new("g"), Tags: kong.StringSlice("a")},
+		}},
+	}
+	rules := RewriteRules{
+		Hosts:        map[string]string{"a.internal": "b.internal"},
+		Tags:         map[string]string{"a": "z"},
+		ExcludeKinds: []types.EntityType{types.Service},
+	}
+	require.NoError(t, rules.validate())
+	rules.apply(raw)
+
+	assert.Empty(t, raw.Services)
+	require.Len(t, raw.Routes, 1)
+	assert.Equal(t, kong.StringSlice("b.internal", "c.internal"), raw.Routes[0].Hosts)
+	require.Len(t, raw.Plugins, 2)
+	assert.Equal(t, "p2", *raw.Plugins[0].Name)
+	assert.Equal(t, kong.StringSlice("z"), raw.Plugins[1].Tags)
+	assert.Equal(t, "b.internal:8000", *raw.Targets[0].Target)
+	assert.Equal(t, "b.internal:8000", *raw.Targets[1].Target)
+	assert.Equal(t, kong.StringSlice("z"), raw.ConsumerGroups[0].ConsumerGroup.Tags)
+
+	require.EqualError(t, RewriteRules{ExcludeKinds: []types.EntityType{types.ServicePackage}}.validate(),
+		`rewrite rules: kind "service-package" cannot be excluded`)
+	require.EqualError(t, RewriteRules{Tags: map[string]string{"a": ""}}.validate(),
+		"rewrite rules: tags must not be empty")
+}
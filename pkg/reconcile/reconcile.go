@@ -0,0 +1,296 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/blang/semver/v4"
+	"github.com/kong/go-database-reconciler/pkg/diff"
+	"github.com/kong/go-database-reconciler/pkg/dump"
+	"github.com/kong/go-database-reconciler/pkg/file"
+	"github.com/kong/go-database-reconciler/pkg/policy"
+	"github.com/kong/go-database-reconciler/pkg/schema"
+	"github.com/kong/go-database-reconciler/pkg/state"
+	"github.com/kong/go-database-reconciler/pkg/utils"
+	"github.com/kong/go-kong/kong"
+)
+
+// Options configures how a Content is reconciled with Kong.
+type Options struct {
+	// DumpConfig is used to dump the current state of Kong and to render the
+	// target state. The selector tags of the Content, if any, take precedence
+	// over the ones set here.
+	DumpConfig dump.Config
+
+	// KongVersion is the version of Kong the target state is rendered for.
+	// When unset, the version is read from Kong.
+	KongVersion semver.Version
+
+	// Parallelism is the number of concurrent operations performed by the
+	// Syncer. It defaults to 10.
+	Parallelism int
+
+	// DryRun computes the changes without applying them.
+	DryRun bool
+
+	NoDeletes       bool
+	NoMaskValues    bool
+	SilenceWarnings bool
+
+	// Policies are evaluated against the target state before solving.
+	Policies []policy.Policy
+
+	// OwnershipTag, if set, is added to every entity synced to Kong, and the
+	// entities of Kong without it are never updated nor deleted. See
+	// diff.SyncerOpts.OwnershipTag.
+	OwnershipTag string
+
+	// Adopt matches the entities of the target state to the ones of Kong by
+	// natural key and reuses their IDs, including the ones of entities not
+	// carrying the selector tags. Entities created outside of the target
+	// state are then updated, which adds the selector and ownership tags to
+	// them, rather than deleted and created again.
+	Adopt bool
+
+	// SchemaRegistry is shared by the dump, the rendering of the target state
+	// and the Syncer. When nil, a registry is created for the client.
+	SchemaRegistry *schema.Registry
+}
+
+const defaultParallelism = 10
+
+// Result is the outcome of reconciling a Content with Kong.
+type Result struct {
+	// Workspace is the workspace the Content was reconciled with.
+	Workspace string
+
+	Stats   diff.Stats
+	Changes diff.EntityChanges
+	Errors  []error
+
+	// Adopted lists the entities adopted into management if Options.Adopt
+	// is set.
+	Adopted []Adoption
+}
+
+func newResult(workspace string) *Result {
+	return &Result{
+		Workspace: workspace,
+		Stats: diff.Stats{
+			CreateOps: &utils.AtomicInt32Counter{},
+			UpdateOps: &utils.AtomicInt32Counter{},
+			DeleteOps: &utils.AtomicInt32Counter{},
+		},
+		Changes: diff.EntityChanges{
+			Creating: []diff.EntityState{},
+			Updating: []diff.EntityState{},
+			Deleting: []diff.EntityState{},
+		},
+	}
+}
+
+// Err returns the errors of the Result as a utils.ErrArray, or nil if the
+// Content was reconciled successfully.
+func (r *Result) Err() error {
+	if len(r.Errors) == 0 {
+		return nil
+	}
+	return utils.ErrArray{Errors: r.Errors}
+}
+
+// Sync dumps the workspace of client, renders content against it and applies
+// the changes needed for Kong to match content, or only computes them if
+// opts.DryRun is set. The workspace must exist unless opts.DryRun is set.
+//
+// Changes are collected in the Result rather than printed. Errors, including
+// the ones preventing the sync from starting, are recorded in the Result.
+func Sync(ctx context.Context, client *kong.Client, content *file.Content, opts Options) *Result {
+	res := newResult(client.Workspace())
+	if err := run(ctx, client, content, opts, res); err != nil {
+		res.Errors = append(res.Errors, err)
+	}
+	return res
+}
+
+func run(ctx context.Context, client *kong.Client, content *file.Content, opts Options, res *Result) error {
+	syncer, adopted, err := newSyncer(ctx, client, content, opts, false)
+	if err != nil {
+		return err
+	}
+	res.Adopted = adopted
+	stats, errs, changes := syncer.Solve(ctx, opts.parallelism(), opts.DryRun, true)
+	res.Stats = stats
+	res.Changes = changes
+	res.Errors = append(res.Errors, errs...)
+	return nil
+}
+
+func (opts Options) parallelism() int {
+	if opts.Parallelism <= 0 {
+		return defaultParallelism
+	}
+	return opts.Parallelism
+}
+
+// newSyncer dumps the workspace of client and renders content against it,
+// returning a Syncer from the current to the target state, along with the
+// entities adopted if opts.Adopt is set.
+func newSyncer(ctx context.Context, client *kong.Client, content *file.Content, opts Options,
+	enableEntityActions bool,
+) (*diff.Syncer, []Adoption, error) {
+	dumpConfig, err := contentDumpConfig(content, opts.DumpConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	isKonnect := dumpConfig.KonnectControlPlane != ""
+	if opts.SchemaRegistry == nil {
+		opts.SchemaRegistry = schema.NewRegistry(client, isKonnect)
+	}
+	dumpConfig.SchemaRegistry = opts.SchemaRegistry
+
+	workspaceExists, err := utils.WorkspaceExists(ctx, client)
+	if err != nil {
+		return nil, nil, fmt.Errorf("checking if workspace exists: %w", err)
+	}
+	currentState, err := state.NewKongState()
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating state: %w", err)
+	}
+	currentRaw := &utils.KongRawState{}
+	if workspaceExists {
+		currentRaw, err = dump.Get(ctx, client, dumpConfig)
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading configuration from Kong: %w", err)
+		}
+		currentState, err = state.Get(currentRaw)
+		if err != nil {
+			return nil, nil, fmt.Errorf("building current state: %w", err)
+		}
+	} else if dumpConfig.IncludeWorkspaces && declaresWorkspace(content, client.Workspace()) {
+		// The workspace is created before any of its entities.
+		currentRaw.Workspaces, err = dump.GetAllWorkspaces(ctx, client)
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading workspaces from Kong: %w", err)
+		}
+		currentState, err = state.Get(currentRaw)
+		if err != nil {
+			return nil, nil, fmt.Errorf("building current state: %w", err)
+		}
+	} else if !opts.DryRun {
+		return nil, nil, fmt.Errorf("workspace %s does not exist", client.Workspace())
+	}
+
+	kongVersion, err := getKongVersion(ctx, client, opts.KongVersion)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	targetRaw, err := file.Get(ctx, content, file.RenderConfig{
+		CurrentState: currentState,
+		KongVersion:  kongVersion,
+	}, dumpConfig, client)
+	if err != nil {
+		return nil, nil, err
+	}
+	var adopted []Adoption
+	if opts.Adopt && workspaceExists {
+		live := currentRaw
+		if len(dumpConfig.SelectorTags) > 0 || dumpConfig.SelectorTagExpression != nil {
+			liveConfig := dumpConfig
+			liveConfig.SelectorTags = nil
+			liveConfig.SelectorTagExpression = nil
+			live, err = dump.Get(ctx, client, liveConfig)
+			if err != nil {
+				return nil, nil, fmt.Errorf("reading configuration from Kong: %w", err)
+			}
+		}
+		adopted = adopt(targetRaw, currentRaw, live)
+		if len(adopted) > 0 {
+			currentState, err = state.Get(currentRaw)
+			if err != nil {
+				return nil, nil, fmt.Errorf("building current state: %w", err)
+			}
+		}
+	}
+	targetState, err := state.Get(targetRaw)
+	if err != nil {
+		return nil, nil, fmt.Errorf("building target state: %w", err)
+	}
+
+	syncer, err := diff.NewSyncer(diff.SyncerOpts{
+		CurrentState:      currentState,
+		TargetState:       targetState,
+		KongClient:        client,
+		IsKonnect:         isKonnect,
+		SilenceWarnings:   opts.SilenceWarnings,
+		NoMaskValues:      opts.NoMaskValues,
+		IncludeLicenses:   dumpConfig.IncludeLicenses,
+		IncludeWorkspaces: dumpConfig.IncludeWorkspaces,
+		IncludeEventHooks: dumpConfig.IncludeEventHooks,
+		NoDeletes:         opts.NoDeletes,
+		SchemaRegistry:    opts.SchemaRegistry,
+		Policies:          opts.Policies,
+		OwnershipTag:      opts.OwnershipTag,
+		AdoptedIDs:        adoptedIDs(adopted),
+
+		EnableEntityActions: enableEntityActions,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating syncer: %w", err)
+	}
+	return syncer, adopted, nil
+}
+
+// declaresWorkspace returns true if the workspaces section of content declares
+// workspace.
+func declaresWorkspace(content *file.Content, workspace string) bool {
+	return slices.ContainsFunc(content.Workspaces, func(w file.FWorkspace) bool {
+		return w.Name != nil && *w.Name == workspace
+	})
+}
+
+func adoptedIDs(adopted []Adoption) []string {
+	ids := make([]string, 0, len(adopted))
+	for _, adoption := range adopted {
+		ids = append(ids, adoption.ID)
+	}
+	return ids
+}
+
+// contentDumpConfig returns config with the selector tags declared in the
+// _info section of content.
+func contentDumpConfig(content *file.Content, config dump.Config) (dump.Config, error) {
+	if content.Info == nil {
+		return config, nil
+	}
+	expression, err := content.Info.SelectorTagExpression()
+	if err != nil {
+		return config, err
+	}
+	if expression != nil {
+		config.SelectorTags = nil
+		config.SelectorTagExpression = expression
+	} else if len(content.Info.SelectorTags) > 0 {
+		config.SelectorTags = content.Info.SelectorTags
+		config.SelectorTagExpression = nil
+	}
+	return config, nil
+}
+
+// getKongVersion returns version, or the version reported by client when
+// version is not set.
+func getKongVersion(ctx context.Context, client *kong.Client, version semver.Version) (semver.Version, error) {
+	if !version.Equals(semver.Version{}) {
+		return version, nil
+	}
+	info, err := client.Info.Get(ctx)
+	if err != nil {
+		return semver.Version{}, fmt.Errorf("reading Kong version: %w", err)
+	}
+	version, err = utils.ParseKongVersion(info.Version)
+	if err != nil {
+		return semver.Version{}, fmt.Errorf("parsing Kong version: %w", err)
+	}
+	return version, nil
+}
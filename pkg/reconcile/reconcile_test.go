@@ -0,0 +1,65 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncOwnershipTag(t *testing.T) {
+	fake, client := newFakeKongClient(t, map[string][]map[string]any{
+		"services": {
+			{"id": "s1", "name": "shared", "host": "other.example.com", "tags": []string{"team:b"}},
+			{"id": "s2", "name": "rogue", "host": "rogue.example.com"},
+			{"id": "s3", "name": "stale", "host": "stale.example.com", "tags": []string{"managed-by:ci"}},
+		},
+	})
+	content := workspaceContent("", "shared")
+	content.Services = append(content.Services, workspaceContent("", "new").Services...)
+
+	res := Sync(context.Background(), client, content, Options{OwnershipTag: "managed-by:ci"})
+	require.NoError(t, res.Err())
+	assert.Equal(t, int32(1), res.Stats.CreateOps.Count())
+	assert.Equal(t, int32(0), res.Stats.UpdateOps.Count())
+	assert.Equal(t, int32(1), res.Stats.DeleteOps.Count())
+	assert.Equal(t, []string{
+		"service shared is not tagged with managed-by:ci and was not updated",
+		"1 service entities are not tagged with managed-by:ci and were not deleted",
+	}, res.Changes.Warnings)
+
+	services := fake.entities[""]["services"]
+	require.Len(t, services, 3)
+	assert.Equal(t, "shared", services[0]["name"])
+	assert.Equal(t, "other.example.com", services[0]["host"])
+	assert.Equal(t, "rogue", services[1]["name"])
+	assert.Equal(t, "new", services[2]["name"])
+	assert.Equal(t, []any{"managed-by:ci"}, services[2]["tags"])
+}
+
+func TestSyncEventHooks(t *testing.T) {
+	hook := map[string]any{
+		"id": "h1", "source": "crud", "event": "consumers", "handler": "webhook",
+		"config": map[string]any{"url": "https://example.com/hook"},
+	}
+	fake, client := newFakeKongClient(t, map[string][]map[string]any{
+		"event-hooks": {hook},
+	})
+	content := workspaceContent("", "svc")
+
+	// event hooks are left alone unless included, so that syncing a file
+	// without event hooks does not delete them.
+	res := Sync(context.Background(), client, content, Options{})
+	require.NoError(t, res.Err())
+	assert.Equal(t, int32(1), res.Stats.CreateOps.Count())
+	assert.Equal(t, int32(0), res.Stats.DeleteOps.Count())
+	assert.Len(t, fake.entities[""]["event-hooks"], 1)
+
+	opts := Options{}
+	opts.DumpConfig.IncludeEventHooks = true
+	res = Sync(context.Background(), client, content, opts)
+	require.NoError(t, res.Err())
+	assert.Equal(t, int32(1), res.Stats.DeleteOps.Count())
+	assert.Empty(t, fake.entities[""]["event-hooks"])
+}
@@ -0,0 +1,248 @@
+package reconcile
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"slices"
+
+	"github.com/kong/go-database-reconciler/pkg/types"
+	"github.com/kong/go-database-reconciler/pkg/utils"
+	"github.com/kong/go-kong/kong"
+)
+
+// RewriteRules are applied to the configuration of a source gateway before it
+// is promoted to a destination gateway.
+type RewriteRules struct {
+	// Hosts maps hostnames of the source gateway to the ones of the
+	// destination. Hostnames are substituted in the host of services and
+	// upstreams, the hosts of routes, the target of targets and the name of
+	// SNIs. Only exact matches are substituted.
+	Hosts map[string]string
+
+	// Tags maps tags of the source gateway to the ones of the destination,
+	// including the selector tags.
+	Tags map[string]string
+
+	// ExcludeKinds lists the kinds of entities that are not promoted, such as
+	// types.Consumer or types.Certificate. Entities referring to an entity of
+	// an excluded kind are excluded as well.
+	ExcludeKinds []types.EntityType
+}
+
+// excludableKinds clears the entities of each kind from a raw state.
+var excludableKinds = map[types.EntityType]func(raw *utils.KongRawState){
+	types.Service:                func(raw *utils.KongRawState) { raw.Services = nil },
+	types.Route:                  func(raw *utils.KongRawState) { raw.Routes = nil },
+	types.Plugin:                 func(raw *utils.KongRawState) { raw.Plugins = nil },
+	types.FilterChain:            func(raw *utils.KongRawState) { raw.FilterChains = nil },
+	types.Upstream:               func(raw *utils.KongRawState) { raw.Upstreams = nil },
+	types.Target:                 func(raw *utils.KongRawState) { raw.Targets = nil },
+	types.Certificate:            func(raw *utils.KongRawState) { raw.Certificates = nil },
+	types.SNI:                    func(raw *utils.KongRawState) { raw.SNIs = nil },
+	types.CACertificate:          func(raw *utils.KongRawState) { raw.CACertificates = nil },
+	types.Consumer:               func(raw *utils.KongRawState) { raw.Consumers = nil },
+	types.ConsumerGroup:          func(raw *utils.KongRawState) { raw.ConsumerGroups = nil },
+	types.Vault:                  func(raw *utils.KongRawState) { raw.Vaults = nil },
+	types.License:                func(raw *utils.KongRawState) { raw.Licenses = nil },
+	types.Workspace:              func(raw *utils.KongRawState) { raw.Workspaces = nil },
+	types.Partial:                func(raw *utils.KongRawState) { raw.Partials = nil },
+	types.KeyAuth:                func(raw *utils.KongRawState) { raw.KeyAuths = nil },
+	types.HMACAuth:               func(raw *utils.KongRawState) { raw.HMACAuths = nil },
+	types.JWTAuth:                func(raw *utils.KongRawState) { raw.JWTAuths = nil },
+	types.BasicAuth:              func(raw *utils.KongRawState) { raw.BasicAuths = nil },
+	types.ACLGroup:               func(raw *utils.KongRawState) { raw.ACLGroups = nil },
+	types.OAuth2Cred:             func(raw *utils.KongRawState) { raw.Oauth2Creds = nil },
+	types.MTLSAuth:               func(raw *utils.KongRawState) { raw.MTLSAuths = nil },
+	types.DegraphqlRoute:         func(raw *utils.KongRawState) { raw.DegraphqlRoutes = nil },
+	types.Key:                    func(raw *utils.KongRawState) { raw.Keys = nil },
+	types.KeySet:                 func(raw *utils.KongRawState) { raw.KeySets = nil },
+	types.ClonedPluginDefinition: func(raw *utils.KongRawState) { raw.ClonedPluginDefinitions = nil },
+	types.CustomPluginDefinition: func(raw *utils.KongRawState) { raw.CustomPluginDefinitions = nil },
+	types.GraphqlRateLimitingCostDecoration: func(raw *utils.KongRawState) {
+		raw.GraphqlRateLimitingCostDecorations = nil
+	},
+}
+
+func (r RewriteRules) validate() error {
+	for _, kind := range r.ExcludeKinds {
+		if _, ok := excludableKinds[kind]; !ok {
+			return fmt.Errorf("rewrite rules: kind %q cannot be excluded", kind)
+		}
+	}
+	for from, to := range r.Tags {
+		if from == "" || to == "" {
+			return fmt.Errorf("rewrite rules: tags must not be empty")
+		}
+	}
+	for from, to := range r.Hosts {
+		if from == "" || to == "" {
+			return fmt.Errorf("rewrite rules: hosts must not be empty")
+		}
+	}
+	return nil
+}
+
+// apply rewrites raw in place.
+func (r RewriteRules) apply(raw *utils.KongRawState) {
+	for _, kind := range r.ExcludeKinds {
+		excludableKinds[kind](raw)
+	}
+	if len(r.ExcludeKinds) > 0 {
+		dropDangling(raw, r.ExcludeKinds)
+	}
+	if len(r.Hosts) > 0 {
+		r.rewriteHosts(raw)
+	}
+	if len(r.Tags) > 0 {
+		rewriteTags(reflect.ValueOf(raw), r.tag)
+	}
+}
+
+func (r RewriteRules) host(host string) string {
+	if to, ok := r.Hosts[host]; ok {
+		return to
+	}
+	return host
+}
+
+func (r RewriteRules) tag(tag string) string {
+	if to, ok := r.Tags[tag]; ok {
+		return to
+	}
+	return tag
+}
+
+func (r RewriteRules) rewriteHosts(raw *utils.KongRawState) {
+	rewrite := func(host *string) {
+		if host != nil {
+			*host = r.host(*host)
+		}
+	}
+	for _, service := range raw.Services {
+		rewrite(service.Host)
+	}
+	for _, route := range raw.Routes {
+		for _, host := range route.Hosts {
+			rewrite(host)
+		}
+	}
+	for _, upstream := range raw.Upstreams {
+		rewrite(upstream.Name)
+		rewrite(upstream.HostHeader)
+	}
+	for _, target := range raw.Targets {
+		if target.Target == nil {
+			continue
+		}
+		if host, port, err := net.SplitHostPort(*target.Target); err == nil {
+			target.Target = new(net.JoinHostPort(r.host(host), port))
+		} else {
+			rewrite(target.Target)
+		}
+	}
+	for _, sni := range raw.SNIs {
+		rewrite(sni.Name)
+	}
+}
+
+// rewriteTags renames the tags of every entity reachable from v.
+func rewriteTags(v reflect.Value, rename func(string) string) {
+	//nolint:exhaustive
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			rewriteTags(v.Elem(), rename)
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			rewriteTags(v.Index(i), rename)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Type().Field(i).IsExported() {
+				continue
+			}
+			field := v.Field(i)
+			if tags, ok := field.Interface().([]*string); ok && v.Type().Field(i).Name == "Tags" {
+				for _, tag := range tags {
+					if tag != nil {
+						*tag = rename(*tag)
+					}
+				}
+				continue
+			}
+			rewriteTags(field, rename)
+		}
+	}
+}
+
+// referenceFields are the fields through which entities refer to entities of
+// another kind.
+var referenceFields = map[types.EntityType]string{
+	types.Service:       "Service",
+	types.Route:         "Route",
+	types.Consumer:      "Consumer",
+	types.ConsumerGroup: "ConsumerGroup",
+	types.Upstream:      "Upstream",
+	types.Certificate:   "Certificate",
+}
+
+// dropDangling removes the entities of raw referring to an entity of an
+// excluded kind.
+func dropDangling(raw *utils.KongRawState, excluded []types.EntityType) {
+	fields := map[string]bool{}
+	for _, kind := range excluded {
+		if field, ok := referenceFields[kind]; ok {
+			fields[field] = true
+		}
+	}
+	// Routes of excluded services are dropped, and so are the entities
+	// referring to them.
+	droppedRoutes := map[string]bool{}
+
+	dangling := func(entity any) bool {
+		v := reflect.Indirect(reflect.ValueOf(entity))
+		for field := range fields {
+			ref := v.FieldByName(field)
+			if ref.IsValid() && ref.Kind() == reflect.Pointer && !ref.IsNil() {
+				return true
+			}
+		}
+		if route := v.FieldByName("Route"); route.IsValid() {
+			ref, ok := route.Interface().(*kong.Route)
+			return ok && ref != nil && ref.ID != nil && droppedRoutes[*ref.ID]
+		}
+		return false
+	}
+
+	raw.Routes = slices.DeleteFunc(raw.Routes, func(e *kong.Route) bool {
+		if dangling(e) {
+			if e.ID != nil {
+				droppedRoutes[*e.ID] = true
+			}
+			return true
+		}
+		return false
+	})
+	raw.Plugins = slices.DeleteFunc(raw.Plugins, func(e *kong.Plugin) bool { return dangling(e) })
+	raw.Targets = slices.DeleteFunc(raw.Targets, func(e *kong.Target) bool { return dangling(e) })
+	raw.SNIs = slices.DeleteFunc(raw.SNIs, func(e *kong.SNI) bool { return dangling(e) })
+	raw.KeyAuths = slices.DeleteFunc(raw.KeyAuths, func(e *kong.KeyAuth) bool { return dangling(e) })
+	raw.HMACAuths = slices.DeleteFunc(raw.HMACAuths, func(e *kong.HMACAuth) bool { return dangling(e) })
+	raw.JWTAuths = slices.DeleteFunc(raw.JWTAuths, func(e *kong.JWTAuth) bool { return dangling(e) })
+	raw.BasicAuths = slices.DeleteFunc(raw.BasicAuths, func(e *kong.BasicAuthOptions) bool {
+		return dangling(&e.BasicAuth)
+	})
+	raw.ACLGroups = slices.DeleteFunc(raw.ACLGroups, func(e *kong.ACLGroup) bool { return dangling(e) })
+	raw.Oauth2Creds = slices.DeleteFunc(raw.Oauth2Creds, func(e *kong.Oauth2Credential) bool { return dangling(e) })
+	raw.MTLSAuths = slices.DeleteFunc(raw.MTLSAuths, func(e *kong.MTLSAuth) bool { return dangling(e) })
+	raw.DegraphqlRoutes = slices.DeleteFunc(raw.DegraphqlRoutes, func(e *kong.DegraphqlRoute) bool {
+		return dangling(e)
+	})
+	if fields["Consumer"] {
+		for _, group := range raw.ConsumerGroups {
+			group.Consumers = nil
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/kong/go-database-reconciler/pkg/crud"
+	"github.com/kong/go-database-reconciler/pkg/diff"
+	"github.com/kong/go-database-reconciler/pkg/file"
+	"github.com/kong/go-database-reconciler/pkg/utils"
+	"github.com/kong/go-kong/kong"
+)
+
+// WatchOptions configures a Watcher.
+type WatchOptions struct {
+	// Options configures how the state files are reconciled with Kong.
+	Options
+
+	// EnvVarsMode controls how environment variables are rendered in the
+	// state files.
+	EnvVarsMode file.RenderEnvVarsMode
+
+	// Debounce is the time to wait after a change to the state files before
+	// reconciling them, so that a burst of changes is reconciled once. It
+	// defaults to 500ms.
+	Debounce time.Duration
+}
+
+const defaultDebounce = 500 * time.Millisecond
+
+// Watcher reconciles state files with Kong each time they change.
+type Watcher struct {
+	client    *kong.Client
+	filenames []string
+	opts      WatchOptions
+
+	// files and dirs are the absolute paths of the state files and
+	// directories being watched.
+	files map[string]bool
+	dirs  []string
+
+	results chan diff.EntityAction
+	last    *file.Content
+}
+
+// NewWatcher returns a Watcher reconciling the state files or directories
+// filenames, as read by file.GetContentFromFiles, with the workspace of
+// client.
+func NewWatcher(client *kong.Client, filenames []string, opts WatchOptions) (*Watcher, error) {
+	if len(filenames) == 0 {
+		return nil, file.ErrorFilenameEmpty
+	}
+	if opts.Debounce <= 0 {
+		opts.Debounce = defaultDebounce
+	}
+	w := &Watcher{
+		client:    client,
+		filenames: filenames,
+		opts:      opts,
+		files:     map[string]bool{},
+		results:   make(chan diff.EntityAction),
+	}
+	for _, filename := range filenames {
+		if filename == "-" {
+			return nil, fmt.Errorf("state files cannot be read from stdin when watching them")
+		}
+		path, err := filepath.Abs(filename)
+		if err != nil {
+			return nil, fmt.Errorf("watching %s: %w", filename, err)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("watching %s: %w", filename, err)
+		}
+		if info.IsDir() {
+			w.dirs = append(w.dirs, path)
+		} else {
+			w.files[path] = true
+		}
+	}
+	return w, nil
+}
+
+// Results returns the channel the outcome of each reconciliation is sent to.
+// An EntityAction is sent for each entity created, updated or deleted. Errors
+// not related to an entity, such as invalid state files, are sent as an
+// EntityAction with only Error set.
+//
+// The channel must be consumed while Run is running, and is closed when Run
+// returns.
+func (w *Watcher) Results() <-chan diff.EntityAction {
+	return w.results
+}
+
+// Run reconciles the state files once, then again each time they change,
+// until ctx is done. Changes are only applied if the rendered state files
+// differ from the ones last reconciled successfully.
+func (w *Watcher) Run(ctx context.Context) error {
+	defer close(w.results)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("watching state files: %w", err)
+	}
+	defer watcher.Close()
+	for path := range w.files {
+		// The parent directory is watched rather than the file itself, since
+		// editors commonly replace files rather than writing to them.
+		if err := watcher.Add(filepath.Dir(path)); err != nil {
+			return fmt.Errorf("watching %s: %w", path, err)
+		}
+	}
+	for _, dir := range w.dirs {
+		if err := watchTree(watcher, dir); err != nil {
+			return err
+		}
+	}
+
+	w.reconcile(ctx)
+	timer := time.NewTimer(w.opts.Debounce)
+	timer.Stop()
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Has(fsnotify.Create) && w.inDirs(event.Name) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := watchTree(watcher, event.Name); err != nil {
+						w.report(ctx, err)
+					}
+				}
+			}
+			if event.Has(fsnotify.Chmod) || !w.watched(event.Name) {
+				continue
+			}
+			timer.Reset(w.opts.Debounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			w.report(ctx, fmt.Errorf("watching state files: %w", err))
+		case <-timer.C:
+			w.reconcile(ctx)
+		}
+	}
+}
+
+// watchTree watches dir and all the directories below it.
+func watchTree(watcher *fsnotify.Watcher, dir string) error {
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return watcher.Add(path)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("watching %s: %w", dir, err)
+	}
+	return nil
+}
+
+func (w *Watcher) inDirs(path string) bool {
+	for _, dir := range w.dirs {
+		if path == dir || strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// watched reports whether a change to path may change the state files.
+func (w *Watcher) watched(path string) bool {
+	if w.files[path] {
+		return true
+	}
+	if !w.inDirs(path) {
+		return false
+	}
+	// path may be a removed directory, which cannot be told apart from a
+	// file anymore.
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json", "":
+		return true
+	}
+	return false
+}
+
+// report sends err to the results channel.
+func (w *Watcher) report(ctx context.Context, err error) {
+	select {
+	case w.results <- diff.EntityAction{Error: err}:
+	case <-ctx.Done():
+	}
+}
+
+// reconcile renders the state files and syncs them with Kong, unless they did
+// not change since they were last reconciled successfully.
+func (w *Watcher) reconcile(ctx context.Context) {
+	content, err := file.GetContentFromFilesWithEnvVars(w.filenames, w.opts.EnvVarsMode)
+	if err != nil {
+		w.report(ctx, err)
+		return
+	}
+	if reflect.DeepEqual(content, w.last) {
+		return
+	}
+	syncer, _, err := newSyncer(ctx, w.client, content, w.opts.Options, true)
+	if err != nil {
+		w.report(ctx, err)
+		return
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for action := range syncer.GetResultChan() {
+			select {
+			case w.results <- action:
+			case <-ctx.Done():
+			}
+		}
+	}()
+	_, errs, _ := syncer.Solve(ctx, w.opts.parallelism(), w.opts.DryRun, true)
+	<-done
+
+	// Errors of entities were already sent along with their EntityAction.
+	var unreported []error
+	for _, err := range errs {
+		if _, ok := errors.AsType[*crud.ActionError](err); !ok {
+			unreported = append(unreported, err)
+		}
+	}
+	if len(unreported) > 0 {
+		w.report(ctx, utils.ErrArray{Errors: unreported})
+	}
+	if len(errs) == 0 {
+		w.last = content
+	}
+}
@@ -0,0 +1,75 @@
+package reconcile
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kong/go-database-reconciler/pkg/diff"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func nextAction(t *testing.T, results <-chan diff.EntityAction) diff.EntityAction {
+	t.Helper()
+	select {
+	case action := <-results:
+		return action
+	case <-time.After(10 * time.Second):
+		require.FailNow(t, "no EntityAction received")
+	}
+	return diff.EntityAction{}
+}
+
+func TestWatcher(t *testing.T) {
+	fake, client := newFakeKongClient(t, nil)
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+	writeFile("svc1.yaml", "_format_version: \"3.0\"\nservices:\n- name: svc1\n  host: svc1.example.com\n")
+
+	w, err := NewWatcher(client, []string{dir}, WatchOptions{Debounce: 20 * time.Millisecond})
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- w.Run(ctx) }()
+	results := w.Results()
+
+	action := nextAction(t, results)
+	require.NoError(t, action.Error)
+	assert.Equal(t, diff.CreateAction, action.Action)
+	assert.Equal(t, "svc1", action.Entity.Name)
+
+	// Files added to a sub-directory created after the watcher started are
+	// reconciled too.
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "more"), 0o700))
+	time.Sleep(50 * time.Millisecond)
+	writeFile("more/svc2.yaml", "_format_version: \"3.0\"\nservices:\n- name: svc2\n  host: svc2.example.com\n")
+	action = nextAction(t, results)
+	require.NoError(t, action.Error)
+	assert.Equal(t, diff.CreateAction, action.Action)
+	assert.Equal(t, "svc2", action.Entity.Name)
+	assert.Equal(t, map[string]int{" services": 2}, fake.writes)
+
+	writeFile("svc1.yaml", "services: [")
+	action = nextAction(t, results)
+	require.Error(t, action.Error)
+	assert.Empty(t, action.Action)
+
+	cancel()
+	require.NoError(t, <-done)
+	_, ok := <-results
+	assert.False(t, ok)
+}
+
+func TestNewWatcherErrors(t *testing.T) {
+	_, err := NewWatcher(nil, nil, WatchOptions{})
+	require.Error(t, err)
+	_, err = NewWatcher(nil, []string{"-"}, WatchOptions{})
+	require.EqualError(t, err, "state files cannot be read from stdin when watching them")
+	_, err = NewWatcher(nil, []string{filepath.Join(t.TempDir(), "missing.yaml")}, WatchOptions{})
+	require.Error(t, err)
+}
@@ -0,0 +1,282 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/kong/go-database-reconciler/pkg/diff"
+	"github.com/kong/go-database-reconciler/pkg/dump"
+	"github.com/kong/go-database-reconciler/pkg/file"
+	"github.com/kong/go-database-reconciler/pkg/schema"
+	"github.com/kong/go-database-reconciler/pkg/state"
+	"github.com/kong/go-database-reconciler/pkg/utils"
+	"github.com/kong/go-kong/kong"
+	"golang.org/x/sync/errgroup"
+)
+
+// WorkspacesOptions configures how several workspaces are reconciled.
+type WorkspacesOptions struct {
+	Options
+
+	// Concurrency is the maximum number of workspaces reconciled at the same
+	// time. Zero means all workspaces are reconciled concurrently.
+	Concurrency int
+}
+
+// WorkspacesResult is the combined outcome of reconciling several workspaces.
+type WorkspacesResult struct {
+	// Workspaces holds the Result of each workspace, sorted by workspace name.
+	Workspaces []*Result
+
+	// WorkspaceEntities holds the Result of reconciling the workspaces
+	// themselves, if DumpConfig.IncludeWorkspaces is set.
+	WorkspaceEntities *Result
+
+	// Stats is the sum of the Stats of all workspaces.
+	Stats diff.Stats
+}
+
+// Errors returns the errors of all workspaces, prefixed with the name of the
+// workspace they occurred in.
+func (r *WorkspacesResult) Errors() []error {
+	var errs []error
+	if r.WorkspaceEntities != nil {
+		for _, err := range r.WorkspaceEntities.Errors {
+			errs = append(errs, fmt.Errorf("workspaces: %w", err))
+		}
+	}
+	for _, res := range r.Workspaces {
+		for _, err := range res.Errors {
+			errs = append(errs, fmt.Errorf("workspace %s: %w", res.Workspace, err))
+		}
+	}
+	return errs
+}
+
+// Err returns the errors of all workspaces as a utils.ErrArray, or nil if
+// every workspace was reconciled successfully.
+func (r *WorkspacesResult) Err() error {
+	errs := r.Errors()
+	if len(errs) == 0 {
+		return nil
+	}
+	return utils.ErrArray{Errors: errs}
+}
+
+// SyncWorkspaces reconciles several workspaces in a single operation.
+//
+// contents maps the name of each workspace to its Content, as returned by
+// file.GetContentByWorkspace. The empty name stands for the workspace of
+// config. Each workspace is dumped and synced concurrently with a client
+// obtained from config.ForWorkspace, and all of them share a single schema
+// registry.
+//
+// If opts.DumpConfig.IncludeWorkspaces is set, the workspaces declared in the
+// workspaces section of contents are reconciled first, including the
+// deletion of the undeclared ones except for the default workspace. Each
+// workspace of contents must then be declared. Otherwise, the workspaces of
+// contents which do not exist are created, with the settings declared for
+// them if any.
+//
+// A failing workspace does not stop the others; its errors are recorded in
+// the returned WorkspacesResult. An error is returned only if the workspaces
+// cannot be reconciled at all.
+func SyncWorkspaces(ctx context.Context, config utils.KongClientConfig, contents map[string]*file.Content,
+	opts WorkspacesOptions,
+) (*WorkspacesResult, error) {
+	if opts.Concurrency < 0 {
+		return nil, fmt.Errorf("reconcile: Concurrency must not be negative")
+	}
+	byWorkspace := make(map[string]*file.Content, len(contents))
+	for workspace, content := range contents {
+		if content == nil {
+			return nil, fmt.Errorf("no content for workspace %q", workspace)
+		}
+		if content.Workspace != "" && content.Workspace != workspace {
+			return nil, fmt.Errorf("content for workspace %q targets workspace %q", workspace, content.Workspace)
+		}
+		if workspace == "" {
+			workspace = config.Workspace
+		}
+		if _, ok := byWorkspace[workspace]; ok {
+			return nil, fmt.Errorf("workspace %q is declared more than once", workspace)
+		}
+		byWorkspace[workspace] = content
+	}
+	workspaces := slices.Sorted(maps.Keys(byWorkspace))
+	declared, err := declaredWorkspaces(contents)
+	if err != nil {
+		return nil, err
+	}
+	includeWorkspaces := opts.DumpConfig.IncludeWorkspaces
+	if includeWorkspaces {
+		for _, workspace := range workspaces {
+			if _, ok := declared[workspace]; !ok && workspace != "" {
+				return nil, fmt.Errorf("workspace %q is not declared in the workspaces section", workspace)
+			}
+		}
+		// Each workspace is synced without the workspaces, which are
+		// reconciled once beforehand.
+		opts.DumpConfig.IncludeWorkspaces = false
+	}
+
+	// Clients are created upfront since GetKongClient sets the transport of
+	// a shared HTTP client.
+	clients := make([]*kong.Client, len(workspaces))
+	for i, workspace := range workspaces {
+		client, err := utils.GetKongClient(config.ForWorkspace(workspace))
+		if err != nil {
+			return nil, fmt.Errorf("creating client for workspace %q: %w", workspace, err)
+		}
+		clients[i] = client
+	}
+	rootClient, err := utils.GetKongClient(config.ForWorkspace(""))
+	if err != nil {
+		return nil, fmt.Errorf("creating client: %w", err)
+	}
+	if opts.SchemaRegistry == nil {
+		opts.SchemaRegistry = schema.NewRegistry(rootClient, false)
+	}
+
+	res := &WorkspacesResult{
+		Stats: diff.Stats{
+			CreateOps: &utils.AtomicInt32Counter{},
+			UpdateOps: &utils.AtomicInt32Counter{},
+			DeleteOps: &utils.AtomicInt32Counter{},
+		},
+	}
+	if includeWorkspaces {
+		entities := reconcileWorkspaces(ctx, rootClient, slices.Collect(maps.Values(declared)), opts.Options)
+		res.WorkspaceEntities = entities
+		res.Stats.CreateOps.Increment(entities.Stats.CreateOps.Count())
+		res.Stats.UpdateOps.Increment(entities.Stats.UpdateOps.Count())
+		res.Stats.DeleteOps.Increment(entities.Stats.DeleteOps.Count())
+		if len(entities.Errors) > 0 {
+			// The workspaces are not synced, since their entities would be
+			// synced in workspaces which may not exist.
+			return res, nil
+		}
+	}
+
+	res.Workspaces = make([]*Result, len(workspaces))
+
+	var group errgroup.Group
+	if opts.Concurrency > 0 {
+		group.SetLimit(opts.Concurrency)
+	}
+	for i, workspace := range workspaces {
+		group.Go(func() error {
+			var result *Result
+			skipCreate := config.SkipWorkspaceCrud || opts.DryRun || includeWorkspaces
+			if err := ensureWorkspace(ctx, rootClient, workspace, declared[workspace], skipCreate); err != nil {
+				result = newResult(workspace)
+				result.Errors = append(result.Errors, err)
+			} else {
+				result = Sync(ctx, clients[i], byWorkspace[workspace], opts.Options)
+			}
+			res.Workspaces[i] = result
+			res.Stats.CreateOps.Increment(result.Stats.CreateOps.Count())
+			res.Stats.UpdateOps.Increment(result.Stats.UpdateOps.Count())
+			res.Stats.DeleteOps.Increment(result.Stats.DeleteOps.Count())
+			return nil
+		})
+	}
+	_ = group.Wait()
+	return res, nil
+}
+
+// declaredWorkspaces returns the workspaces declared in the workspaces
+// section of contents, by name.
+func declaredWorkspaces(contents map[string]*file.Content) (map[string]file.FWorkspace, error) {
+	declared := map[string]file.FWorkspace{}
+	for _, content := range contents {
+		for _, workspace := range content.Workspaces {
+			if utils.Empty(workspace.Name) {
+				return nil, fmt.Errorf("workspace name is required")
+			}
+			if _, ok := declared[*workspace.Name]; ok {
+				return nil, fmt.Errorf("workspace %q is defined more than once", *workspace.Name)
+			}
+			declared[*workspace.Name] = workspace
+		}
+	}
+	return declared, nil
+}
+
+// reconcileWorkspaces creates, updates and deletes the workspaces of Kong
+// with rootClient to match declared, leaving the entities of all workspaces
+// untouched.
+func reconcileWorkspaces(ctx context.Context, rootClient *kong.Client, declared []file.FWorkspace,
+	opts Options,
+) *Result {
+	res := newResult("")
+	err := func() error {
+		current, err := dump.GetAllWorkspaces(ctx, rootClient)
+		if err != nil {
+			return fmt.Errorf("reading workspaces from Kong: %w", err)
+		}
+		currentState, err := state.Get(&utils.KongRawState{Workspaces: current})
+		if err != nil {
+			return fmt.Errorf("building current state: %w", err)
+		}
+		targetRaw, err := file.Get(ctx, &file.Content{Workspaces: declared}, file.RenderConfig{
+			CurrentState: currentState,
+			KongVersion:  opts.KongVersion,
+		}, dump.Config{IncludeWorkspaces: true}, rootClient)
+		if err != nil {
+			return err
+		}
+		targetState, err := state.Get(targetRaw)
+		if err != nil {
+			return fmt.Errorf("building target state: %w", err)
+		}
+		syncer, err := diff.NewSyncer(diff.SyncerOpts{
+			CurrentState:      currentState,
+			TargetState:       targetState,
+			KongClient:        rootClient,
+			SilenceWarnings:   opts.SilenceWarnings,
+			NoMaskValues:      opts.NoMaskValues,
+			IncludeWorkspaces: true,
+			NoDeletes:         opts.NoDeletes,
+			SchemaRegistry:    opts.SchemaRegistry,
+		})
+		if err != nil {
+			return fmt.Errorf("creating syncer: %w", err)
+		}
+		stats, errs, changes := syncer.Solve(ctx, opts.parallelism(), opts.DryRun, true)
+		res.Stats = stats
+		res.Changes = changes
+		res.Errors = append(res.Errors, errs...)
+		return nil
+	}()
+	if err != nil {
+		res.Errors = append(res.Errors, err)
+	}
+	return res
+}
+
+// ensureWorkspace creates workspace with the root client if it does not exist
+// yet, unless skipCreate is set. It is created with the settings of
+// definition, if declared.
+func ensureWorkspace(ctx context.Context, rootClient *kong.Client, workspace string, definition file.FWorkspace,
+	skipCreate bool,
+) error {
+	if workspace == "" || skipCreate {
+		return nil
+	}
+	exists, err := rootClient.Workspaces.Exists(ctx, &workspace)
+	if err != nil {
+		return fmt.Errorf("checking if workspace exists: %w", err)
+	}
+	if exists {
+		return nil
+	}
+	ws := definition.DeepCopy().Workspace
+	ws.Name = &workspace
+	if _, err := rootClient.Workspaces.Create(ctx, &ws); err != nil {
+		return fmt.Errorf("creating workspace: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,316 @@
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/kong/go-database-reconciler/pkg/dump"
+	"github.com/kong/go-database-reconciler/pkg/file"
+	"github.com/kong/go-database-reconciler/pkg/utils"
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeKong is a minimal Admin API storing entities by workspace and kind.
+// writes counts the entities written, by workspace and kind.
+type fakeKong struct {
+	mu         sync.Mutex
+	workspaces map[string]bool
+	entities   map[string]map[string][]map[string]any
+	writes     map[string]int
+}
+
+func newFakeKong(t *testing.T, workspaces ...string) (*fakeKong, *httptest.Server) {
+	f := &fakeKong{
+		workspaces: map[string]bool{},
+		entities:   map[string]map[string][]map[string]any{},
+		writes:     map[string]int{},
+	}
+	for _, workspace := range workspaces {
+		f.workspaces[workspace] = true
+		f.upsert("", "workspaces", map[string]any{"id": workspace + "-id", "name": workspace})
+	}
+	server := httptest.NewServer(f)
+	t.Cleanup(server.Close)
+	return f, server
+}
+
+func (f *fakeKong) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if parts[0] == "" || len(parts) == 2 && parts[1] == "kong" {
+		_, _ = w.Write([]byte(`{"version": "3.9.0"}`))
+		return
+	}
+	workspace := ""
+	if f.workspaces[parts[0]] || len(parts) > 1 && parts[1] == "workspaces" {
+		workspace, parts = parts[0], parts[1:]
+	}
+	kind := parts[0]
+
+	switch {
+	case kind == "workspaces" && len(parts) == 2 && r.Method == http.MethodGet:
+		if !f.workspaces[parts[1]] {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "Not found"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"name": parts[1]})
+	case kind == "workspaces" && r.Method == http.MethodPost:
+		var ws kong.Workspace
+		_ = json.NewDecoder(r.Body).Decode(&ws)
+		f.workspaces[*ws.Name] = true
+		f.writes[*ws.Name+" workspaces"]++
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(ws)
+	case kind == "workspaces" && (r.Method == http.MethodPut || r.Method == http.MethodPatch):
+		var entity map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&entity)
+		entity["id"] = parts[1]
+		f.upsert("", kind, entity)
+		f.workspaces[entity["name"].(string)] = true
+		f.writes["workspaces"]++
+		_ = json.NewEncoder(w).Encode(entity)
+	case kind == "workspaces" && r.Method == http.MethodDelete:
+		entities := f.entities[""][kind]
+		for i, entity := range entities {
+			if entity["id"] == parts[1] {
+				f.entities[""][kind] = append(entities[:i:i], entities[i+1:]...)
+				delete(f.workspaces, entity["name"].(string))
+			}
+		}
+		f.writes["workspaces"]++
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodGet && len(parts) == 1:
+		data := []map[string]any{}
+		for _, entity := range f.entities[workspace][kind] {
+			if hasTags(entity, r.URL.Query().Get("tags")) {
+				data = append(data, entity)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+	case r.Method == http.MethodPut || r.Method == http.MethodPost || r.Method == http.MethodPatch:
+		var entity map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&entity)
+		if len(parts) == 2 {
+			entity["id"] = parts[1]
+		}
+		f.upsert(workspace, kind, entity)
+		f.writes[workspace+" "+kind]++
+		_ = json.NewEncoder(w).Encode(entity)
+	case r.Method == http.MethodDelete && len(parts) == 2:
+		entities := f.entities[workspace][kind]
+		for i, entity := range entities {
+			if entity["id"] == parts[1] || entity["name"] == parts[1] {
+				f.entities[workspace][kind] = append(entities[:i:i], entities[i+1:]...)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message": "Not found"}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message": "Not found"}`))
+	}
+}
+
+// upsert replaces the entity of workspace and kind with the ID of entity, or
+// adds entity.
+func (f *fakeKong) upsert(workspace, kind string, entity map[string]any) {
+	if f.entities[workspace] == nil {
+		f.entities[workspace] = map[string][]map[string]any{}
+	}
+	for i, existing := range f.entities[workspace][kind] {
+		if entity["id"] != nil && existing["id"] == entity["id"] {
+			f.entities[workspace][kind][i] = entity
+			return
+		}
+	}
+	f.entities[workspace][kind] = append(f.entities[workspace][kind], entity)
+}
+
+// hasTags reports whether entity carries all the comma-separated tags.
+func hasTags(entity map[string]any, tags string) bool {
+	if tags == "" {
+		return true
+	}
+	// Tags are []string in the entities of tests and []any in written ones.
+	var entityTags []string
+	switch t := entity["tags"].(type) {
+	case []string:
+		entityTags = t
+	case []any:
+		for _, tag := range t {
+			s, _ := tag.(string)
+			entityTags = append(entityTags, s)
+		}
+	}
+	for tag := range strings.SplitSeq(tags, ",") {
+		if !slices.Contains(entityTags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+func workspaceContent(workspace, service string) *file.Content {
+	return &file.Content{
+		Workspace: workspace,
+		Services: []file.FService{{
+			Service: kong.Service{Name: new(service), Host: new(service + ".example.com")},
+		}},
+	}
+}
+
+func TestSyncWorkspaces(t *testing.T) {
+	fake, server := newFakeKong(t, "foo")
+	contents := map[string]*file.Content{
+		"foo": workspaceContent("foo", "svc1"),
+		"bar": workspaceContent("bar", "svc2"),
+	}
+	opts := WorkspacesOptions{Options: Options{KongVersion: semver.MustParse("3.9.0")}}
+
+	res, err := SyncWorkspaces(context.Background(), utils.KongClientConfig{Address: server.URL}, contents, opts)
+	require.NoError(t, err)
+	require.NoError(t, res.Err())
+
+	require.Len(t, res.Workspaces, 2)
+	assert.Equal(t, "bar", res.Workspaces[0].Workspace)
+	assert.Equal(t, "foo", res.Workspaces[1].Workspace)
+	for _, result := range res.Workspaces {
+		assert.Equal(t, int32(1), result.Stats.CreateOps.Count())
+	}
+	assert.Equal(t, int32(2), res.Stats.CreateOps.Count())
+	assert.Equal(t, int32(0), res.Stats.UpdateOps.Count())
+	assert.Equal(t, int32(0), res.Stats.DeleteOps.Count())
+
+	assert.Equal(t, map[string]int{
+		"bar workspaces": 1,
+		"bar services":   1,
+		"foo services":   1,
+	}, fake.writes)
+
+	// Syncing again finds both workspaces in sync.
+	res, err = SyncWorkspaces(context.Background(), utils.KongClientConfig{Address: server.URL}, contents, opts)
+	require.NoError(t, err)
+	require.NoError(t, res.Err())
+	assert.Equal(t, int32(0), res.Stats.CreateOps.Count())
+	assert.Equal(t, int32(0), res.Stats.UpdateOps.Count())
+}
+
+func TestSyncWorkspacesDryRun(t *testing.T) {
+	fake, server := newFakeKong(t)
+	contents := map[string]*file.Content{
+		"foo": workspaceContent("foo", "svc1"),
+		"bar": workspaceContent("", "svc2"),
+	}
+	opts := WorkspacesOptions{
+		Options:     Options{KongVersion: semver.MustParse("3.9.0"), DryRun: true},
+		Concurrency: 1,
+	}
+
+	res, err := SyncWorkspaces(context.Background(), utils.KongClientConfig{Address: server.URL}, contents, opts)
+	require.NoError(t, err)
+	require.NoError(t, res.Err())
+	assert.Equal(t, int32(2), res.Stats.CreateOps.Count())
+	for _, result := range res.Workspaces {
+		require.Len(t, result.Changes.Creating, 1)
+		assert.Equal(t, "service", result.Changes.Creating[0].Kind)
+	}
+	assert.Empty(t, fake.writes)
+}
+
+func TestSyncWorkspacesErrors(t *testing.T) {
+	_, server := newFakeKong(t, "foo")
+	config := utils.KongClientConfig{Address: server.URL, Workspace: "foo"}
+	opts := WorkspacesOptions{Options: Options{KongVersion: semver.MustParse("3.9.0")}}
+
+	_, err := SyncWorkspaces(context.Background(), config, map[string]*file.Content{
+		"foo": workspaceContent("bar", "svc1"),
+	}, opts)
+	require.EqualError(t, err, `content for workspace "foo" targets workspace "bar"`)
+
+	_, err = SyncWorkspaces(context.Background(), config, map[string]*file.Content{
+		"":    workspaceContent("", "svc1"),
+		"foo": workspaceContent("foo", "svc2"),
+	}, opts)
+	require.EqualError(t, err, `workspace "foo" is declared more than once`)
+
+	// A workspace that cannot be synced does not prevent the others from
+	// being synced.
+	config.SkipWorkspaceCrud = true
+	res, err := SyncWorkspaces(context.Background(), config, map[string]*file.Content{
+		"foo": workspaceContent("foo", "svc1"),
+		"bar": workspaceContent("bar", "svc2"),
+	}, opts)
+	require.NoError(t, err)
+	require.EqualError(t, res.Err(), "1 errors occurred:\n\tworkspace bar: workspace bar does not exist\n")
+	assert.Equal(t, int32(1), res.Stats.CreateOps.Count())
+}
+
+func TestSyncWorkspacesDeclared(t *testing.T) {
+	fake, server := newFakeKong(t, "default", "foo", "old")
+	contents := map[string]*file.Content{
+		"foo": workspaceContent("foo", "svc1"),
+		"bar": workspaceContent("bar", "svc2"),
+	}
+	contents["foo"].Workspaces = []file.FWorkspace{
+		{Workspace: kong.Workspace{Name: new("foo"), Comment: new("team foo")}},
+		{Workspace: kong.Workspace{Name: new("bar"), Meta: map[string]any{"color": "#ff0000"}}},
+	}
+	opts := WorkspacesOptions{Options: Options{
+		KongVersion: semver.MustParse("3.9.0"),
+		DumpConfig:  dump.Config{IncludeWorkspaces: true},
+	}}
+
+	res, err := SyncWorkspaces(context.Background(), utils.KongClientConfig{Address: server.URL}, contents, opts)
+	require.NoError(t, err)
+	require.NoError(t, res.Err())
+
+	// bar is created, foo updated and old deleted, before the services of
+	// bar and foo are created. The default workspace is kept.
+	require.NotNil(t, res.WorkspaceEntities)
+	assert.Equal(t, int32(1), res.WorkspaceEntities.Stats.CreateOps.Count())
+	assert.Equal(t, int32(1), res.WorkspaceEntities.Stats.UpdateOps.Count())
+	assert.Equal(t, int32(1), res.WorkspaceEntities.Stats.DeleteOps.Count())
+	assert.Equal(t, int32(3), res.Stats.CreateOps.Count())
+	assert.Equal(t, map[string]bool{"default": true, "foo": true, "bar": true}, fake.workspaces)
+	assert.Equal(t, map[string]int{
+		"workspaces":   3,
+		"bar services": 1,
+		"foo services": 1,
+	}, fake.writes)
+	for _, workspace := range fake.entities[""]["workspaces"] {
+		switch workspace["name"] {
+		case "foo":
+			assert.Equal(t, "team foo", workspace["comment"])
+		case "bar":
+			assert.Equal(t, map[string]any{"color": "#ff0000"}, workspace["meta"])
+		}
+	}
+
+	// Syncing again finds the workspaces in sync.
+	res, err = SyncWorkspaces(context.Background(), utils.KongClientConfig{Address: server.URL}, contents, opts)
+	require.NoError(t, err)
+	require.NoError(t, res.Err())
+	assert.Equal(t, int32(0), res.Stats.CreateOps.Count())
+	assert.Equal(t, int32(0), res.Stats.UpdateOps.Count())
+	assert.Equal(t, int32(0), res.Stats.DeleteOps.Count())
+
+	// Each workspace synced must be declared.
+	contents["baz"] = workspaceContent("baz", "svc3")
+	_, err = SyncWorkspaces(context.Background(), utils.KongClientConfig{Address: server.URL}, contents, opts)
+	require.EqualError(t, err, `workspace "baz" is not declared in the workspaces section`)
+}
@@ -0,0 +1,111 @@
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/kong/go-kong/kong"
+)
+
+// EntityDefinition describes an entity of Kong as derived from its schema:
+// the fields which identify it and the entities it references. It allows
+// entities of the DAOs of plugins to be synced without code specific to them.
+type EntityDefinition struct {
+	// Name is the name of the entity, which is also the path of its
+	// endpoint on the Admin API.
+	Name string
+	// NaturalKey lists the fields identifying an entity besides its ID, if
+	// any: the endpoint key of the entity, its cache key or else its first
+	// unique field.
+	NaturalKey []string
+	// ForeignKeys maps the fields referencing other entities to the name of
+	// the entity they reference.
+	ForeignKeys map[string]string
+}
+
+// NewEntityDefinition derives the definition of the entity name from its
+// schema, as returned by the /schemas endpoint of Kong. Only entities with an
+// id primary key are supported.
+func NewEntityDefinition(name string, entitySchema kong.Schema) (*EntityDefinition, error) {
+	if entitySchema == nil {
+		return nil, fmt.Errorf("entity %s has no schema", name)
+	}
+	var parsed struct {
+		PrimaryKey  []string                    `json:"primary_key"`
+		EndpointKey string                      `json:"endpoint_key"`
+		CacheKey    []string                    `json:"cache_key"`
+		Fields      []map[string]map[string]any `json:"fields"`
+	}
+	b, err := json.Marshal(entitySchema)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &parsed); err != nil {
+		return nil, fmt.Errorf("parsing schema of entity %s: %w", name, err)
+	}
+	if len(parsed.PrimaryKey) > 0 && !slices.Equal(parsed.PrimaryKey, []string{"id"}) {
+		return nil, fmt.Errorf("entity %s: primary key %v is not supported, only id is", name, parsed.PrimaryKey)
+	}
+
+	definition := &EntityDefinition{Name: name, ForeignKeys: map[string]string{}}
+	var unique []string
+	for _, field := range parsed.Fields {
+		for fieldName, attributes := range field {
+			if attributes["type"] == "foreign" {
+				reference, ok := attributes["reference"].(string)
+				if !ok {
+					return nil, fmt.Errorf("entity %s: foreign key %s has no reference", name, fieldName)
+				}
+				definition.ForeignKeys[fieldName] = reference
+			}
+			if attributes["unique"] == true && fieldName != "id" {
+				unique = append(unique, fieldName)
+			}
+		}
+	}
+	switch {
+	case parsed.EndpointKey != "":
+		definition.NaturalKey = []string{parsed.EndpointKey}
+	case len(parsed.CacheKey) > 0:
+		definition.NaturalKey = parsed.CacheKey
+	case len(unique) > 0:
+		definition.NaturalKey = unique[:1]
+	}
+	return definition, nil
+}
+
+// NaturalKeyOf returns the natural key of object, an entity of the type of d,
+// with the foreign keys reduced to the ID of the entity they reference. It
+// returns false if d has no natural key.
+func (d *EntityDefinition) NaturalKeyOf(object map[string]any) (string, bool) {
+	if len(d.NaturalKey) == 0 {
+		return "", false
+	}
+	values := make([]any, 0, len(d.NaturalKey))
+	for _, field := range d.NaturalKey {
+		value := object[field]
+		if _, ok := d.ForeignKeys[field]; ok {
+			if reference, ok := value.(map[string]any); ok {
+				value = reference["id"]
+			}
+		}
+		values = append(values, value)
+	}
+	b, err := json.Marshal(values)
+	if err != nil {
+		return "", false
+	}
+	return string(b), true
+}
+
+// GetEntityDefinition fetches the schema of the entity entityType and derives
+// its definition from it.
+func (r *Registry) GetEntityDefinition(ctx context.Context, entityType string) (*EntityDefinition, error) {
+	entitySchema, err := r.GetEntitySchema(ctx, entityType)
+	if err != nil {
+		return nil, err
+	}
+	return NewEntityDefinition(entityType, entitySchema)
+}
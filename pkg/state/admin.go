@@ -0,0 +1,183 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/kong/go-database-reconciler/pkg/utils"
+)
+
+const (
+	adminTableName = "admin"
+)
+
+var adminTableSchema = &memdb.TableSchema{
+	Name: adminTableName,
+	Indexes: map[string]*memdb.IndexSchema{
+		"id": {
+			Name:    "id",
+			Unique:  true,
+			Indexer: &memdb.StringFieldIndex{Field: "ID"},
+		},
+		"Username": {
+			Name:         "Username",
+			Unique:       true,
+			Indexer:      &memdb.StringFieldIndex{Field: "Username"},
+			AllowMissing: true,
+		},
+		"Email": {
+			Name:         "Email",
+			Unique:       true,
+			Indexer:      &memdb.StringFieldIndex{Field: "Email"},
+			AllowMissing: true,
+		},
+		all: allIndex,
+	},
+}
+
+// AdminsCollection stores and indexes Kong Manager admins.
+type AdminsCollection collection
+
+// Add adds an admin into AdminsCollection
+// admin.ID should not be nil else an error is thrown.
+func (k *AdminsCollection) Add(admin Admin) error {
+	// TODO abstract this check in the go-memdb library itself
+	if utils.Empty(admin.ID) {
+		return errIDRequired
+	}
+
+	txn := k.db.Txn(true)
+	defer txn.Abort()
+
+	var searchBy []string
+	searchBy = append(searchBy, *admin.ID)
+	if !utils.Empty(admin.Username) {
+		searchBy = append(searchBy, *admin.Username)
+	}
+	if !utils.Empty(admin.Email) {
+		searchBy = append(searchBy, *admin.Email)
+	}
+	_, err := getAdmin(txn, searchBy...)
+	if err == nil {
+		return fmt.Errorf("inserting admin %v: %w", admin.Console(), ErrAlreadyExists)
+	} else if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	err = txn.Insert(adminTableName, &admin)
+	if err != nil {
+		return err
+	}
+	txn.Commit()
+	return nil
+}
+
+func getAdmin(txn *memdb.Txn, IDs ...string) (*Admin, error) {
+	for _, id := range IDs {
+		res, err := multiIndexLookupUsingTxn(txn, adminTableName,
+			[]string{"Username", "Email", "id"}, id)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		admin, ok := res.(*Admin)
+		if !ok {
+			panic(unexpectedType)
+		}
+		return &Admin{Admin: *admin.DeepCopy(), Roles: slices.Clone(admin.Roles)}, nil
+	}
+	return nil, ErrNotFound
+}
+
+// Get gets an admin by username, email or ID.
+func (k *AdminsCollection) Get(usernameEmailOrID string) (*Admin, error) {
+	if usernameEmailOrID == "" {
+		return nil, errIDRequired
+	}
+
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+	return getAdmin(txn, usernameEmailOrID)
+}
+
+// Update updates an existing admin.
+// It returns an error if the admin is not already present.
+func (k *AdminsCollection) Update(admin Admin) error {
+	// TODO abstract this check in the go-memdb library itself
+	if utils.Empty(admin.ID) {
+		return errIDRequired
+	}
+
+	txn := k.db.Txn(true)
+	defer txn.Abort()
+
+	err := deleteAdmin(txn, *admin.ID)
+	if err != nil {
+		return err
+	}
+
+	err = txn.Insert(adminTableName, &admin)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+func deleteAdmin(txn *memdb.Txn, usernameEmailOrID string) error {
+	admin, err := getAdmin(txn, usernameEmailOrID)
+	if err != nil {
+		return err
+	}
+
+	err = txn.Delete(adminTableName, admin)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Delete deletes an admin by username, email or ID.
+func (k *AdminsCollection) Delete(usernameEmailOrID string) error {
+	if usernameEmailOrID == "" {
+		return errIDRequired
+	}
+
+	txn := k.db.Txn(true)
+	defer txn.Abort()
+
+	err := deleteAdmin(txn, usernameEmailOrID)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// GetAll returns all the admins.
+func (k *AdminsCollection) GetAll() ([]*Admin, error) {
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+
+	iter, err := txn.Get(adminTableName, all, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*Admin
+	for el := iter.Next(); el != nil; el = iter.Next() {
+		a, ok := el.(*Admin)
+		if !ok {
+			panic(unexpectedType)
+		}
+		res = append(res, &Admin{Admin: *a.DeepCopy(), Roles: slices.Clone(a.Roles)})
+	}
+	txn.Commit()
+	return res, nil
+}
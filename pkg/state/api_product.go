@@ -0,0 +1,171 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/kong/go-database-reconciler/pkg/utils"
+)
+
+const (
+	apiProductTableName = "api-product"
+)
+
+var apiProductTableSchema = &memdb.TableSchema{
+	Name: apiProductTableName,
+	Indexes: map[string]*memdb.IndexSchema{
+		"id": {
+			Name:    "id",
+			Unique:  true,
+			Indexer: &memdb.StringFieldIndex{Field: "ID"},
+		},
+		nameIndex: {
+			Name:    nameIndex,
+			Unique:  true,
+			Indexer: &memdb.StringFieldIndex{Field: nameFieldIndex},
+		},
+		all: allIndex,
+	},
+}
+
+// APIProductsCollection stores and indexes Konnect API products.
+type APIProductsCollection collection
+
+// Add adds an API product to the collection.
+// apiProduct.ID should not be nil else an error is thrown.
+func (k *APIProductsCollection) Add(apiProduct APIProduct) error {
+	// TODO abstract this check in the go-memdb library itself
+	if utils.Empty(apiProduct.ID) {
+		return errIDRequired
+	}
+	txn := k.db.Txn(true)
+	defer txn.Abort()
+
+	var searchBy []string
+	searchBy = append(searchBy, *apiProduct.ID)
+	if !utils.Empty(apiProduct.Name) {
+		searchBy = append(searchBy, *apiProduct.Name)
+	}
+	_, err := getAPIProduct(txn, searchBy...)
+	if err == nil {
+		return fmt.Errorf("inserting api-product %v: %w", apiProduct.Console(), ErrAlreadyExists)
+	} else if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	err = txn.Insert(apiProductTableName, &apiProduct)
+	if err != nil {
+		return err
+	}
+	txn.Commit()
+	return nil
+}
+
+func getAPIProduct(txn *memdb.Txn, IDs ...string) (*APIProduct, error) {
+	for _, id := range IDs {
+		res, err := multiIndexLookupUsingTxn(txn, apiProductTableName,
+			[]string{nameIndex, "id"}, id)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		apiProduct, ok := res.(*APIProduct)
+		if !ok {
+			panic(unexpectedType)
+		}
+		return &APIProduct{APIProduct: *apiProduct.DeepCopy()}, nil
+	}
+	return nil, ErrNotFound
+}
+
+// Get gets an API product by name or ID.
+func (k *APIProductsCollection) Get(nameOrID string) (*APIProduct, error) {
+	if nameOrID == "" {
+		return nil, errIDRequired
+	}
+
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+	return getAPIProduct(txn, nameOrID)
+}
+
+// Update updates an existing API product.
+// It returns an error if the API product is not already present.
+func (k *APIProductsCollection) Update(apiProduct APIProduct) error {
+	// TODO abstract this check in the go-memdb library itself
+	if utils.Empty(apiProduct.ID) {
+		return errIDRequired
+	}
+
+	txn := k.db.Txn(true)
+	defer txn.Abort()
+
+	err := deleteAPIProduct(txn, *apiProduct.ID)
+	if err != nil {
+		return err
+	}
+
+	err = txn.Insert(apiProductTableName, &apiProduct)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+func deleteAPIProduct(txn *memdb.Txn, nameOrID string) error {
+	apiProduct, err := getAPIProduct(txn, nameOrID)
+	if err != nil {
+		return err
+	}
+
+	err = txn.Delete(apiProductTableName, apiProduct)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Delete deletes an API product by name or ID.
+func (k *APIProductsCollection) Delete(nameOrID string) error {
+	if nameOrID == "" {
+		return errIDRequired
+	}
+
+	txn := k.db.Txn(true)
+	defer txn.Abort()
+
+	err := deleteAPIProduct(txn, nameOrID)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// GetAll returns all the API products.
+func (k *APIProductsCollection) GetAll() ([]*APIProduct, error) {
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+
+	iter, err := txn.Get(apiProductTableName, all, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*APIProduct
+	for el := iter.Next(); el != nil; el = iter.Next() {
+		s, ok := el.(*APIProduct)
+		if !ok {
+			panic(unexpectedType)
+		}
+		res = append(res, &APIProduct{APIProduct: *s.DeepCopy()})
+	}
+	txn.Commit()
+	return res, nil
+}
@@ -0,0 +1,227 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/kong/go-database-reconciler/pkg/state/indexers"
+	"github.com/kong/go-database-reconciler/pkg/utils"
+)
+
+const (
+	apiProductDocumentTableName = "api-product-document"
+	documentsByAPIProductID     = "apiProductDocumentsByAPIProductID"
+)
+
+var errInvalidProductOfDocument = fmt.Errorf("apiProduct.ID is required in APIProductDocument")
+
+var apiProductDocumentTableSchema = &memdb.TableSchema{
+	Name: apiProductDocumentTableName,
+	Indexes: map[string]*memdb.IndexSchema{
+		"id": {
+			Name:    "id",
+			Unique:  true,
+			Indexer: &memdb.StringFieldIndex{Field: "ID"},
+		},
+		all: allIndex,
+		// foreign
+		documentsByAPIProductID: {
+			Name: documentsByAPIProductID,
+			Indexer: &indexers.SubFieldIndexer{
+				Fields: []indexers.Field{
+					{
+						Struct: "APIProduct",
+						Sub:    "ID",
+					},
+				},
+			},
+		},
+	},
+}
+
+func validateProductOfDocument(document APIProductDocument) error {
+	if document.APIProduct == nil ||
+		utils.Empty(document.APIProduct.ID) {
+		return errInvalidProductOfDocument
+	}
+	return nil
+}
+
+// APIProductDocumentsCollection stores and indexes documents of API products.
+type APIProductDocumentsCollection collection
+
+// Add adds a document into APIProductDocumentsCollection.
+// document.ID should not be nil else an error is thrown.
+func (k *APIProductDocumentsCollection) Add(document APIProductDocument) error {
+	// TODO abstract this check in the go-memdb library itself
+	if utils.Empty(document.ID) {
+		return errIDRequired
+	}
+
+	if err := validateProductOfDocument(document); err != nil {
+		return err
+	}
+
+	txn := k.db.Txn(true)
+	defer txn.Abort()
+
+	var searchBy []string
+	searchBy = append(searchBy, *document.ID)
+	if !utils.Empty(document.Slug) {
+		searchBy = append(searchBy, *document.Slug)
+	}
+	_, err := getAPIProductDocument(txn, *document.APIProduct.ID, searchBy...)
+	if err == nil {
+		return fmt.Errorf("inserting api-product-document %v: %w", document.Console(), ErrAlreadyExists)
+	} else if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	err = txn.Insert(apiProductDocumentTableName, &document)
+	if err != nil {
+		return err
+	}
+	txn.Commit()
+	return nil
+}
+
+func getAPIProductDocument(txn *memdb.Txn, productID string, IDs ...string) (*APIProductDocument, error) {
+	if productID == "" {
+		return nil, fmt.Errorf("productID is required")
+	}
+	documents, err := getAllDocumentsByProductID(txn, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range IDs {
+		for _, document := range documents {
+			if id == *document.ID || id == *document.Slug {
+				return &APIProductDocument{APIProductDocument: *document.DeepCopy()}, nil
+			}
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// Get gets an API product document by slug or ID.
+func (k *APIProductDocumentsCollection) Get(productID, slugOrID string) (*APIProductDocument, error) {
+	if slugOrID == "" {
+		return nil, errIDRequired
+	}
+
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+	document, err := getAPIProductDocument(txn, productID, slugOrID)
+	if err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+// Update updates an API product document.
+func (k *APIProductDocumentsCollection) Update(document APIProductDocument) error {
+	// TODO abstract this check in the go-memdb library itself
+	if utils.Empty(document.ID) {
+		return errIDRequired
+	}
+	if err := validateProductOfDocument(document); err != nil {
+		return err
+	}
+
+	txn := k.db.Txn(true)
+	defer txn.Abort()
+
+	err := deleteAPIProductDocument(txn, *document.APIProduct.ID, *document.ID)
+	if err != nil {
+		return err
+	}
+
+	err = txn.Insert(apiProductDocumentTableName, &document)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+func deleteAPIProductDocument(txn *memdb.Txn, productID, slugOrID string) error {
+	document, err := getAPIProductDocument(txn, productID, slugOrID)
+	if err != nil {
+		return err
+	}
+
+	err = txn.Delete(apiProductDocumentTableName, document)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Delete deletes a document by slug or ID.
+func (k *APIProductDocumentsCollection) Delete(productID, slugOrID string) error {
+	if slugOrID == "" {
+		return errIDRequired
+	}
+
+	txn := k.db.Txn(true)
+	defer txn.Abort()
+
+	err := deleteAPIProductDocument(txn, productID, slugOrID)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// GetAll gets all API product documents.
+func (k *APIProductDocumentsCollection) GetAll() ([]*APIProductDocument, error) {
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+
+	iter, err := txn.Get(apiProductDocumentTableName, all, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*APIProductDocument
+	for el := iter.Next(); el != nil; el = iter.Next() {
+		s, ok := el.(*APIProductDocument)
+		if !ok {
+			panic(unexpectedType)
+		}
+		res = append(res, &APIProductDocument{APIProductDocument: *s.DeepCopy()})
+	}
+	txn.Commit()
+	return res, nil
+}
+
+func getAllDocumentsByProductID(txn *memdb.Txn, productID string) ([]*APIProductDocument, error) {
+	iter, err := txn.Get(apiProductDocumentTableName, documentsByAPIProductID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	var documents []*APIProductDocument
+	for el := iter.Next(); el != nil; el = iter.Next() {
+		v, ok := el.(*APIProductDocument)
+		if !ok {
+			panic(unexpectedType)
+		}
+		documents = append(documents, &APIProductDocument{APIProductDocument: *v.DeepCopy()})
+	}
+	return documents, nil
+}
+
+// GetAllByAPIProductID returns all documents of an API product.
+func (k *APIProductDocumentsCollection) GetAllByAPIProductID(id string) ([]*APIProductDocument,
+	error,
+) {
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+	return getAllDocumentsByProductID(txn, id)
+}
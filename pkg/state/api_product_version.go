@@ -0,0 +1,227 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/kong/go-database-reconciler/pkg/state/indexers"
+	"github.com/kong/go-database-reconciler/pkg/utils"
+)
+
+const (
+	apiProductVersionTableName = "api-product-version"
+	versionsByAPIProductID     = "apiProductVersionsByAPIProductID"
+)
+
+var errInvalidProductOfVersion = fmt.Errorf("apiProduct.ID is required in APIProductVersion")
+
+var apiProductVersionTableSchema = &memdb.TableSchema{
+	Name: apiProductVersionTableName,
+	Indexes: map[string]*memdb.IndexSchema{
+		"id": {
+			Name:    "id",
+			Unique:  true,
+			Indexer: &memdb.StringFieldIndex{Field: "ID"},
+		},
+		all: allIndex,
+		// foreign
+		versionsByAPIProductID: {
+			Name: versionsByAPIProductID,
+			Indexer: &indexers.SubFieldIndexer{
+				Fields: []indexers.Field{
+					{
+						Struct: "APIProduct",
+						Sub:    "ID",
+					},
+				},
+			},
+		},
+	},
+}
+
+func validateProductOfVersion(version APIProductVersion) error {
+	if version.APIProduct == nil ||
+		utils.Empty(version.APIProduct.ID) {
+		return errInvalidProductOfVersion
+	}
+	return nil
+}
+
+// APIProductVersionsCollection stores and indexes versions of API products.
+type APIProductVersionsCollection collection
+
+// Add adds a version into APIProductVersionsCollection.
+// version.ID should not be nil else an error is thrown.
+func (k *APIProductVersionsCollection) Add(version APIProductVersion) error {
+	// TODO abstract this check in the go-memdb library itself
+	if utils.Empty(version.ID) {
+		return errIDRequired
+	}
+
+	if err := validateProductOfVersion(version); err != nil {
+		return err
+	}
+
+	txn := k.db.Txn(true)
+	defer txn.Abort()
+
+	var searchBy []string
+	searchBy = append(searchBy, *version.ID)
+	if !utils.Empty(version.Name) {
+		searchBy = append(searchBy, *version.Name)
+	}
+	_, err := getAPIProductVersion(txn, *version.APIProduct.ID, searchBy...)
+	if err == nil {
+		return fmt.Errorf("inserting api-product-version %v: %w", version.Console(), ErrAlreadyExists)
+	} else if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	err = txn.Insert(apiProductVersionTableName, &version)
+	if err != nil {
+		return err
+	}
+	txn.Commit()
+	return nil
+}
+
+func getAPIProductVersion(txn *memdb.Txn, productID string, IDs ...string) (*APIProductVersion, error) {
+	if productID == "" {
+		return nil, fmt.Errorf("productID is required")
+	}
+	versions, err := getAllVersionsByProductID(txn, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range IDs {
+		for _, version := range versions {
+			if id == *version.ID || id == *version.Name {
+				return &APIProductVersion{APIProductVersion: *version.DeepCopy()}, nil
+			}
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// Get gets an API product version by name or ID.
+func (k *APIProductVersionsCollection) Get(productID, nameOrID string) (*APIProductVersion, error) {
+	if nameOrID == "" {
+		return nil, errIDRequired
+	}
+
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+	version, err := getAPIProductVersion(txn, productID, nameOrID)
+	if err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// Update updates an API product version.
+func (k *APIProductVersionsCollection) Update(version APIProductVersion) error {
+	// TODO abstract this check in the go-memdb library itself
+	if utils.Empty(version.ID) {
+		return errIDRequired
+	}
+	if err := validateProductOfVersion(version); err != nil {
+		return err
+	}
+
+	txn := k.db.Txn(true)
+	defer txn.Abort()
+
+	err := deleteAPIProductVersion(txn, *version.APIProduct.ID, *version.ID)
+	if err != nil {
+		return err
+	}
+
+	err = txn.Insert(apiProductVersionTableName, &version)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+func deleteAPIProductVersion(txn *memdb.Txn, productID, nameOrID string) error {
+	version, err := getAPIProductVersion(txn, productID, nameOrID)
+	if err != nil {
+		return err
+	}
+
+	err = txn.Delete(apiProductVersionTableName, version)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Delete deletes a version by name or ID.
+func (k *APIProductVersionsCollection) Delete(productID, nameOrID string) error {
+	if nameOrID == "" {
+		return errIDRequired
+	}
+
+	txn := k.db.Txn(true)
+	defer txn.Abort()
+
+	err := deleteAPIProductVersion(txn, productID, nameOrID)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// GetAll gets all API product versions.
+func (k *APIProductVersionsCollection) GetAll() ([]*APIProductVersion, error) {
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+
+	iter, err := txn.Get(apiProductVersionTableName, all, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*APIProductVersion
+	for el := iter.Next(); el != nil; el = iter.Next() {
+		s, ok := el.(*APIProductVersion)
+		if !ok {
+			panic(unexpectedType)
+		}
+		res = append(res, &APIProductVersion{APIProductVersion: *s.DeepCopy()})
+	}
+	txn.Commit()
+	return res, nil
+}
+
+func getAllVersionsByProductID(txn *memdb.Txn, productID string) ([]*APIProductVersion, error) {
+	iter, err := txn.Get(apiProductVersionTableName, versionsByAPIProductID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []*APIProductVersion
+	for el := iter.Next(); el != nil; el = iter.Next() {
+		v, ok := el.(*APIProductVersion)
+		if !ok {
+			panic(unexpectedType)
+		}
+		versions = append(versions, &APIProductVersion{APIProductVersion: *v.DeepCopy()})
+	}
+	return versions, nil
+}
+
+// GetAllByAPIProductID returns all versions of an API product.
+func (k *APIProductVersionsCollection) GetAllByAPIProductID(id string) ([]*APIProductVersion,
+	error,
+) {
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+	return getAllVersionsByProductID(txn, id)
+}
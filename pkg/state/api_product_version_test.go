@@ -0,0 +1,53 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/kong/go-database-reconciler/pkg/konnect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIProductVersionsCollection(t *testing.T) {
+	collection := state().APIProductVersions
+
+	require.ErrorIs(t, collection.Add(APIProductVersion{
+		APIProductVersion: konnect.APIProductVersion{ID: new("v1-id"), Name: new("v1")},
+	}), errInvalidProductOfVersion)
+
+	for _, productID := range []string{"foo-id", "bar-id"} {
+		require.NoError(t, collection.Add(APIProductVersion{
+			APIProductVersion: konnect.APIProductVersion{
+				ID:         new("v1-" + productID),
+				Name:       new("v1"),
+				APIProduct: &konnect.APIProduct{ID: new(productID)},
+			},
+		}))
+	}
+	// versions are unique per API product
+	require.ErrorIs(t, collection.Add(APIProductVersion{
+		APIProductVersion: konnect.APIProductVersion{
+			ID:         new("other-id"),
+			Name:       new("v1"),
+			APIProduct: &konnect.APIProduct{ID: new("foo-id")},
+		},
+	}), ErrAlreadyExists)
+
+	version, err := collection.Get("bar-id", "v1")
+	require.NoError(t, err)
+	assert.Equal(t, "v1-bar-id", *version.ID)
+
+	version.GatewayService = &konnect.GatewayServiceRef{ID: new("service-id")}
+	require.NoError(t, collection.Update(*version))
+	version, err = collection.Get("bar-id", "v1-bar-id")
+	require.NoError(t, err)
+	assert.Equal(t, "service-id", *version.GatewayService.ID)
+
+	require.NoError(t, collection.Delete("foo-id", "v1"))
+	_, err = collection.Get("foo-id", "v1")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	versions, err := collection.GetAllByAPIProductID("bar-id")
+	require.NoError(t, err)
+	assert.Len(t, versions, 1)
+}
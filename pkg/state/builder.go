@@ -5,8 +5,10 @@ import (
 	"fmt"
 
 	"github.com/kong/go-database-reconciler/pkg/cprint"
+	"github.com/kong/go-database-reconciler/pkg/schema"
 	"github.com/kong/go-database-reconciler/pkg/utils"
 	"github.com/kong/go-kong/kong"
+	"github.com/kong/go-kong/kong/custom"
 )
 
 // Get builds a KongState from a raw representation of Kong.
@@ -22,6 +24,32 @@ func Get(raw *utils.KongRawState) (*KongState, error) {
 	return kongState, nil
 }
 
+// Builder builds a KongState incrementally from pages of a raw representation
+// of Kong, such as the ones streamed by dump.Stream. References to entities are
+// resolved against the entities added by previous pages.
+type Builder struct {
+	state *KongState
+}
+
+// NewBuilder returns a Builder for an empty KongState.
+func NewBuilder() (*Builder, error) {
+	kongState, err := NewKongState()
+	if err != nil {
+		return nil, fmt.Errorf("creating new in-memory state of Kong: %w", err)
+	}
+	return &Builder{state: kongState}, nil
+}
+
+// Add adds the entities of page to the state.
+func (b *Builder) Add(page *utils.KongRawState) error {
+	return buildKong(b.state, page)
+}
+
+// State returns the state built so far.
+func (b *Builder) State() *KongState {
+	return b.state
+}
+
 func ensureService(kongState *KongState, serviceID string) (bool, *kong.Service, error) {
 	s, err := kongState.Services.Get(serviceID)
 	if err != nil {
@@ -418,6 +446,32 @@ func buildKong(kongState *KongState, raw *utils.KongRawState) error {
 			return fmt.Errorf("inserting rbac endpoint permissions into state: %w", err)
 		}
 	}
+	for _, r := range raw.RBACEntityPermissions {
+		err := kongState.RBACEntityPermissions.Add(RBACEntityPermission{RBACEntityPermission: *r})
+		if err != nil {
+			return fmt.Errorf("inserting rbac entity permissions into state: %w", err)
+		}
+	}
+	for _, u := range raw.RBACUsers {
+		user := RBACUser{RBACUser: *u}
+		if u.ID != nil {
+			user.Roles = raw.RBACUserRoles[*u.ID]
+		}
+		err := kongState.RBACUsers.Add(user)
+		if err != nil {
+			return fmt.Errorf("inserting rbac users into state: %w", err)
+		}
+	}
+	for _, a := range raw.Admins {
+		admin := Admin{Admin: *a}
+		if a.ID != nil {
+			admin.Roles = raw.AdminRoles[*a.ID]
+		}
+		err := kongState.Admins.Add(admin)
+		if err != nil {
+			return fmt.Errorf("inserting admins into state: %w", err)
+		}
+	}
 	for _, v := range raw.Vaults {
 		err := kongState.Vaults.Add(Vault{Vault: *v})
 		if err != nil {
@@ -430,6 +484,18 @@ func buildKong(kongState *KongState, raw *utils.KongRawState) error {
 			return fmt.Errorf("inserting license into state: %w", err)
 		}
 	}
+	for _, w := range raw.Workspaces {
+		err := kongState.Workspaces.Add(Workspace{Workspace: *w})
+		if err != nil {
+			return fmt.Errorf("inserting workspace into state: %w", err)
+		}
+	}
+	for _, h := range raw.EventHooks {
+		err := kongState.EventHooks.Add(EventHook{EventHook: *h})
+		if err != nil {
+			return fmt.Errorf("inserting event hook into state: %w", err)
+		}
+	}
 
 	for _, d := range raw.DegraphqlRoutes {
 		if d.Service != nil && !utils.Empty(d.Service.ID) {
@@ -490,6 +556,16 @@ func buildKong(kongState *KongState, raw *utils.KongRawState) error {
 			if err != nil {
 				return fmt.Errorf("inserting graphql ratelimiting cost decoration into state: %w", err)
 			}
+		default:
+			entity, err := buildCustomEntity(c, raw.CustomEntityDefinitions[string(c.Type())])
+			if err != nil {
+				return fmt.Errorf("building custom entity: %w", err)
+			}
+
+			err = kongState.CustomEntities.Add(*entity)
+			if err != nil {
+				return fmt.Errorf("inserting custom entity into state: %w", err)
+			}
 		}
 	}
 
@@ -541,6 +617,38 @@ func buildKonnect(kongState *KongState, raw *utils.KonnectRawState) error {
 			return fmt.Errorf("inserting document into state: %w", err)
 		}
 	}
+
+	for _, p := range raw.Portals {
+		err := kongState.Portals.Add(Portal{Portal: *p.DeepCopy()})
+		if err != nil {
+			return fmt.Errorf("inserting portal into state: %w", err)
+		}
+	}
+	for _, p := range raw.APIProducts {
+		err := kongState.APIProducts.Add(APIProduct{APIProduct: *p.DeepCopy()})
+		if err != nil {
+			return fmt.Errorf("inserting api-product into state: %w", err)
+		}
+	}
+	for _, v := range raw.APIProductVersions {
+		err := kongState.APIProductVersions.Add(APIProductVersion{APIProductVersion: *v.DeepCopy()})
+		if err != nil {
+			return fmt.Errorf("inserting api-product-version into state: %w", err)
+		}
+	}
+	for _, d := range raw.APIProductDocuments {
+		err := kongState.APIProductDocuments.Add(APIProductDocument{APIProductDocument: *d.DeepCopy()})
+		if err != nil {
+			return fmt.Errorf("inserting api-product-document into state: %w", err)
+		}
+	}
+
+	for _, c := range raw.ControlPlanes {
+		err := kongState.ControlPlanes.Add(ControlPlane{ControlPlaneV2: *c.DeepCopy()})
+		if err != nil {
+			return fmt.Errorf("inserting control-plane into state: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -728,3 +836,37 @@ func buildGraphqlRateLimitingCostDecorationFromCustomEntity(kongState *KongState
 
 	return decoration, nil
 }
+
+// buildCustomEntity builds a CustomEntity from the entity e of a custom DAO,
+// with its foreign keys reduced to references to the ID of another entity.
+func buildCustomEntity(e custom.Entity, definition *schema.EntityDefinition) (*CustomEntity, error) {
+	if definition == nil {
+		return nil, fmt.Errorf("custom entity type %s has no definition", e.Type())
+	}
+	object := kong.Configuration(e.Object()).DeepCopy()
+	id, ok := object["id"].(string)
+	if !ok || id == "" {
+		return nil, fmt.Errorf("%s: id must be a non-empty string", e.Type())
+	}
+	delete(object, "id")
+	for field := range definition.ForeignKeys {
+		if object[field] == nil {
+			continue
+		}
+		reference, ok := object[field].(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s %s: %s must be of type object", e.Type(), id, field)
+		}
+		referenceID, ok := reference["id"].(string)
+		if !ok {
+			return nil, fmt.Errorf("%s %s: %s must be of type object with a string id", e.Type(), id, field)
+		}
+		object[field] = map[string]any{"id": referenceID}
+	}
+	return &CustomEntity{
+		Type:       string(e.Type()),
+		ID:         id,
+		Fields:     object,
+		Definition: definition,
+	}, nil
+}
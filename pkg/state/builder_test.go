@@ -0,0 +1,40 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/kong/go-database-reconciler/pkg/utils"
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder(t *testing.T) {
+	b, err := NewBuilder()
+	require.NoError(t, err)
+
+	require.NoError(t, b.Add(&utils.KongRawState{
+		Consumers: []*kong.Consumer{{ID: new("c1"), Username: new("alice")}},
+	}))
+	require.NoError(t, b.Add(&utils.KongRawState{
+		Consumers: []*kong.Consumer{{ID: new("c2"), Username: new("bob")}},
+	}))
+	require.NoError(t, b.Add(&utils.KongRawState{
+		KeyAuths: []*kong.KeyAuth{
+			{ID: new("k1"), Key: new("alice-key"), Consumer: &kong.Consumer{ID: new("c1")}},
+			{ID: new("k2"), Key: new("bob-key"), Consumer: &kong.Consumer{ID: new("c2")}},
+			// credentials of consumers which were not added are dropped.
+			{ID: new("k3"), Key: new("other-key"), Consumer: &kong.Consumer{ID: new("c3")}},
+		},
+	}))
+
+	consumers, err := b.State().Consumers.GetAll()
+	require.NoError(t, err)
+	assert.Len(t, consumers, 2)
+	keyAuths, err := b.State().KeyAuths.GetAll()
+	require.NoError(t, err)
+	require.Len(t, keyAuths, 2)
+	keyAuth, err := b.State().KeyAuths.Get("k2")
+	require.NoError(t, err)
+	assert.Equal(t, "bob", *keyAuth.Consumer.Username)
+}
@@ -0,0 +1,171 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/kong/go-database-reconciler/pkg/utils"
+)
+
+const (
+	controlPlaneTableName = "control-plane"
+)
+
+var controlPlaneTableSchema = &memdb.TableSchema{
+	Name: controlPlaneTableName,
+	Indexes: map[string]*memdb.IndexSchema{
+		"id": {
+			Name:    "id",
+			Unique:  true,
+			Indexer: &memdb.StringFieldIndex{Field: "ID"},
+		},
+		nameIndex: {
+			Name:    nameIndex,
+			Unique:  true,
+			Indexer: &memdb.StringFieldIndex{Field: nameFieldIndex},
+		},
+		all: allIndex,
+	},
+}
+
+// ControlPlanesCollection stores and indexes Konnect control planes.
+type ControlPlanesCollection collection
+
+// Add adds a control plane to the collection.
+// controlPlane.ID should not be nil else an error is thrown.
+func (k *ControlPlanesCollection) Add(controlPlane ControlPlane) error {
+	// TODO abstract this check in the go-memdb library itself
+	if utils.Empty(controlPlane.ID) {
+		return errIDRequired
+	}
+	txn := k.db.Txn(true)
+	defer txn.Abort()
+
+	var searchBy []string
+	searchBy = append(searchBy, *controlPlane.ID)
+	if !utils.Empty(controlPlane.Name) {
+		searchBy = append(searchBy, *controlPlane.Name)
+	}
+	_, err := getControlPlane(txn, searchBy...)
+	if err == nil {
+		return fmt.Errorf("inserting control-plane %v: %w", controlPlane.Console(), ErrAlreadyExists)
+	} else if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	err = txn.Insert(controlPlaneTableName, &controlPlane)
+	if err != nil {
+		return err
+	}
+	txn.Commit()
+	return nil
+}
+
+func getControlPlane(txn *memdb.Txn, IDs ...string) (*ControlPlane, error) {
+	for _, id := range IDs {
+		res, err := multiIndexLookupUsingTxn(txn, controlPlaneTableName,
+			[]string{nameIndex, "id"}, id)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		controlPlane, ok := res.(*ControlPlane)
+		if !ok {
+			panic(unexpectedType)
+		}
+		return &ControlPlane{ControlPlaneV2: *controlPlane.DeepCopy()}, nil
+	}
+	return nil, ErrNotFound
+}
+
+// Get gets a control plane by name or ID.
+func (k *ControlPlanesCollection) Get(nameOrID string) (*ControlPlane, error) {
+	if nameOrID == "" {
+		return nil, errIDRequired
+	}
+
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+	return getControlPlane(txn, nameOrID)
+}
+
+// Update updates an existing control plane.
+// It returns an error if the control plane is not already present.
+func (k *ControlPlanesCollection) Update(controlPlane ControlPlane) error {
+	// TODO abstract this check in the go-memdb library itself
+	if utils.Empty(controlPlane.ID) {
+		return errIDRequired
+	}
+
+	txn := k.db.Txn(true)
+	defer txn.Abort()
+
+	err := deleteControlPlane(txn, *controlPlane.ID)
+	if err != nil {
+		return err
+	}
+
+	err = txn.Insert(controlPlaneTableName, &controlPlane)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+func deleteControlPlane(txn *memdb.Txn, nameOrID string) error {
+	controlPlane, err := getControlPlane(txn, nameOrID)
+	if err != nil {
+		return err
+	}
+
+	err = txn.Delete(controlPlaneTableName, controlPlane)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Delete deletes a control plane by name or ID.
+func (k *ControlPlanesCollection) Delete(nameOrID string) error {
+	if nameOrID == "" {
+		return errIDRequired
+	}
+
+	txn := k.db.Txn(true)
+	defer txn.Abort()
+
+	err := deleteControlPlane(txn, nameOrID)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// GetAll returns all the control planes.
+func (k *ControlPlanesCollection) GetAll() ([]*ControlPlane, error) {
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+
+	iter, err := txn.Get(controlPlaneTableName, all, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*ControlPlane
+	for el := iter.Next(); el != nil; el = iter.Next() {
+		s, ok := el.(*ControlPlane)
+		if !ok {
+			panic(unexpectedType)
+		}
+		res = append(res, &ControlPlane{ControlPlaneV2: *s.DeepCopy()})
+	}
+	txn.Commit()
+	return res, nil
+}
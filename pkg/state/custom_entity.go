@@ -0,0 +1,190 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+
+	memdb "github.com/hashicorp/go-memdb"
+)
+
+const (
+	customEntityTableName = "custom-entity"
+	customEntitiesByType  = "customEntitiesByType"
+)
+
+var errInvalidCustomEntity = errors.New("type and ID of custom entity are required")
+
+var customEntityTableSchema = &memdb.TableSchema{
+	Name: customEntityTableName,
+	Indexes: map[string]*memdb.IndexSchema{
+		"id": {
+			Name:   "id",
+			Unique: true,
+			Indexer: &memdb.CompoundIndex{
+				Indexes: []memdb.Indexer{
+					&memdb.StringFieldIndex{Field: "Type"},
+					&memdb.StringFieldIndex{Field: "ID"},
+				},
+			},
+		},
+		customEntitiesByType: {
+			Name:    customEntitiesByType,
+			Indexer: &memdb.StringFieldIndex{Field: "Type"},
+		},
+		all: allIndex,
+	},
+}
+
+// CustomEntitiesCollection stores and indexes the entities of custom DAOs
+// handled generically, whatever their type.
+type CustomEntitiesCollection collection
+
+// Add adds a custom entity to the collection.
+// The type and ID of entity should not be empty else an error is thrown.
+func (k *CustomEntitiesCollection) Add(entity CustomEntity) error {
+	if entity.Type == "" || entity.ID == "" {
+		return errInvalidCustomEntity
+	}
+	txn := k.db.Txn(true)
+	defer txn.Abort()
+
+	_, err := getCustomEntity(txn, entity.Type, entity.ID)
+	if err == nil {
+		return fmt.Errorf("inserting custom entity %v: %w", entity.Console(), ErrAlreadyExists)
+	} else if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	err = txn.Insert(customEntityTableName, &entity)
+	if err != nil {
+		return err
+	}
+	txn.Commit()
+	return nil
+}
+
+func getCustomEntity(txn *memdb.Txn, entityType, id string) (*CustomEntity, error) {
+	res, err := txn.First(customEntityTableName, "id", entityType, id)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, ErrNotFound
+	}
+	entity, ok := res.(*CustomEntity)
+	if !ok {
+		panic(unexpectedType)
+	}
+	return entity.DeepCopy(), nil
+}
+
+// Get gets a custom entity of type entityType by ID.
+func (k *CustomEntitiesCollection) Get(entityType, id string) (*CustomEntity, error) {
+	if entityType == "" || id == "" {
+		return nil, errInvalidCustomEntity
+	}
+
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+	return getCustomEntity(txn, entityType, id)
+}
+
+// GetByNaturalKey gets the custom entity of type entityType with the natural
+// key key, as returned by the NaturalKeyOf method of its definition.
+func (k *CustomEntitiesCollection) GetByNaturalKey(entityType, key string) (*CustomEntity, error) {
+	entities, err := k.GetAllByType(entityType)
+	if err != nil {
+		return nil, err
+	}
+	for _, entity := range entities {
+		if entity.Definition == nil {
+			continue
+		}
+		if entityKey, ok := entity.Definition.NaturalKeyOf(entity.Fields); ok && entityKey == key {
+			return entity, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// Update updates an existing custom entity.
+// It returns an error if the custom entity is not already present.
+func (k *CustomEntitiesCollection) Update(entity CustomEntity) error {
+	if entity.Type == "" || entity.ID == "" {
+		return errInvalidCustomEntity
+	}
+
+	txn := k.db.Txn(true)
+	defer txn.Abort()
+
+	err := deleteCustomEntity(txn, entity.Type, entity.ID)
+	if err != nil {
+		return err
+	}
+
+	err = txn.Insert(customEntityTableName, &entity)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+func deleteCustomEntity(txn *memdb.Txn, entityType, id string) error {
+	entity, err := getCustomEntity(txn, entityType, id)
+	if err != nil {
+		return err
+	}
+
+	return txn.Delete(customEntityTableName, entity)
+}
+
+// Delete deletes a custom entity of type entityType by ID.
+func (k *CustomEntitiesCollection) Delete(entityType, id string) error {
+	if entityType == "" || id == "" {
+		return errInvalidCustomEntity
+	}
+
+	txn := k.db.Txn(true)
+	defer txn.Abort()
+
+	err := deleteCustomEntity(txn, entityType, id)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// GetAll returns all the custom entities, whatever their type.
+func (k *CustomEntitiesCollection) GetAll() ([]*CustomEntity, error) {
+	return k.getAll(all, true)
+}
+
+// GetAllByType returns all the custom entities of type entityType.
+func (k *CustomEntitiesCollection) GetAllByType(entityType string) ([]*CustomEntity, error) {
+	return k.getAll(customEntitiesByType, entityType)
+}
+
+func (k *CustomEntitiesCollection) getAll(index string, args ...any) ([]*CustomEntity, error) {
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+
+	iter, err := txn.Get(customEntityTableName, index, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*CustomEntity
+	for el := iter.Next(); el != nil; el = iter.Next() {
+		entity, ok := el.(*CustomEntity)
+		if !ok {
+			panic(unexpectedType)
+		}
+		res = append(res, entity.DeepCopy())
+	}
+	txn.Commit()
+	return res, nil
+}
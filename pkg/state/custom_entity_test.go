@@ -0,0 +1,69 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/kong/go-database-reconciler/pkg/schema"
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomEntitiesCollection(t *testing.T) {
+	c := state().CustomEntities
+	definition := &schema.EntityDefinition{
+		Name:        "my_entities",
+		NaturalKey:  []string{"name", "service"},
+		ForeignKeys: map[string]string{"service": "services"},
+	}
+
+	require.ErrorIs(t, c.Add(CustomEntity{Type: "my_entities"}), errInvalidCustomEntity)
+
+	entity := CustomEntity{
+		Type: "my_entities",
+		ID:   "entity-1",
+		Fields: kong.Configuration{
+			"name":    "a",
+			"service": map[string]any{"id": "service-1"},
+		},
+		Definition: definition,
+	}
+	require.NoError(t, c.Add(entity))
+	require.ErrorIs(t, c.Add(entity), ErrAlreadyExists)
+	// the same ID is allowed for another type
+	require.NoError(t, c.Add(CustomEntity{Type: "other_entities", ID: "entity-1"}))
+
+	res, err := c.Get("my_entities", "entity-1")
+	require.NoError(t, err)
+	assert.True(t, entity.Equal(res))
+	assert.Equal(t, "my_entities entity-1", res.Console())
+
+	key, ok := definition.NaturalKeyOf(map[string]any{
+		"name":    "a",
+		"service": map[string]any{"id": "service-1"},
+	})
+	require.True(t, ok)
+	res, err = c.GetByNaturalKey("my_entities", key)
+	require.NoError(t, err)
+	assert.Equal(t, "entity-1", res.ID)
+	_, err = c.GetByNaturalKey("other_entities", key)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	entity.Fields["name"] = "b"
+	require.NoError(t, c.Update(entity))
+	res, err = c.Get("my_entities", "entity-1")
+	require.NoError(t, err)
+	assert.Equal(t, "b", res.Fields["name"])
+
+	all, err := c.GetAll()
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+	all, err = c.GetAllByType("my_entities")
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+
+	require.NoError(t, c.Delete("my_entities", "entity-1"))
+	_, err = c.Get("my_entities", "entity-1")
+	require.ErrorIs(t, err, ErrNotFound)
+	require.ErrorIs(t, c.Delete("my_entities", "entity-1"), ErrNotFound)
+}
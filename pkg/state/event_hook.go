@@ -0,0 +1,148 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/kong/go-database-reconciler/pkg/utils"
+)
+
+const (
+	eventHookTableName = "event-hook"
+)
+
+var eventHookTableSchema = &memdb.TableSchema{
+	Name: eventHookTableName,
+	Indexes: map[string]*memdb.IndexSchema{
+		"id": {
+			Name:    "id",
+			Unique:  true,
+			Indexer: &memdb.StringFieldIndex{Field: "ID"},
+		},
+		all: allIndex,
+	},
+}
+
+// EventHooksCollection stores and indexes Kong Enterprise event hooks.
+type EventHooksCollection collection
+
+// Add adds an event hook to the collection.
+// hook.ID should not be nil else an error is thrown.
+func (k *EventHooksCollection) Add(hook EventHook) error {
+	if utils.Empty(hook.ID) {
+		return errIDRequired
+	}
+	txn := k.db.Txn(true)
+	defer txn.Abort()
+
+	_, err := getEventHook(txn, *hook.ID)
+	if err == nil {
+		return fmt.Errorf("inserting event hook %v: %w", hook.Console(), ErrAlreadyExists)
+	} else if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	err = txn.Insert(eventHookTableName, &hook)
+	if err != nil {
+		return err
+	}
+	txn.Commit()
+	return nil
+}
+
+func getEventHook(txn *memdb.Txn, id string) (*EventHook, error) {
+	res, err := multiIndexLookupUsingTxn(txn, eventHookTableName, []string{"id"}, id)
+	if err != nil {
+		return nil, err
+	}
+	hook, ok := res.(*EventHook)
+	if !ok {
+		panic(unexpectedType)
+	}
+	return &EventHook{EventHook: *hook.DeepCopy()}, nil
+}
+
+// Get gets an event hook by ID.
+func (k *EventHooksCollection) Get(id string) (*EventHook, error) {
+	if id == "" {
+		return nil, errIDRequired
+	}
+
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+	return getEventHook(txn, id)
+}
+
+// Update updates an existing event hook.
+// It returns an error if the event hook is not already present.
+func (k *EventHooksCollection) Update(hook EventHook) error {
+	if utils.Empty(hook.ID) {
+		return errIDRequired
+	}
+
+	txn := k.db.Txn(true)
+	defer txn.Abort()
+
+	err := deleteEventHook(txn, *hook.ID)
+	if err != nil {
+		return err
+	}
+
+	err = txn.Insert(eventHookTableName, &hook)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+func deleteEventHook(txn *memdb.Txn, id string) error {
+	hook, err := getEventHook(txn, id)
+	if err != nil {
+		return err
+	}
+
+	return txn.Delete(eventHookTableName, hook)
+}
+
+// Delete deletes an event hook by ID.
+func (k *EventHooksCollection) Delete(id string) error {
+	if id == "" {
+		return errIDRequired
+	}
+
+	txn := k.db.Txn(true)
+	defer txn.Abort()
+
+	err := deleteEventHook(txn, id)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// GetAll returns all the event hooks.
+func (k *EventHooksCollection) GetAll() ([]*EventHook, error) {
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+
+	iter, err := txn.Get(eventHookTableName, all, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*EventHook
+	for el := iter.Next(); el != nil; el = iter.Next() {
+		hook, ok := el.(*EventHook)
+		if !ok {
+			panic(unexpectedType)
+		}
+		res = append(res, &EventHook{EventHook: *hook.DeepCopy()})
+	}
+	txn.Commit()
+	return res, nil
+}
@@ -0,0 +1,55 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/kong/go-database-reconciler/pkg/utils"
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventHooksCollection(t *testing.T) {
+	c := state().EventHooks
+
+	require.ErrorIs(t, c.Add(EventHook{EventHook: utils.EventHook{Source: new("crud")}}), errIDRequired)
+
+	hook := EventHook{
+		EventHook: utils.EventHook{
+			ID:      new("hook-1"),
+			Source:  new("crud"),
+			Event:   new("consumers"),
+			Handler: new("webhook"),
+			Config:  kong.Configuration{"url": "https://example.com"},
+		},
+	}
+	require.NoError(t, c.Add(hook))
+	require.ErrorIs(t, c.Add(hook), ErrAlreadyExists)
+
+	res, err := c.Get("hook-1")
+	require.NoError(t, err)
+	assert.True(t, hook.Equal(res))
+	assert.Equal(t, "webhook crud:consumers (hook-1)", res.Console())
+
+	// Event hooks returned are copies.
+	res.Config["url"] = "https://example.org"
+	res, err = c.Get("hook-1")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com", res.Config["url"])
+
+	hook.Snooze = new(60)
+	require.NoError(t, c.Update(hook))
+	res, err = c.Get("hook-1")
+	require.NoError(t, err)
+	assert.Equal(t, 60, *res.Snooze)
+
+	require.NoError(t, c.Add(EventHook{EventHook: utils.EventHook{ID: new("hook-2"), Source: new("balancer")}}))
+	all, err := c.GetAll()
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	require.NoError(t, c.Delete("hook-1"))
+	_, err = c.Get("hook-1")
+	require.ErrorIs(t, err, ErrNotFound)
+	require.ErrorIs(t, c.Delete("hook-1"), ErrNotFound)
+}
@@ -2,6 +2,7 @@ package state
 
 import (
 	"reflect"
+	"sort"
 
 	"github.com/kong/go-database-reconciler/pkg/konnect"
 )
@@ -141,3 +142,272 @@ func (s1 *ServiceVersion) EqualWithOpts(s2 *ServiceVersion,
 	}
 	return reflect.DeepEqual(s1Copy, s2Copy)
 }
+
+// Portal represents a Dev Portal in Konnect.
+// It adds some helper methods along with Meta to the original Portal object.
+type Portal struct {
+	konnect.Portal `yaml:",inline"`
+	Meta
+}
+
+// Identifier returns the endpoint key name or ID.
+func (p1 *Portal) Identifier() string {
+	if p1.Name != nil {
+		return *p1.Name
+	}
+	return *p1.ID
+}
+
+// Console returns an entity's identity in a human
+// readable string.
+func (p1 *Portal) Console() string {
+	return p1.Identifier()
+}
+
+// Equal returns true if p1 and p2 are equal.
+func (p1 *Portal) Equal(p2 *Portal) bool {
+	return p1.EqualWithOpts(p2, false, false)
+}
+
+// EqualWithOpts returns true if p1 and p2 are equal.
+// If ignoreID is set to true, IDs will be ignored while comparison.
+// If ignoreTS is set to true, timestamp fields will be ignored.
+func (p1 *Portal) EqualWithOpts(p2 *Portal,
+	ignoreID bool, _ bool,
+) bool {
+	p1Copy := p1.DeepCopy()
+	p2Copy := p2.DeepCopy()
+
+	if ignoreID {
+		p1Copy.ID = nil
+		p2Copy.ID = nil
+	}
+	return reflect.DeepEqual(p1Copy, p2Copy)
+}
+
+// APIProduct represents an API product in Konnect.
+// It adds some helper methods along with Meta to the original APIProduct
+// object.
+type APIProduct struct {
+	konnect.APIProduct `yaml:",inline"`
+	Meta
+}
+
+// Identifier returns the endpoint key name or ID.
+func (p1 *APIProduct) Identifier() string {
+	if p1.Name != nil {
+		return *p1.Name
+	}
+	return *p1.ID
+}
+
+// Console returns an entity's identity in a human
+// readable string.
+func (p1 *APIProduct) Console() string {
+	return p1.Identifier()
+}
+
+// Equal returns true if p1 and p2 are equal.
+func (p1 *APIProduct) Equal(p2 *APIProduct) bool {
+	return p1.EqualWithOpts(p2, false, false)
+}
+
+// EqualWithOpts returns true if p1 and p2 are equal.
+// If ignoreID is set to true, IDs will be ignored while comparison.
+// If ignoreTS is set to true, timestamp fields will be ignored.
+// Portals are compared regardless of their order.
+func (p1 *APIProduct) EqualWithOpts(p2 *APIProduct,
+	ignoreID bool, _ bool,
+) bool {
+	p1Copy := p1.DeepCopy()
+	p2Copy := p2.DeepCopy()
+
+	if ignoreID {
+		p1Copy.ID = nil
+		p2Copy.ID = nil
+	}
+	for _, p := range []*konnect.APIProduct{p1Copy, p2Copy} {
+		if len(p.PortalIDs) == 0 {
+			p.PortalIDs = nil
+		}
+		sort.Strings(p.PortalIDs)
+		if len(p.Labels) == 0 {
+			p.Labels = nil
+		}
+	}
+	return reflect.DeepEqual(p1Copy, p2Copy)
+}
+
+// APIProductVersion represents a version of an API product in Konnect.
+// It adds some helper methods along with Meta to the original
+// APIProductVersion object.
+type APIProductVersion struct {
+	konnect.APIProductVersion `yaml:",inline"`
+	Meta
+}
+
+// Identifier returns the endpoint key name or ID.
+func (v1 *APIProductVersion) Identifier() string {
+	if v1.Name != nil {
+		return *v1.Name
+	}
+	return *v1.ID
+}
+
+// Console returns an entity's identity in a human
+// readable string.
+func (v1 *APIProductVersion) Console() string {
+	return v1.Identifier()
+}
+
+// Equal returns true if v1 and v2 are equal.
+func (v1 *APIProductVersion) Equal(v2 *APIProductVersion) bool {
+	return v1.EqualWithOpts(v2, false, false, false)
+}
+
+// EqualWithOpts returns true if v1 and v2 are equal.
+// If ignoreID is set to true, IDs will be ignored while comparison.
+// If ignoreTS is set to true, timestamp fields will be ignored.
+// If ignoreForeign is set to true, the API product is ignored.
+func (v1 *APIProductVersion) EqualWithOpts(v2 *APIProductVersion,
+	ignoreID, _, ignoreForeign bool,
+) bool {
+	v1Copy := v1.DeepCopy()
+	v2Copy := v2.DeepCopy()
+
+	if ignoreID {
+		v1Copy.ID = nil
+		v2Copy.ID = nil
+	}
+	if ignoreForeign {
+		v1Copy.APIProduct = nil
+		v2Copy.APIProduct = nil
+	}
+	return reflect.DeepEqual(v1Copy, v2Copy)
+}
+
+// APIProductDocument represents a document of an API product in Konnect.
+// It adds some helper methods along with Meta to the original
+// APIProductDocument object.
+type APIProductDocument struct {
+	konnect.APIProductDocument `yaml:",inline"`
+	Meta
+}
+
+// Identifier returns the endpoint key name or ID.
+func (d1 *APIProductDocument) Identifier() string {
+	if d1.Slug != nil {
+		return *d1.Slug
+	}
+	return *d1.ID
+}
+
+// Console returns an entity's identity in a human
+// readable string.
+func (d1 *APIProductDocument) Console() string {
+	return d1.Identifier()
+}
+
+// Equal returns true if d1 and d2 are equal.
+func (d1 *APIProductDocument) Equal(d2 *APIProductDocument) bool {
+	return d1.EqualWithOpts(d2, false, false, false)
+}
+
+// EqualWithOpts returns true if d1 and d2 are equal.
+// If ignoreID is set to true, IDs will be ignored while comparison.
+// If ignoreTS is set to true, timestamp fields will be ignored.
+// If ignoreForeign is set to true, the API product is ignored.
+func (d1 *APIProductDocument) EqualWithOpts(d2 *APIProductDocument,
+	ignoreID, _, ignoreForeign bool,
+) bool {
+	d1Copy := d1.DeepCopy()
+	d2Copy := d2.DeepCopy()
+
+	if ignoreID {
+		d1Copy.ID = nil
+		d2Copy.ID = nil
+	}
+	if ignoreForeign {
+		d1Copy.APIProduct = nil
+		d2Copy.APIProduct = nil
+	}
+	return reflect.DeepEqual(d1Copy, d2Copy)
+}
+
+// ControlPlane represents a control plane in Konnect.
+// It adds some helper methods along with Meta to the original ControlPlaneV2
+// object.
+type ControlPlane struct {
+	konnect.ControlPlaneV2 `yaml:",inline"`
+	Meta
+}
+
+// Identifier returns the endpoint key name or ID.
+func (c1 *ControlPlane) Identifier() string {
+	if c1.Name != nil {
+		return *c1.Name
+	}
+	return *c1.ID
+}
+
+// Console returns an entity's identity in a human
+// readable string.
+func (c1 *ControlPlane) Console() string {
+	return c1.Identifier()
+}
+
+// Equal returns true if c1 and c2 are equal.
+func (c1 *ControlPlane) Equal(c2 *ControlPlane) bool {
+	return c1.EqualWithOpts(c2, false, false)
+}
+
+// EqualWithOpts returns true if c1 and c2 are equal.
+// If ignoreID is set to true, IDs will be ignored while comparison.
+// If ignoreTS is set to true, timestamp fields will be ignored.
+// The members of control plane groups are compared regardless of their
+// order.
+func (c1 *ControlPlane) EqualWithOpts(c2 *ControlPlane,
+	ignoreID bool, _ bool,
+) bool {
+	c1Copy := c1.DeepCopy()
+	c2Copy := c2.DeepCopy()
+
+	if ignoreID {
+		c1Copy.ID = nil
+		c2Copy.ID = nil
+	}
+	for _, c := range []*konnect.ControlPlaneV2{c1Copy, c2Copy} {
+		if len(c.Members) == 0 {
+			c.Members = nil
+		}
+		sort.Strings(c.Members)
+		if len(c.Labels) == 0 {
+			c.Labels = nil
+		}
+	}
+	return reflect.DeepEqual(c1Copy, c2Copy)
+}
+
+// ControlPlaneGroupMembership represents the members of a control plane group
+// in Konnect. It is not stored in the state: the members are stored along
+// with the group.
+type ControlPlaneGroupMembership struct {
+	GroupID   *string `json:"group_id,omitempty" yaml:"group_id,omitempty"`
+	GroupName *string `json:"group_name,omitempty" yaml:"group_name,omitempty"`
+	// Members are the IDs of the members of the group.
+	Members []string `json:"members" yaml:"members"`
+}
+
+// Identifier returns the endpoint key name or ID.
+func (m *ControlPlaneGroupMembership) Identifier() string {
+	if m.GroupName != nil {
+		return *m.GroupName
+	}
+	return *m.GroupID
+}
+
+// Console returns an entity's identity in a human
+// readable string.
+func (m *ControlPlaneGroupMembership) Console() string {
+	return m.Identifier()
+}
@@ -0,0 +1,171 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/kong/go-database-reconciler/pkg/utils"
+)
+
+const (
+	portalTableName = "portal"
+)
+
+var portalTableSchema = &memdb.TableSchema{
+	Name: portalTableName,
+	Indexes: map[string]*memdb.IndexSchema{
+		"id": {
+			Name:    "id",
+			Unique:  true,
+			Indexer: &memdb.StringFieldIndex{Field: "ID"},
+		},
+		nameIndex: {
+			Name:    nameIndex,
+			Unique:  true,
+			Indexer: &memdb.StringFieldIndex{Field: nameFieldIndex},
+		},
+		all: allIndex,
+	},
+}
+
+// PortalsCollection stores and indexes Konnect Dev Portals.
+type PortalsCollection collection
+
+// Add adds a portal to the collection.
+// portal.ID should not be nil else an error is thrown.
+func (k *PortalsCollection) Add(portal Portal) error {
+	// TODO abstract this check in the go-memdb library itself
+	if utils.Empty(portal.ID) {
+		return errIDRequired
+	}
+	txn := k.db.Txn(true)
+	defer txn.Abort()
+
+	var searchBy []string
+	searchBy = append(searchBy, *portal.ID)
+	if !utils.Empty(portal.Name) {
+		searchBy = append(searchBy, *portal.Name)
+	}
+	_, err := getPortal(txn, searchBy...)
+	if err == nil {
+		return fmt.Errorf("inserting portal %v: %w", portal.Console(), ErrAlreadyExists)
+	} else if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	err = txn.Insert(portalTableName, &portal)
+	if err != nil {
+		return err
+	}
+	txn.Commit()
+	return nil
+}
+
+func getPortal(txn *memdb.Txn, IDs ...string) (*Portal, error) {
+	for _, id := range IDs {
+		res, err := multiIndexLookupUsingTxn(txn, portalTableName,
+			[]string{nameIndex, "id"}, id)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		portal, ok := res.(*Portal)
+		if !ok {
+			panic(unexpectedType)
+		}
+		return &Portal{Portal: *portal.DeepCopy()}, nil
+	}
+	return nil, ErrNotFound
+}
+
+// Get gets a portal by name or ID.
+func (k *PortalsCollection) Get(nameOrID string) (*Portal, error) {
+	if nameOrID == "" {
+		return nil, errIDRequired
+	}
+
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+	return getPortal(txn, nameOrID)
+}
+
+// Update updates an existing portal.
+// It returns an error if the portal is not already present.
+func (k *PortalsCollection) Update(portal Portal) error {
+	// TODO abstract this check in the go-memdb library itself
+	if utils.Empty(portal.ID) {
+		return errIDRequired
+	}
+
+	txn := k.db.Txn(true)
+	defer txn.Abort()
+
+	err := deletePortal(txn, *portal.ID)
+	if err != nil {
+		return err
+	}
+
+	err = txn.Insert(portalTableName, &portal)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+func deletePortal(txn *memdb.Txn, nameOrID string) error {
+	portal, err := getPortal(txn, nameOrID)
+	if err != nil {
+		return err
+	}
+
+	err = txn.Delete(portalTableName, portal)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Delete deletes a portal by name or ID.
+func (k *PortalsCollection) Delete(nameOrID string) error {
+	if nameOrID == "" {
+		return errIDRequired
+	}
+
+	txn := k.db.Txn(true)
+	defer txn.Abort()
+
+	err := deletePortal(txn, nameOrID)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// GetAll returns all the portals.
+func (k *PortalsCollection) GetAll() ([]*Portal, error) {
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+
+	iter, err := txn.Get(portalTableName, all, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*Portal
+	for el := iter.Next(); el != nil; el = iter.Next() {
+		s, ok := el.(*Portal)
+		if !ok {
+			panic(unexpectedType)
+		}
+		res = append(res, &Portal{Portal: *s.DeepCopy()})
+	}
+	txn.Commit()
+	return res, nil
+}
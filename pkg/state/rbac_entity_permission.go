@@ -0,0 +1,207 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/kong/go-database-reconciler/pkg/state/indexers"
+	"github.com/kong/go-database-reconciler/pkg/utils"
+)
+
+const (
+	rbacEntityPermissionTableName = "rbac-entitypermission"
+	rbacEntityPermissionsByRoleID = "rbacEntityPermissionsByRoleID"
+)
+
+var (
+	errInvalidEntityPermission      = fmt.Errorf("role.ID and entity_id are required in rbacEntityPermission")
+	rbacEntityPermissionTableSchema = &memdb.TableSchema{
+		Name: rbacEntityPermissionTableName,
+		Indexes: map[string]*memdb.IndexSchema{
+			// ID in the case of an RBACEntityPermission is a composite key of role ID and entity ID
+			"id": {
+				Name:    "id",
+				Unique:  true,
+				Indexer: &memdb.StringFieldIndex{Field: "ID"},
+			},
+			all: allIndex,
+			// foreign
+			rbacEntityPermissionsByRoleID: {
+				Name: rbacEntityPermissionsByRoleID,
+				Indexer: &indexers.SubFieldIndexer{
+					Fields: []indexers.Field{
+						{
+							Struct: "Role",
+							Sub:    "ID",
+						},
+					},
+				},
+			},
+		},
+	}
+)
+
+func validateRBACEntityPermission(rbacEntityPermission *RBACEntityPermission) error {
+	if rbacEntityPermission.Role == nil ||
+		utils.Empty(rbacEntityPermission.Role.ID) ||
+		utils.Empty(rbacEntityPermission.EntityID) {
+		return errInvalidEntityPermission
+	}
+	return nil
+}
+
+// RBACEntityPermissionsCollection stores and indexes Kong RBACEntityPermissions.
+type RBACEntityPermissionsCollection collection
+
+// Add adds a rbacEntityPermission into RBACEntityPermissionsCollection
+// rbacEntityPermission.Role.ID and EntityID should not be nil else an error
+// is thrown.
+func (k *RBACEntityPermissionsCollection) Add(rbacEntityPermission RBACEntityPermission) error {
+	if err := validateRBACEntityPermission(&rbacEntityPermission); err != nil {
+		return err
+	}
+
+	txn := k.db.Txn(true)
+	defer txn.Abort()
+
+	_, err := getRBACEntityPermission(txn, rbacEntityPermission.Identifier())
+	if err == nil {
+		return fmt.Errorf("inserting rbacEntityPermission %v: %w", rbacEntityPermission.Console(), ErrAlreadyExists)
+	} else if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	rbacEntityPermission.ID = rbacEntityPermission.Identifier()
+	err = txn.Insert(rbacEntityPermissionTableName, &rbacEntityPermission)
+	if err != nil {
+		return err
+	}
+	txn.Commit()
+	return nil
+}
+
+func getRBACEntityPermission(txn *memdb.Txn, IDs ...string) (*RBACEntityPermission, error) {
+	for _, id := range IDs {
+		res, err := multiIndexLookupUsingTxn(txn, rbacEntityPermissionTableName,
+			[]string{"id"}, id)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		rbacEntityPermission, ok := res.(*RBACEntityPermission)
+		if !ok {
+			panic(unexpectedType)
+		}
+		return &RBACEntityPermission{
+			ID:                   rbacEntityPermission.ID,
+			RBACEntityPermission: *rbacEntityPermission.DeepCopy(),
+		}, nil
+	}
+	return nil, ErrNotFound
+}
+
+// Get gets a rbacEntityPermission by its composite ID.
+func (k *RBACEntityPermissionsCollection) Get(id string) (*RBACEntityPermission, error) {
+	if id == "" {
+		return nil, errIDRequired
+	}
+
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+	return getRBACEntityPermission(txn, id)
+}
+
+// Update updates a rbacEntityPermission
+func (k *RBACEntityPermissionsCollection) Update(rbacEntityPermission RBACEntityPermission) error {
+	if err := validateRBACEntityPermission(&rbacEntityPermission); err != nil {
+		return err
+	}
+
+	txn := k.db.Txn(true)
+	defer txn.Abort()
+
+	err := deleteRBACEntityPermission(txn, rbacEntityPermission.Identifier())
+	if err != nil {
+		return err
+	}
+
+	rbacEntityPermission.ID = rbacEntityPermission.Identifier()
+	err = txn.Insert(rbacEntityPermissionTableName, &rbacEntityPermission)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+func deleteRBACEntityPermission(txn *memdb.Txn, id string) error {
+	rbacEntityPermission, err := getRBACEntityPermission(txn, id)
+	if err != nil {
+		return err
+	}
+	err = txn.Delete(rbacEntityPermissionTableName, rbacEntityPermission)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Delete deletes a rbacEntityPermission by its composite ID.
+func (k *RBACEntityPermissionsCollection) Delete(id string) error {
+	if id == "" {
+		return errIDRequired
+	}
+
+	txn := k.db.Txn(true)
+	defer txn.Abort()
+
+	err := deleteRBACEntityPermission(txn, id)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// GetAll gets all the rbacEntityPermissions.
+func (k *RBACEntityPermissionsCollection) GetAll() ([]*RBACEntityPermission, error) {
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+
+	iter, err := txn.Get(rbacEntityPermissionTableName, all, true)
+	if err != nil {
+		return nil, err
+	}
+	return rbacEntityPermissionsFromIterator(iter), nil
+}
+
+// GetAllByRoleID returns all entity permissions by referencing a role
+// by its id.
+func (k *RBACEntityPermissionsCollection) GetAllByRoleID(id string) ([]*RBACEntityPermission,
+	error,
+) {
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+
+	iter, err := txn.Get(rbacEntityPermissionTableName, rbacEntityPermissionsByRoleID, id)
+	if err != nil {
+		return nil, err
+	}
+	return rbacEntityPermissionsFromIterator(iter), nil
+}
+
+func rbacEntityPermissionsFromIterator(iter memdb.ResultIterator) []*RBACEntityPermission {
+	var res []*RBACEntityPermission
+	for el := iter.Next(); el != nil; el = iter.Next() {
+		r, ok := el.(*RBACEntityPermission)
+		if !ok {
+			panic(unexpectedType)
+		}
+		res = append(res, &RBACEntityPermission{ID: r.ID, RBACEntityPermission: *r.DeepCopy()})
+	}
+	return res
+}
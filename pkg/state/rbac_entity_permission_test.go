@@ -0,0 +1,56 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRBACEntityPermissionsCollection(t *testing.T) {
+	c := state().RBACEntityPermissions
+
+	err := c.Add(RBACEntityPermission{RBACEntityPermission: kong.RBACEntityPermission{
+		EntityID: new("service-1"),
+	}})
+	require.ErrorIs(t, err, errInvalidEntityPermission)
+
+	ep := RBACEntityPermission{RBACEntityPermission: kong.RBACEntityPermission{
+		EntityID:   new("service-1"),
+		EntityType: new("services"),
+		Actions:    kong.StringSlice("read", "update"),
+		Role:       &kong.RBACRole{ID: new("role-1")},
+	}}
+	require.NoError(t, c.Add(ep))
+	require.ErrorIs(t, c.Add(ep), ErrAlreadyExists)
+
+	res, err := c.Get("role-1-service-1")
+	require.NoError(t, err)
+	assert.Equal(t, "role-1-service-1", res.ID)
+	assert.True(t, ep.Equal(res))
+
+	ep.Actions = kong.StringSlice("update", "read")
+	assert.True(t, ep.Equal(res), "actions are compared regardless of their order")
+	ep.Actions = kong.StringSlice("read")
+	require.NoError(t, c.Update(ep))
+	res, err = c.Get("role-1-service-1")
+	require.NoError(t, err)
+	assert.Equal(t, kong.StringSlice("read"), res.Actions)
+
+	require.NoError(t, c.Add(RBACEntityPermission{RBACEntityPermission: kong.RBACEntityPermission{
+		EntityID: new("route-1"),
+		Role:     &kong.RBACRole{ID: new("role-2")},
+	}}))
+	all, err := c.GetAll()
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+	byRole, err := c.GetAllByRoleID("role-1")
+	require.NoError(t, err)
+	require.Len(t, byRole, 1)
+	assert.Equal(t, "service-1", *byRole[0].EntityID)
+
+	require.NoError(t, c.Delete("role-1-service-1"))
+	_, err = c.Get("role-1-service-1")
+	require.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,173 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/kong/go-database-reconciler/pkg/utils"
+)
+
+const (
+	rbacUserTableName = "rbac-user"
+)
+
+var rbacUserTableSchema = &memdb.TableSchema{
+	Name: rbacUserTableName,
+	Indexes: map[string]*memdb.IndexSchema{
+		"id": {
+			Name:    "id",
+			Unique:  true,
+			Indexer: &memdb.StringFieldIndex{Field: "ID"},
+		},
+		nameIndex: {
+			Name:    nameIndex,
+			Unique:  true,
+			Indexer: &memdb.StringFieldIndex{Field: nameFieldIndex},
+		},
+		all: allIndex,
+	},
+}
+
+// RBACUsersCollection stores and indexes Kong Enterprise RBAC users.
+type RBACUsersCollection collection
+
+// Add adds a rbacUser into RBACUsersCollection
+// rbacUser.ID should not be nil else an error is thrown.
+func (k *RBACUsersCollection) Add(rbacUser RBACUser) error {
+	// TODO abstract this check in the go-memdb library itself
+	if utils.Empty(rbacUser.ID) {
+		return errIDRequired
+	}
+
+	txn := k.db.Txn(true)
+	defer txn.Abort()
+
+	var searchBy []string
+	searchBy = append(searchBy, *rbacUser.ID)
+	if !utils.Empty(rbacUser.Name) {
+		searchBy = append(searchBy, *rbacUser.Name)
+	}
+	_, err := getRBACUser(txn, searchBy...)
+	if err == nil {
+		return fmt.Errorf("inserting rbacUser %v: %w", rbacUser.Console(), ErrAlreadyExists)
+	} else if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	err = txn.Insert(rbacUserTableName, &rbacUser)
+	if err != nil {
+		return err
+	}
+	txn.Commit()
+	return nil
+}
+
+func getRBACUser(txn *memdb.Txn, IDs ...string) (*RBACUser, error) {
+	for _, id := range IDs {
+		res, err := multiIndexLookupUsingTxn(txn, rbacUserTableName,
+			[]string{nameIndex, "id"}, id)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		rbacUser, ok := res.(*RBACUser)
+		if !ok {
+			panic(unexpectedType)
+		}
+		return &RBACUser{RBACUser: *rbacUser.DeepCopy(), Roles: slices.Clone(rbacUser.Roles)}, nil
+	}
+	return nil, ErrNotFound
+}
+
+// Get gets a rbacUser by name or ID.
+func (k *RBACUsersCollection) Get(nameOrID string) (*RBACUser, error) {
+	if nameOrID == "" {
+		return nil, errIDRequired
+	}
+
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+	return getRBACUser(txn, nameOrID)
+}
+
+// Update updates an existing rbacUser.
+// It returns an error if the rbacUser is not already present.
+func (k *RBACUsersCollection) Update(rbacUser RBACUser) error {
+	// TODO abstract this check in the go-memdb library itself
+	if utils.Empty(rbacUser.ID) {
+		return errIDRequired
+	}
+
+	txn := k.db.Txn(true)
+	defer txn.Abort()
+
+	err := deleteRBACUser(txn, *rbacUser.ID)
+	if err != nil {
+		return err
+	}
+
+	err = txn.Insert(rbacUserTableName, &rbacUser)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+func deleteRBACUser(txn *memdb.Txn, nameOrID string) error {
+	rbacUser, err := getRBACUser(txn, nameOrID)
+	if err != nil {
+		return err
+	}
+
+	err = txn.Delete(rbacUserTableName, rbacUser)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Delete deletes a rbacUser by name or ID.
+func (k *RBACUsersCollection) Delete(nameOrID string) error {
+	if nameOrID == "" {
+		return errIDRequired
+	}
+
+	txn := k.db.Txn(true)
+	defer txn.Abort()
+
+	err := deleteRBACUser(txn, nameOrID)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// GetAll returns all the rbacUsers.
+func (k *RBACUsersCollection) GetAll() ([]*RBACUser, error) {
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+
+	iter, err := txn.Get(rbacUserTableName, all, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*RBACUser
+	for el := iter.Next(); el != nil; el = iter.Next() {
+		u, ok := el.(*RBACUser)
+		if !ok {
+			panic(unexpectedType)
+		}
+		res = append(res, &RBACUser{RBACUser: *u.DeepCopy(), Roles: slices.Clone(u.Roles)})
+	}
+	txn.Commit()
+	return res, nil
+}
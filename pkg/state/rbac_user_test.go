@@ -0,0 +1,135 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRBACUsersCollection(t *testing.T) {
+	c := state().RBACUsers
+
+	require.ErrorIs(t, c.Add(RBACUser{RBACUser: kong.RBACUser{Name: new("foo")}}), errIDRequired)
+
+	user := RBACUser{
+		RBACUser: kong.RBACUser{
+			ID:        new("user-1"),
+			Name:      new("foo"),
+			UserToken: new("secret"),
+		},
+		Roles: []string{"read-only"},
+	}
+	require.NoError(t, c.Add(user))
+	err := c.Add(RBACUser{RBACUser: kong.RBACUser{ID: new("user-2"), Name: new("foo")}})
+	require.ErrorIs(t, err, ErrAlreadyExists)
+
+	byName, err := c.Get("foo")
+	require.NoError(t, err)
+	byID, err := c.Get("user-1")
+	require.NoError(t, err)
+	assert.Equal(t, byName, byID)
+	assert.True(t, user.Equal(byName))
+
+	// Users returned are copies.
+	byName.Roles[0] = "admin"
+	byID, err = c.Get("foo")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"read-only"}, byID.Roles)
+
+	user.Roles = []string{"admin"}
+	require.NoError(t, c.Update(user))
+	updated, err := c.Get("user-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"admin"}, updated.Roles)
+
+	require.NoError(t, c.Add(RBACUser{RBACUser: kong.RBACUser{ID: new("user-2"), Name: new("bar")}}))
+	all, err := c.GetAll()
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	require.NoError(t, c.Delete("foo"))
+	_, err = c.Get("user-1")
+	require.ErrorIs(t, err, ErrNotFound)
+	require.ErrorIs(t, c.Delete("foo"), ErrNotFound)
+}
+
+func TestRBACUserEqualWithOpts(t *testing.T) {
+	u1 := &RBACUser{
+		RBACUser: kong.RBACUser{
+			ID:        new("user-1"),
+			Name:      new("foo"),
+			CreatedAt: new(1),
+			UserToken: new("secret"),
+		},
+		Roles: []string{"b", "a"},
+	}
+	u2 := &RBACUser{RBACUser: *u1.DeepCopy(), Roles: []string{"a", "b"}}
+	// Kong only returns a hash of the token.
+	u2.UserToken = new("$2b$09$hash")
+	u2.UserTokenIdent = new("4d870")
+	u2.CreatedAt = new(2)
+
+	assert.False(t, u1.EqualWithOpts(u2, false, false))
+	assert.True(t, u1.EqualWithOpts(u2, false, true))
+
+	// A changed token is detected through its identifier.
+	u1.UserTokenIdent = new("4d870")
+	assert.True(t, u1.EqualWithOpts(u2, false, true))
+	u1.UserTokenIdent = new("a1b2c")
+	assert.False(t, u1.EqualWithOpts(u2, false, true))
+	u1.UserTokenIdent = nil
+
+	u2.Roles = []string{"a"}
+	assert.False(t, u1.EqualWithOpts(u2, false, true))
+}
+
+func TestAdminsCollection(t *testing.T) {
+	c := state().Admins
+
+	admin := Admin{
+		Admin: kong.Admin{
+			ID:       new("admin-1"),
+			Username: new("alice"),
+			Email:    new("alice@example.com"),
+		},
+		Roles: []string{"super-admin"},
+	}
+	require.NoError(t, c.Add(admin))
+	err := c.Add(Admin{Admin: kong.Admin{ID: new("admin-2"), Email: new("alice@example.com")}})
+	require.ErrorIs(t, err, ErrAlreadyExists)
+
+	for _, key := range []string{"admin-1", "alice", "alice@example.com"} {
+		a, err := c.Get(key)
+		require.NoError(t, err)
+		assert.True(t, admin.Equal(a))
+	}
+
+	// Admins without an email can be added.
+	require.NoError(t, c.Add(Admin{Admin: kong.Admin{ID: new("admin-2"), Username: new("bob")}}))
+	require.NoError(t, c.Add(Admin{Admin: kong.Admin{ID: new("admin-3"), Username: new("carol")}}))
+	all, err := c.GetAll()
+	require.NoError(t, err)
+	assert.Len(t, all, 3)
+
+	require.NoError(t, c.Delete("alice"))
+	_, err = c.Get("alice@example.com")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestAdminEqualWithOpts(t *testing.T) {
+	a1 := &Admin{Admin: kong.Admin{
+		ID:       new("admin-1"),
+		Username: new("alice"),
+		Password: new("secret"),
+	}}
+	a2 := &Admin{Admin: *a1.DeepCopy()}
+	a2.Password = nil
+	a2.Token = new("token")
+
+	assert.True(t, a1.EqualWithOpts(a2, false, true))
+
+	a2.Email = new("alice@example.com")
+	assert.False(t, a1.EqualWithOpts(a2, false, true))
+}
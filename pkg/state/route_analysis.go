@@ -0,0 +1,328 @@
+package state
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/kong/go-database-reconciler/pkg/utils"
+	"github.com/kong/go-kong/kong"
+)
+
+// RouteDiagnostic is a problem found by AnalyzeRoutes.
+type RouteDiagnostic struct {
+	// Code is either utils.DiagnosticCodeRouteConflict or
+	// utils.DiagnosticCodeRouteUnreachable.
+	Code utils.DiagnosticCode
+	// Route is the affected route in human-readable form.
+	Route string
+	// Others are the routes conflicting with or shadowing Route.
+	Others []string
+	// Paths are the paths of Route affected by the problem, if any.
+	Paths []string
+}
+
+func (d RouteDiagnostic) String() string {
+	others := "route " + strings.Join(d.Others, ", route ")
+	switch d.Code {
+	case utils.DiagnosticCodeRouteUnreachable:
+		return fmt.Sprintf("route %s is unreachable: all its requests are matched first by %s", d.Route, others)
+	default:
+		res := fmt.Sprintf("route %s and %s match the same requests with the same priority", d.Route, others)
+		if len(d.Paths) > 0 {
+			res += " (paths: " + strings.Join(d.Paths, ", ") + ")"
+		}
+		return res
+	}
+}
+
+var httpRouteProtocols = []string{"http", "https", "grpc", "grpcs", "ws", "wss"}
+
+// routeMatcher is the normalized form of the match criteria of a
+// route, as evaluated by Kong's traditional router.
+type routeMatcher struct {
+	name string
+
+	protocols []string
+	hosts     []string
+	methods   []string
+	snis      []string
+	headers   map[string][]string
+	paths     []routePath
+
+	regexPriority int
+}
+
+type routePath struct {
+	raw   string
+	regex *regexp.Regexp
+	// prefix is the path with the regex marker stripped.
+	prefix string
+}
+
+func (p routePath) isRegex() bool {
+	return strings.HasPrefix(p.raw, "~")
+}
+
+func lowerAll(values []*string) []string {
+	res := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != nil {
+			res = append(res, strings.ToLower(*v))
+		}
+	}
+	sort.Strings(res)
+	return slices.Compact(res)
+}
+
+func newRouteMatcher(r *Route) (*routeMatcher, bool) {
+	// expression routes are not evaluated by the traditional router.
+	if !utils.Empty(r.Expression) {
+		return nil, false
+	}
+	m := &routeMatcher{
+		name:      r.Console(),
+		protocols: lowerAll(r.Protocols),
+		hosts:     lowerAll(r.Hosts),
+		methods:   lowerAll(r.Methods),
+		snis:      lowerAll(r.SNIs),
+		headers:   map[string][]string{},
+	}
+	if len(m.protocols) == 0 {
+		m.protocols = []string{"http", "https"}
+	}
+	if !slices.ContainsFunc(m.protocols, func(p string) bool {
+		return slices.Contains(httpRouteProtocols, p)
+	}) {
+		// stream routes are matched on sources and destinations only.
+		return nil, false
+	}
+	if r.RegexPriority != nil {
+		m.regexPriority = *r.RegexPriority
+	}
+	for name, values := range r.Headers {
+		m.headers[strings.ToLower(name)] = lowerAll(kong.StringSlice(values...))
+	}
+	for _, p := range r.Paths {
+		if p == nil {
+			continue
+		}
+		path := routePath{raw: *p, prefix: *p}
+		if path.isRegex() {
+			path.prefix = strings.TrimPrefix(*p, "~")
+			// Kong anchors regex paths at the beginning of the request path.
+			re, err := regexp.Compile("^(?:" + path.prefix + ")")
+			if err == nil {
+				path.regex = re
+			}
+		}
+		m.paths = append(m.paths, path)
+	}
+	return m, true
+}
+
+// sameCategories returns true if both matchers define the same set of
+// match categories.
+func (m *routeMatcher) sameCategories(o *routeMatcher) bool {
+	return (len(m.hosts) > 0) == (len(o.hosts) > 0) &&
+		(len(m.methods) > 0) == (len(o.methods) > 0) &&
+		(len(m.snis) > 0) == (len(o.snis) > 0) &&
+		(len(m.headers) > 0) == (len(o.headers) > 0) &&
+		(len(m.paths) > 0) == (len(o.paths) > 0)
+}
+
+func isSubset(sub, super []string) bool {
+	for _, v := range sub {
+		if !slices.Contains(super, v) {
+			return false
+		}
+	}
+	return true
+}
+
+// coversExceptPaths returns true if every request matched by o, paths aside,
+// is also matched by m with the same priority.
+// Plain hosts take precedence over wildcard hosts, so host and SNI coverage
+// is only considered for identical values.
+func (m *routeMatcher) coversExceptPaths(o *routeMatcher) bool {
+	if !m.sameCategories(o) || len(m.headers) != len(o.headers) {
+		return false
+	}
+	if !isSubset(o.protocols, m.protocols) ||
+		!isSubset(o.hosts, m.hosts) ||
+		!isSubset(o.methods, m.methods) ||
+		!isSubset(o.snis, m.snis) {
+		return false
+	}
+	for name, values := range o.headers {
+		mValues, ok := m.headers[name]
+		if !ok || !isSubset(values, mValues) {
+			return false
+		}
+	}
+	return true
+}
+
+// equalExceptPaths returns true if both matchers match exactly the same
+// requests, paths aside.
+func (m *routeMatcher) equalExceptPaths(o *routeMatcher) bool {
+	return m.coversExceptPaths(o) && o.coversExceptPaths(m)
+}
+
+type pathRelation int
+
+const (
+	pathUnrelated pathRelation = iota
+	// pathShadows means that every request matching the other path is
+	// matched first by this path.
+	pathShadows
+	// pathConflicts means that both paths match the same requests and
+	// have the same priority.
+	pathConflicts
+)
+
+// comparePaths evaluates path p of m against path o of other, following the
+// traditional router's evaluation order: regex paths are evaluated before
+// prefix paths, by descending regex_priority; prefix paths are evaluated
+// from the longest to the shortest.
+func (m *routeMatcher) comparePaths(p routePath, other *routeMatcher, o routePath) pathRelation {
+	switch {
+	case p.isRegex() && o.isRegex():
+		if p.raw != o.raw {
+			return pathUnrelated
+		}
+		if m.regexPriority > other.regexPriority {
+			return pathShadows
+		}
+		if m.regexPriority == other.regexPriority {
+			return pathConflicts
+		}
+	case p.isRegex():
+		// Kong only anchors regex paths at the beginning of the request
+		// path, so a regex matching the start of the prefix also matches
+		// every request path starting with it, unless the match depends on
+		// what follows it.
+		if p.regex == nil || strings.Contains(p.prefix, "$") ||
+			strings.Contains(p.prefix, `\b`) || strings.Contains(p.prefix, `\B`) ||
+			strings.Contains(p.prefix, `\z`) {
+			return pathUnrelated
+		}
+		if p.regex.MatchString(o.prefix) {
+			return pathShadows
+		}
+	case !o.isRegex():
+		if p.raw == o.raw {
+			return pathConflicts
+		}
+	}
+	return pathUnrelated
+}
+
+// AnalyzeRoutes evaluates the matching rules of all the routes in the
+// collection using the semantics of Kong's traditional router, and reports
+// routes with the same match criteria that match the same requests with the
+// same priority (utils.DiagnosticCodeRouteConflict) as well as routes which
+// can never be matched because other routes always take precedence or cover
+// all their requests (utils.DiagnosticCodeRouteUnreachable).
+// Expression and stream routes are ignored.
+func AnalyzeRoutes(routes *RoutesCollection) ([]RouteDiagnostic, error) {
+	all, err := routes.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("fetching routes from state: %w", err)
+	}
+	matchers := make([]*routeMatcher, 0, len(all))
+	for _, r := range all {
+		if m, ok := newRouteMatcher(r); ok {
+			matchers = append(matchers, m)
+		}
+	}
+	sort.Slice(matchers, func(i, j int) bool {
+		return matchers[i].name < matchers[j].name
+	})
+
+	var res []RouteDiagnostic
+	// conflicts are symmetric and reported once per pair of routes,
+	// on the route that sorts first.
+	conflicts := map[[2]int]*RouteDiagnostic{}
+	addConflict := func(i, j int, path string) {
+		key := [2]int{min(i, j), max(i, j)}
+		d, ok := conflicts[key]
+		if !ok {
+			d = &RouteDiagnostic{
+				Code:   utils.DiagnosticCodeRouteConflict,
+				Route:  matchers[key[0]].name,
+				Others: []string{matchers[key[1]].name},
+			}
+			conflicts[key] = d
+		}
+		if path != "" && !slices.Contains(d.Paths, path) {
+			d.Paths = append(d.Paths, path)
+		}
+	}
+
+	for i, m := range matchers {
+		var shadowers []string
+		shadowed := make([]bool, len(m.paths))
+		for j, other := range matchers {
+			if i == j || !other.coversExceptPaths(m) {
+				continue
+			}
+			// only routes with the same match criteria conflict: a route
+			// covering a narrower one shadows it instead.
+			equal := other.equalExceptPaths(m)
+			if len(m.paths) == 0 {
+				if equal {
+					addConflict(i, j, "")
+				} else {
+					shadowers = append(shadowers, other.name)
+				}
+				continue
+			}
+			shadows := false
+			for k, mp := range m.paths {
+				for _, op := range other.paths {
+					switch other.comparePaths(op, m, mp) {
+					case pathShadows:
+						shadowed[k] = true
+						shadows = true
+					case pathConflicts:
+						if equal {
+							addConflict(i, j, mp.raw)
+						} else {
+							shadowed[k] = true
+							shadows = true
+						}
+					case pathUnrelated:
+					}
+				}
+			}
+			if shadows {
+				shadowers = append(shadowers, other.name)
+			}
+		}
+		if len(shadowers) > 0 && !slices.Contains(shadowed, false) {
+			res = append(res, RouteDiagnostic{
+				Code:   utils.DiagnosticCodeRouteUnreachable,
+				Route:  m.name,
+				Others: shadowers,
+			})
+		}
+	}
+
+	keys := make([][2]int, 0, len(conflicts))
+	for key := range conflicts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	for _, key := range keys {
+		res = append(res, *conflicts[key])
+	}
+	return res, nil
+}
@@ -0,0 +1,174 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/kong/go-database-reconciler/pkg/utils"
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeRoutes(t *testing.T) {
+	tests := []struct {
+		name   string
+		routes []kong.Route
+		want   []RouteDiagnostic
+	}{
+		{
+			name: "distinct routes",
+			routes: []kong.Route{
+				{Name: new("a"), Paths: []*string{new("/a")}},
+				{Name: new("b"), Paths: []*string{new("/b")}},
+				{Name: new("c"), Paths: []*string{new("/a")}, Hosts: []*string{new("example.com")}},
+			},
+		},
+		{
+			name: "identical prefix paths conflict",
+			routes: []kong.Route{
+				{Name: new("a"), Paths: []*string{new("/foo"), new("/bar")}, Methods: []*string{new("GET")}},
+				{Name: new("b"), Paths: []*string{new("/foo")}, Methods: []*string{new("get")}},
+			},
+			want: []RouteDiagnostic{
+				{
+					Code:   utils.DiagnosticCodeRouteConflict,
+					Route:  "a",
+					Others: []string{"b"},
+					Paths:  []string{"/foo"},
+				},
+			},
+		},
+		{
+			name: "identical prefix paths of a broader route shadow",
+			routes: []kong.Route{
+				{Name: new("a"), Paths: []*string{new("/foo")}, Methods: []*string{new("GET")}},
+				{Name: new("b"), Paths: []*string{new("/foo")}, Methods: []*string{new("get"), new("POST")}},
+				{Name: new("c"), Paths: []*string{new("/foo"), new("/bar")}, Methods: []*string{new("GET")}},
+			},
+			want: []RouteDiagnostic{
+				{Code: utils.DiagnosticCodeRouteUnreachable, Route: "a", Others: []string{"b"}},
+				{
+					Code:   utils.DiagnosticCodeRouteConflict,
+					Route:  "a",
+					Others: []string{"c"},
+					Paths:  []string{"/foo"},
+				},
+			},
+		},
+		{
+			name: "route without paths conflicts with an identical route",
+			routes: []kong.Route{
+				{Name: new("a"), Hosts: []*string{new("example.com")}},
+				{Name: new("b"), Hosts: []*string{new("Example.com")}},
+			},
+			want: []RouteDiagnostic{
+				{Code: utils.DiagnosticCodeRouteConflict, Route: "a", Others: []string{"b"}},
+			},
+		},
+		{
+			name: "route without paths covered by a broader route is shadowed",
+			routes: []kong.Route{
+				{Name: new("a"), Hosts: []*string{new("example.com")}, Methods: []*string{new("GET")}},
+				{Name: new("b"), Hosts: []*string{new("example.com")}, Methods: []*string{new("GET"), new("POST")}},
+			},
+			want: []RouteDiagnostic{
+				{Code: utils.DiagnosticCodeRouteUnreachable, Route: "a", Others: []string{"b"}},
+			},
+		},
+		{
+			name: "longer prefix is evaluated first",
+			routes: []kong.Route{
+				{Name: new("a"), Paths: []*string{new("/foo")}},
+				{Name: new("b"), Paths: []*string{new("/foo/bar")}},
+			},
+		},
+		{
+			name: "regex shadows prefix paths",
+			routes: []kong.Route{
+				{Name: new("catch-all"), Paths: []*string{new("~/api/v[0-9]+")}},
+				{Name: new("v1"), Paths: []*string{new("/api/v1")}},
+				{Name: new("anchored"), Paths: []*string{new("~/api/v[0-9]+$")}},
+			},
+			want: []RouteDiagnostic{
+				{Code: utils.DiagnosticCodeRouteUnreachable, Route: "v1", Others: []string{"catch-all"}},
+			},
+		},
+		{
+			name: "regex matching the start of a prefix shadows it",
+			routes: []kong.Route{
+				{Name: new("api"), Paths: []*string{new("~/api")}},
+				{Name: new("v1"), Paths: []*string{new("/api/v1/users")}},
+				{Name: new("word"), Paths: []*string{new("~/api/v1\\b")}},
+			},
+			want: []RouteDiagnostic{
+				{Code: utils.DiagnosticCodeRouteUnreachable, Route: "v1", Others: []string{"api"}},
+			},
+		},
+		{
+			name: "regex priority decides between identical regexes",
+			routes: []kong.Route{
+				{Name: new("high"), Paths: []*string{new("~/users/\\d+")}, RegexPriority: new(10)},
+				{Name: new("low"), Paths: []*string{new("~/users/\\d+")}},
+			},
+			want: []RouteDiagnostic{
+				{Code: utils.DiagnosticCodeRouteUnreachable, Route: "low", Others: []string{"high"}},
+			},
+		},
+		{
+			name: "route is reachable through unshadowed paths",
+			routes: []kong.Route{
+				{Name: new("a"), Paths: []*string{new("~/api")}},
+				{Name: new("b"), Paths: []*string{new("/api/users"), new("/other")}},
+			},
+		},
+		{
+			name: "narrower match criteria are not shadowed",
+			routes: []kong.Route{
+				{Name: new("a"), Paths: []*string{new("~/api")}},
+				{
+					Name: new("b"), Paths: []*string{new("/api/users")},
+					Headers: map[string][]string{"x-version": {"2"}},
+				},
+			},
+		},
+		{
+			name: "expression and stream routes are ignored",
+			routes: []kong.Route{
+				{Name: new("a"), Expression: new(`http.path == "/foo"`)},
+				{Name: new("b"), Expression: new(`http.path == "/foo"`)},
+				{Name: new("c"), Protocols: []*string{new("tcp")}},
+				{Name: new("d"), Protocols: []*string{new("tcp")}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ks := state()
+			for _, r := range tt.routes {
+				r.ID = new(*r.Name + "-id")
+				require.NoError(t, ks.Routes.Add(Route{Route: r}))
+			}
+			got, err := AnalyzeRoutes(ks.Routes)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRouteDiagnosticString(t *testing.T) {
+	assert.Equal(t,
+		"route a and route b match the same requests with the same priority (paths: /foo)",
+		RouteDiagnostic{
+			Code:   utils.DiagnosticCodeRouteConflict,
+			Route:  "a",
+			Others: []string{"b"},
+			Paths:  []string{"/foo"},
+		}.String())
+	assert.Equal(t,
+		"route c is unreachable: all its requests are matched first by route a, route b",
+		RouteDiagnostic{
+			Code:   utils.DiagnosticCodeRouteUnreachable,
+			Route:  "c",
+			Others: []string{"a", "b"},
+		}.String())
+}
@@ -26,6 +26,9 @@ type KongState struct {
 	Consumers               *ConsumersCollection
 	Vaults                  *VaultsCollection
 	Licenses                *LicensesCollection
+	Workspaces              *WorkspacesCollection
+	EventHooks              *EventHooksCollection
+	CustomEntities          *CustomEntitiesCollection
 	ConsumerGroups          *ConsumerGroupsCollection
 	ConsumerGroupConsumers  *ConsumerGroupConsumersCollection
 	ConsumerGroupPlugins    *ConsumerGroupPluginsCollection
@@ -46,11 +49,21 @@ type KongState struct {
 	GraphqlRateLimitingCostDecorations *GraphqlRateLimitingCostDecorationsCollection
 	RBACRoles                          *RBACRolesCollection
 	RBACEndpointPermissions            *RBACEndpointPermissionsCollection
+	RBACEntityPermissions              *RBACEntityPermissionsCollection
+	RBACUsers                          *RBACUsersCollection
+	Admins                             *AdminsCollection
 
 	// konnect-specific entities
 	ServicePackages *ServicePackagesCollection
 	ServiceVersions *ServiceVersionsCollection
 	Documents       *DocumentsCollection
+
+	APIProducts         *APIProductsCollection
+	APIProductVersions  *APIProductVersionsCollection
+	APIProductDocuments *APIProductDocumentsCollection
+	Portals             *PortalsCollection
+
+	ControlPlanes *ControlPlanesCollection
 }
 
 // NewKongState creates a new in-memory KongState.
@@ -82,8 +95,14 @@ func NewKongState() (*KongState, error) {
 			consumerGroupPluginTableName:    consumerGroupPluginTableSchema,
 			rbacRoleTableName:               rbacRoleTableSchema,
 			rbacEndpointPermissionTableName: rbacEndpointPermissionTableSchema,
+			rbacEntityPermissionTableName:   rbacEntityPermissionTableSchema,
+			rbacUserTableName:               rbacUserTableSchema,
+			adminTableName:                  adminTableSchema,
 			vaultTableName:                  vaultTableSchema,
 			licenseTableName:                licenseTableSchema,
+			workspaceTableName:              workspaceTableSchema,
+			eventHookTableName:              eventHookTableSchema,
+			customEntityTableName:           customEntityTableSchema,
 			partialTableName:                partialTableSchema,
 			keyTableName:                    keyTableSchema,
 			keySetTableName:                 keySetTableSchema,
@@ -106,6 +125,13 @@ func NewKongState() (*KongState, error) {
 			servicePackageTableName: servicePackageTableSchema,
 			serviceVersionTableName: serviceVersionTableSchema,
 			documentTableName:       documentTableSchema,
+
+			apiProductTableName:         apiProductTableSchema,
+			apiProductVersionTableName:  apiProductVersionTableSchema,
+			apiProductDocumentTableName: apiProductDocumentTableSchema,
+			portalTableName:             portalTableSchema,
+
+			controlPlaneTableName: controlPlaneTableSchema,
 		},
 	}
 
@@ -133,8 +159,14 @@ func NewKongState() (*KongState, error) {
 	state.ConsumerGroupPlugins = (*ConsumerGroupPluginsCollection)(&state.common)
 	state.RBACRoles = (*RBACRolesCollection)(&state.common)
 	state.RBACEndpointPermissions = (*RBACEndpointPermissionsCollection)(&state.common)
+	state.RBACEntityPermissions = (*RBACEntityPermissionsCollection)(&state.common)
+	state.RBACUsers = (*RBACUsersCollection)(&state.common)
+	state.Admins = (*AdminsCollection)(&state.common)
 	state.Vaults = (*VaultsCollection)(&state.common)
 	state.Licenses = (*LicensesCollection)(&state.common)
+	state.Workspaces = (*WorkspacesCollection)(&state.common)
+	state.EventHooks = (*EventHooksCollection)(&state.common)
+	state.CustomEntities = (*CustomEntitiesCollection)(&state.common)
 	state.Partials = (*PartialsCollection)(&state.common)
 	state.Keys = (*KeysCollection)(&state.common)
 	state.KeySets = (*KeySetsCollection)(&state.common)
@@ -158,5 +190,12 @@ func NewKongState() (*KongState, error) {
 	state.ServiceVersions = (*ServiceVersionsCollection)(&state.common)
 	state.Documents = (*DocumentsCollection)(&state.common)
 
+	state.APIProducts = (*APIProductsCollection)(&state.common)
+	state.APIProductVersions = (*APIProductVersionsCollection)(&state.common)
+	state.APIProductDocuments = (*APIProductDocumentsCollection)(&state.common)
+	state.Portals = (*PortalsCollection)(&state.common)
+
+	state.ControlPlanes = (*ControlPlanesCollection)(&state.common)
+
 	return &state, nil
 }
@@ -0,0 +1,94 @@
+package state
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/kong/go-database-reconciler/pkg/utils"
+)
+
+// MergeTags adds tags to every entity in the state that can be tagged.
+// Entities without tags, such as consumer group memberships, are left as is.
+func (k *KongState) MergeTags(tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	v := reflect.ValueOf(k).Elem()
+	for i := range v.NumField() {
+		field := v.Field(i)
+		if !v.Type().Field(i).IsExported() || field.IsNil() {
+			continue
+		}
+		if err := mergeCollectionTags(field, tags); err != nil {
+			return fmt.Errorf("tagging %s: %w", v.Type().Field(i).Name, err)
+		}
+	}
+	return nil
+}
+
+// mergeCollectionTags adds tags to every entity of collection, using its
+// GetAll and Update methods.
+func mergeCollectionTags(collection reflect.Value, tags []string) error {
+	getAll := collection.MethodByName("GetAll")
+	update := collection.MethodByName("Update")
+	if !getAll.IsValid() || !update.IsValid() ||
+		getAll.Type().NumIn() != 0 || getAll.Type().NumOut() != 2 || update.Type().NumIn() != 1 ||
+		update.Type().NumOut() != 1 {
+		return nil
+	}
+	out := getAll.Call(nil)
+	if err, _ := out[1].Interface().(error); err != nil {
+		return err
+	}
+	entities := out[0]
+	updateType := update.Type().In(0)
+	for i := range entities.Len() {
+		entity := entities.Index(i)
+		if entity.Kind() == reflect.Interface {
+			entity = entity.Elem()
+		}
+		if entity.Kind() != reflect.Pointer || !entity.Elem().FieldByName("Tags").IsValid() {
+			continue
+		}
+		entity = deepCopyEntity(entity)
+		if err := utils.MergeTags(entity.Interface(), tags); err != nil {
+			return err
+		}
+		arg := entity
+		if !entity.Type().AssignableTo(updateType) {
+			arg = entity.Elem()
+		}
+		if !arg.Type().AssignableTo(updateType) {
+			return fmt.Errorf("unexpected type %s", entity.Type())
+		}
+		if err, _ := update.Call([]reflect.Value{arg})[0].Interface().(error); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deepCopyEntity returns a copy of entity, a pointer to an entity of the
+// state, whose fields can be changed without changing the entity stored in
+// the collection. Entities embed the Kong entity they wrap, which is copied
+// with its DeepCopy method.
+func deepCopyEntity(entity reflect.Value) reflect.Value {
+	res := reflect.New(entity.Elem().Type())
+	res.Elem().Set(entity.Elem())
+	for i := range res.Elem().NumField() {
+		field := res.Elem().Field(i)
+		if !res.Elem().Type().Field(i).Anonymous {
+			continue
+		}
+		deepCopy := field.Addr().MethodByName("DeepCopy")
+		if deepCopy.IsValid() && deepCopy.Type().NumIn() == 0 && deepCopy.Type().NumOut() == 1 &&
+			deepCopy.Type().Out(0) == field.Addr().Type() {
+			field.Set(deepCopy.Call(nil)[0].Elem())
+		}
+	}
+	// the tags are replaced even if the entity could not be copied
+	if tags := res.Elem().FieldByName("Tags"); tags.Kind() == reflect.Slice && !tags.IsNil() {
+		tags.Set(reflect.AppendSlice(reflect.MakeSlice(tags.Type(), 0, tags.Len()), tags))
+	}
+	return res
+}
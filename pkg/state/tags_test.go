@@ -0,0 +1,54 @@
+package state
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/kong/go-database-reconciler/pkg/utils"
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKongStateMergeTags(t *testing.T) {
+	s := state()
+	require.NoError(t, s.Services.Add(Service{Service: kong.Service{
+		ID:   kong.String("s1"),
+		Name: kong.String("svc"),
+		Tags: kong.StringSlice("team:a"),
+	}}))
+	require.NoError(t, s.KeyAuths.Add(KeyAuth{KeyAuth: kong.KeyAuth{
+		ID:       kong.String("k1"),
+		Key:      kong.String("secret"),
+		Consumer: &kong.Consumer{ID: kong.String("c1")},
+	}}))
+
+	require.NoError(t, s.MergeTags([]string{"managed-by:test"}))
+	// Merging is idempotent.
+	require.NoError(t, s.MergeTags([]string{"managed-by:test"}))
+
+	service, err := s.Services.Get("svc")
+	require.NoError(t, err)
+	assert.Equal(t, kong.StringSlice("team:a", "managed-by:test"), service.Tags)
+	keyAuth, err := s.KeyAuths.Get("k1")
+	require.NoError(t, err)
+	assert.Equal(t, kong.StringSlice("managed-by:test"), keyAuth.Tags)
+}
+
+func TestDeepCopyEntity(t *testing.T) {
+	service := &Service{Service: kong.Service{
+		ID:   kong.String("s1"),
+		Tags: kong.StringSlice("team:a"),
+	}}
+	service.Tags = slices.Grow(service.Tags, 1)
+
+	res := deepCopyEntity(reflect.ValueOf(service)).Interface().(*Service)
+	require.NoError(t, utils.MergeTags(res, []string{"managed-by:test"}))
+	*res.ID = "s2"
+	assert.Equal(t, "s1", *service.ID)
+	assert.Equal(t, kong.StringSlice("team:a"), service.Tags)
+	// the tags are not appended to the spare capacity of the stored entity
+	assert.Nil(t, service.Tags[:cap(service.Tags)][1])
+	assert.Equal(t, kong.StringSlice("team:a", "managed-by:test"), res.Tags)
+}
@@ -3,11 +3,15 @@ package state
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"reflect"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 
+	"github.com/kong/go-database-reconciler/pkg/schema"
+	"github.com/kong/go-database-reconciler/pkg/utils"
 	"github.com/kong/go-kong/kong"
 	"github.com/tidwall/gjson"
 )
@@ -1790,6 +1794,185 @@ func (r1 *RBACEndpointPermission) EqualWithOpts(r2 *RBACEndpointPermission, igno
 	return reflect.DeepEqual(r1Copy, r2Copy)
 }
 
+// RBACEntityPermission represents an RBAC entity permission in Kong
+// Enterprise. It adds some helper methods along with Meta to the original
+// RBACEntityPermission object.
+type RBACEntityPermission struct {
+	ID                        string
+	kong.RBACEntityPermission `yaml:",inline"`
+	Meta
+}
+
+// Identifier returns a composite ID based on the role ID and the entity ID.
+func (r1 *RBACEntityPermission) Identifier() string {
+	return fmt.Sprintf("%s-%s", *r1.Role.ID, *r1.EntityID)
+}
+
+// Console returns an entity's identity in a human
+// readable string.
+func (r1 *RBACEntityPermission) Console() string {
+	if r1.EntityType != nil {
+		return fmt.Sprintf("%s %s for role %s", *r1.EntityType, *r1.EntityID, *r1.Role.ID)
+	}
+	return fmt.Sprintf("%s for role %s", *r1.EntityID, *r1.Role.ID)
+}
+
+// Equal returns true if r1 and r2 are equal.
+func (r1 *RBACEntityPermission) Equal(r2 *RBACEntityPermission) bool {
+	return r1.EqualWithOpts(r2, false, false)
+}
+
+// EqualWithOpts returns true if r1 and r2 are equal.
+// If ignoreID is set to true, IDs will be ignored while comparison.
+// If ignoreTS is set to true, timestamp fields will be ignored.
+// Actions are compared regardless of their order.
+func (r1 *RBACEntityPermission) EqualWithOpts(r2 *RBACEntityPermission, ignoreID,
+	ignoreTS bool,
+) bool {
+	r1Copy := r1.DeepCopy()
+	r2Copy := r2.DeepCopy()
+
+	if ignoreID {
+		r1Copy.EntityID = nil
+		r2Copy.EntityID = nil
+	}
+	if ignoreTS {
+		r1Copy.CreatedAt = nil
+		r2Copy.CreatedAt = nil
+	}
+
+	for _, r := range []*kong.RBACEntityPermission{r1Copy, r2Copy} {
+		sort.Slice(r.Actions, func(i, j int) bool {
+			return *r.Actions[i] < *r.Actions[j]
+		})
+		if r.Role != nil {
+			r.Role = &kong.RBACRole{ID: r.Role.ID}
+		}
+	}
+
+	return reflect.DeepEqual(r1Copy, r2Copy)
+}
+
+// RBACUser represents an RBAC user in Kong Enterprise.
+// It adds some helper methods along with Meta to the original RBACUser
+// object.
+type RBACUser struct {
+	kong.RBACUser `yaml:",inline"`
+	// Roles are the names of the roles assigned to the user.
+	Roles []string `json:"roles,omitempty" yaml:"roles,omitempty"`
+	Meta
+}
+
+// Identifier returns the endpoint key name or ID.
+func (u1 *RBACUser) Identifier() string {
+	if u1.Name != nil {
+		return *u1.Name
+	}
+	return *u1.ID
+}
+
+// Console returns an entity's identity in a human
+// readable string.
+func (u1 *RBACUser) Console() string {
+	return u1.Identifier()
+}
+
+// Equal returns true if u1 and u2 are equal.
+func (u1 *RBACUser) Equal(u2 *RBACUser) bool {
+	return u1.EqualWithOpts(u2, false, false)
+}
+
+// EqualWithOpts returns true if u1 and u2 are equal.
+// If ignoreID is set to true, IDs will be ignored while comparison.
+// If ignoreTS is set to true, timestamp fields will be ignored.
+// User tokens are not compared, as Kong only returns a hash of them: a
+// changed token is only detected through the user_token_ident Kong derives
+// from it, when both users have one.
+// Roles are compared regardless of their order.
+func (u1 *RBACUser) EqualWithOpts(u2 *RBACUser, ignoreID, ignoreTS bool) bool {
+	u1Copy := &RBACUser{RBACUser: *u1.DeepCopy(), Roles: sortedRoles(u1.Roles)}
+	u2Copy := &RBACUser{RBACUser: *u2.DeepCopy(), Roles: sortedRoles(u2.Roles)}
+
+	if ignoreID {
+		u1Copy.ID = nil
+		u2Copy.ID = nil
+	}
+	if ignoreTS {
+		u1Copy.CreatedAt = nil
+		u2Copy.CreatedAt = nil
+	}
+	if u1Copy.UserTokenIdent == nil || u2Copy.UserTokenIdent == nil {
+		u1Copy.UserTokenIdent = nil
+		u2Copy.UserTokenIdent = nil
+	}
+	u1Copy.UserToken = nil
+	u2Copy.UserToken = nil
+	return reflect.DeepEqual(u1Copy, u2Copy)
+}
+
+// Admin represents a Kong Manager admin in Kong Enterprise.
+// It adds some helper methods along with Meta to the original Admin object.
+type Admin struct {
+	kong.Admin `yaml:",inline"`
+	// Roles are the names of the roles assigned to the admin.
+	Roles []string `json:"roles,omitempty" yaml:"roles,omitempty"`
+	Meta
+}
+
+// Identifier returns the endpoint key name or ID.
+func (a1 *Admin) Identifier() string {
+	if a1.Username != nil {
+		return *a1.Username
+	}
+	return *a1.ID
+}
+
+// Console returns an entity's identity in a human
+// readable string.
+func (a1 *Admin) Console() string {
+	return a1.Identifier()
+}
+
+// Equal returns true if a1 and a2 are equal.
+func (a1 *Admin) Equal(a2 *Admin) bool {
+	return a1.EqualWithOpts(a2, false, false)
+}
+
+// EqualWithOpts returns true if a1 and a2 are equal.
+// If ignoreID is set to true, IDs will be ignored while comparison.
+// If ignoreTS is set to true, timestamp fields will be ignored.
+// Passwords and tokens are never compared: Kong does not return them, and
+// they are set by admins registering to Kong Manager.
+// Roles are compared regardless of their order.
+func (a1 *Admin) EqualWithOpts(a2 *Admin, ignoreID, ignoreTS bool) bool {
+	a1Copy := &Admin{Admin: *a1.DeepCopy(), Roles: sortedRoles(a1.Roles)}
+	a2Copy := &Admin{Admin: *a2.DeepCopy(), Roles: sortedRoles(a2.Roles)}
+
+	if ignoreID {
+		a1Copy.ID = nil
+		a2Copy.ID = nil
+	}
+	if ignoreTS {
+		a1Copy.CreatedAt = nil
+		a2Copy.CreatedAt = nil
+	}
+	for _, a := range []*Admin{a1Copy, a2Copy} {
+		a.Password = nil
+		a.Token = nil
+	}
+	return reflect.DeepEqual(a1Copy, a2Copy)
+}
+
+// sortedRoles returns a sorted copy of roles, or nil if there is none.
+func sortedRoles(roles []string) []string {
+	if len(roles) == 0 {
+		return nil
+	}
+	res := slices.Clone(roles)
+	sort.Strings(res)
+	return res
+}
+
 // GetID returns ID.
 // If ID is empty, it returns an empty string.
 func (b1 *MTLSAuth) GetID() string {
@@ -1921,6 +2104,130 @@ func (l *License) EqualWithOpts(l2 *License, ignoreID, ignoreTS bool) bool {
 	return reflect.DeepEqual(l1Copy, l2Copy)
 }
 
+// Workspace represents a workspace in Kong Enterprise.
+// It adds some helper methods along with Meta to the original Workspace
+// object.
+type Workspace struct {
+	kong.Workspace `yaml:",inline"`
+	Meta
+}
+
+// DeepCopy returns a deep copy of the Workspace object, which go-kong does
+// not generate.
+func (w *Workspace) DeepCopy() *kong.Workspace {
+	out := w.Workspace
+	if w.CreatedAt != nil {
+		out.CreatedAt = new(*w.CreatedAt)
+	}
+	if w.ID != nil {
+		out.ID = new(*w.ID)
+	}
+	if w.Name != nil {
+		out.Name = new(*w.Name)
+	}
+	if w.Comment != nil {
+		out.Comment = new(*w.Comment)
+	}
+	if w.Config != nil {
+		out.Config = kong.Configuration(w.Config).DeepCopy()
+	}
+	// Meta of the kong.Workspace is shadowed by the embedded Meta.
+	if w.Workspace.Meta != nil {
+		out.Meta = kong.Configuration(w.Workspace.Meta).DeepCopy()
+	}
+	return &out
+}
+
+// Identifier returns the endpoint key name or ID.
+func (w *Workspace) Identifier() string {
+	if w.Name != nil {
+		return *w.Name
+	}
+	return *w.ID
+}
+
+// Console returns an entity's identity in a human
+// readable string.
+func (w *Workspace) Console() string {
+	return w.Identifier()
+}
+
+// Equal returns true if workspaces w and w2 are equal.
+func (w *Workspace) Equal(w2 *Workspace) bool {
+	return w.EqualWithOpts(w2, false, false)
+}
+
+// EqualWithOpts returns true if workspaces w and w2 are equal.
+// If ignoreID is set to true, IDs will be ignored while comparison.
+// If ignoreTS is set to true, timestamp fields will be ignored.
+func (w *Workspace) EqualWithOpts(w2 *Workspace, ignoreID, ignoreTS bool) bool {
+	w1Copy := w.DeepCopy()
+	w2Copy := w2.DeepCopy()
+
+	if ignoreID {
+		w1Copy.ID = nil
+		w2Copy.ID = nil
+	}
+	if ignoreTS {
+		w1Copy.CreatedAt = nil
+		w2Copy.CreatedAt = nil
+	}
+	return reflect.DeepEqual(w1Copy, w2Copy)
+}
+
+// EventHook represents an event hook in Kong Enterprise.
+// It adds some helper methods along with Meta to the original EventHook
+// object.
+type EventHook struct {
+	utils.EventHook `yaml:",inline"`
+	Meta
+}
+
+// Identifier returns the ID of the event hook, which has no other unique
+// field.
+func (h *EventHook) Identifier() string {
+	return *h.ID
+}
+
+// Console returns an entity's identity in a human
+// readable string.
+func (h *EventHook) Console() string {
+	var source, event, handler string
+	if h.Source != nil {
+		source = *h.Source
+	}
+	if h.Event != nil {
+		event = *h.Event
+	}
+	if h.Handler != nil {
+		handler = *h.Handler
+	}
+	return fmt.Sprintf("%s %s:%s (%s)", handler, source, event, *h.ID)
+}
+
+// Equal returns true if event hooks h and h2 are equal.
+func (h *EventHook) Equal(h2 *EventHook) bool {
+	return h.EqualWithOpts(h2, false, false)
+}
+
+// EqualWithOpts returns true if event hooks h and h2 are equal.
+// If ignoreID is set to true, IDs will be ignored while comparison.
+// If ignoreTS is set to true, timestamp fields will be ignored.
+func (h *EventHook) EqualWithOpts(h2 *EventHook, ignoreID, ignoreTS bool) bool {
+	h1Copy := h.DeepCopy()
+	h2Copy := h2.DeepCopy()
+
+	if ignoreID {
+		h1Copy.ID = nil
+		h2Copy.ID = nil
+	}
+	if ignoreTS {
+		h1Copy.CreatedAt = nil
+		h2Copy.CreatedAt = nil
+	}
+	return reflect.DeepEqual(h1Copy, h2Copy)
+}
+
 type customEntity interface {
 	// ID of the plugin entity.
 	GetCustomEntityID() string
@@ -2295,3 +2602,78 @@ func (c1 *CustomPluginDefinition) EqualWithOpts(c2 *CustomPluginDefinition, igno
 	}
 	return reflect.DeepEqual(c1Copy, c2Copy)
 }
+
+// CustomEntity represents an entity of a custom DAO, such as the DAO of a
+// plugin, which is handled generically from its definition rather than with
+// code specific to its type.
+type CustomEntity struct {
+	// Type is the name of the entity, such as "acme_storage".
+	Type string
+	ID   string
+	// Fields holds the fields of the entity but its ID, with its foreign keys
+	// as references to the ID of another entity.
+	Fields     kong.Configuration
+	Definition *schema.EntityDefinition
+	Meta
+}
+
+// DeepCopy returns a copy of the CustomEntity, which shares its definition.
+func (e *CustomEntity) DeepCopy() *CustomEntity {
+	return &CustomEntity{
+		Type:       e.Type,
+		ID:         e.ID,
+		Fields:     e.Fields.DeepCopy(),
+		Definition: e.Definition,
+	}
+}
+
+// Object returns the fields of the entity along with its ID, as sent to and
+// returned by Kong.
+func (e *CustomEntity) Object() map[string]any {
+	object := make(map[string]any, len(e.Fields)+1)
+	maps.Copy(object, e.Fields.DeepCopy())
+	object["id"] = e.ID
+	return object
+}
+
+// MarshalJSON marshals the entity as its object.
+func (e *CustomEntity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.Object())
+}
+
+// Identifier returns the ID of the entity.
+func (e *CustomEntity) Identifier() string {
+	return e.ID
+}
+
+// Console returns an entity's identity in a human
+// readable string.
+func (e *CustomEntity) Console() string {
+	return e.Type + " " + e.ID
+}
+
+// Equal returns true if custom entities e and e2 are equal.
+func (e *CustomEntity) Equal(e2 *CustomEntity) bool {
+	return e.EqualWithOpts(e2, false, false)
+}
+
+// EqualWithOpts returns true if custom entities e and e2 are equal.
+// If ignoreID is set to true, IDs will be ignored while comparison.
+// If ignoreTS is set to true, timestamp fields will be ignored.
+func (e *CustomEntity) EqualWithOpts(e2 *CustomEntity, ignoreID, ignoreTS bool) bool {
+	if e.Type != e2.Type || (!ignoreID && e.ID != e2.ID) {
+		return false
+	}
+	e1Fields := e.Fields.DeepCopy()
+	e2Fields := e2.Fields.DeepCopy()
+	if ignoreTS {
+		for _, field := range []string{"created_at", "updated_at"} {
+			delete(e1Fields, field)
+			delete(e2Fields, field)
+		}
+	}
+	if len(e1Fields) == 0 && len(e2Fields) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(e1Fields, e2Fields)
+}
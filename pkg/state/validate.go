@@ -0,0 +1,637 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kong/go-database-reconciler/pkg/utils"
+	"github.com/kong/go-kong/kong"
+)
+
+// IntegrityErrorKind classifies a problem reported by Validate.
+type IntegrityErrorKind string
+
+const (
+	// DanglingReference is reported when an entity references a foreign
+	// entity which is not present in the state.
+	DanglingReference IntegrityErrorKind = "dangling-reference"
+	// MissingReference is reported when an entity does not reference a
+	// foreign entity which it requires, e.g. a target without an upstream.
+	MissingReference IntegrityErrorKind = "missing-reference"
+	// OrphanedCredential is reported when a credential belongs to a
+	// consumer which is not present in the state.
+	OrphanedCredential IntegrityErrorKind = "orphaned-credential"
+	// DuplicateKey is reported when two entities share the same natural key,
+	// e.g. two plugins with the same name on the same scope.
+	DuplicateKey IntegrityErrorKind = "duplicate-key"
+)
+
+// IntegrityError describes a single referential integrity problem
+// found in a KongState.
+type IntegrityError struct {
+	// Kind is the class of the problem.
+	Kind IntegrityErrorKind
+	// EntityType is the type of the offending entity, e.g. "route".
+	EntityType string
+	// Entity is the offending entity in human-readable form.
+	Entity string
+	// Reference describes the missing or conflicting entity.
+	Reference string
+}
+
+func (e *IntegrityError) Error() string {
+	switch e.Kind {
+	case OrphanedCredential:
+		return fmt.Sprintf("%s %s is orphaned: %s not found", e.EntityType, e.Entity, e.Reference)
+	case DuplicateKey:
+		return fmt.Sprintf("%s %s is a duplicate of %s", e.EntityType, e.Entity, e.Reference)
+	case MissingReference:
+		return fmt.Sprintf("%s %s does not reference any %s", e.EntityType, e.Entity, e.Reference)
+	default:
+		return fmt.Sprintf("%s %s references %s which does not exist", e.EntityType, e.Entity, e.Reference)
+	}
+}
+
+// Validate walks the collections of the KongState whose entities reference
+// other gateway entities and reports all broken foreign keys, orphaned
+// credentials and duplicate natural keys.
+// Collections without foreign keys, such as vaults, licenses or event hooks,
+// and Konnect entities are not validated.
+// It can be used both on a target state built from a file and on the
+// current state built from a dump.
+// The returned error, if any, is a utils.ErrArray of *IntegrityError.
+func Validate(ks *KongState) error {
+	if ks == nil {
+		return fmt.Errorf("state is nil")
+	}
+	v := &validator{state: ks}
+	checks := []func() error{
+		v.routes,
+		v.services,
+		v.upstreams,
+		v.targets,
+		v.snis,
+		v.plugins,
+		v.filterChains,
+		v.consumerGroupConsumers,
+		v.consumerGroupPlugins,
+		v.credentials,
+		v.rbacEndpointPermissions,
+		v.rbacEntityPermissions,
+		v.rbacUsers,
+		v.admins,
+		v.keys,
+		v.customEntities,
+	}
+	for _, check := range checks {
+		if err := check(); err != nil {
+			return err
+		}
+	}
+	if len(v.errs.Errors) > 0 {
+		return v.errs
+	}
+	return nil
+}
+
+type validator struct {
+	state *KongState
+	errs  utils.ErrArray
+}
+
+func (v *validator) report(kind IntegrityErrorKind, entityType, entity, reference string) {
+	v.errs.Errors = append(v.errs.Errors, &IntegrityError{
+		Kind:       kind,
+		EntityType: entityType,
+		Entity:     entity,
+		Reference:  reference,
+	})
+}
+
+// resolver looks up an entity by name or ID and returns its ID.
+// It returns ErrNotFound if the entity is not present in the state.
+type resolver func(nameOrID string) (string, error)
+
+func (v *validator) resolveService(nameOrID string) (string, error) {
+	s, err := v.state.Services.Get(nameOrID)
+	if err != nil {
+		return "", err
+	}
+	return *s.ID, nil
+}
+
+func (v *validator) resolveRoute(nameOrID string) (string, error) {
+	r, err := v.state.Routes.Get(nameOrID)
+	if err != nil {
+		return "", err
+	}
+	return *r.ID, nil
+}
+
+func (v *validator) resolveConsumer(usernameOrID string) (string, error) {
+	c, err := v.state.Consumers.GetByIDOrUsername(usernameOrID)
+	if err != nil {
+		return "", err
+	}
+	return *c.ID, nil
+}
+
+func (v *validator) resolveConsumerGroup(nameOrID string) (string, error) {
+	cg, err := v.state.ConsumerGroups.Get(nameOrID)
+	if err != nil {
+		return "", err
+	}
+	return *cg.ID, nil
+}
+
+func (v *validator) resolveCertificate(id string) (string, error) {
+	c, err := v.state.Certificates.Get(id)
+	if err != nil {
+		return "", err
+	}
+	return *c.ID, nil
+}
+
+func (v *validator) resolveCACertificate(certOrID string) (string, error) {
+	c, err := v.state.CACertificates.Get(certOrID)
+	if err != nil {
+		return "", err
+	}
+	return *c.ID, nil
+}
+
+func (v *validator) resolveUpstream(nameOrID string) (string, error) {
+	u, err := v.state.Upstreams.Get(nameOrID)
+	if err != nil {
+		return "", err
+	}
+	return *u.ID, nil
+}
+
+func (v *validator) resolvePartial(nameOrID string) (string, error) {
+	p, err := v.state.Partials.Get(nameOrID)
+	if err != nil {
+		return "", err
+	}
+	return *p.ID, nil
+}
+
+func (v *validator) resolveRBACRole(nameOrID string) (string, error) {
+	r, err := v.state.RBACRoles.Get(nameOrID)
+	if err != nil {
+		return "", err
+	}
+	return *r.ID, nil
+}
+
+func (v *validator) resolveKeySet(nameOrID string) (string, error) {
+	ks, err := v.state.KeySets.Get(nameOrID)
+	if err != nil {
+		return "", err
+	}
+	return *ks.ID, nil
+}
+
+// checkRef reports a dangling reference if the entity identified by id
+// can not be resolved. It returns the ID of the referenced entity, or id
+// as is if the reference is dangling, so that callers can build natural
+// keys independently of whether the reference used a name or an ID.
+func (v *validator) checkRef(entityType, entity, refType, id string, resolve resolver) (string, error) {
+	resolved, err := resolve(id)
+	if errors.Is(err, ErrNotFound) {
+		v.report(DanglingReference, entityType, entity, refType+" "+id)
+		return id, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("looking up %s %q: %w", refType, id, err)
+	}
+	return resolved, nil
+}
+
+// checkDuplicate reports a duplicate if key has already been seen.
+func (v *validator) checkDuplicate(seen map[string]string, key, entityType, entity string) {
+	if previous, ok := seen[key]; ok {
+		v.report(DuplicateKey, entityType, entity, previous)
+		return
+	}
+	seen[key] = entity
+}
+
+func (v *validator) routes() error {
+	routes, err := v.state.Routes.GetAll()
+	if err != nil {
+		return fmt.Errorf("fetching routes from state: %w", err)
+	}
+	for _, r := range routes {
+		if r.Service == nil || utils.Empty(r.Service.ID) {
+			continue
+		}
+		if _, err := v.checkRef("route", r.Console(), "service", *r.Service.ID, v.resolveService); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *validator) services() error {
+	services, err := v.state.Services.GetAll()
+	if err != nil {
+		return fmt.Errorf("fetching services from state: %w", err)
+	}
+	for _, s := range services {
+		if s.ClientCertificate != nil && !utils.Empty(s.ClientCertificate.ID) {
+			_, err := v.checkRef("service", s.Console(), "certificate", *s.ClientCertificate.ID,
+				v.resolveCertificate)
+			if err != nil {
+				return err
+			}
+		}
+		for _, id := range s.CACertificates {
+			if utils.Empty(id) {
+				continue
+			}
+			if _, err := v.checkRef("service", s.Console(), "ca-certificate", *id, v.resolveCACertificate); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (v *validator) upstreams() error {
+	upstreams, err := v.state.Upstreams.GetAll()
+	if err != nil {
+		return fmt.Errorf("fetching upstreams from state: %w", err)
+	}
+	for _, u := range upstreams {
+		if u.ClientCertificate == nil || utils.Empty(u.ClientCertificate.ID) {
+			continue
+		}
+		_, err := v.checkRef("upstream", u.Console(), "certificate", *u.ClientCertificate.ID,
+			v.resolveCertificate)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *validator) targets() error {
+	targets, err := v.state.Targets.GetAll()
+	if err != nil {
+		return fmt.Errorf("fetching targets from state: %w", err)
+	}
+	seen := map[string]string{}
+	for _, t := range targets {
+		if t.Upstream == nil || utils.Empty(t.Upstream.ID) {
+			v.report(MissingReference, "target", t.FriendlyName(), "upstream")
+			continue
+		}
+		upstreamID, err := v.checkRef("target", t.Console(), "upstream", *t.Upstream.ID, v.resolveUpstream)
+		if err != nil {
+			return err
+		}
+		if !utils.Empty(t.Target.Target) {
+			v.checkDuplicate(seen, upstreamID+"|"+*t.Target.Target, "target", t.Console())
+		}
+	}
+	return nil
+}
+
+func (v *validator) snis() error {
+	snis, err := v.state.SNIs.GetAll()
+	if err != nil {
+		return fmt.Errorf("fetching snis from state: %w", err)
+	}
+	for _, s := range snis {
+		if s.Certificate == nil || utils.Empty(s.Certificate.ID) {
+			v.report(MissingReference, "sni", s.Console(), "certificate")
+			continue
+		}
+		if _, err := v.checkRef("sni", s.Console(), "certificate", *s.Certificate.ID, v.resolveCertificate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *validator) plugins() error {
+	plugins, err := v.state.Plugins.GetAll()
+	if err != nil {
+		return fmt.Errorf("fetching plugins from state: %w", err)
+	}
+	seen := map[string]string{}
+	for _, p := range plugins {
+		name := p.Console()
+		var serviceID, routeID, consumerID, consumerGroupID string
+		if p.Service != nil && !utils.Empty(p.Service.ID) {
+			serviceID, err = v.checkRef("plugin", name, "service", *p.Service.ID, v.resolveService)
+			if err != nil {
+				return err
+			}
+		}
+		if p.Route != nil && !utils.Empty(p.Route.ID) {
+			routeID, err = v.checkRef("plugin", name, "route", *p.Route.ID, v.resolveRoute)
+			if err != nil {
+				return err
+			}
+		}
+		if p.Consumer != nil && !utils.Empty(p.Consumer.ID) {
+			consumerID, err = v.checkRef("plugin", name, "consumer", *p.Consumer.ID, v.resolveConsumer)
+			if err != nil {
+				return err
+			}
+		}
+		if p.ConsumerGroup != nil && !utils.Empty(p.ConsumerGroup.ID) {
+			consumerGroupID, err = v.checkRef("plugin", name, "consumer-group", *p.ConsumerGroup.ID,
+				v.resolveConsumerGroup)
+			if err != nil {
+				return err
+			}
+		}
+		for _, partial := range p.Partials {
+			if partial == nil || partial.Partial == nil || utils.Empty(partial.ID) {
+				continue
+			}
+			if _, err := v.checkRef("plugin", name, "partial", *partial.ID, v.resolvePartial); err != nil {
+				return err
+			}
+		}
+		// the state only guards against duplicates using the references as
+		// they are written, which may be a name for one plugin and an ID for another.
+		key := strings.Join([]string{*p.Name, serviceID, routeID, consumerID, consumerGroupID}, "|")
+		v.checkDuplicate(seen, key, "plugin", name)
+	}
+	return nil
+}
+
+func (v *validator) filterChains() error {
+	filterChains, err := v.state.FilterChains.GetAll()
+	if err != nil {
+		return fmt.Errorf("fetching filter chains from state: %w", err)
+	}
+	seen := map[string]string{}
+	for _, f := range filterChains {
+		name := f.Console()
+		var serviceID, routeID string
+		if f.Service != nil && !utils.Empty(f.Service.ID) {
+			serviceID, err = v.checkRef("filter-chain", name, "service", *f.Service.ID, v.resolveService)
+			if err != nil {
+				return err
+			}
+		}
+		if f.Route != nil && !utils.Empty(f.Route.ID) {
+			routeID, err = v.checkRef("filter-chain", name, "route", *f.Route.ID, v.resolveRoute)
+			if err != nil {
+				return err
+			}
+		}
+		// Kong allows a single filter chain per service or route.
+		v.checkDuplicate(seen, serviceID+"|"+routeID, "filter-chain", name)
+	}
+	return nil
+}
+
+func (v *validator) consumerGroupConsumers() error {
+	members, err := v.state.ConsumerGroupConsumers.GetAll()
+	if err != nil {
+		return fmt.Errorf("fetching consumer group consumers from state: %w", err)
+	}
+	for _, m := range members {
+		name := m.Console()
+		if m.ConsumerGroup != nil && !utils.Empty(m.ConsumerGroup.ID) {
+			_, err := v.checkRef("consumer-group-consumer", name, "consumer-group", *m.ConsumerGroup.ID,
+				v.resolveConsumerGroup)
+			if err != nil {
+				return err
+			}
+		}
+		if m.Consumer != nil && !utils.Empty(m.Consumer.ID) {
+			_, err := v.checkRef("consumer-group-consumer", name, "consumer", *m.Consumer.ID, v.resolveConsumer)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (v *validator) consumerGroupPlugins() error {
+	plugins, err := v.state.ConsumerGroupPlugins.GetAll()
+	if err != nil {
+		return fmt.Errorf("fetching consumer group plugins from state: %w", err)
+	}
+	for _, p := range plugins {
+		if p.ConsumerGroup == nil || utils.Empty(p.ConsumerGroup.ID) {
+			continue
+		}
+		_, err := v.checkRef("consumer-group-plugin", p.Console(), "consumer-group", *p.ConsumerGroup.ID,
+			v.resolveConsumerGroup)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *validator) credentials() error {
+	keyAuths, err := v.state.KeyAuths.GetAll()
+	if err != nil {
+		return fmt.Errorf("fetching key-auths from state: %w", err)
+	}
+	for _, c := range keyAuths {
+		if _, err := v.checkCredential("key-auth", c.Console(), c.Consumer); err != nil {
+			return err
+		}
+	}
+	hmacAuths, err := v.state.HMACAuths.GetAll()
+	if err != nil {
+		return fmt.Errorf("fetching hmac-auths from state: %w", err)
+	}
+	for _, c := range hmacAuths {
+		if _, err := v.checkCredential("hmac-auth", c.Console(), c.Consumer); err != nil {
+			return err
+		}
+	}
+	jwtAuths, err := v.state.JWTAuths.GetAll()
+	if err != nil {
+		return fmt.Errorf("fetching jwt-auths from state: %w", err)
+	}
+	for _, c := range jwtAuths {
+		if _, err := v.checkCredential("jwt-auth", c.Console(), c.Consumer); err != nil {
+			return err
+		}
+	}
+	basicAuths, err := v.state.BasicAuths.GetAll()
+	if err != nil {
+		return fmt.Errorf("fetching basic-auths from state: %w", err)
+	}
+	for _, c := range basicAuths {
+		if _, err := v.checkCredential("basic-auth", c.Console(), c.Consumer); err != nil {
+			return err
+		}
+	}
+	oauth2Creds, err := v.state.Oauth2Creds.GetAll()
+	if err != nil {
+		return fmt.Errorf("fetching oauth2-creds from state: %w", err)
+	}
+	for _, c := range oauth2Creds {
+		if _, err := v.checkCredential("oauth2-cred", c.Console(), c.Consumer); err != nil {
+			return err
+		}
+	}
+	mtlsAuths, err := v.state.MTLSAuths.GetAll()
+	if err != nil {
+		return fmt.Errorf("fetching mtls-auths from state: %w", err)
+	}
+	for _, c := range mtlsAuths {
+		if _, err := v.checkCredential("mtls-auth", c.Console(), c.Consumer); err != nil {
+			return err
+		}
+	}
+	aclGroups, err := v.state.ACLGroups.GetAll()
+	if err != nil {
+		return fmt.Errorf("fetching acl-groups from state: %w", err)
+	}
+	seen := map[string]string{}
+	for _, c := range aclGroups {
+		consumerID, err := v.checkCredential("acl-group", c.Console(), c.Consumer)
+		if err != nil {
+			return err
+		}
+		if !utils.Empty(c.Group) {
+			v.checkDuplicate(seen, consumerID+"|"+*c.Group, "acl-group", c.Console())
+		}
+	}
+	return nil
+}
+
+// checkCredential reports the credential as orphaned if its consumer
+// is not present in the state and returns the consumer's ID.
+func (v *validator) checkCredential(kind, entity string, consumer *kong.Consumer) (string, error) {
+	if consumer == nil || utils.Empty(consumer.ID) {
+		v.report(OrphanedCredential, kind, entity, "consumer")
+		return "", nil
+	}
+	resolved, err := v.resolveConsumer(*consumer.ID)
+	if errors.Is(err, ErrNotFound) {
+		v.report(OrphanedCredential, kind, entity, "consumer "+*consumer.ID)
+		return *consumer.ID, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("looking up consumer %q: %w", *consumer.ID, err)
+	}
+	return resolved, nil
+}
+
+func (v *validator) rbacEndpointPermissions() error {
+	permissions, err := v.state.RBACEndpointPermissions.GetAll()
+	if err != nil {
+		return fmt.Errorf("fetching rbac endpoint permissions from state: %w", err)
+	}
+	for _, p := range permissions {
+		if p.Role == nil || utils.Empty(p.Role.ID) {
+			continue
+		}
+		_, err := v.checkRef("rbac-endpoint-permission", p.Console(), "rbac-role", *p.Role.ID, v.resolveRBACRole)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *validator) rbacEntityPermissions() error {
+	permissions, err := v.state.RBACEntityPermissions.GetAll()
+	if err != nil {
+		return fmt.Errorf("fetching rbac entity permissions from state: %w", err)
+	}
+	for _, p := range permissions {
+		if p.Role == nil || utils.Empty(p.Role.ID) {
+			continue
+		}
+		_, err := v.checkRef("rbac-entity-permission", p.Console(), "rbac-role", *p.Role.ID, v.resolveRBACRole)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *validator) rbacUsers() error {
+	users, err := v.state.RBACUsers.GetAll()
+	if err != nil {
+		return fmt.Errorf("fetching rbac users from state: %w", err)
+	}
+	for _, u := range users {
+		for _, role := range u.Roles {
+			if _, err := v.checkRef("rbac-user", u.Console(), "rbac-role", role, v.resolveRBACRole); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (v *validator) admins() error {
+	admins, err := v.state.Admins.GetAll()
+	if err != nil {
+		return fmt.Errorf("fetching admins from state: %w", err)
+	}
+	for _, a := range admins {
+		for _, role := range a.Roles {
+			if _, err := v.checkRef("admin", a.Console(), "rbac-role", role, v.resolveRBACRole); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (v *validator) keys() error {
+	keys, err := v.state.Keys.GetAll()
+	if err != nil {
+		return fmt.Errorf("fetching keys from state: %w", err)
+	}
+	for _, k := range keys {
+		if k.Set == nil || utils.Empty(k.Set.ID) {
+			continue
+		}
+		if _, err := v.checkRef("key", k.Console(), "key-set", *k.Set.ID, v.resolveKeySet); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *validator) customEntities() error {
+	degraphqlRoutes, err := v.state.DegraphqlRoutes.GetAll()
+	if err != nil {
+		return fmt.Errorf("fetching degraphql routes from state: %w", err)
+	}
+	for _, d := range degraphqlRoutes {
+		if d.Service == nil || utils.Empty(d.Service.ID) {
+			continue
+		}
+		_, err := v.checkRef("degraphql_routes", d.Console(), "service", *d.Service.ID, v.resolveService)
+		if err != nil {
+			return err
+		}
+	}
+	decorations, err := v.state.GraphqlRateLimitingCostDecorations.GetAll()
+	if err != nil {
+		return fmt.Errorf("fetching graphql ratelimiting cost decorations from state: %w", err)
+	}
+	for _, d := range decorations {
+		if d.Service == nil || utils.Empty(d.Service.ID) {
+			continue
+		}
+		_, err := v.checkRef("graphql_ratelimiting_cost_decorations", d.Console(), "service", *d.Service.ID,
+			v.resolveService)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
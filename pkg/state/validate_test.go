@@ -0,0 +1,161 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/kong/go-database-reconciler/pkg/utils"
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func integrityErrors(t *testing.T, err error) []*IntegrityError {
+	t.Helper()
+	var errs utils.ErrArray
+	require.ErrorAs(t, err, &errs)
+	res := make([]*IntegrityError, 0, len(errs.Errors))
+	for _, e := range errs.Errors {
+		var integrityErr *IntegrityError
+		require.ErrorAs(t, e, &integrityErr)
+		res = append(res, integrityErr)
+	}
+	return res
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("consistent state", func(t *testing.T) {
+		ks := state()
+		require.NoError(t, ks.Services.Add(Service{Service: kong.Service{
+			ID: new("svc-id"), Name: new("svc"),
+		}}))
+		require.NoError(t, ks.Routes.Add(Route{Route: kong.Route{
+			ID: new("route-id"), Name: new("route"),
+			Service: &kong.Service{ID: new("svc-id")},
+		}}))
+		require.NoError(t, ks.Consumers.Add(Consumer{Consumer: kong.Consumer{
+			ID: new("consumer-id"), Username: new("alice"),
+		}}))
+		require.NoError(t, ks.KeyAuths.Add(KeyAuth{KeyAuth: kong.KeyAuth{
+			ID: new("key-id"), Key: new("secret"),
+			Consumer: &kong.Consumer{ID: new("consumer-id")},
+		}}))
+		require.NoError(t, ks.Plugins.Add(Plugin{Plugin: kong.Plugin{
+			ID: new("plugin-id"), Name: new("key-auth"),
+			Route: &kong.Route{ID: new("route-id")},
+		}}))
+
+		assert.NoError(t, Validate(ks))
+	})
+
+	t.Run("reports dangling references", func(t *testing.T) {
+		ks := state()
+		require.NoError(t, ks.Routes.Add(Route{Route: kong.Route{
+			ID: new("route-id"), Name: new("route"),
+			Service: &kong.Service{ID: new("missing-service")},
+		}}))
+		require.NoError(t, ks.SNIs.Add(SNI{SNI: kong.SNI{
+			ID: new("sni-id"), Name: new("example.com"),
+			Certificate: &kong.Certificate{ID: new("missing-cert")},
+		}}))
+		require.NoError(t, ks.Plugins.Add(Plugin{Plugin: kong.Plugin{
+			ID: new("plugin-id"), Name: new("rate-limiting-advanced"),
+			ConsumerGroup: &kong.ConsumerGroup{ID: new("missing-group")},
+		}}))
+		require.NoError(t, ks.Keys.Add(Key{Key: kong.Key{
+			ID: new("key-id"), Name: new("key"),
+			Set: &kong.KeySet{ID: new("missing-set")},
+		}}))
+
+		errs := integrityErrors(t, Validate(ks))
+		require.Len(t, errs, 4)
+		references := make([]string, 0, len(errs))
+		for _, e := range errs {
+			assert.Equal(t, DanglingReference, e.Kind)
+			references = append(references, e.EntityType+" -> "+e.Reference)
+		}
+		assert.ElementsMatch(t, []string{
+			"route -> service missing-service",
+			"sni -> certificate missing-cert",
+			"plugin -> consumer-group missing-group",
+			"key -> key-set missing-set",
+		}, references)
+	})
+
+	t.Run("reports missing references", func(t *testing.T) {
+		ks := state()
+		// the collections reject these entities, so they are inserted as is.
+		txn := ks.common.db.Txn(true)
+		require.NoError(t, txn.Insert(targetTableName, &Target{Target: kong.Target{
+			ID: new("target-id"), Target: new("10.0.0.1:80"),
+			Upstream: &kong.Upstream{},
+		}}))
+		require.NoError(t, txn.Insert(sniTableName, &SNI{SNI: kong.SNI{
+			ID: new("sni-id"), Name: new("example.com"),
+			Certificate: &kong.Certificate{},
+		}}))
+		txn.Commit()
+
+		errs := integrityErrors(t, Validate(ks))
+		require.Len(t, errs, 2)
+		messages := make([]string, 0, len(errs))
+		for _, e := range errs {
+			assert.Equal(t, MissingReference, e.Kind)
+			messages = append(messages, e.Error())
+		}
+		assert.ElementsMatch(t, []string{
+			"target 10.0.0.1:80 does not reference any upstream",
+			"sni example.com does not reference any certificate",
+		}, messages)
+	})
+
+	t.Run("reports orphaned credentials", func(t *testing.T) {
+		ks := state()
+		require.NoError(t, ks.BasicAuths.Add(BasicAuth{BasicAuth: kong.BasicAuth{
+			ID: new("basic-id"), Username: new("bob"),
+			Consumer: &kong.Consumer{ID: new("missing-consumer")},
+		}}))
+
+		errs := integrityErrors(t, Validate(ks))
+		require.Len(t, errs, 1)
+		assert.Equal(t, OrphanedCredential, errs[0].Kind)
+		assert.Equal(t, "basic-auth", errs[0].EntityType)
+		assert.EqualError(t, errs[0],
+			"basic-auth bob for consumer missing-consumer is orphaned: consumer missing-consumer not found")
+	})
+
+	t.Run("reports duplicate natural keys", func(t *testing.T) {
+		ks := state()
+		require.NoError(t, ks.Upstreams.Add(Upstream{Upstream: kong.Upstream{
+			ID: new("upstream-id"), Name: new("upstream"),
+		}}))
+		require.NoError(t, ks.Services.Add(Service{Service: kong.Service{
+			ID: new("svc-id"), Name: new("svc"),
+		}}))
+		// the same upstream and service are referenced once by ID and once by name
+		for id, upstream := range map[string]string{"target-1": "upstream-id", "target-2": "upstream"} {
+			require.NoError(t, ks.Targets.Add(Target{Target: kong.Target{
+				ID: new(id), Target: new("10.0.0.1:80"),
+				Upstream: &kong.Upstream{ID: new(upstream)},
+			}}))
+		}
+		for id, service := range map[string]string{"plugin-1": "svc-id", "plugin-2": "svc"} {
+			require.NoError(t, ks.Plugins.Add(Plugin{Plugin: kong.Plugin{
+				ID: new(id), Name: new("cors"),
+				Service: &kong.Service{ID: new(service)},
+			}}))
+		}
+
+		errs := integrityErrors(t, Validate(ks))
+		require.Len(t, errs, 2)
+		types := make([]string, 0, len(errs))
+		for _, e := range errs {
+			assert.Equal(t, DuplicateKey, e.Kind)
+			types = append(types, e.EntityType)
+		}
+		assert.ElementsMatch(t, []string{"target", "plugin"}, types)
+	})
+
+	t.Run("nil state", func(t *testing.T) {
+		assert.Error(t, Validate(nil))
+	})
+}
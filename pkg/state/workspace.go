@@ -0,0 +1,171 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/kong/go-database-reconciler/pkg/utils"
+)
+
+const (
+	workspaceTableName = "workspace"
+)
+
+var workspaceTableSchema = &memdb.TableSchema{
+	Name: workspaceTableName,
+	Indexes: map[string]*memdb.IndexSchema{
+		"id": {
+			Name:    "id",
+			Unique:  true,
+			Indexer: &memdb.StringFieldIndex{Field: "ID"},
+		},
+		nameIndex: {
+			Name:    nameIndex,
+			Unique:  true,
+			Indexer: &memdb.StringFieldIndex{Field: nameFieldIndex},
+		},
+		all: allIndex,
+	},
+}
+
+// WorkspacesCollection stores and indexes Kong Enterprise workspaces.
+type WorkspacesCollection collection
+
+// Add adds a workspace to the collection.
+// workspace.ID should not be nil else an error is thrown.
+func (k *WorkspacesCollection) Add(workspace Workspace) error {
+	// TODO abstract this check in the go-memdb library itself
+	if utils.Empty(workspace.ID) {
+		return errIDRequired
+	}
+	txn := k.db.Txn(true)
+	defer txn.Abort()
+
+	var searchBy []string
+	searchBy = append(searchBy, *workspace.ID)
+	if !utils.Empty(workspace.Name) {
+		searchBy = append(searchBy, *workspace.Name)
+	}
+	_, err := getWorkspace(txn, searchBy...)
+	if err == nil {
+		return fmt.Errorf("inserting workspace %v: %w", workspace.Console(), ErrAlreadyExists)
+	} else if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	err = txn.Insert(workspaceTableName, &workspace)
+	if err != nil {
+		return err
+	}
+	txn.Commit()
+	return nil
+}
+
+func getWorkspace(txn *memdb.Txn, IDs ...string) (*Workspace, error) {
+	for _, id := range IDs {
+		res, err := multiIndexLookupUsingTxn(txn, workspaceTableName,
+			[]string{nameIndex, "id"}, id)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		ws, ok := res.(*Workspace)
+		if !ok {
+			panic(unexpectedType)
+		}
+		return &Workspace{Workspace: *ws.DeepCopy()}, nil
+	}
+	return nil, ErrNotFound
+}
+
+// Get gets a workspace by name or ID.
+func (k *WorkspacesCollection) Get(nameOrID string) (*Workspace, error) {
+	if nameOrID == "" {
+		return nil, errIDRequired
+	}
+
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+	return getWorkspace(txn, nameOrID)
+}
+
+// Update updates an existing workspace.
+// It returns an error if the workspace is not already present.
+func (k *WorkspacesCollection) Update(workspace Workspace) error {
+	// TODO abstract this check in the go-memdb library itself
+	if utils.Empty(workspace.ID) {
+		return errIDRequired
+	}
+
+	txn := k.db.Txn(true)
+	defer txn.Abort()
+
+	err := deleteWorkspace(txn, *workspace.ID)
+	if err != nil {
+		return err
+	}
+
+	err = txn.Insert(workspaceTableName, &workspace)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+func deleteWorkspace(txn *memdb.Txn, nameOrID string) error {
+	ws, err := getWorkspace(txn, nameOrID)
+	if err != nil {
+		return err
+	}
+
+	err = txn.Delete(workspaceTableName, ws)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Delete deletes a workspace by name or ID.
+func (k *WorkspacesCollection) Delete(nameOrID string) error {
+	if nameOrID == "" {
+		return errIDRequired
+	}
+
+	txn := k.db.Txn(true)
+	defer txn.Abort()
+
+	err := deleteWorkspace(txn, nameOrID)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// GetAll returns all the workspaces.
+func (k *WorkspacesCollection) GetAll() ([]*Workspace, error) {
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+
+	iter, err := txn.Get(workspaceTableName, all, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*Workspace
+	for el := iter.Next(); el != nil; el = iter.Next() {
+		s, ok := el.(*Workspace)
+		if !ok {
+			panic(unexpectedType)
+		}
+		res = append(res, &Workspace{Workspace: *s.DeepCopy()})
+	}
+	txn.Commit()
+	return res, nil
+}
@@ -0,0 +1,75 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkspacesCollection(t *testing.T) {
+	c := state().Workspaces
+
+	require.ErrorIs(t, c.Add(Workspace{Workspace: kong.Workspace{Name: new("foo")}}), errIDRequired)
+
+	workspace := Workspace{
+		Workspace: kong.Workspace{
+			ID:      new("ws-1"),
+			Name:    new("foo"),
+			Comment: new("team foo"),
+			Meta:    map[string]any{"color": "#ff0000"},
+		},
+	}
+	require.NoError(t, c.Add(workspace))
+	err := c.Add(Workspace{Workspace: kong.Workspace{ID: new("ws-2"), Name: new("foo")}})
+	require.ErrorIs(t, err, ErrAlreadyExists)
+
+	byName, err := c.Get("foo")
+	require.NoError(t, err)
+	byID, err := c.Get("ws-1")
+	require.NoError(t, err)
+	assert.Equal(t, byName, byID)
+	assert.True(t, workspace.Equal(byName))
+
+	// Workspaces returned are copies.
+	byName.Workspace.Meta["color"] = "#00ff00"
+	byID, err = c.Get("foo")
+	require.NoError(t, err)
+	assert.Equal(t, "#ff0000", byID.Workspace.Meta["color"])
+
+	workspace.Comment = new("team bar")
+	require.NoError(t, c.Update(workspace))
+	updated, err := c.Get("ws-1")
+	require.NoError(t, err)
+	assert.Equal(t, "team bar", *updated.Comment)
+
+	require.NoError(t, c.Add(Workspace{Workspace: kong.Workspace{ID: new("ws-2"), Name: new("bar")}}))
+	all, err := c.GetAll()
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	require.NoError(t, c.Delete("foo"))
+	_, err = c.Get("ws-1")
+	require.ErrorIs(t, err, ErrNotFound)
+	require.ErrorIs(t, c.Delete("foo"), ErrNotFound)
+}
+
+func TestWorkspaceEqualWithOpts(t *testing.T) {
+	w1 := &Workspace{Workspace: kong.Workspace{
+		ID:        new("ws-1"),
+		Name:      new("foo"),
+		CreatedAt: new(1),
+		Config:    map[string]any{"portal": true},
+	}}
+	w2 := &Workspace{Workspace: *w1.DeepCopy()}
+	w2.ID = new("ws-2")
+	w2.CreatedAt = new(2)
+
+	assert.False(t, w1.Equal(w2))
+	assert.False(t, w1.EqualWithOpts(w2, true, false))
+	assert.True(t, w1.EqualWithOpts(w2, true, true))
+
+	w2.Config["portal"] = false
+	assert.False(t, w1.EqualWithOpts(w2, true, true))
+}
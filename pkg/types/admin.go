@@ -0,0 +1,214 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/kong/go-database-reconciler/pkg/cprint"
+	"github.com/kong/go-database-reconciler/pkg/crud"
+	"github.com/kong/go-database-reconciler/pkg/state"
+	"github.com/kong/go-kong/kong"
+)
+
+// adminCRUD implements crud.Actions interface.
+// The roles of admins are assigned along with them.
+type adminCRUD struct {
+	client *kong.Client
+}
+
+func adminFromStruct(arg crud.Event) *state.Admin {
+	admin, ok := arg.Obj.(*state.Admin)
+	if !ok {
+		panic("unexpected type, expected *state.Admin")
+	}
+	return admin
+}
+
+// adminForKong returns a copy of admin without the fields Kong does not accept
+// on writes: the credentials of admins are set when they register to Kong
+// Manager.
+func adminForKong(admin *state.Admin) *kong.Admin {
+	res := admin.DeepCopy()
+	res.Password = nil
+	res.Token = nil
+	return res
+}
+
+// Create invites an Admin to Kong Manager and assigns its roles.
+// The arg should be of type crud.Event, containing the admin to be created,
+// else the function will panic.
+// It returns a the created *state.Admin.
+func (s *adminCRUD) Create(ctx context.Context, arg ...crud.Arg) (crud.Arg, error) {
+	event := crud.EventFromArg(arg[0])
+	admin := adminFromStruct(event)
+	createdAdmin, err := s.client.Admins.Create(ctx, adminForKong(admin))
+	if err != nil {
+		return nil, err
+	}
+	if added, _ := roleChanges(nil, admin.Roles); len(added) > 0 {
+		if _, err := s.client.Admins.UpdateRoles(ctx, createdAdmin.ID, added); err != nil {
+			return nil, err
+		}
+	}
+	return &state.Admin{Admin: *createdAdmin, Roles: admin.Roles}, nil
+}
+
+// Delete deletes an Admin in Kong.
+// The arg should be of type crud.Event, containing the admin to be deleted,
+// else the function will panic.
+// It returns a the deleted *state.Admin.
+func (s *adminCRUD) Delete(ctx context.Context, arg ...crud.Arg) (crud.Arg, error) {
+	event := crud.EventFromArg(arg[0])
+	admin := adminFromStruct(event)
+	err := s.client.Admins.Delete(ctx, admin.ID)
+	if err != nil {
+		return nil, err
+	}
+	return admin, nil
+}
+
+// Update updates an Admin in Kong along with its roles.
+// The arg should be of type crud.Event, containing the admin to be updated,
+// else the function will panic.
+// It returns a the updated *state.Admin.
+func (s *adminCRUD) Update(ctx context.Context, arg ...crud.Arg) (crud.Arg, error) {
+	event := crud.EventFromArg(arg[0])
+	admin := adminFromStruct(event)
+
+	updatedAdmin, err := s.client.Admins.Update(ctx, adminForKong(admin))
+	if err != nil {
+		return nil, err
+	}
+
+	var currentRoles []string
+	if oldAdmin, ok := event.OldObj.(*state.Admin); ok {
+		currentRoles = oldAdmin.Roles
+	}
+	added, removed := roleChanges(currentRoles, admin.Roles)
+	if len(added) > 0 {
+		if _, err := s.client.Admins.UpdateRoles(ctx, admin.ID, added); err != nil {
+			return nil, err
+		}
+	}
+	if len(removed) > 0 {
+		if err := s.client.Admins.DeleteRoles(ctx, admin.ID, removed); err != nil {
+			return nil, err
+		}
+	}
+	return &state.Admin{Admin: *updatedAdmin, Roles: admin.Roles}, nil
+}
+
+type adminDiffer struct {
+	kind crud.Kind
+	once sync.Once
+
+	currentState, targetState *state.KongState
+}
+
+func (d *adminDiffer) warnCredentials() {
+	const (
+		adminCredentialsWarning = "Warning: the password and token of admins are not " +
+			"synced: they are set by admins registering to Kong Manager."
+	)
+	d.once.Do(func() {
+		cprint.UpdatePrintlnStdErr(adminCredentialsWarning)
+	})
+}
+
+func (d *adminDiffer) Deletes(handler func(crud.Event) error) error {
+	currentAdmins, err := d.currentState.Admins.GetAll()
+	if err != nil {
+		return fmt.Errorf("error fetching admins from state: %w", err)
+	}
+
+	for _, admin := range currentAdmins {
+		n, err := d.deleteAdmin(admin)
+		if err != nil {
+			return err
+		}
+		if n != nil {
+			err = handler(*n)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (d *adminDiffer) deleteAdmin(admin *state.Admin) (*crud.Event, error) {
+	_, err := d.targetState.Admins.Get(*admin.ID)
+	if errors.Is(err, state.ErrNotFound) {
+		return &crud.Event{
+			Op:   crud.Delete,
+			Kind: d.kind,
+			Obj:  admin,
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("looking up admin %q: %w",
+			admin.Identifier(), err)
+	}
+	return nil, nil
+}
+
+func (d *adminDiffer) CreateAndUpdates(handler func(crud.Event) error) error {
+	targetAdmins, err := d.targetState.Admins.GetAll()
+	if err != nil {
+		return fmt.Errorf("error fetching admins from state: %w", err)
+	}
+
+	for _, admin := range targetAdmins {
+		n, err := d.createUpdateAdmin(admin)
+		if err != nil {
+			return err
+		}
+		if n != nil {
+			err = handler(*n)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (d *adminDiffer) createUpdateAdmin(admin *state.Admin) (*crud.Event, error) {
+	if admin.Password != nil || admin.Token != nil {
+		d.warnCredentials()
+	}
+	adminCopy := &state.Admin{Admin: *admin.DeepCopy(), Roles: slices.Clone(admin.Roles)}
+	currentAdmin, err := d.currentState.Admins.Get(*admin.ID)
+	if errors.Is(err, state.ErrNotFound) {
+		return &crud.Event{
+			Op:   crud.Create,
+			Kind: d.kind,
+			Obj:  adminCopy,
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error looking up admin %q: %w",
+			admin.Identifier(), err)
+	}
+
+	// the status of admins is maintained by Kong as they register, and
+	// their RBAC token is enabled unless stated otherwise
+	if adminCopy.Status == nil {
+		adminCopy.Status = currentAdmin.Status
+	}
+	if adminCopy.RBACTokenEnabled == nil {
+		adminCopy.RBACTokenEnabled = currentAdmin.RBACTokenEnabled
+	}
+	if !currentAdmin.EqualWithOpts(adminCopy, false, true) {
+		return &crud.Event{
+			Op:     crud.Update,
+			Kind:   d.kind,
+			Obj:    adminCopy,
+			OldObj: currentAdmin,
+		}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,160 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kong/go-database-reconciler/pkg/crud"
+	"github.com/kong/go-database-reconciler/pkg/konnect"
+	"github.com/kong/go-database-reconciler/pkg/state"
+)
+
+// apiProductCRUD implements crud.Actions interface.
+type apiProductCRUD struct {
+	client *konnect.Client
+}
+
+func apiProductFromStruct(arg crud.Event) *state.APIProduct {
+	product, ok := arg.Obj.(*state.APIProduct)
+	if !ok {
+		panic("unexpected type, expected *state.APIProduct")
+	}
+	return product
+}
+
+// Create creates an API product in Konnect.
+// The arg should be of type crud.Event, containing the API product to be created,
+// else the function will panic.
+// It returns the created *state.APIProduct.
+func (s *apiProductCRUD) Create(ctx context.Context, arg ...crud.Arg) (crud.Arg, error) {
+	event := crud.EventFromArg(arg[0])
+	product := apiProductFromStruct(event)
+	created, err := s.client.APIProducts.Create(ctx, &product.APIProduct)
+	if err != nil {
+		return nil, err
+	}
+	return &state.APIProduct{APIProduct: *created}, nil
+}
+
+// Delete deletes an API product in Konnect.
+// The arg should be of type crud.Event, containing the API product to be deleted,
+// else the function will panic.
+// It returns the deleted *state.APIProduct.
+func (s *apiProductCRUD) Delete(ctx context.Context, arg ...crud.Arg) (crud.Arg, error) {
+	event := crud.EventFromArg(arg[0])
+	product := apiProductFromStruct(event)
+	err := s.client.APIProducts.Delete(ctx, product.ID)
+	if err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// Update updates an API product in Konnect.
+// The arg should be of type crud.Event, containing the API product to be updated,
+// else the function will panic.
+// It returns the updated *state.APIProduct.
+func (s *apiProductCRUD) Update(ctx context.Context, arg ...crud.Arg) (crud.Arg, error) {
+	event := crud.EventFromArg(arg[0])
+	product := apiProductFromStruct(event)
+
+	updated, err := s.client.APIProducts.Update(ctx, &product.APIProduct)
+	if err != nil {
+		return nil, err
+	}
+	return &state.APIProduct{APIProduct: *updated}, nil
+}
+
+type apiProductDiffer struct {
+	kind crud.Kind
+
+	currentState, targetState *state.KongState
+}
+
+func (d *apiProductDiffer) Deletes(handler func(crud.Event) error) error {
+	currentAPIProducts, err := d.currentState.APIProducts.GetAll()
+	if err != nil {
+		return fmt.Errorf("error fetching API products from state: %w", err)
+	}
+
+	for _, product := range currentAPIProducts {
+		n, err := d.deleteAPIProduct(product)
+		if err != nil {
+			return err
+		}
+		if n != nil {
+			err = handler(*n)
+			if err != nil {
+				return err
+			}
+		}
+
+	}
+	return nil
+}
+
+func (d *apiProductDiffer) deleteAPIProduct(product *state.APIProduct) (*crud.Event, error) {
+	_, err := d.targetState.APIProducts.Get(*product.ID)
+	if errors.Is(err, state.ErrNotFound) {
+		return &crud.Event{
+			Op:   crud.Delete,
+			Kind: d.kind,
+			Obj:  product,
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("looking up API product %q: %w",
+			product.Identifier(), err)
+	}
+	return nil, nil
+}
+
+func (d *apiProductDiffer) CreateAndUpdates(handler func(crud.Event) error) error {
+	targetAPIProducts, err := d.targetState.APIProducts.GetAll()
+	if err != nil {
+		return fmt.Errorf("error fetching API products from state: %w", err)
+	}
+
+	for _, product := range targetAPIProducts {
+		n, err := d.createUpdateAPIProduct(product)
+		if err != nil {
+			return err
+		}
+		if n != nil {
+			err = handler(*n)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (d *apiProductDiffer) createUpdateAPIProduct(product *state.APIProduct) (*crud.Event, error) {
+	target := &state.APIProduct{APIProduct: *product.DeepCopy()}
+	current, err := d.currentState.APIProducts.Get(*product.ID)
+
+	if errors.Is(err, state.ErrNotFound) {
+		return &crud.Event{
+			Op:   crud.Create,
+			Kind: d.kind,
+			Obj:  target,
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error looking up API product %q: %w",
+			product.Identifier(), err)
+	}
+
+	// found, check if update needed
+	if !current.EqualWithOpts(target, false, true) {
+		return &crud.Event{
+			Op:     crud.Update,
+			Kind:   d.kind,
+			Obj:    target,
+			OldObj: current,
+		}, nil
+	}
+	return nil, nil
+}
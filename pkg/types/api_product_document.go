@@ -0,0 +1,160 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kong/go-database-reconciler/pkg/crud"
+	"github.com/kong/go-database-reconciler/pkg/konnect"
+	"github.com/kong/go-database-reconciler/pkg/state"
+)
+
+// apiProductDocumentCRUD implements crud.Actions interface.
+type apiProductDocumentCRUD struct {
+	client *konnect.Client
+}
+
+func apiProductDocumentFromStruct(arg crud.Event) *state.APIProductDocument {
+	document, ok := arg.Obj.(*state.APIProductDocument)
+	if !ok {
+		panic("unexpected type, expected *state.APIProductDocument")
+	}
+	return document
+}
+
+// Create creates a document of an API product in Konnect.
+// The arg should be of type crud.Event, containing the document to be created,
+// else the function will panic.
+// It returns the created *state.APIProductDocument.
+func (s *apiProductDocumentCRUD) Create(ctx context.Context, arg ...crud.Arg) (crud.Arg, error) {
+	event := crud.EventFromArg(arg[0])
+	document := apiProductDocumentFromStruct(event)
+	created, err := s.client.APIProductDocuments.Create(ctx, &document.APIProductDocument)
+	if err != nil {
+		return nil, err
+	}
+	return &state.APIProductDocument{APIProductDocument: *created}, nil
+}
+
+// Delete deletes a document of an API product in Konnect.
+// The arg should be of type crud.Event, containing the document to be deleted,
+// else the function will panic.
+// It returns the deleted *state.APIProductDocument.
+func (s *apiProductDocumentCRUD) Delete(ctx context.Context, arg ...crud.Arg) (crud.Arg, error) {
+	event := crud.EventFromArg(arg[0])
+	document := apiProductDocumentFromStruct(event)
+	err := s.client.APIProductDocuments.Delete(ctx, &document.APIProductDocument)
+	if err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+// Update updates a document of an API product in Konnect.
+// The arg should be of type crud.Event, containing the document to be updated,
+// else the function will panic.
+// It returns the updated *state.APIProductDocument.
+func (s *apiProductDocumentCRUD) Update(ctx context.Context, arg ...crud.Arg) (crud.Arg, error) {
+	event := crud.EventFromArg(arg[0])
+	document := apiProductDocumentFromStruct(event)
+	updated, err := s.client.APIProductDocuments.Update(ctx, &document.APIProductDocument)
+	if err != nil {
+		return nil, err
+	}
+	return &state.APIProductDocument{APIProductDocument: *updated}, nil
+}
+
+type apiProductDocumentDiffer struct {
+	kind crud.Kind
+
+	currentState, targetState *state.KongState
+}
+
+func (d *apiProductDocumentDiffer) Deletes(handler func(crud.Event) error) error {
+	currentDocuments, err := d.currentState.APIProductDocuments.GetAll()
+	if err != nil {
+		return fmt.Errorf("error fetching API product documents from state: %w", err)
+	}
+
+	for _, document := range currentDocuments {
+		n, err := d.deleteAPIProductDocument(document)
+		if err != nil {
+			return err
+		}
+		if n != nil {
+			err = handler(*n)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (d *apiProductDocumentDiffer) deleteAPIProductDocument(document *state.APIProductDocument) (*crud.Event, error) {
+	_, err := d.targetState.APIProductDocuments.Get(*document.APIProduct.ID, *document.ID)
+	if errors.Is(err, state.ErrNotFound) {
+		return &crud.Event{
+			Op:   crud.Delete,
+			Kind: d.kind,
+			Obj:  document,
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("looking up API product document %q: %w",
+			document.Identifier(), err)
+	}
+	return nil, nil
+}
+
+func (d *apiProductDocumentDiffer) CreateAndUpdates(handler func(crud.Event) error) error {
+	targetDocuments, err := d.targetState.APIProductDocuments.GetAll()
+	if err != nil {
+		return fmt.Errorf("error fetching API product documents from state: %w", err)
+	}
+
+	for _, document := range targetDocuments {
+		n, err := d.createUpdateAPIProductDocument(document)
+		if err != nil {
+			return err
+		}
+		if n != nil {
+			err = handler(*n)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (d *apiProductDocumentDiffer) createUpdateAPIProductDocument(document *state.APIProductDocument) (*crud.Event,
+	error,
+) {
+	target := &state.APIProductDocument{APIProductDocument: *document.DeepCopy()}
+	current, err := d.currentState.APIProductDocuments.Get(*document.APIProduct.ID, *document.ID)
+
+	if errors.Is(err, state.ErrNotFound) {
+		return &crud.Event{
+			Op:   crud.Create,
+			Kind: d.kind,
+			Obj:  target,
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error looking up API product document %q: %w",
+			document.Identifier(), err)
+	}
+
+	// found, check if update needed
+	if !current.EqualWithOpts(target, false, true, true) {
+		return &crud.Event{
+			Op:     crud.Update,
+			Kind:   d.kind,
+			Obj:    target,
+			OldObj: current,
+		}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,167 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kong/go-database-reconciler/pkg/crud"
+	"github.com/kong/go-database-reconciler/pkg/konnect"
+	"github.com/kong/go-database-reconciler/pkg/state"
+	"github.com/kong/go-database-reconciler/pkg/utils"
+)
+
+// apiProductVersionCRUD implements crud.Actions interface.
+type apiProductVersionCRUD struct {
+	client *konnect.Client
+}
+
+func apiProductVersionFromStruct(arg crud.Event) *state.APIProductVersion {
+	version, ok := arg.Obj.(*state.APIProductVersion)
+	if !ok {
+		panic("unexpected type, expected *state.APIProductVersion")
+	}
+	return version
+}
+
+// Create creates a version of an API product in Konnect.
+// The arg should be of type crud.Event, containing the version to be created,
+// else the function will panic.
+// It returns the created *state.APIProductVersion.
+func (s *apiProductVersionCRUD) Create(ctx context.Context, arg ...crud.Arg) (crud.Arg, error) {
+	event := crud.EventFromArg(arg[0])
+	version := apiProductVersionFromStruct(event)
+	created, err := s.client.APIProductVersions.Create(ctx, &version.APIProductVersion)
+	if err != nil {
+		return nil, err
+	}
+	return &state.APIProductVersion{APIProductVersion: *created}, nil
+}
+
+// Delete deletes a version of an API product in Konnect.
+// The arg should be of type crud.Event, containing the version to be deleted,
+// else the function will panic.
+// It returns the deleted *state.APIProductVersion.
+func (s *apiProductVersionCRUD) Delete(ctx context.Context, arg ...crud.Arg) (crud.Arg, error) {
+	event := crud.EventFromArg(arg[0])
+	version := apiProductVersionFromStruct(event)
+	err := s.client.APIProductVersions.Delete(ctx, &version.APIProductVersion)
+	if err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// Update updates a version of an API product in Konnect.
+// The arg should be of type crud.Event, containing the version to be updated,
+// else the function will panic.
+// It returns the updated *state.APIProductVersion.
+func (s *apiProductVersionCRUD) Update(ctx context.Context, arg ...crud.Arg) (crud.Arg, error) {
+	event := crud.EventFromArg(arg[0])
+	version := apiProductVersionFromStruct(event)
+	updated, err := s.client.APIProductVersions.Update(ctx, &version.APIProductVersion)
+	if err != nil {
+		return nil, err
+	}
+	return &state.APIProductVersion{APIProductVersion: *updated}, nil
+}
+
+type apiProductVersionDiffer struct {
+	kind crud.Kind
+
+	currentState, targetState *state.KongState
+}
+
+func (d *apiProductVersionDiffer) Deletes(handler func(crud.Event) error) error {
+	currentVersions, err := d.currentState.APIProductVersions.GetAll()
+	if err != nil {
+		return fmt.Errorf("error fetching API product versions from state: %w", err)
+	}
+
+	for _, version := range currentVersions {
+		n, err := d.deleteAPIProductVersion(version)
+		if err != nil {
+			return err
+		}
+		if n != nil {
+			err = handler(*n)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (d *apiProductVersionDiffer) deleteAPIProductVersion(version *state.APIProductVersion) (*crud.Event, error) {
+	_, err := d.targetState.APIProductVersions.Get(*version.APIProduct.ID, *version.ID)
+	if errors.Is(err, state.ErrNotFound) {
+		return &crud.Event{
+			Op:   crud.Delete,
+			Kind: d.kind,
+			Obj:  version,
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("looking up API product version %q: %w",
+			version.Identifier(), err)
+	}
+	return nil, nil
+}
+
+func (d *apiProductVersionDiffer) CreateAndUpdates(handler func(crud.Event) error) error {
+	targetVersions, err := d.targetState.APIProductVersions.GetAll()
+	if err != nil {
+		return fmt.Errorf("error fetching API product versions from state: %w", err)
+	}
+
+	for _, version := range targetVersions {
+		n, err := d.createUpdateAPIProductVersion(version)
+		if err != nil {
+			return err
+		}
+		if n != nil {
+			err = handler(*n)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (d *apiProductVersionDiffer) createUpdateAPIProductVersion(version *state.APIProductVersion) (*crud.Event,
+	error,
+) {
+	target := &state.APIProductVersion{APIProductVersion: *version.DeepCopy()}
+	current, err := d.currentState.APIProductVersions.Get(*version.APIProduct.ID, *version.ID)
+
+	if errors.Is(err, state.ErrNotFound) {
+		return &crud.Event{
+			Op:   crud.Create,
+			Kind: d.kind,
+			Obj:  target,
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error looking up API product version %q: %w",
+			version.Identifier(), err)
+	}
+
+	// the control plane of the service defaults to the one being synced
+	if target.GatewayService != nil && current.GatewayService != nil &&
+		utils.Empty(target.GatewayService.ControlPlaneID) {
+		target.GatewayService.ControlPlaneID = current.GatewayService.ControlPlaneID
+	}
+
+	// found, check if update needed
+	if !current.EqualWithOpts(target, false, true, true) {
+		return &crud.Event{
+			Op:     crud.Update,
+			Kind:   d.kind,
+			Obj:    target,
+			OldObj: current,
+		}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,298 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/kong/go-database-reconciler/pkg/crud"
+	"github.com/kong/go-database-reconciler/pkg/konnect"
+	"github.com/kong/go-database-reconciler/pkg/state"
+)
+
+// controlPlaneCRUD implements crud.Actions interface.
+type controlPlaneCRUD struct {
+	client *konnect.Client
+}
+
+func controlPlaneFromStruct(arg crud.Event) *state.ControlPlane {
+	cp, ok := arg.Obj.(*state.ControlPlane)
+	if !ok {
+		panic("unexpected type, expected *state.ControlPlane")
+	}
+	return cp
+}
+
+// Create creates a control plane in Konnect.
+// The arg should be of type crud.Event, containing the control plane to be created,
+// else the function will panic.
+// It returns the created *state.ControlPlane.
+func (s *controlPlaneCRUD) Create(ctx context.Context, arg ...crud.Arg) (crud.Arg, error) {
+	event := crud.EventFromArg(arg[0])
+	cp := controlPlaneFromStruct(event)
+	created, err := s.client.ControlPlanesV2.Create(ctx, &cp.ControlPlaneV2)
+	if err != nil {
+		return nil, err
+	}
+	return &state.ControlPlane{ControlPlaneV2: *created}, nil
+}
+
+// Delete deletes a control plane in Konnect.
+// The arg should be of type crud.Event, containing the control plane to be deleted,
+// else the function will panic.
+// It returns the deleted *state.ControlPlane.
+func (s *controlPlaneCRUD) Delete(ctx context.Context, arg ...crud.Arg) (crud.Arg, error) {
+	event := crud.EventFromArg(arg[0])
+	cp := controlPlaneFromStruct(event)
+	err := s.client.ControlPlanesV2.Delete(ctx, cp.ID)
+	if err != nil {
+		return nil, err
+	}
+	return cp, nil
+}
+
+// Update updates a control plane in Konnect.
+// The arg should be of type crud.Event, containing the control plane to be updated,
+// else the function will panic.
+// It returns the updated *state.ControlPlane.
+func (s *controlPlaneCRUD) Update(ctx context.Context, arg ...crud.Arg) (crud.Arg, error) {
+	event := crud.EventFromArg(arg[0])
+	cp := controlPlaneFromStruct(event)
+
+	updated, err := s.client.ControlPlanesV2.Update(ctx, &cp.ControlPlaneV2)
+	if err != nil {
+		return nil, err
+	}
+	// members are not returned by Konnect and are synced separately
+	updated.Members = cp.Members
+	return &state.ControlPlane{ControlPlaneV2: *updated}, nil
+}
+
+type controlPlaneDiffer struct {
+	kind crud.Kind
+
+	currentState, targetState *state.KongState
+}
+
+func (d *controlPlaneDiffer) Deletes(handler func(crud.Event) error) error {
+	currentControlPlanes, err := d.currentState.ControlPlanes.GetAll()
+	if err != nil {
+		return fmt.Errorf("error fetching control planes from state: %w", err)
+	}
+
+	for _, cp := range currentControlPlanes {
+		n, err := d.deleteControlPlane(cp)
+		if err != nil {
+			return err
+		}
+		if n != nil {
+			err = handler(*n)
+			if err != nil {
+				return err
+			}
+		}
+
+	}
+	return nil
+}
+
+func (d *controlPlaneDiffer) deleteControlPlane(cp *state.ControlPlane) (*crud.Event, error) {
+	_, err := d.targetState.ControlPlanes.Get(*cp.ID)
+	if errors.Is(err, state.ErrNotFound) {
+		return &crud.Event{
+			Op:   crud.Delete,
+			Kind: d.kind,
+			Obj:  cp,
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("looking up control plane %q: %w",
+			cp.Identifier(), err)
+	}
+	return nil, nil
+}
+
+func (d *controlPlaneDiffer) CreateAndUpdates(handler func(crud.Event) error) error {
+	targetControlPlanes, err := d.targetState.ControlPlanes.GetAll()
+	if err != nil {
+		return fmt.Errorf("error fetching control planes from state: %w", err)
+	}
+
+	for _, cp := range targetControlPlanes {
+		n, err := d.createUpdateControlPlane(cp)
+		if err != nil {
+			return err
+		}
+		if n != nil {
+			err = handler(*n)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (d *controlPlaneDiffer) createUpdateControlPlane(cp *state.ControlPlane) (*crud.Event, error) {
+	target := &state.ControlPlane{ControlPlaneV2: *cp.DeepCopy()}
+	current, err := d.currentState.ControlPlanes.Get(*cp.ID)
+
+	if errors.Is(err, state.ErrNotFound) {
+		// members of groups are added once all control planes exist
+		target.Members = nil
+		return &crud.Event{
+			Op:   crud.Create,
+			Kind: d.kind,
+			Obj:  target,
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error looking up control plane %q: %w",
+			cp.Identifier(), err)
+	}
+	target.Members = current.Members
+
+	// found, check if update needed
+	if !current.EqualWithOpts(target, false, true) {
+		return &crud.Event{
+			Op:     crud.Update,
+			Kind:   d.kind,
+			Obj:    target,
+			OldObj: current,
+		}, nil
+	}
+	return nil, nil
+}
+
+// controlPlaneGroupMembershipCRUD implements crud.Actions interface.
+type controlPlaneGroupMembershipCRUD struct {
+	client *konnect.Client
+}
+
+func controlPlaneGroupMembershipFromStruct(arg crud.Event) (*state.ControlPlaneGroupMembership,
+	*state.ControlPlaneGroupMembership,
+) {
+	membership, ok := arg.Obj.(*state.ControlPlaneGroupMembership)
+	if !ok {
+		panic("unexpected type, expected *state.ControlPlaneGroupMembership")
+	}
+	oldMembership, _ := arg.OldObj.(*state.ControlPlaneGroupMembership)
+	if oldMembership == nil {
+		oldMembership = &state.ControlPlaneGroupMembership{}
+	}
+	return membership, oldMembership
+}
+
+// Create adds the members of a control plane group in Konnect.
+// The arg should be of type crud.Event, containing the membership to be
+// created, else the function will panic.
+// It returns the created *state.ControlPlaneGroupMembership.
+func (s *controlPlaneGroupMembershipCRUD) Create(ctx context.Context, arg ...crud.Arg) (crud.Arg, error) {
+	membership, _ := controlPlaneGroupMembershipFromStruct(crud.EventFromArg(arg[0]))
+	err := s.client.ControlPlanesV2.AddGroupMembers(ctx, *membership.GroupID, membership.Members)
+	if err != nil {
+		return nil, err
+	}
+	return membership, nil
+}
+
+// Delete removes the members of a control plane group in Konnect.
+// The arg should be of type crud.Event, containing the membership to be
+// deleted, else the function will panic.
+// It returns the deleted *state.ControlPlaneGroupMembership.
+func (s *controlPlaneGroupMembershipCRUD) Delete(ctx context.Context, arg ...crud.Arg) (crud.Arg, error) {
+	membership, _ := controlPlaneGroupMembershipFromStruct(crud.EventFromArg(arg[0]))
+	err := s.client.ControlPlanesV2.RemoveGroupMembers(ctx, *membership.GroupID, membership.Members)
+	if err != nil {
+		return nil, err
+	}
+	return membership, nil
+}
+
+// Update adds and removes members of a control plane group in Konnect so
+// that they match the membership.
+// The arg should be of type crud.Event, containing the membership to be
+// updated, else the function will panic.
+// It returns the updated *state.ControlPlaneGroupMembership.
+func (s *controlPlaneGroupMembershipCRUD) Update(ctx context.Context, arg ...crud.Arg) (crud.Arg, error) {
+	membership, oldMembership := controlPlaneGroupMembershipFromStruct(crud.EventFromArg(arg[0]))
+	err := s.client.ControlPlanesV2.RemoveGroupMembers(ctx, *membership.GroupID,
+		subtract(oldMembership.Members, membership.Members))
+	if err != nil {
+		return nil, err
+	}
+	err = s.client.ControlPlanesV2.AddGroupMembers(ctx, *membership.GroupID,
+		subtract(membership.Members, oldMembership.Members))
+	if err != nil {
+		return nil, err
+	}
+	return membership, nil
+}
+
+// subtract returns the elements of a missing from b.
+func subtract(a, b []string) []string {
+	var res []string
+	for _, s := range a {
+		if !slices.Contains(b, s) {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+type controlPlaneGroupMembershipDiffer struct {
+	kind crud.Kind
+
+	currentState, targetState *state.KongState
+}
+
+// Deletes generates no event: memberships are deleted along with their group.
+func (d *controlPlaneGroupMembershipDiffer) Deletes(func(crud.Event) error) error {
+	return nil
+}
+
+func (d *controlPlaneGroupMembershipDiffer) CreateAndUpdates(handler func(crud.Event) error) error {
+	targetControlPlanes, err := d.targetState.ControlPlanes.GetAll()
+	if err != nil {
+		return fmt.Errorf("error fetching control planes from state: %w", err)
+	}
+
+	for _, cp := range targetControlPlanes {
+		if !cp.IsGroup() {
+			continue
+		}
+		current, err := d.currentState.ControlPlanes.Get(*cp.ID)
+		if errors.Is(err, state.ErrNotFound) {
+			// the group could not be created
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("error looking up control plane %q: %w",
+				cp.Identifier(), err)
+		}
+		target := &state.ControlPlane{ControlPlaneV2: *current.DeepCopy()}
+		target.Members = cp.Members
+		if current.EqualWithOpts(target, false, true) {
+			continue
+		}
+		err = handler(crud.Event{
+			Op:   crud.Update,
+			Kind: d.kind,
+			Obj: &state.ControlPlaneGroupMembership{
+				GroupID:   cp.ID,
+				GroupName: cp.Name,
+				Members:   cp.Members,
+			},
+			OldObj: &state.ControlPlaneGroupMembership{
+				GroupID:   current.ID,
+				GroupName: current.Name,
+				Members:   current.Members,
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -117,6 +117,12 @@ const (
 	RBACRole EntityType = "rbac-role"
 	// RBACEndpointPermission identifies a RBACEndpointPermission in Kong Enterprise.
 	RBACEndpointPermission EntityType = "rbac-endpoint-permission"
+	// RBACEntityPermission identifies a RBACEntityPermission in Kong Enterprise.
+	RBACEntityPermission EntityType = "rbac-entity-permission"
+	// RBACUser identifies a RBACUser in Kong Enterprise.
+	RBACUser EntityType = "rbac-user"
+	// Admin identifies a Kong Manager Admin in Kong Enterprise.
+	Admin EntityType = "admin"
 
 	// ServicePackage identifies a ServicePackage in Konnect.
 	ServicePackage EntityType = "service-package"
@@ -125,10 +131,28 @@ const (
 	// Document identifies a Document in Konnect.
 	Document EntityType = "document"
 
+	// APIProduct identifies an APIProduct in Konnect.
+	APIProduct EntityType = "api-product"
+	// APIProductVersion identifies an APIProductVersion in Konnect.
+	APIProductVersion EntityType = "api-product-version"
+	// APIProductDocument identifies an APIProductDocument in Konnect.
+	APIProductDocument EntityType = "api-product-document"
+	// Portal identifies a Portal in Konnect.
+	Portal EntityType = "portal"
+	// ControlPlane identifies a ControlPlane in Konnect.
+	ControlPlane EntityType = "control-plane"
+	// ControlPlaneGroupMembership identifies the members of a control plane
+	// group in Konnect.
+	ControlPlaneGroupMembership EntityType = "control-plane-group-membership"
+
 	// Vault identifies a Vault in Kong.
 	Vault EntityType = "vault"
 	// License identifies a License in Kong Enterprise.
 	License EntityType = "license"
+	// Workspace identifies a Workspace in Kong Enterprise.
+	Workspace EntityType = "workspace"
+	// EventHook identifies an EventHook in Kong Enterprise.
+	EventHook EntityType = "event-hook"
 
 	// FilterChain identifies a FilterChain in Kong.
 	FilterChain EntityType = "filter-chain"
@@ -151,6 +175,10 @@ const (
 
 	// CustomPluginDefinition identifies a CustomPluginDefinition in Kong.
 	CustomPluginDefinition EntityType = "custom-plugin"
+
+	// CustomEntity identifies an entity of a custom DAO in Kong, handled
+	// generically whatever its type.
+	CustomEntity EntityType = "custom-entity"
 )
 
 // AllTypes represents all types defined in the
@@ -168,11 +196,14 @@ var AllTypes = []EntityType{
 	HMACAuth, JWTAuth, OAuth2Cred,
 	MTLSAuth,
 
-	RBACRole, RBACEndpointPermission,
+	RBACRole, RBACEndpointPermission, RBACEntityPermission,
+	RBACUser, Admin,
 
 	ServicePackage, ServiceVersion, Document,
+	APIProduct, APIProductVersion, APIProductDocument, Portal,
+	ControlPlane, ControlPlaneGroupMembership,
 
-	Vault, License,
+	Vault, License, Workspace, EventHook,
 
 	FilterChain,
 
@@ -187,6 +218,8 @@ var AllTypes = []EntityType{
 	ClonedPluginDefinition,
 
 	CustomPluginDefinition,
+
+	CustomEntity,
 }
 
 func entityTypeToKind(t EntityType) crud.Kind {
@@ -383,6 +416,96 @@ func NewEntity(t EntityType, opts EntityOpts) (Entity, error) {
 				targetState:  opts.TargetState,
 			},
 		}, nil
+	case APIProduct:
+		return entityImpl{
+			typ: APIProduct,
+			crudActions: &apiProductCRUD{
+				client: opts.KonnectClient,
+			},
+			postProcessActions: &apiProductPostAction{
+				currentState: opts.CurrentState,
+			},
+			differ: &apiProductDiffer{
+				kind:         entityTypeToKind(APIProduct),
+				currentState: opts.CurrentState,
+				targetState:  opts.TargetState,
+			},
+		}, nil
+	case APIProductVersion:
+		return entityImpl{
+			typ: APIProductVersion,
+			crudActions: &apiProductVersionCRUD{
+				client: opts.KonnectClient,
+			},
+			postProcessActions: &apiProductVersionPostAction{
+				currentState: opts.CurrentState,
+			},
+			differ: &apiProductVersionDiffer{
+				kind:         entityTypeToKind(APIProductVersion),
+				currentState: opts.CurrentState,
+				targetState:  opts.TargetState,
+			},
+		}, nil
+	case APIProductDocument:
+		return entityImpl{
+			typ: APIProductDocument,
+			crudActions: &apiProductDocumentCRUD{
+				client: opts.KonnectClient,
+			},
+			postProcessActions: &apiProductDocumentPostAction{
+				currentState: opts.CurrentState,
+			},
+			differ: &apiProductDocumentDiffer{
+				kind:         entityTypeToKind(APIProductDocument),
+				currentState: opts.CurrentState,
+				targetState:  opts.TargetState,
+			},
+		}, nil
+	case Portal:
+		return entityImpl{
+			typ: Portal,
+			crudActions: &portalCRUD{
+				client: opts.KonnectClient,
+			},
+			postProcessActions: &portalPostAction{
+				currentState: opts.CurrentState,
+			},
+			differ: &portalDiffer{
+				kind:         entityTypeToKind(Portal),
+				currentState: opts.CurrentState,
+				targetState:  opts.TargetState,
+			},
+		}, nil
+	case ControlPlane:
+		return entityImpl{
+			typ: ControlPlane,
+			crudActions: &controlPlaneCRUD{
+				client: opts.KonnectClient,
+			},
+			postProcessActions: &controlPlanePostAction{
+				currentState: opts.CurrentState,
+			},
+			differ: &controlPlaneDiffer{
+				kind:         entityTypeToKind(ControlPlane),
+				currentState: opts.CurrentState,
+				targetState:  opts.TargetState,
+			},
+		}, nil
+	case ControlPlaneGroupMembership:
+		return entityImpl{
+			typ: ControlPlaneGroupMembership,
+			crudActions: &controlPlaneGroupMembershipCRUD{
+				client: opts.KonnectClient,
+			},
+			postProcessActions: &controlPlaneGroupMembershipPostAction{
+				currentState: opts.CurrentState,
+			},
+			differ: &controlPlaneGroupMembershipDiffer{
+				kind:         entityTypeToKind(ControlPlaneGroupMembership),
+				currentState: opts.CurrentState,
+				targetState:  opts.TargetState,
+			},
+		}, nil
 	case Certificate:
 		return entityImpl{
 			typ: Certificate,
@@ -446,6 +569,51 @@ func NewEntity(t EntityType, opts EntityOpts) (Entity, error) {
 				targetState:  opts.TargetState,
 			},
 		}, nil
+	case RBACEntityPermission:
+		return entityImpl{
+			typ: RBACEntityPermission,
+			crudActions: &rbacEntityPermissionCRUD{
+				client: opts.KongClient,
+			},
+			postProcessActions: &rbacEntityPermissionPostAction{
+				currentState: opts.CurrentState,
+			},
+			differ: &rbacEntityPermissionDiffer{
+				kind:         entityTypeToKind(RBACEntityPermission),
+				currentState: opts.CurrentState,
+				targetState:  opts.TargetState,
+			},
+		}, nil
+	case RBACUser:
+		return entityImpl{
+			typ: RBACUser,
+			crudActions: &rbacUserCRUD{
+				client: opts.KongClient,
+			},
+			postProcessActions: &rbacUserPostAction{
+				currentState: opts.CurrentState,
+			},
+			differ: &rbacUserDiffer{
+				kind:         entityTypeToKind(RBACUser),
+				currentState: opts.CurrentState,
+				targetState:  opts.TargetState,
+			},
+		}, nil
+	case Admin:
+		return entityImpl{
+			typ: Admin,
+			crudActions: &adminCRUD{
+				client: opts.KongClient,
+			},
+			postProcessActions: &adminPostAction{
+				currentState: opts.CurrentState,
+			},
+			differ: &adminDiffer{
+				kind:         entityTypeToKind(Admin),
+				currentState: opts.CurrentState,
+				targetState:  opts.TargetState,
+			},
+		}, nil
 	case RBACRole:
 		return entityImpl{
 			typ: RBACRole,
@@ -597,6 +765,38 @@ func NewEntity(t EntityType, opts EntityOpts) (Entity, error) {
 				targetState:  opts.TargetState,
 			},
 		}, nil
+	case Workspace:
+		return entityImpl{
+			typ: Workspace,
+			crudActions: &workspaceCRUD{
+				client:    opts.KongClient,
+				isKonnect: opts.IsKonnect,
+			},
+			postProcessActions: &workspacePostAction{
+				currentState: opts.CurrentState,
+			},
+			differ: &workspaceDiffer{
+				kind:         entityTypeToKind(Workspace),
+				currentState: opts.CurrentState,
+				targetState:  opts.TargetState,
+			},
+		}, nil
+	case EventHook:
+		return entityImpl{
+			typ: EventHook,
+			crudActions: &eventHookCRUD{
+				client:    opts.KongClient,
+				isKonnect: opts.IsKonnect,
+			},
+			postProcessActions: &eventHookPostAction{
+				currentState: opts.CurrentState,
+			},
+			differ: &eventHookDiffer{
+				kind:         entityTypeToKind(EventHook),
+				currentState: opts.CurrentState,
+				targetState:  opts.TargetState,
+			},
+		}, nil
 	case FilterChain:
 		return entityImpl{
 			typ: FilterChain,
@@ -722,6 +922,21 @@ func NewEntity(t EntityType, opts EntityOpts) (Entity, error) {
 				targetState:  opts.TargetState,
 			},
 		}, nil
+	case CustomEntity:
+		return entityImpl{
+			typ: CustomEntity,
+			crudActions: &customEntityCRUD{
+				client: opts.KongClient,
+			},
+			postProcessActions: &customEntityPostAction{
+				currentState: opts.CurrentState,
+			},
+			differ: &customEntityDiffer{
+				kind:         entityTypeToKind(CustomEntity),
+				currentState: opts.CurrentState,
+				targetState:  opts.TargetState,
+			},
+		}, nil
 	default:
 		return nil, fmt.Errorf("unknown type: %q", t)
 	}
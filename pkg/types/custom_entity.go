@@ -0,0 +1,206 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+
+	"github.com/kong/go-database-reconciler/pkg/crud"
+	"github.com/kong/go-database-reconciler/pkg/state"
+	"github.com/kong/go-kong/kong"
+)
+
+// customEntityCRUD manages the entities of custom DAOs with raw requests to
+// their endpoint on the Admin API, which is named after their type.
+type customEntityCRUD struct {
+	client *kong.Client
+}
+
+var _ crud.Actions = &customEntityCRUD{}
+
+func customEntityFromStruct(arg crud.Event) *state.CustomEntity {
+	entity, ok := arg.Obj.(*state.CustomEntity)
+	if !ok {
+		panic("unexpected type, expected *state.CustomEntity")
+	}
+	return entity
+}
+
+func (s *customEntityCRUD) do(ctx context.Context, method string,
+	entity *state.CustomEntity,
+) (*state.CustomEntity, error) {
+	var reqBody any
+	if method != http.MethodDelete {
+		reqBody = entity.Object()
+	}
+	endpoint := fmt.Sprintf("/%s/%s", entity.Type, entity.ID)
+	req, err := s.client.NewRequest(method, endpoint, nil, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	if method == http.MethodDelete {
+		_, err = s.client.Do(ctx, req, nil)
+		return nil, err
+	}
+	var object map[string]any
+	if _, err := s.client.Do(ctx, req, &object); err != nil {
+		return nil, err
+	}
+	delete(object, "id")
+	return &state.CustomEntity{
+		Type:       entity.Type,
+		ID:         entity.ID,
+		Fields:     object,
+		Definition: entity.Definition,
+	}, nil
+}
+
+// Create creates a CustomEntity in Kong.
+// The arg should be of type crud.Event, containing the custom entity to be
+// created, else the function will panic.
+// It returns a the created *state.CustomEntity.
+func (s *customEntityCRUD) Create(ctx context.Context, arg ...crud.Arg) (crud.Arg, error) {
+	if len(arg) == 0 {
+		return nil, ErrEmptyCRUDArgs
+	}
+	event := crud.EventFromArg(arg[0])
+	entity := customEntityFromStruct(event)
+	return s.do(ctx, http.MethodPut, entity)
+}
+
+// Delete deletes a CustomEntity in Kong.
+// The arg should be of type crud.Event, containing the custom entity to be
+// deleted, else the function will panic.
+// It returns a the deleted *state.CustomEntity.
+func (s *customEntityCRUD) Delete(ctx context.Context, arg ...crud.Arg) (crud.Arg, error) {
+	if len(arg) == 0 {
+		return nil, ErrEmptyCRUDArgs
+	}
+	event := crud.EventFromArg(arg[0])
+	entity := customEntityFromStruct(event)
+	if _, err := s.do(ctx, http.MethodDelete, entity); err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
+
+// Update updates a CustomEntity in Kong.
+// The arg should be of type crud.Event, containing the custom entity to be
+// updated, else the function will panic.
+// It returns a the updated *state.CustomEntity.
+func (s *customEntityCRUD) Update(ctx context.Context, arg ...crud.Arg) (crud.Arg, error) {
+	if len(arg) == 0 {
+		return nil, ErrEmptyCRUDArgs
+	}
+	event := crud.EventFromArg(arg[0])
+	entity := customEntityFromStruct(event)
+	return s.do(ctx, http.MethodPatch, entity)
+}
+
+type customEntityDiffer struct {
+	kind crud.Kind
+
+	currentState, targetState *state.KongState
+}
+
+var _ Differ = &customEntityDiffer{}
+
+// withUndeclaredFields returns a copy of target holding the fields of current
+// which target does not declare. Kong fills in the fields of entities with
+// their default value, which would otherwise always show as changes.
+func withUndeclaredFields(target, current *state.CustomEntity) *state.CustomEntity {
+	res := target.DeepCopy()
+	fields := current.Fields.DeepCopy()
+	if fields == nil {
+		return res
+	}
+	maps.Copy(fields, res.Fields)
+	res.Fields = fields
+	return res
+}
+
+func (d *customEntityDiffer) createUpdateCustomEntity(entity *state.CustomEntity) (*crud.Event, error) {
+	entityCopy := entity.DeepCopy()
+	currentEntity, err := d.currentState.CustomEntities.Get(entity.Type, entity.ID)
+	if errors.Is(err, state.ErrNotFound) {
+		return &crud.Event{
+			Op:   crud.Create,
+			Kind: d.kind,
+			Obj:  entityCopy,
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !currentEntity.EqualWithOpts(withUndeclaredFields(entityCopy, currentEntity), false, true) {
+		return &crud.Event{
+			Op:     crud.Update,
+			Kind:   d.kind,
+			Obj:    entityCopy,
+			OldObj: currentEntity,
+		}, nil
+	}
+	return nil, nil
+}
+
+// CreateAndUpdates generates a memdb CRUD CREATE/UPDATE event for
+// CustomEntities which is then consumed by the differ and used to gate Kong
+// client calls.
+func (d *customEntityDiffer) CreateAndUpdates(handler func(crud.Event) error) error {
+	targetEntities, err := d.targetState.CustomEntities.GetAll()
+	if err != nil {
+		return err
+	}
+
+	for _, entity := range targetEntities {
+		event, err := d.createUpdateCustomEntity(entity)
+		if err != nil {
+			return err
+		}
+		if event != nil {
+			err = handler(*event)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (d *customEntityDiffer) deleteCustomEntity(entity *state.CustomEntity) (*crud.Event, error) {
+	_, err := d.targetState.CustomEntities.Get(entity.Type, entity.ID)
+	if errors.Is(err, state.ErrNotFound) {
+		return &crud.Event{
+			Op:   crud.Delete,
+			Kind: d.kind,
+			Obj:  entity,
+		}, nil
+	}
+	return nil, err
+}
+
+// Deletes generates a memdb CRUD DELETE event for CustomEntities
+// which is then consumed by the differ and used to gate Kong client calls.
+func (d *customEntityDiffer) Deletes(handler func(crud.Event) error) error {
+	currentEntities, err := d.currentState.CustomEntities.GetAll()
+	if err != nil {
+		return err
+	}
+
+	for _, entity := range currentEntities {
+		event, err := d.deleteCustomEntity(entity)
+		if err != nil {
+			return err
+		}
+		if event != nil {
+			err = handler(*event)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net/http"
+
+	"github.com/kong/go-database-reconciler/pkg/crud"
+	"github.com/kong/go-database-reconciler/pkg/state"
+	"github.com/kong/go-database-reconciler/pkg/utils"
+	"github.com/kong/go-kong/kong"
+)
+
+// eventHookCRUD manages event hooks with raw requests to the Admin API, since
+// go-kong does not support them.
+type eventHookCRUD struct {
+	client *kong.Client
+	// isKonnect indicates whether it is syncing with Konnect, which has no
+	// event hooks.
+	isKonnect bool
+}
+
+var _ crud.Actions = &eventHookCRUD{}
+
+func eventHookFromStruct(arg crud.Event) *state.EventHook {
+	hook, ok := arg.Obj.(*state.EventHook)
+	if !ok {
+		panic("unexpected type, expected *state.EventHook")
+	}
+	return hook
+}
+
+func (s *eventHookCRUD) do(ctx context.Context, method, endpoint string,
+	body *utils.EventHook,
+) (*utils.EventHook, error) {
+	var reqBody any
+	if body != nil {
+		reqBody = body
+	}
+	req, err := s.client.NewRequest(method, endpoint, nil, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	if method == http.MethodDelete {
+		_, err = s.client.Do(ctx, req, nil)
+		return nil, err
+	}
+	var hook utils.EventHook
+	if _, err := s.client.Do(ctx, req, &hook); err != nil {
+		return nil, err
+	}
+	return &hook, nil
+}
+
+// Create creates an EventHook in Kong.
+// The arg should be of type crud.Event, containing the event hook to be
+// created, else the function will panic.
+// It returns a the created *state.EventHook.
+func (s *eventHookCRUD) Create(ctx context.Context, arg ...crud.Arg) (crud.Arg, error) {
+	if s.isKonnect {
+		return nil, nil
+	}
+	if len(arg) == 0 {
+		return nil, ErrEmptyCRUDArgs
+	}
+	event := crud.EventFromArg(arg[0])
+	hook := eventHookFromStruct(event)
+	createdHook, err := s.do(ctx, http.MethodPut, "/event-hooks/"+*hook.ID, &hook.EventHook)
+	if err != nil {
+		return nil, err
+	}
+	return &state.EventHook{EventHook: *createdHook}, nil
+}
+
+// Delete deletes an EventHook in Kong.
+// The arg should be of type crud.Event, containing the event hook to be
+// deleted, else the function will panic.
+// It returns a the deleted *state.EventHook.
+func (s *eventHookCRUD) Delete(ctx context.Context, arg ...crud.Arg) (crud.Arg, error) {
+	if s.isKonnect {
+		return nil, nil
+	}
+	if len(arg) == 0 {
+		return nil, ErrEmptyCRUDArgs
+	}
+	event := crud.EventFromArg(arg[0])
+	hook := eventHookFromStruct(event)
+	if _, err := s.do(ctx, http.MethodDelete, "/event-hooks/"+*hook.ID, nil); err != nil {
+		return nil, err
+	}
+	return hook, nil
+}
+
+// Update updates an EventHook in Kong.
+// The arg should be of type crud.Event, containing the event hook to be
+// updated, else the function will panic.
+// It returns a the updated *state.EventHook.
+func (s *eventHookCRUD) Update(ctx context.Context, arg ...crud.Arg) (crud.Arg, error) {
+	if s.isKonnect {
+		return nil, nil
+	}
+	if len(arg) == 0 {
+		return nil, ErrEmptyCRUDArgs
+	}
+	event := crud.EventFromArg(arg[0])
+	hook := eventHookFromStruct(event)
+	updatedHook, err := s.do(ctx, http.MethodPatch, "/event-hooks/"+*hook.ID, &hook.EventHook)
+	if err != nil {
+		return nil, err
+	}
+	return &state.EventHook{EventHook: *updatedHook}, nil
+}
+
+type eventHookDiffer struct {
+	kind crud.Kind
+
+	currentState, targetState *state.KongState
+}
+
+var _ Differ = &eventHookDiffer{}
+
+// withEventHookDefaults returns a copy of target holding the config keys and
+// the on_change and snooze settings of current which target does not declare.
+// Kong fills in the config of event hooks with the default values of their
+// handler, which would otherwise always show as changes.
+func withEventHookDefaults(target, current *state.EventHook) *state.EventHook {
+	res := &state.EventHook{EventHook: *target.DeepCopy()}
+	if len(current.Config) > 0 {
+		config := current.Config.DeepCopy()
+		maps.Copy(config, res.Config)
+		res.Config = config
+	}
+	if res.OnChange == nil {
+		res.OnChange = current.OnChange
+	}
+	if res.Snooze == nil {
+		res.Snooze = current.Snooze
+	}
+	return res
+}
+
+func (d *eventHookDiffer) createUpdateEventHook(hook *state.EventHook) (*crud.Event, error) {
+	hookCopy := &state.EventHook{EventHook: *hook.DeepCopy()}
+	currentHook, err := d.currentState.EventHooks.Get(*hook.ID)
+	if errors.Is(err, state.ErrNotFound) {
+		return &crud.Event{
+			Op:   crud.Create,
+			Kind: d.kind,
+			Obj:  hookCopy,
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !currentHook.EqualWithOpts(withEventHookDefaults(hookCopy, currentHook), false, true) {
+		return &crud.Event{
+			Op:     crud.Update,
+			Kind:   d.kind,
+			Obj:    hookCopy,
+			OldObj: currentHook,
+		}, nil
+	}
+	return nil, nil
+}
+
+// CreateAndUpdates generates a memdb CRUD CREATE/UPDATE event for EventHooks
+// which is then consumed by the differ and used to gate Kong client calls.
+func (d *eventHookDiffer) CreateAndUpdates(handler func(crud.Event) error) error {
+	targetHooks, err := d.targetState.EventHooks.GetAll()
+	if err != nil {
+		return err
+	}
+
+	for _, hook := range targetHooks {
+		event, err := d.createUpdateEventHook(hook)
+		if err != nil {
+			return err
+		}
+		if event != nil {
+			err = handler(*event)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (d *eventHookDiffer) deleteEventHook(hook *state.EventHook) (*crud.Event, error) {
+	_, err := d.targetState.EventHooks.Get(*hook.ID)
+	if errors.Is(err, state.ErrNotFound) {
+		return &crud.Event{
+			Op:   crud.Delete,
+			Kind: d.kind,
+			Obj:  hook,
+		}, nil
+	}
+	return nil, err
+}
+
+// Deletes generates a memdb CRUD DELETE event for EventHooks
+// which is then consumed by the differ and used to gate Kong client calls.
+func (d *eventHookDiffer) Deletes(handler func(crud.Event) error) error {
+	currentHooks, err := d.currentState.EventHooks.GetAll()
+	if err != nil {
+		return err
+	}
+
+	for _, hook := range currentHooks {
+		event, err := d.deleteEventHook(hook)
+		if err != nil {
+			return err
+		}
+		if event != nil {
+			err = handler(*event)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
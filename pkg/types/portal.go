@@ -0,0 +1,160 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kong/go-database-reconciler/pkg/crud"
+	"github.com/kong/go-database-reconciler/pkg/konnect"
+	"github.com/kong/go-database-reconciler/pkg/state"
+)
+
+// portalCRUD implements crud.Actions interface.
+type portalCRUD struct {
+	client *konnect.Client
+}
+
+func portalFromStruct(arg crud.Event) *state.Portal {
+	portal, ok := arg.Obj.(*state.Portal)
+	if !ok {
+		panic("unexpected type, expected *state.Portal")
+	}
+	return portal
+}
+
+// Create creates a Portal in Konnect.
+// The arg should be of type crud.Event, containing the Portal to be created,
+// else the function will panic.
+// It returns the created *state.Portal.
+func (s *portalCRUD) Create(ctx context.Context, arg ...crud.Arg) (crud.Arg, error) {
+	event := crud.EventFromArg(arg[0])
+	portal := portalFromStruct(event)
+	created, err := s.client.Portals.Create(ctx, &portal.Portal)
+	if err != nil {
+		return nil, err
+	}
+	return &state.Portal{Portal: *created}, nil
+}
+
+// Delete deletes a Portal in Konnect.
+// The arg should be of type crud.Event, containing the Portal to be deleted,
+// else the function will panic.
+// It returns the deleted *state.Portal.
+func (s *portalCRUD) Delete(ctx context.Context, arg ...crud.Arg) (crud.Arg, error) {
+	event := crud.EventFromArg(arg[0])
+	portal := portalFromStruct(event)
+	err := s.client.Portals.Delete(ctx, portal.ID)
+	if err != nil {
+		return nil, err
+	}
+	return portal, nil
+}
+
+// Update updates a Portal in Konnect.
+// The arg should be of type crud.Event, containing the Portal to be updated,
+// else the function will panic.
+// It returns the updated *state.Portal.
+func (s *portalCRUD) Update(ctx context.Context, arg ...crud.Arg) (crud.Arg, error) {
+	event := crud.EventFromArg(arg[0])
+	portal := portalFromStruct(event)
+
+	updated, err := s.client.Portals.Update(ctx, &portal.Portal)
+	if err != nil {
+		return nil, err
+	}
+	return &state.Portal{Portal: *updated}, nil
+}
+
+type portalDiffer struct {
+	kind crud.Kind
+
+	currentState, targetState *state.KongState
+}
+
+func (d *portalDiffer) Deletes(handler func(crud.Event) error) error {
+	currentPortals, err := d.currentState.Portals.GetAll()
+	if err != nil {
+		return fmt.Errorf("error fetching portals from state: %w", err)
+	}
+
+	for _, portal := range currentPortals {
+		n, err := d.deletePortal(portal)
+		if err != nil {
+			return err
+		}
+		if n != nil {
+			err = handler(*n)
+			if err != nil {
+				return err
+			}
+		}
+
+	}
+	return nil
+}
+
+func (d *portalDiffer) deletePortal(portal *state.Portal) (*crud.Event, error) {
+	_, err := d.targetState.Portals.Get(*portal.ID)
+	if errors.Is(err, state.ErrNotFound) {
+		return &crud.Event{
+			Op:   crud.Delete,
+			Kind: d.kind,
+			Obj:  portal,
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("looking up Portal %q: %w",
+			portal.Identifier(), err)
+	}
+	return nil, nil
+}
+
+func (d *portalDiffer) CreateAndUpdates(handler func(crud.Event) error) error {
+	targetPortals, err := d.targetState.Portals.GetAll()
+	if err != nil {
+		return fmt.Errorf("error fetching portals from state: %w", err)
+	}
+
+	for _, portal := range targetPortals {
+		n, err := d.createUpdatePortal(portal)
+		if err != nil {
+			return err
+		}
+		if n != nil {
+			err = handler(*n)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (d *portalDiffer) createUpdatePortal(portal *state.Portal) (*crud.Event, error) {
+	target := &state.Portal{Portal: *portal.DeepCopy()}
+	current, err := d.currentState.Portals.Get(*portal.ID)
+
+	if errors.Is(err, state.ErrNotFound) {
+		return &crud.Event{
+			Op:   crud.Create,
+			Kind: d.kind,
+			Obj:  target,
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error looking up Portal %q: %w",
+			portal.Identifier(), err)
+	}
+
+	// found, check if update needed
+	if !current.EqualWithOpts(target, false, true) {
+		return &crud.Event{
+			Op:     crud.Update,
+			Kind:   d.kind,
+			Obj:    target,
+			OldObj: current,
+		}, nil
+	}
+	return nil, nil
+}
@@ -415,6 +415,54 @@ func (crud *rbacEndpointPermissionPostAction) Update(_ context.Context, args ...
 	return nil, crud.currentState.RBACEndpointPermissions.Update(*args[0].(*state.RBACEndpointPermission))
 }
 
+type rbacEntityPermissionPostAction struct {
+	currentState *state.KongState
+}
+
+func (crud *rbacEntityPermissionPostAction) Create(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
+	return nil, crud.currentState.RBACEntityPermissions.Add(*args[0].(*state.RBACEntityPermission))
+}
+
+func (crud *rbacEntityPermissionPostAction) Delete(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
+	return nil, crud.currentState.RBACEntityPermissions.Delete(args[0].(*state.RBACEntityPermission).Identifier())
+}
+
+func (crud *rbacEntityPermissionPostAction) Update(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
+	return nil, crud.currentState.RBACEntityPermissions.Update(*args[0].(*state.RBACEntityPermission))
+}
+
+type rbacUserPostAction struct {
+	currentState *state.KongState
+}
+
+func (crud *rbacUserPostAction) Create(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
+	return nil, crud.currentState.RBACUsers.Add(*args[0].(*state.RBACUser))
+}
+
+func (crud *rbacUserPostAction) Delete(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
+	return nil, crud.currentState.RBACUsers.Delete(*((args[0].(*state.RBACUser)).ID))
+}
+
+func (crud *rbacUserPostAction) Update(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
+	return nil, crud.currentState.RBACUsers.Update(*args[0].(*state.RBACUser))
+}
+
+type adminPostAction struct {
+	currentState *state.KongState
+}
+
+func (crud *adminPostAction) Create(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
+	return nil, crud.currentState.Admins.Add(*args[0].(*state.Admin))
+}
+
+func (crud *adminPostAction) Delete(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
+	return nil, crud.currentState.Admins.Delete(*((args[0].(*state.Admin)).ID))
+}
+
+func (crud *adminPostAction) Update(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
+	return nil, crud.currentState.Admins.Update(*args[0].(*state.Admin))
+}
+
 type servicePackagePostAction struct {
 	currentState *state.KongState
 }
@@ -465,6 +513,116 @@ func (crud documentPostAction) Update(_ context.Context, args ...crud.Arg) (crud
 	return nil, crud.currentState.Documents.Update(*args[0].(*state.Document))
 }
 
+type apiProductPostAction struct {
+	currentState *state.KongState
+}
+
+func (crud apiProductPostAction) Create(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
+	return nil, crud.currentState.APIProducts.Add(*args[0].(*state.APIProduct))
+}
+
+func (crud apiProductPostAction) Delete(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
+	return nil, crud.currentState.APIProducts.Delete(*((args[0].(*state.APIProduct)).ID))
+}
+
+func (crud apiProductPostAction) Update(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
+	return nil, crud.currentState.APIProducts.Update(*args[0].(*state.APIProduct))
+}
+
+type apiProductVersionPostAction struct {
+	currentState *state.KongState
+}
+
+func (crud apiProductVersionPostAction) Create(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
+	return nil, crud.currentState.APIProductVersions.Add(*args[0].(*state.APIProductVersion))
+}
+
+func (crud apiProductVersionPostAction) Delete(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
+	v := args[0].(*state.APIProductVersion)
+	return nil, crud.currentState.APIProductVersions.Delete(*v.APIProduct.ID, *v.ID)
+}
+
+func (crud apiProductVersionPostAction) Update(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
+	return nil, crud.currentState.APIProductVersions.Update(*args[0].(*state.APIProductVersion))
+}
+
+type apiProductDocumentPostAction struct {
+	currentState *state.KongState
+}
+
+func (crud apiProductDocumentPostAction) Create(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
+	return nil, crud.currentState.APIProductDocuments.Add(*args[0].(*state.APIProductDocument))
+}
+
+func (crud apiProductDocumentPostAction) Delete(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
+	d := args[0].(*state.APIProductDocument)
+	return nil, crud.currentState.APIProductDocuments.Delete(*d.APIProduct.ID, *d.ID)
+}
+
+func (crud apiProductDocumentPostAction) Update(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
+	return nil, crud.currentState.APIProductDocuments.Update(*args[0].(*state.APIProductDocument))
+}
+
+type portalPostAction struct {
+	currentState *state.KongState
+}
+
+func (crud portalPostAction) Create(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
+	return nil, crud.currentState.Portals.Add(*args[0].(*state.Portal))
+}
+
+func (crud portalPostAction) Delete(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
+	return nil, crud.currentState.Portals.Delete(*((args[0].(*state.Portal)).ID))
+}
+
+func (crud portalPostAction) Update(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
+	return nil, crud.currentState.Portals.Update(*args[0].(*state.Portal))
+}
+
+type controlPlanePostAction struct {
+	currentState *state.KongState
+}
+
+func (crud controlPlanePostAction) Create(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
+	return nil, crud.currentState.ControlPlanes.Add(*args[0].(*state.ControlPlane))
+}
+
+func (crud controlPlanePostAction) Delete(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
+	return nil, crud.currentState.ControlPlanes.Delete(*((args[0].(*state.ControlPlane)).ID))
+}
+
+func (crud controlPlanePostAction) Update(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
+	return nil, crud.currentState.ControlPlanes.Update(*args[0].(*state.ControlPlane))
+}
+
+type controlPlaneGroupMembershipPostAction struct {
+	currentState *state.KongState
+}
+
+func (crud controlPlaneGroupMembershipPostAction) setMembers(groupID string, members []string) error {
+	group, err := crud.currentState.ControlPlanes.Get(groupID)
+	if err != nil {
+		return err
+	}
+	group.Members = members
+	return crud.currentState.ControlPlanes.Update(*group)
+}
+
+func (crud controlPlaneGroupMembershipPostAction) Create(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
+	m := args[0].(*state.ControlPlaneGroupMembership)
+	return nil, crud.setMembers(*m.GroupID, m.Members)
+}
+
+func (crud controlPlaneGroupMembershipPostAction) Delete(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
+	m := args[0].(*state.ControlPlaneGroupMembership)
+	return nil, crud.setMembers(*m.GroupID, nil)
+}
+
+func (crud controlPlaneGroupMembershipPostAction) Update(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
+	m := args[0].(*state.ControlPlaneGroupMembership)
+	return nil, crud.setMembers(*m.GroupID, m.Members)
+}
+
 type vaultPostAction struct {
 	currentState *state.KongState
 }
@@ -497,6 +655,38 @@ func (crud licensePostAction) Update(_ context.Context, args ...crud.Arg) (crud.
 	return nil, crud.currentState.Licenses.Update(*args[0].(*state.License))
 }
 
+type workspacePostAction struct {
+	currentState *state.KongState
+}
+
+func (crud workspacePostAction) Create(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
+	return nil, crud.currentState.Workspaces.Add(*args[0].(*state.Workspace))
+}
+
+func (crud workspacePostAction) Delete(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
+	return nil, crud.currentState.Workspaces.Delete(*((args[0].(*state.Workspace)).ID))
+}
+
+func (crud workspacePostAction) Update(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
+	return nil, crud.currentState.Workspaces.Update(*args[0].(*state.Workspace))
+}
+
+type eventHookPostAction struct {
+	currentState *state.KongState
+}
+
+func (crud eventHookPostAction) Create(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
+	return nil, crud.currentState.EventHooks.Add(*args[0].(*state.EventHook))
+}
+
+func (crud eventHookPostAction) Delete(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
+	return nil, crud.currentState.EventHooks.Delete(*((args[0].(*state.EventHook)).ID))
+}
+
+func (crud eventHookPostAction) Update(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
+	return nil, crud.currentState.EventHooks.Update(*args[0].(*state.EventHook))
+}
+
 type filterChainPostAction struct {
 	currentState *state.KongState
 }
@@ -627,3 +817,20 @@ func (crud customPluginDefinitionPostAction) Delete(_ context.Context, args ...c
 func (crud customPluginDefinitionPostAction) Update(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
 	return nil, crud.currentState.CustomPluginDefinitions.Update(*args[0].(*state.CustomPluginDefinition))
 }
+
+type customEntityPostAction struct {
+	currentState *state.KongState
+}
+
+func (crud customEntityPostAction) Create(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
+	return nil, crud.currentState.CustomEntities.Add(*args[0].(*state.CustomEntity))
+}
+
+func (crud customEntityPostAction) Delete(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
+	entity := args[0].(*state.CustomEntity)
+	return nil, crud.currentState.CustomEntities.Delete(entity.Type, entity.ID)
+}
+
+func (crud customEntityPostAction) Update(_ context.Context, args ...crud.Arg) (crud.Arg, error) {
+	return nil, crud.currentState.CustomEntities.Update(*args[0].(*state.CustomEntity))
+}
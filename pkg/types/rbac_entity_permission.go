@@ -0,0 +1,173 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kong/go-database-reconciler/pkg/crud"
+	"github.com/kong/go-database-reconciler/pkg/state"
+	"github.com/kong/go-kong/kong"
+)
+
+// rbacEntityPermissionCRUD implements crud.Actions interface.
+type rbacEntityPermissionCRUD struct {
+	client *kong.Client
+}
+
+func rbacEntityPermissionFromStruct(arg crud.Event) *state.RBACEntityPermission {
+	ep, ok := arg.Obj.(*state.RBACEntityPermission)
+	if !ok {
+		panic("unexpected type, expected *state.RBACEntityPermission")
+	}
+
+	return ep
+}
+
+// withRole returns ep with the role of the permission sent to Kong, which
+// Kong does not always include in its responses.
+func withRole(ep *kong.RBACEntityPermission, role *kong.RBACRole) *state.RBACEntityPermission {
+	if ep.Role == nil || ep.Role.ID == nil {
+		ep.Role = role
+	}
+	return &state.RBACEntityPermission{RBACEntityPermission: *ep}
+}
+
+// Create creates a RBACEntityPermission in Kong.
+// The arg should be of type crud.Event, containing the ep to be created,
+// else the function will panic.
+// It returns a the created *state.RBACEntityPermission.
+func (s *rbacEntityPermissionCRUD) Create(ctx context.Context, arg ...crud.Arg) (crud.Arg, error) {
+	event := crud.EventFromArg(arg[0])
+	ep := rbacEntityPermissionFromStruct(event)
+	createdRBACEntityPermission, err := s.client.RBACEntityPermissions.Create(ctx, &ep.RBACEntityPermission)
+	if err != nil {
+		return nil, err
+	}
+	return withRole(createdRBACEntityPermission, ep.Role), nil
+}
+
+// Delete deletes a RBACEntityPermission in Kong.
+// The arg should be of type crud.Event, containing the ep to be deleted,
+// else the function will panic.
+// It returns a the deleted *state.RBACEntityPermission.
+func (s *rbacEntityPermissionCRUD) Delete(ctx context.Context, arg ...crud.Arg) (crud.Arg, error) {
+	event := crud.EventFromArg(arg[0])
+	ep := rbacEntityPermissionFromStruct(event)
+	err := s.client.RBACEntityPermissions.Delete(ctx, ep.Role.ID, ep.EntityID)
+	if err != nil {
+		return nil, err
+	}
+	return ep, nil
+}
+
+// Update updates a RBACEntityPermission in Kong.
+// The arg should be of type crud.Event, containing the ep to be updated,
+// else the function will panic.
+// It returns a the updated *state.RBACEntityPermission.
+func (s *rbacEntityPermissionCRUD) Update(ctx context.Context, arg ...crud.Arg) (crud.Arg, error) {
+	event := crud.EventFromArg(arg[0])
+	ep := rbacEntityPermissionFromStruct(event)
+
+	updatedRBACEntityPermission, err := s.client.RBACEntityPermissions.Update(ctx, &ep.RBACEntityPermission)
+	if err != nil {
+		return nil, err
+	}
+	return withRole(updatedRBACEntityPermission, ep.Role), nil
+}
+
+type rbacEntityPermissionDiffer struct {
+	kind crud.Kind
+
+	currentState, targetState *state.KongState
+}
+
+func (d *rbacEntityPermissionDiffer) Deletes(handler func(crud.Event) error) error {
+	currentRBACEntityPermissions, err := d.currentState.RBACEntityPermissions.GetAll()
+	if err != nil {
+		return fmt.Errorf("error fetching rbac entity permissions from state: %w", err)
+	}
+
+	for _, ep := range currentRBACEntityPermissions {
+		n, err := d.deleteRBACEntityPermission(ep)
+		if err != nil {
+			return err
+		}
+		if n != nil {
+			err = handler(*n)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (d *rbacEntityPermissionDiffer) deleteRBACEntityPermission(ep *state.RBACEntityPermission) (
+	*crud.Event, error,
+) {
+	_, err := d.targetState.RBACEntityPermissions.Get(ep.Identifier())
+	if errors.Is(err, state.ErrNotFound) {
+		return &crud.Event{
+			Op:   crud.Delete,
+			Kind: d.kind,
+			Obj:  ep,
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("looking up rbac entity permission %q: %w",
+			ep.Identifier(), err)
+	}
+	return nil, nil
+}
+
+func (d *rbacEntityPermissionDiffer) CreateAndUpdates(handler func(crud.Event) error) error {
+	targetRBACEntityPermissions, err := d.targetState.RBACEntityPermissions.GetAll()
+	if err != nil {
+		return fmt.Errorf("error fetching rbac entity permissions from state: %w", err)
+	}
+
+	for _, ep := range targetRBACEntityPermissions {
+		n, err := d.createUpdateRBACEntityPermission(ep)
+		if err != nil {
+			return err
+		}
+		if n != nil {
+			err = handler(*n)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (d *rbacEntityPermissionDiffer) createUpdateRBACEntityPermission(ep *state.RBACEntityPermission) (
+	*crud.Event, error,
+) {
+	epCopy := &state.RBACEntityPermission{ID: ep.ID, RBACEntityPermission: *ep.DeepCopy()}
+	currentEp, err := d.currentState.RBACEntityPermissions.Get(ep.Identifier())
+
+	if errors.Is(err, state.ErrNotFound) {
+		return &crud.Event{
+			Op:   crud.Create,
+			Kind: d.kind,
+			Obj:  epCopy,
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error looking up rbac entity permission %q: %w",
+			ep.Identifier(), err)
+	}
+
+	// found, check if update needed
+	if !currentEp.EqualWithOpts(epCopy, false, true) {
+		return &crud.Event{
+			Op:     crud.Update,
+			Kind:   d.kind,
+			Obj:    epCopy,
+			OldObj: currentEp,
+		}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,222 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/kong/go-database-reconciler/pkg/cprint"
+	"github.com/kong/go-database-reconciler/pkg/crud"
+	"github.com/kong/go-database-reconciler/pkg/state"
+	"github.com/kong/go-kong/kong"
+)
+
+// rbacUserCRUD implements crud.Actions interface.
+// The roles of users are assigned along with them.
+type rbacUserCRUD struct {
+	client *kong.Client
+}
+
+func rbacUserFromStruct(arg crud.Event) *state.RBACUser {
+	user, ok := arg.Obj.(*state.RBACUser)
+	if !ok {
+		panic("unexpected type, expected *state.RBACUser")
+	}
+	return user
+}
+
+// roleChanges returns the roles of target which are not in current, and the
+// ones of current which are not in target.
+func roleChanges(current, target []string) (added, removed []*kong.RBACRole) {
+	for _, role := range target {
+		if !slices.Contains(current, role) {
+			added = append(added, &kong.RBACRole{Name: new(role)})
+		}
+	}
+	for _, role := range current {
+		if !slices.Contains(target, role) {
+			removed = append(removed, &kong.RBACRole{Name: new(role)})
+		}
+	}
+	return added, removed
+}
+
+// Create creates a RBACUser in Kong and assigns its roles.
+// The arg should be of type crud.Event, containing the user to be created,
+// else the function will panic.
+// It returns a the created *state.RBACUser.
+func (s *rbacUserCRUD) Create(ctx context.Context, arg ...crud.Arg) (crud.Arg, error) {
+	event := crud.EventFromArg(arg[0])
+	user := rbacUserFromStruct(event)
+	userCopy := user.DeepCopy()
+	// the identifier of the token is derived from it by Kong
+	userCopy.UserTokenIdent = nil
+	createdUser, err := s.client.RBACUsers.Create(ctx, userCopy)
+	if err != nil {
+		return nil, err
+	}
+	if added, _ := roleChanges(nil, user.Roles); len(added) > 0 {
+		if _, err := s.client.RBACUsers.AddRoles(ctx, createdUser.ID, added); err != nil {
+			return nil, err
+		}
+	}
+	return &state.RBACUser{RBACUser: *createdUser, Roles: user.Roles}, nil
+}
+
+// Delete deletes a RBACUser in Kong.
+// The arg should be of type crud.Event, containing the user to be deleted,
+// else the function will panic.
+// It returns a the deleted *state.RBACUser.
+func (s *rbacUserCRUD) Delete(ctx context.Context, arg ...crud.Arg) (crud.Arg, error) {
+	event := crud.EventFromArg(arg[0])
+	user := rbacUserFromStruct(event)
+	err := s.client.RBACUsers.Delete(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// Update updates a RBACUser in Kong along with its roles.
+// The arg should be of type crud.Event, containing the user to be updated,
+// else the function will panic.
+// It returns a the updated *state.RBACUser.
+func (s *rbacUserCRUD) Update(ctx context.Context, arg ...crud.Arg) (crud.Arg, error) {
+	event := crud.EventFromArg(arg[0])
+	user := rbacUserFromStruct(event)
+
+	userCopy := user.DeepCopy()
+	// the identifier of the token is derived from it by Kong
+	userCopy.UserTokenIdent = nil
+	updatedUser, err := s.client.RBACUsers.Update(ctx, userCopy)
+	if err != nil {
+		return nil, err
+	}
+
+	var currentRoles []string
+	if oldUser, ok := event.OldObj.(*state.RBACUser); ok {
+		currentRoles = oldUser.Roles
+	}
+	added, removed := roleChanges(currentRoles, user.Roles)
+	if len(added) > 0 {
+		if _, err := s.client.RBACUsers.AddRoles(ctx, user.ID, added); err != nil {
+			return nil, err
+		}
+	}
+	if len(removed) > 0 {
+		if err := s.client.RBACUsers.DeleteRoles(ctx, user.ID, removed); err != nil {
+			return nil, err
+		}
+	}
+	return &state.RBACUser{RBACUser: *updatedUser, Roles: user.Roles}, nil
+}
+
+type rbacUserDiffer struct {
+	kind crud.Kind
+	once sync.Once
+
+	currentState, targetState *state.KongState
+}
+
+func (d *rbacUserDiffer) warnUserToken() {
+	const (
+		userTokenWarning = "Warning: changes to the user_token of rbac users are not " +
+			"detected without their user_token_ident, due to hashing of tokens in Kong."
+	)
+	d.once.Do(func() {
+		cprint.UpdatePrintlnStdErr(userTokenWarning)
+	})
+}
+
+func (d *rbacUserDiffer) Deletes(handler func(crud.Event) error) error {
+	currentUsers, err := d.currentState.RBACUsers.GetAll()
+	if err != nil {
+		return fmt.Errorf("error fetching rbac users from state: %w", err)
+	}
+
+	for _, user := range currentUsers {
+		n, err := d.deleteRBACUser(user)
+		if err != nil {
+			return err
+		}
+		if n != nil {
+			err = handler(*n)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (d *rbacUserDiffer) deleteRBACUser(user *state.RBACUser) (*crud.Event, error) {
+	_, err := d.targetState.RBACUsers.Get(*user.ID)
+	if errors.Is(err, state.ErrNotFound) {
+		return &crud.Event{
+			Op:   crud.Delete,
+			Kind: d.kind,
+			Obj:  user,
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("looking up rbac user %q: %w",
+			user.Identifier(), err)
+	}
+	return nil, nil
+}
+
+func (d *rbacUserDiffer) CreateAndUpdates(handler func(crud.Event) error) error {
+	targetUsers, err := d.targetState.RBACUsers.GetAll()
+	if err != nil {
+		return fmt.Errorf("error fetching rbac users from state: %w", err)
+	}
+
+	for _, user := range targetUsers {
+		n, err := d.createUpdateRBACUser(user)
+		if err != nil {
+			return err
+		}
+		if n != nil {
+			err = handler(*n)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (d *rbacUserDiffer) createUpdateRBACUser(user *state.RBACUser) (*crud.Event, error) {
+	userCopy := &state.RBACUser{RBACUser: *user.DeepCopy(), Roles: slices.Clone(user.Roles)}
+	currentUser, err := d.currentState.RBACUsers.Get(*user.ID)
+	if errors.Is(err, state.ErrNotFound) {
+		return &crud.Event{
+			Op:   crud.Create,
+			Kind: d.kind,
+			Obj:  userCopy,
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error looking up rbac user %q: %w",
+			user.Identifier(), err)
+	}
+
+	if userCopy.UserToken != nil && userCopy.UserTokenIdent == nil {
+		d.warnUserToken()
+	}
+	// users are enabled by Kong unless stated otherwise
+	if userCopy.Enabled == nil {
+		userCopy.Enabled = currentUser.Enabled
+	}
+	if !currentUser.EqualWithOpts(userCopy, false, true) {
+		return &crud.Event{
+			Op:     crud.Update,
+			Kind:   d.kind,
+			Obj:    userCopy,
+			OldObj: currentUser,
+		}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,231 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net/http"
+
+	"github.com/kong/go-database-reconciler/pkg/crud"
+	"github.com/kong/go-database-reconciler/pkg/state"
+	"github.com/kong/go-database-reconciler/pkg/utils"
+	"github.com/kong/go-kong/kong"
+)
+
+// defaultWorkspace is the workspace Kong Enterprise creates, which cannot be
+// deleted.
+const defaultWorkspace = "default"
+
+// workspaceCRUD manages workspaces with requests to the root of the Admin
+// API, so that they are managed whatever the workspace client is scoped to.
+type workspaceCRUD struct {
+	client *kong.Client
+	// isKonnect indicates whether it is syncing with Konnect, which has no
+	// workspaces.
+	isKonnect bool
+}
+
+var _ crud.Actions = &workspaceCRUD{}
+
+func workspaceFromStruct(arg crud.Event) *state.Workspace {
+	workspace, ok := arg.Obj.(*state.Workspace)
+	if !ok {
+		panic("unexpected type, expected *state.Workspace")
+	}
+	return workspace
+}
+
+func (s *workspaceCRUD) do(ctx context.Context, method, endpoint string,
+	body *kong.Workspace,
+) (*kong.Workspace, error) {
+	var reqBody any
+	if body != nil {
+		reqBody = body
+	}
+	req, err := utils.NewRootRequest(s.client, method, endpoint, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	if method == http.MethodDelete {
+		_, err = s.client.Do(ctx, req, nil)
+		return nil, err
+	}
+	var workspace kong.Workspace
+	if _, err := s.client.Do(ctx, req, &workspace); err != nil {
+		return nil, err
+	}
+	return &workspace, nil
+}
+
+// Create creates a Workspace in Kong.
+// The arg should be of type crud.Event, containing the workspace to be created,
+// else the function will panic.
+// It returns a the created *state.Workspace.
+func (s *workspaceCRUD) Create(ctx context.Context, arg ...crud.Arg) (crud.Arg, error) {
+	if s.isKonnect {
+		return nil, nil
+	}
+	if len(arg) == 0 {
+		return nil, ErrEmptyCRUDArgs
+	}
+	event := crud.EventFromArg(arg[0])
+	workspace := workspaceFromStruct(event)
+	createdWorkspace, err := s.do(ctx, http.MethodPut, "/workspaces/"+*workspace.ID, &workspace.Workspace)
+	if err != nil {
+		return nil, err
+	}
+	return &state.Workspace{Workspace: *createdWorkspace}, nil
+}
+
+// Delete deletes a Workspace in Kong.
+// The arg should be of type crud.Event, containing the workspace to be deleted,
+// else the function will panic.
+// It returns a the deleted *state.Workspace.
+func (s *workspaceCRUD) Delete(ctx context.Context, arg ...crud.Arg) (crud.Arg, error) {
+	if s.isKonnect {
+		return nil, nil
+	}
+	if len(arg) == 0 {
+		return nil, ErrEmptyCRUDArgs
+	}
+	event := crud.EventFromArg(arg[0])
+	workspace := workspaceFromStruct(event)
+	if _, err := s.do(ctx, http.MethodDelete, "/workspaces/"+*workspace.ID, nil); err != nil {
+		return nil, err
+	}
+	return workspace, nil
+}
+
+// Update updates a Workspace in Kong.
+// The arg should be of type crud.Event, containing the workspace to be updated,
+// else the function will panic.
+// It returns a the updated *state.Workspace.
+func (s *workspaceCRUD) Update(ctx context.Context, arg ...crud.Arg) (crud.Arg, error) {
+	if s.isKonnect {
+		return nil, nil
+	}
+	if len(arg) == 0 {
+		return nil, ErrEmptyCRUDArgs
+	}
+	event := crud.EventFromArg(arg[0])
+	workspace := workspaceFromStruct(event)
+	updatedWorkspace, err := s.do(ctx, http.MethodPatch, "/workspaces/"+*workspace.ID, &workspace.Workspace)
+	if err != nil {
+		return nil, err
+	}
+	return &state.Workspace{Workspace: *updatedWorkspace}, nil
+}
+
+type workspaceDiffer struct {
+	kind crud.Kind
+
+	currentState, targetState *state.KongState
+}
+
+var _ Differ = &workspaceDiffer{}
+
+// withUndeclaredSettings returns a copy of target holding the config and meta
+// keys of current which target does not declare. Kong fills in the config of
+// workspaces with the default value of its portal settings, which would
+// otherwise always show as changes.
+func withUndeclaredSettings(target, current *state.Workspace) *state.Workspace {
+	res := &state.Workspace{Workspace: *target.DeepCopy()}
+	merge := func(declared, all map[string]any) map[string]any {
+		if len(all) == 0 {
+			return declared
+		}
+		merged := kong.Configuration(all).DeepCopy()
+		maps.Copy(merged, declared)
+		return merged
+	}
+	res.Config = merge(res.Config, current.Config)
+	res.Workspace.Meta = merge(res.Workspace.Meta, current.Workspace.Meta)
+	return res
+}
+
+func (d *workspaceDiffer) createUpdateWorkspace(workspace *state.Workspace) (*crud.Event, error) {
+	workspaceCopy := &state.Workspace{Workspace: *workspace.DeepCopy()}
+	currentWorkspace, err := d.currentState.Workspaces.Get(*workspace.ID)
+	if errors.Is(err, state.ErrNotFound) {
+		return &crud.Event{
+			Op:   crud.Create,
+			Kind: d.kind,
+			Obj:  workspaceCopy,
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !currentWorkspace.EqualWithOpts(withUndeclaredSettings(workspaceCopy, currentWorkspace), false, true) {
+		return &crud.Event{
+			Op:     crud.Update,
+			Kind:   d.kind,
+			Obj:    workspaceCopy,
+			OldObj: currentWorkspace,
+		}, nil
+	}
+	return nil, nil
+}
+
+// CreateAndUpdates generates a memdb CRUD CREATE/UPDATE event for Workspaces
+// which is then consumed by the differ and used to gate Kong client calls.
+func (d *workspaceDiffer) CreateAndUpdates(handler func(crud.Event) error) error {
+	targetWorkspaces, err := d.targetState.Workspaces.GetAll()
+	if err != nil {
+		return err
+	}
+
+	for _, workspace := range targetWorkspaces {
+		event, err := d.createUpdateWorkspace(workspace)
+		if err != nil {
+			return err
+		}
+		if event != nil {
+			err = handler(*event)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (d *workspaceDiffer) deleteWorkspace(workspace *state.Workspace) (*crud.Event, error) {
+	if workspace.Name != nil && *workspace.Name == defaultWorkspace {
+		return nil, nil
+	}
+	_, err := d.targetState.Workspaces.Get(*workspace.ID)
+	if errors.Is(err, state.ErrNotFound) {
+		return &crud.Event{
+			Op:   crud.Delete,
+			Kind: d.kind,
+			Obj:  workspace,
+		}, nil
+	}
+	return nil, err
+}
+
+// Deletes generates a memdb CRUD DELETE event for Workspaces
+// which is then consumed by the differ and used to gate Kong client calls.
+// The default workspace is never deleted.
+func (d *workspaceDiffer) Deletes(handler func(crud.Event) error) error {
+	currentWorkspaces, err := d.currentState.Workspaces.GetAll()
+	if err != nil {
+		return err
+	}
+
+	for _, workspace := range currentWorkspaces {
+		event, err := d.deleteWorkspace(workspace)
+		if err != nil {
+			return err
+		}
+		if event != nil {
+			err = handler(*event)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"slices"
 	"strings"
+	"sync"
 )
 
 type DiagnosticCode string
@@ -12,12 +13,18 @@ const (
 	DiagnosticCodeRouteRegexPathFormat DiagnosticCode = "route-regex-path-format"
 	DiagnosticCodeRLAConsumerGroups    DiagnosticCode = "rla-consumer-groups-deprecated"
 	DiagnosticCodeOIDCMissingConfig    DiagnosticCode = "oidc-missing-required-config"
+	DiagnosticCodeRouteConflict        DiagnosticCode = "route-conflict"
+	DiagnosticCodeRouteUnreachable     DiagnosticCode = "route-unreachable"
+	DiagnosticCodeRouteExpression      DiagnosticCode = "route-invalid-expression"
 )
 
 var validDiagnosticCodes = map[DiagnosticCode]struct{}{
 	DiagnosticCodeRouteRegexPathFormat: {},
 	DiagnosticCodeRLAConsumerGroups:    {},
 	DiagnosticCodeOIDCMissingConfig:    {},
+	DiagnosticCodeRouteConflict:        {},
+	DiagnosticCodeRouteUnreachable:     {},
+	DiagnosticCodeRouteExpression:      {},
 }
 
 type Severity string
@@ -27,10 +34,17 @@ const (
 	SeverityError   Severity = "error"
 )
 
+// diagnosticCodesMu guards validDiagnosticCodes and defaultSeverityByDiagnosticCode
+// against concurrent registrations.
+var diagnosticCodesMu sync.RWMutex
+
 var defaultSeverityByDiagnosticCode = map[DiagnosticCode]Severity{
 	DiagnosticCodeRouteRegexPathFormat: SeverityWarning,
 	DiagnosticCodeRLAConsumerGroups:    SeverityError,
 	DiagnosticCodeOIDCMissingConfig:    SeverityError,
+	DiagnosticCodeRouteConflict:        SeverityWarning,
+	DiagnosticCodeRouteUnreachable:     SeverityWarning,
+	DiagnosticCodeRouteExpression:      SeverityWarning,
 }
 
 type DiagnosticPolicy struct {
@@ -38,6 +52,26 @@ type DiagnosticPolicy struct {
 	AlwaysWarning []DiagnosticCode
 }
 
+// RegisterDiagnosticCode makes code a valid diagnostic code, reported with
+// the given severity unless overridden by a DiagnosticPolicy.
+// Registering an already known code is an error.
+func RegisterDiagnosticCode(code DiagnosticCode, severity Severity) error {
+	if strings.TrimSpace(string(code)) == "" || strings.Contains(string(code), ",") {
+		return fmt.Errorf("invalid diagnostic code: %q", code)
+	}
+	if severity != SeverityWarning && severity != SeverityError {
+		return fmt.Errorf("invalid severity for diagnostic code %s: %q", code, severity)
+	}
+	diagnosticCodesMu.Lock()
+	defer diagnosticCodesMu.Unlock()
+	if _, ok := validDiagnosticCodes[code]; ok {
+		return fmt.Errorf("diagnostic code already registered: %s", code)
+	}
+	validDiagnosticCodes[code] = struct{}{}
+	defaultSeverityByDiagnosticCode[code] = severity
+	return nil
+}
+
 func NewDiagnosticPolicy(alwaysError, alwaysWarning []DiagnosticCode) DiagnosticPolicy {
 	return DiagnosticPolicy{
 		AlwaysError:   deduplicateDiagnosticCodes(alwaysError),
@@ -54,6 +88,9 @@ func ParseDiagnosticCodes(value string) ([]DiagnosticCode, error) {
 	codes := make([]DiagnosticCode, 0, len(parts))
 	seen := map[DiagnosticCode]struct{}{}
 
+	diagnosticCodesMu.RLock()
+	defer diagnosticCodesMu.RUnlock()
+
 	for _, part := range parts {
 		normalized := DiagnosticCode(strings.TrimSpace(part))
 		if normalized == "" {
@@ -73,6 +110,8 @@ func ParseDiagnosticCodes(value string) ([]DiagnosticCode, error) {
 }
 
 func ValidDiagnosticCodes() []DiagnosticCode {
+	diagnosticCodesMu.RLock()
+	defer diagnosticCodesMu.RUnlock()
 	codes := make([]DiagnosticCode, 0, len(validDiagnosticCodes))
 	for code := range validDiagnosticCodes {
 		codes = append(codes, code)
@@ -110,13 +149,12 @@ func (p DiagnosticPolicy) ResolveSeverity(code DiagnosticCode) Severity {
 	if p.IsAlwaysWarning(code) {
 		return SeverityWarning
 	}
-	if severity, ok := defaultSeverityByDiagnosticCode[code]; ok {
-		return severity
-	}
-	return SeverityWarning
+	return DefaultSeverity(code)
 }
 
 func DefaultSeverity(code DiagnosticCode) Severity {
+	diagnosticCodesMu.RLock()
+	defer diagnosticCodesMu.RUnlock()
 	if severity, ok := defaultSeverityByDiagnosticCode[code]; ok {
 		return severity
 	}
@@ -70,7 +70,27 @@ func TestDiagnosticPolicyResolveSeverity(t *testing.T) {
 func TestValidDiagnosticCodesString(t *testing.T) {
 	assert.Equal(
 		t,
-		"oidc-missing-required-config,rla-consumer-groups-deprecated,route-regex-path-format",
+		"oidc-missing-required-config,rla-consumer-groups-deprecated,route-conflict,route-invalid-expression,"+
+			"route-regex-path-format,route-unreachable",
 		ValidDiagnosticCodesString(),
 	)
 }
+
+func TestRegisterDiagnosticCode(t *testing.T) {
+	const code DiagnosticCode = "custom-rule"
+	t.Cleanup(func() {
+		delete(validDiagnosticCodes, code)
+		delete(defaultSeverityByDiagnosticCode, code)
+	})
+
+	require.NoError(t, RegisterDiagnosticCode(code, SeverityError))
+	assert.Equal(t, SeverityError, DefaultSeverity(code))
+	codes, err := ParseDiagnosticCodes("custom-rule")
+	require.NoError(t, err)
+	assert.Equal(t, []DiagnosticCode{code}, codes)
+
+	require.ErrorContains(t, RegisterDiagnosticCode(code, SeverityWarning), "already registered")
+	require.ErrorContains(t, RegisterDiagnosticCode(DiagnosticCodeRouteConflict, SeverityWarning), "already registered")
+	require.ErrorContains(t, RegisterDiagnosticCode("a,b", SeverityWarning), "invalid diagnostic code")
+	require.ErrorContains(t, RegisterDiagnosticCode("other-rule", "info"), "invalid severity")
+}
@@ -0,0 +1,168 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+
+	"github.com/kong/go-kong/kong"
+)
+
+const eventHooksPageSize = 1000
+
+// EventHook is an event hook of Kong Enterprise, which runs a handler when an
+// event of a source, such as the creation of a consumer, happens in Kong.
+// go-kong does not define event hooks.
+type EventHook struct {
+	ID        *string `json:"id,omitempty" yaml:"id,omitempty"`
+	CreatedAt *int    `json:"created_at,omitempty" yaml:"created_at,omitempty"`
+	Source    *string `json:"source,omitempty" yaml:"source,omitempty"`
+	// Event is optional: when nil, the hook runs for all the events of its
+	// source.
+	Event    *string            `json:"event,omitempty" yaml:"event,omitempty"`
+	Handler  *string            `json:"handler,omitempty" yaml:"handler,omitempty"`
+	OnChange *bool              `json:"on_change,omitempty" yaml:"on_change,omitempty"`
+	Snooze   *int               `json:"snooze,omitempty" yaml:"snooze,omitempty"`
+	Config   kong.Configuration `json:"config,omitempty" yaml:"config,omitempty"`
+}
+
+// DeepCopyInto copies the receiver into out.
+func (h *EventHook) DeepCopyInto(out *EventHook) {
+	*out = *h
+	if h.ID != nil {
+		out.ID = new(*h.ID)
+	}
+	if h.CreatedAt != nil {
+		out.CreatedAt = new(*h.CreatedAt)
+	}
+	if h.Source != nil {
+		out.Source = new(*h.Source)
+	}
+	if h.Event != nil {
+		out.Event = new(*h.Event)
+	}
+	if h.Handler != nil {
+		out.Handler = new(*h.Handler)
+	}
+	if h.OnChange != nil {
+		out.OnChange = new(*h.OnChange)
+	}
+	if h.Snooze != nil {
+		out.Snooze = new(*h.Snooze)
+	}
+	if h.Config != nil {
+		out.Config = h.Config.DeepCopy()
+	}
+}
+
+// DeepCopy copies the receiver, creating a new EventHook.
+func (h *EventHook) DeepCopy() *EventHook {
+	if h == nil {
+		return nil
+	}
+	out := new(EventHook)
+	h.DeepCopyInto(out)
+	return out
+}
+
+// eventHookHandlers maps the handlers of event hooks to the config fields
+// they require.
+var eventHookHandlers = map[string][]string{
+	"webhook":        {"url"},
+	"webhook-custom": {"url", "method"},
+	"log":            {},
+	"lambda":         {"functions"},
+}
+
+// EventHookEvent describes an event of an event hook source.
+type EventHookEvent struct {
+	Description string   `json:"description,omitempty"`
+	Fields      []string `json:"fields,omitempty"`
+	Unique      []string `json:"unique,omitempty"`
+}
+
+// EventHookSources maps the sources of event hooks to their events, as
+// returned by the /event-hooks/sources endpoint of Kong.
+type EventHookSources map[string]map[string]EventHookEvent
+
+// ValidateEventHook checks that the handler of hook is known and configured
+// with the fields it requires and, if sources is not nil, that hook listens to
+// an event of a known source.
+func ValidateEventHook(hook *EventHook, sources EventHookSources) error {
+	if Empty(hook.Source) {
+		return fmt.Errorf("source is required")
+	}
+	if Empty(hook.Handler) {
+		return fmt.Errorf("handler is required")
+	}
+	required, ok := eventHookHandlers[*hook.Handler]
+	if !ok {
+		handlers := make([]string, 0, len(eventHookHandlers))
+		for handler := range eventHookHandlers {
+			handlers = append(handlers, handler)
+		}
+		slices.Sort(handlers)
+		return fmt.Errorf("invalid handler %q, must be one of %v", *hook.Handler, handlers)
+	}
+	for _, field := range required {
+		if _, ok := hook.Config[field]; !ok {
+			return fmt.Errorf("config.%s is required by the %s handler", field, *hook.Handler)
+		}
+	}
+	if sources == nil {
+		return nil
+	}
+	events, ok := sources[*hook.Source]
+	if !ok {
+		return fmt.Errorf("unknown source %q", *hook.Source)
+	}
+	if !Empty(hook.Event) {
+		if _, ok := events[*hook.Event]; !ok {
+			return fmt.Errorf("unknown event %q of source %q", *hook.Event, *hook.Source)
+		}
+	}
+	return nil
+}
+
+// ListEventHooks lists all the event hooks of the workspace of client.
+func ListEventHooks(ctx context.Context, client *kong.Client) ([]*EventHook, error) {
+	var hooks []*EventHook
+	query := url.Values{"size": {strconv.Itoa(eventHooksPageSize)}}
+	for {
+		req, err := client.NewRequest(http.MethodGet, "/event-hooks?"+query.Encode(), nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Data   []*EventHook `json:"data"`
+			Offset string       `json:"offset"`
+		}
+		if _, err := client.Do(ctx, req, &page); err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, page.Data...)
+		if page.Offset == "" {
+			return hooks, nil
+		}
+		query.Set("offset", page.Offset)
+	}
+}
+
+// ListEventHookSources lists the sources of event hooks, along with their
+// events, available in the workspace of client.
+func ListEventHookSources(ctx context.Context, client *kong.Client) (EventHookSources, error) {
+	req, err := client.NewRequest(http.MethodGet, "/event-hooks/sources", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var res struct {
+		Data EventHookSources `json:"data"`
+	}
+	if _, err := client.Do(ctx, req, &res); err != nil {
+		return nil, err
+	}
+	return res.Data, nil
+}
@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateEventHook(t *testing.T) {
+	var res struct {
+		Data EventHookSources `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"data": {
+			"crud": {
+				"consumers": {"fields": ["operation", "entity", "old_entity"]},
+				"services": {"fields": ["operation", "entity", "old_entity"]}
+			},
+			"balancer": {
+				"health": {"fields": ["upstream_id", "ip", "port", "hostname", "health"]}
+			}
+		}
+	}`), &res))
+	sources := res.Data
+
+	tests := []struct {
+		name    string
+		hook    EventHook
+		sources EventHookSources
+		wantErr string
+	}{
+		{
+			name: "webhook on an event",
+			hook: EventHook{
+				Source: new("crud"), Event: new("consumers"), Handler: new("webhook"),
+				Config: kong.Configuration{"url": "https://example.com"},
+			},
+			sources: sources,
+		},
+		{
+			name:    "log on all the events of a source",
+			hook:    EventHook{Source: new("balancer"), Handler: new("log")},
+			sources: sources,
+		},
+		{
+			name:    "missing source",
+			hook:    EventHook{Handler: new("log")},
+			wantErr: "source is required",
+		},
+		{
+			name:    "unknown handler",
+			hook:    EventHook{Source: new("crud"), Handler: new("email")},
+			wantErr: `invalid handler "email", must be one of [lambda log webhook webhook-custom]`,
+		},
+		{
+			name: "missing required config",
+			hook: EventHook{
+				Source: new("crud"), Handler: new("webhook-custom"),
+				Config: kong.Configuration{"url": "https://example.com"},
+			},
+			wantErr: "config.method is required by the webhook-custom handler",
+		},
+		{
+			name:    "unknown source",
+			hook:    EventHook{Source: new("dao:crud"), Handler: new("log")},
+			sources: sources,
+			wantErr: `unknown source "dao:crud"`,
+		},
+		{
+			name:    "unknown event",
+			hook:    EventHook{Source: new("balancer"), Event: new("consumers"), Handler: new("log")},
+			sources: sources,
+			wantErr: `unknown event "consumers" of source "balancer"`,
+		},
+		{
+			name: "sources not available",
+			hook: EventHook{Source: new("dao:crud"), Handler: new("log")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateEventHook(&tt.hook, tt.sources)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
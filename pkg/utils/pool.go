@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"context"
+	"sync"
+)
+
+// Pool runs tasks concurrently, with at most a fixed number of them running
+// at the same time, and collects the errors of all of them.
+//
+// Go does not block, so a task can schedule follow-up tasks in the same pool,
+// for example to fetch the children of the entities it fetched, without
+// holding its slot.
+type Pool struct {
+	ctx   context.Context
+	slots chan struct{}
+	wg    sync.WaitGroup
+
+	mu   sync.Mutex
+	errs []error
+}
+
+// NewPool returns a Pool running at most concurrency tasks at the same time.
+// Tasks are passed ctx; once it is done, tasks not started yet are skipped.
+func NewPool(ctx context.Context, concurrency int) *Pool {
+	return &Pool{
+		ctx:   ctx,
+		slots: make(chan struct{}, max(concurrency, 1)),
+	}
+}
+
+// Go schedules task to run once a slot is free. It must not be called after
+// Wait returned.
+func (p *Pool) Go(task func(ctx context.Context) error) {
+	p.wg.Go(func() {
+		select {
+		case p.slots <- struct{}{}:
+		case <-p.ctx.Done():
+			return
+		}
+		defer func() { <-p.slots }()
+		if p.ctx.Err() != nil {
+			return
+		}
+		if err := task(p.ctx); err != nil {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			p.errs = append(p.errs, err)
+		}
+	})
+}
+
+// Wait waits for all tasks, including the ones they scheduled, to complete.
+// It returns the context error if the context of the pool is done, the error
+// of the task if a single one failed, or an ErrArray holding the errors of
+// all the tasks that failed.
+func (p *Pool) Wait() error {
+	p.wg.Wait()
+	if err := p.ctx.Err(); err != nil {
+		return err
+	}
+	switch len(p.errs) {
+	case 0:
+		return nil
+	case 1:
+		return p.errs[0]
+	default:
+		return ErrArray{Errors: p.errs}
+	}
+}
+
+// Collector accumulates the results of tasks running concurrently.
+type Collector[T any] struct {
+	mu    sync.Mutex
+	items []T
+}
+
+// Add appends items to the results.
+func (c *Collector[T]) Add(items ...T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = append(c.items, items...)
+}
+
+// Items returns the results added so far.
+func (c *Collector[T]) Items() []T {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.items
+}
@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPool(t *testing.T) {
+	pool := NewPool(context.Background(), 3)
+	var running, maxRunning atomic.Int32
+	var results Collector[int]
+	for i := range 10 {
+		pool.Go(func(context.Context) error {
+			n := running.Add(1)
+			defer running.Add(-1)
+			if n > maxRunning.Load() {
+				maxRunning.Store(n)
+			}
+			// follow-up tasks run in the same pool
+			pool.Go(func(context.Context) error {
+				results.Add(i * 10)
+				return nil
+			})
+			results.Add(i)
+			return nil
+		})
+	}
+	require.NoError(t, pool.Wait())
+	assert.Len(t, results.Items(), 20)
+	assert.LessOrEqual(t, maxRunning.Load(), int32(3))
+}
+
+func TestPoolErrors(t *testing.T) {
+	pool := NewPool(context.Background(), 2)
+	pool.Go(func(context.Context) error { return errors.New("first") })
+	pool.Go(func(context.Context) error { return nil })
+	require.EqualError(t, pool.Wait(), "first")
+
+	pool = NewPool(context.Background(), 2)
+	for range 3 {
+		pool.Go(func(context.Context) error { return errors.New("failed") })
+	}
+	var errs ErrArray
+	require.ErrorAs(t, pool.Wait(), &errs)
+	assert.Len(t, errs.Errors, 3)
+}
+
+func TestPoolCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	pool := NewPool(ctx, 1)
+	started, release := make(chan struct{}), make(chan struct{})
+	pool.Go(func(context.Context) error {
+		close(started)
+		<-release
+		return nil
+	})
+	<-started
+	var ran atomic.Int32
+	for range 5 {
+		pool.Go(func(context.Context) error {
+			ran.Add(1)
+			return nil
+		})
+	}
+	cancel()
+	close(release)
+	require.ErrorIs(t, pool.Wait(), context.Canceled)
+	// tasks waiting for a slot are skipped
+	assert.Zero(t, ran.Load())
+}
@@ -0,0 +1,485 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// TagExpression is a boolean expression over entity tags, used to select
+// the entities managed by a configuration with more than a plain list of
+// tags that must all be present.
+//
+// Tags are combined with AND, OR and NOT (or &&, || and !), and
+// parentheses can be used for grouping. NOT binds tighter than AND, which
+// binds tighter than OR. Tags containing spaces, parentheses or quotes, or
+// spelled like an operator, must be double-quoted:
+//
+//	team-a OR team-b
+//	managed AND NOT legacy
+//	(team-a OR team-b) AND NOT "and"
+type TagExpression struct {
+	root tagTerm
+}
+
+type tagTerm interface {
+	match(tags map[string]struct{}) bool
+	String() string
+}
+
+type tagLiteral string
+
+type tagNot struct {
+	term tagTerm
+}
+
+type tagAnd []tagTerm
+
+type tagOr []tagTerm
+
+func (t tagLiteral) match(tags map[string]struct{}) bool {
+	_, ok := tags[string(t)]
+	return ok
+}
+
+func (t tagLiteral) String() string {
+	s := string(t)
+	if s == "" || isTagOperator(s) || strings.ContainsFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(`()"!&|`, r)
+	}) {
+		return fmt.Sprintf("%q", s)
+	}
+	return s
+}
+
+func (t tagNot) match(tags map[string]struct{}) bool {
+	return !t.term.match(tags)
+}
+
+func (t tagNot) String() string {
+	if _, ok := t.term.(tagLiteral); ok {
+		return "NOT " + t.term.String()
+	}
+	if _, ok := t.term.(tagNot); ok {
+		return "NOT " + t.term.String()
+	}
+	return "NOT (" + t.term.String() + ")"
+}
+
+func (t tagAnd) match(tags map[string]struct{}) bool {
+	for _, term := range t {
+		if !term.match(tags) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t tagAnd) String() string {
+	parts := make([]string, 0, len(t))
+	for _, term := range t {
+		if _, ok := term.(tagOr); ok {
+			parts = append(parts, "("+term.String()+")")
+			continue
+		}
+		parts = append(parts, term.String())
+	}
+	return strings.Join(parts, " AND ")
+}
+
+func (t tagOr) match(tags map[string]struct{}) bool {
+	for _, term := range t {
+		if term.match(tags) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t tagOr) String() string {
+	parts := make([]string, 0, len(t))
+	for _, term := range t {
+		parts = append(parts, term.String())
+	}
+	return strings.Join(parts, " OR ")
+}
+
+// ParseTagExpression parses a boolean tag expression.
+func ParseTagExpression(expression string) (*TagExpression, error) {
+	p := &tagParser{input: expression}
+	if err := p.next(); err != nil {
+		return nil, p.errorf("%w", err)
+	}
+	if p.token.kind == tagTokenEOF {
+		return nil, fmt.Errorf("invalid tag expression %q: expression is empty", expression)
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.token.kind != tagTokenEOF {
+		return nil, p.errorf("unexpected %q", p.token.text)
+	}
+	return &TagExpression{root: root}, nil
+}
+
+// MustParseTagExpression is same as ParseTagExpression but panics if there is an error.
+func MustParseTagExpression(expression string) *TagExpression {
+	e, err := ParseTagExpression(expression)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// String returns the canonical form of the expression.
+func (e *TagExpression) String() string {
+	return e.root.String()
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (e *TagExpression) MarshalText() ([]byte, error) {
+	return []byte(e.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (e *TagExpression) UnmarshalText(text []byte) error {
+	parsed, err := ParseTagExpression(string(text))
+	if err != nil {
+		return err
+	}
+	*e = *parsed
+	return nil
+}
+
+// Matches reports whether an entity carrying tags is selected by the expression.
+func (e *TagExpression) Matches(tags []string) bool {
+	set := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		set[tag] = struct{}{}
+	}
+	return e.root.match(set)
+}
+
+// MatchesObject reports whether the Tags of obj are selected by the expression.
+// Objects without a Tags field cannot be tagged and are always selected.
+func (e *TagExpression) MatchesObject(obj any) bool {
+	v := reflect.Indirect(reflect.ValueOf(obj))
+	if v.Kind() != reflect.Struct {
+		return true
+	}
+	structTags := v.FieldByName("Tags")
+	if !structTags.IsValid() || structTags.Kind() != reflect.Slice {
+		return true
+	}
+	tags := make([]string, 0, structTags.Len())
+	for i := 0; i < structTags.Len(); i++ {
+		tag := reflect.Indirect(structTags.Index(i))
+		if tag.IsValid() {
+			tags = append(tags, tag.String())
+		}
+	}
+	return e.Matches(tags)
+}
+
+// RequiredTags returns the tags that every entity selected by the expression
+// carries. These are the tags stamped onto entities when the expression is used
+// to select the entities of a configuration.
+func (e *TagExpression) RequiredTags() []string {
+	return requiredTags(e.root)
+}
+
+func requiredTags(term tagTerm) []string {
+	switch t := term.(type) {
+	case tagLiteral:
+		return []string{string(t)}
+	case tagAnd:
+		var res []string
+		for _, term := range t {
+			for _, tag := range requiredTags(term) {
+				if !slices.Contains(res, tag) {
+					res = append(res, tag)
+				}
+			}
+		}
+		return res
+	case tagOr:
+		res := requiredTags(t[0])
+		for _, term := range t[1:] {
+			other := requiredTags(term)
+			res = slices.DeleteFunc(res, func(tag string) bool {
+				return !slices.Contains(other, tag)
+			})
+		}
+		if len(res) == 0 {
+			return nil
+		}
+		return res
+	}
+	return nil
+}
+
+// MapTags returns a copy of the expression with every tag replaced by the
+// result of mapping.
+func (e *TagExpression) MapTags(mapping func(string) string) (*TagExpression, error) {
+	// The mapped expression is parsed again so that the mapped tags are
+	// validated like any other.
+	return ParseTagExpression(mapTags(e.root, mapping).String())
+}
+
+func mapTags(term tagTerm, mapping func(string) string) tagTerm {
+	switch t := term.(type) {
+	case tagLiteral:
+		return tagLiteral(mapping(string(t)))
+	case tagNot:
+		return tagNot{term: mapTags(t.term, mapping)}
+	case tagAnd:
+		res := make(tagAnd, 0, len(t))
+		for _, term := range t {
+			res = append(res, mapTags(term, mapping))
+		}
+		return res
+	case tagOr:
+		res := make(tagOr, 0, len(t))
+		for _, term := range t {
+			res = append(res, mapTags(term, mapping))
+		}
+		return res
+	}
+	return term
+}
+
+// AllOf returns the tags of an expression that is a conjunction of plain tags,
+// such as "a AND b". ok is false for any other expression.
+func (e *TagExpression) AllOf() (tags []string, ok bool) {
+	return plainTags[tagAnd](e.root)
+}
+
+// AnyOf returns the tags of an expression that is a disjunction of plain tags,
+// such as "a OR b". ok is false for any other expression.
+func (e *TagExpression) AnyOf() (tags []string, ok bool) {
+	return plainTags[tagOr](e.root)
+}
+
+func plainTags[T tagAnd | tagOr](term tagTerm) ([]string, bool) {
+	if literal, ok := term.(tagLiteral); ok {
+		return []string{string(literal)}, true
+	}
+	terms, ok := term.(T)
+	if !ok {
+		return nil, false
+	}
+	res := make([]string, 0, len(terms))
+	for _, term := range terms {
+		literal, ok := term.(tagLiteral)
+		if !ok {
+			return nil, false
+		}
+		res = append(res, string(literal))
+	}
+	return res, true
+}
+
+type tagTokenKind int
+
+const (
+	tagTokenEOF tagTokenKind = iota
+	tagTokenTag
+	tagTokenAnd
+	tagTokenOr
+	tagTokenNot
+	tagTokenLParen
+	tagTokenRParen
+)
+
+type tagToken struct {
+	kind   tagTokenKind
+	text   string
+	offset int
+}
+
+type tagParser struct {
+	input string
+	pos   int
+	token tagToken
+}
+
+func isTagOperator(s string) bool {
+	switch strings.ToUpper(s) {
+	case "AND", "OR", "NOT", "&&", "||":
+		return true
+	}
+	return false
+}
+
+func (p *tagParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("invalid tag expression %q: %w at offset %d", p.input,
+		fmt.Errorf(format, args...), p.token.offset)
+}
+
+func (p *tagParser) next() error {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+	start := p.pos
+	p.token = tagToken{offset: start}
+	if p.pos == len(p.input) {
+		return nil
+	}
+	switch c := p.input[p.pos]; {
+	case c == '(':
+		p.pos++
+		p.token.kind, p.token.text = tagTokenLParen, "("
+	case c == ')':
+		p.pos++
+		p.token.kind, p.token.text = tagTokenRParen, ")"
+	case c == '!':
+		p.pos++
+		p.token.kind, p.token.text = tagTokenNot, "!"
+	case strings.HasPrefix(p.input[p.pos:], "&&"):
+		p.pos += 2
+		p.token.kind, p.token.text = tagTokenAnd, "&&"
+	case strings.HasPrefix(p.input[p.pos:], "||"):
+		p.pos += 2
+		p.token.kind, p.token.text = tagTokenOr, "||"
+	case c == '"':
+		end := strings.IndexByte(p.input[p.pos+1:], '"')
+		if end < 0 {
+			return fmt.Errorf("unterminated quoted tag")
+		}
+		p.token.kind, p.token.text = tagTokenTag, p.input[p.pos+1:p.pos+1+end]
+		p.pos += end + 2
+	default:
+		for p.pos < len(p.input) {
+			c := p.input[p.pos]
+			if unicode.IsSpace(rune(c)) || strings.IndexByte(`()"!`, c) >= 0 ||
+				strings.HasPrefix(p.input[p.pos:], "&&") || strings.HasPrefix(p.input[p.pos:], "||") {
+				break
+			}
+			p.pos++
+		}
+		p.token.kind, p.token.text = tagTokenTag, p.input[start:p.pos]
+		switch strings.ToUpper(p.token.text) {
+		case "AND":
+			p.token.kind = tagTokenAnd
+		case "OR":
+			p.token.kind = tagTokenOr
+		case "NOT":
+			p.token.kind = tagTokenNot
+		}
+	}
+	return nil
+}
+
+func (p *tagParser) advance() error {
+	if err := p.next(); err != nil {
+		return p.errorf("%w", err)
+	}
+	return nil
+}
+
+func (p *tagParser) parseOr() (tagTerm, error) {
+	term, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	terms := tagOr{}
+	for p.token.kind == tagTokenOr {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if len(terms) == 0 {
+			terms = appendTagTerm(terms, term)
+		}
+		terms = appendTagTerm(terms, right)
+	}
+	if len(terms) == 0 {
+		return term, nil
+	}
+	return terms, nil
+}
+
+func (p *tagParser) parseAnd() (tagTerm, error) {
+	term, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	terms := tagAnd{}
+	for p.token.kind == tagTokenAnd {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if len(terms) == 0 {
+			terms = appendTagTerm(terms, term)
+		}
+		terms = appendTagTerm(terms, right)
+	}
+	if len(terms) == 0 {
+		return term, nil
+	}
+	return terms, nil
+}
+
+// appendTagTerm appends term to terms, flattening nested terms of the same
+// kind so that "(a AND b) AND c" is equivalent to "a AND b AND c".
+func appendTagTerm[T tagAnd | tagOr](terms T, term tagTerm) T {
+	if nested, ok := term.(T); ok {
+		return append(terms, nested...)
+	}
+	return append(terms, term)
+}
+
+func (p *tagParser) parseUnary() (tagTerm, error) {
+	switch p.token.kind {
+	case tagTokenNot:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		term, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return tagNot{term: term}, nil
+	case tagTokenLParen:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		term, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.token.kind != tagTokenRParen {
+			return nil, p.errorf(`expected ")"`)
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		return term, nil
+	case tagTokenTag:
+		if p.token.text == "" {
+			return nil, p.errorf("tags must not be empty")
+		}
+		if strings.ContainsAny(p.token.text, ",/") {
+			return nil, p.errorf("tag %q must not contain ',' or '/'", p.token.text)
+		}
+		term := tagLiteral(p.token.text)
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		return term, nil
+	case tagTokenEOF:
+		return nil, p.errorf("unexpected end of expression")
+	case tagTokenAnd, tagTokenOr, tagTokenRParen:
+	}
+	return nil, p.errorf("unexpected %q", p.token.text)
+}
@@ -0,0 +1,151 @@
+package utils
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTagExpression(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       string
+		matches    [][]string
+		misses     [][]string
+	}{
+		{
+			expression: "team-a OR team-b",
+			want:       "team-a OR team-b",
+			matches:    [][]string{{"team-a"}, {"team-b", "other"}},
+			misses:     [][]string{{}, {"team-c"}},
+		},
+		{
+			expression: "managed and not legacy",
+			want:       "managed AND NOT legacy",
+			matches:    [][]string{{"managed"}, {"managed", "other"}},
+			misses:     [][]string{{"managed", "legacy"}, {"legacy"}},
+		},
+		{
+			expression: "(team-a || team-b) && !legacy",
+			want:       "(team-a OR team-b) AND NOT legacy",
+			matches:    [][]string{{"team-b"}},
+			misses:     [][]string{{"team-a", "legacy"}, {"managed"}},
+		},
+		{
+			expression: `a AND (b AND c) OR NOT (d OR "and")`,
+			want:       `a AND b AND c OR NOT (d OR "and")`,
+			matches:    [][]string{{"a", "b", "c", "d"}, {}},
+			misses:     [][]string{{"a", "b", "and"}},
+		},
+		{
+			expression: "NOT NOT managed:team",
+			want:       "NOT NOT managed:team",
+			matches:    [][]string{{"managed:team"}},
+			misses:     [][]string{{"managed"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			e, err := ParseTagExpression(tt.expression)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, e.String())
+			for _, tags := range tt.matches {
+				assert.True(t, e.Matches(tags), "tags: %v", tags)
+			}
+			for _, tags := range tt.misses {
+				assert.False(t, e.Matches(tags), "tags: %v", tags)
+			}
+			reparsed, err := ParseTagExpression(e.String())
+			require.NoError(t, err)
+			assert.Equal(t, e, reparsed)
+		})
+	}
+}
+
+func TestParseTagExpressionErrors(t *testing.T) {
+	tests := map[string]string{
+		"":              `invalid tag expression "": expression is empty`,
+		"a AND":         `invalid tag expression "a AND": unexpected end of expression at offset 5`,
+		"(a OR b":       `invalid tag expression "(a OR b": expected ")" at offset 7`,
+		"a b":           `invalid tag expression "a b": unexpected "b" at offset 2`,
+		"OR a":          `invalid tag expression "OR a": unexpected "OR" at offset 0`,
+		`"a`:            `invalid tag expression "\"a": unterminated quoted tag at offset 0`,
+		`a AND ""`:      `invalid tag expression "a AND \"\"": tags must not be empty at offset 6`,
+		"team/a OR b":   `invalid tag expression "team/a OR b": tag "team/a" must not contain ',' or '/' at offset 0`,
+		"a AND (b OR )": `invalid tag expression "a AND (b OR )": unexpected ")" at offset 12`,
+	}
+	for expression, want := range tests {
+		t.Run(expression, func(t *testing.T) {
+			_, err := ParseTagExpression(expression)
+			require.EqualError(t, err, want)
+		})
+	}
+}
+
+func TestTagExpressionTerms(t *testing.T) {
+	tests := []struct {
+		expression string
+		required   []string
+		allOf      []string
+		anyOf      []string
+	}{
+		{expression: "a", required: []string{"a"}, allOf: []string{"a"}, anyOf: []string{"a"}},
+		{expression: "a AND b AND a", required: []string{"a", "b"}, allOf: []string{"a", "b", "a"}},
+		{expression: "a OR b", anyOf: []string{"a", "b"}},
+		{expression: "managed AND NOT legacy", required: []string{"managed"}},
+		{expression: "(a AND b) OR (a AND c)", required: []string{"a"}},
+		{expression: "a AND (b OR c)", required: []string{"a"}},
+		{expression: "NOT a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			e := MustParseTagExpression(tt.expression)
+			assert.Equal(t, tt.required, e.RequiredTags())
+			allOf, ok := e.AllOf()
+			assert.Equal(t, tt.allOf != nil, ok)
+			assert.Equal(t, tt.allOf, allOf)
+			anyOf, ok := e.AnyOf()
+			assert.Equal(t, tt.anyOf != nil, ok)
+			assert.Equal(t, tt.anyOf, anyOf)
+		})
+	}
+}
+
+func TestTagExpressionMatchesObject(t *testing.T) {
+	e := MustParseTagExpression("managed AND NOT legacy")
+	assert.True(t, e.MatchesObject(&kong.Service{Tags: kong.StringSlice("managed")}))
+	assert.False(t, e.MatchesObject(kong.Service{Tags: kong.StringSlice("managed", "legacy")}))
+	assert.False(t, e.MatchesObject(&kong.Route{}))
+	// objects which cannot be tagged are always selected.
+	assert.True(t, e.MatchesObject(&kong.ConsumerGroupObject{}))
+	assert.True(t, e.MatchesObject((*kong.Service)(nil)))
+
+	t.Run("text encoding", func(t *testing.T) {
+		var v struct {
+			Expression *TagExpression `json:"expression"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(`{"expression":"a or (b and not c)"}`), &v))
+		out, err := json.Marshal(v)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"expression":"a OR b AND NOT c"}`, string(out))
+		require.Error(t, json.Unmarshal([]byte(`{"expression":"a or"}`), &v))
+	})
+}
+
+func TestTagExpressionMapTags(t *testing.T) {
+	rename := func(tag string) string {
+		if tag == "staging" {
+			return "production"
+		}
+		return tag
+	}
+	e, err := MustParseTagExpression("(staging OR shared) AND NOT staging-legacy").MapTags(rename)
+	require.NoError(t, err)
+	assert.Equal(t, "(production OR shared) AND NOT staging-legacy", e.String())
+
+	_, err = MustParseTagExpression("staging").MapTags(func(string) string { return "a/b" })
+	require.Error(t, err)
+}
@@ -17,6 +17,7 @@ import (
 
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/kong/go-database-reconciler/pkg/konnect"
+	"github.com/kong/go-database-reconciler/pkg/schema"
 	"github.com/kong/go-kong/kong"
 	"github.com/kong/go-kong/kong/custom"
 	"github.com/ssgelm/cookiejarparser"
@@ -42,11 +43,21 @@ type KongRawState struct {
 	Consumers      []*kong.Consumer
 	ConsumerGroups []*kong.ConsumerGroupObject
 	CustomEntities []custom.Entity
+	// CustomEntityDefinitions maps the types of the custom entities, besides
+	// degraphql_routes and graphql_ratelimiting_cost_decorations, to their
+	// definition derived from their schema.
+	CustomEntityDefinitions map[string]*schema.EntityDefinition
 
 	Vaults   []*kong.Vault
 	Licenses []*kong.License
 	Partials []*kong.Partial
 
+	// Workspaces are global to Kong Enterprise rather than scoped to the
+	// workspace of the client.
+	Workspaces []*kong.Workspace
+
+	EventHooks []*EventHook
+
 	KeyAuths    []*kong.KeyAuth
 	HMACAuths   []*kong.HMACAuth
 	JWTAuths    []*kong.JWTAuth
@@ -60,6 +71,13 @@ type KongRawState struct {
 
 	RBACRoles               []*kong.RBACRole
 	RBACEndpointPermissions []*kong.RBACEndpointPermission
+	RBACEntityPermissions   []*kong.RBACEntityPermission
+	RBACUsers               []*kong.RBACUser
+	// RBACUserRoles maps the ID of RBAC users to the names of their roles.
+	RBACUserRoles map[string][]string
+	Admins        []*kong.Admin
+	// AdminRoles maps the ID of admins to the names of their roles.
+	AdminRoles map[string][]string
 
 	Keys    []*kong.Key
 	KeySets []*kong.KeySet
@@ -72,6 +90,13 @@ type KongRawState struct {
 type KonnectRawState struct {
 	ServicePackages []*konnect.ServicePackage
 	Documents       []*konnect.Document
+
+	APIProducts         []*konnect.APIProduct
+	APIProductVersions  []*konnect.APIProductVersion
+	APIProductDocuments []*konnect.APIProductDocument
+	Portals             []*konnect.Portal
+
+	ControlPlanes []*konnect.ControlPlaneV2
 }
 
 // ErrArray holds an array of errors.
@@ -118,6 +143,10 @@ type KongClientConfig struct {
 
 	Headers []string
 
+	// CredentialProvider, if set, supplies the bearer token of each request,
+	// such as to the Admin API of a Konnect control plane.
+	CredentialProvider konnect.CredentialProvider
+
 	HTTPClient *http.Client
 
 	Timeout int
@@ -136,6 +165,15 @@ type KonnectConfig struct {
 	Token    string
 	Debug    bool
 
+	// CredentialProvider, if set, supplies the token of each request to
+	// Konnect instead of Token.
+	CredentialProvider konnect.CredentialProvider
+
+	// RetryPolicy controls how requests to Konnect are retried. It defaults
+	// to konnect.DefaultRetryPolicy; set MaxRetries to zero to disable
+	// retries.
+	RetryPolicy *konnect.RetryPolicy
+
 	Address string
 
 	Headers []string
@@ -256,6 +294,9 @@ func GetKongClient(opt KongClientConfig) (*kong.Client, error) {
 		TLSClientConfig:     tlsConfig,
 		ForceAttemptHTTP2:   true,
 	}
+	if opt.CredentialProvider != nil {
+		c.Transport = konnect.NewCredentialTransport(c.Transport, opt.CredentialProvider)
+	}
 	address := CleanAddress(opt.Address)
 
 	headers, err := parseHeaders(opt.Headers)
@@ -362,8 +403,14 @@ func GetKonnectClient(httpClient *http.Client, config KonnectConfig) (*konnect.C
 		return nil, fmt.Errorf("parsing headers: %w", err)
 	}
 	httpClient = kong.HTTPClientWithHeaders(httpClient, headers)
+	retryPolicy := konnect.DefaultRetryPolicy()
+	if config.RetryPolicy != nil {
+		retryPolicy = *config.RetryPolicy
+	}
 	client, err := konnect.NewClient(httpClient, konnect.ClientOpts{
-		BaseURL: address,
+		BaseURL:            address,
+		CredentialProvider: config.CredentialProvider,
+		RetryPolicy:        &retryPolicy,
 	})
 	if err != nil {
 		return nil, err
@@ -34,6 +34,61 @@ var ErrorConsumerGroupUpgrade = errors.New(
 		"Check https://docs.konghq.com/gateway/latest/kong-enterprise/consumer-groups/ for more information",
 )
 
+// UsesRLAConsumerGroups returns true if config, the configuration of a
+// rate-limiting-advanced plugin, uses the consumer_groups or
+// enforce_consumer_groups fields deprecated since Kong Enterprise 3.4.0.
+func UsesRLAConsumerGroups(config kong.Configuration) bool {
+	// if groups is an array of length > 0, then consumer_groups is set
+	if groups, ok := config["consumer_groups"].([]any); ok && len(groups) > 0 {
+		return true
+	}
+	enforce, ok := config["enforce_consumer_groups"].(bool)
+	return ok && enforce
+}
+
+// openIDConnectRequiredConfigFields lists the fields of the openid-connect
+// plugin which Kong generates when they are not set, and which would then be
+// regenerated on every sync.
+var openIDConnectRequiredConfigFields = []string{
+	"cache_tokens_salt",
+}
+
+// MissingOpenIDConnectConfig returns the fields which must be set explicitly
+// in config, the configuration of an openid-connect plugin, and are not.
+func MissingOpenIDConnectConfig(config kong.Configuration) []string {
+	var missingFields []string
+	for _, field := range openIDConnectRequiredConfigFields {
+		value, ok := config[field]
+		if !ok || isEmptyPluginConfigValue(value) {
+			missingFields = append(missingFields, field)
+		}
+	}
+	return missingFields
+}
+
+// OpenIDConnectMissingConfigMessage returns the message reporting the fields
+// missing from the configuration of an openid-connect plugin.
+func OpenIDConnectMissingConfigMessage(missingFields []string) string {
+	return fmt.Sprintf(
+		"openid-connect plugin requires explicit non-empty config values for %s "+
+			"to avoid regenerating session credentials during sync",
+		strings.Join(missingFields, ", "),
+	)
+}
+
+func isEmptyPluginConfigValue(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case *string:
+		return v == nil || *v == ""
+	default:
+		return false
+	}
+}
+
 var UpgradeMessage = "Please upgrade your configuration to account for 3.0\n" +
 	"breaking changes using the following command:\n\n" +
 	"deck convert --from kong-gateway-2.x --to kong-gateway-3.x\n\n" +
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/kong/go-kong/kong"
+)
+
+const workspacesPageSize = 1000
+
+// NewRootRequest creates a request to endpoint of the Admin API of client,
+// outside of the workspace client is scoped to. Workspaces are managed with
+// such requests since they are global to Kong Enterprise.
+func NewRootRequest(client *kong.Client, method, endpoint string, body any) (*http.Request, error) {
+	return client.NewRequestRaw(method, client.BaseRootURL(), endpoint, nil, body)
+}
+
+// ListWorkspaces lists all the workspaces of Kong Enterprise, whatever the
+// workspace client is scoped to.
+func ListWorkspaces(ctx context.Context, client *kong.Client) ([]*kong.Workspace, error) {
+	var workspaces []*kong.Workspace
+	query := url.Values{"size": {strconv.Itoa(workspacesPageSize)}}
+	for {
+		req, err := NewRootRequest(client, http.MethodGet, "/workspaces?"+query.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Data   []*kong.Workspace `json:"data"`
+			Offset string            `json:"offset"`
+		}
+		if _, err := client.Do(ctx, req, &page); err != nil {
+			return nil, err
+		}
+		workspaces = append(workspaces, page.Data...)
+		if page.Offset == "" {
+			return workspaces, nil
+		}
+		query.Set("offset", page.Offset)
+	}
+}